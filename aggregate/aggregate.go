@@ -0,0 +1,204 @@
+// Package aggregate summarizes polled values in time-aligned windows (i.e. one-minute summaries of a high-frequency poll).
+//
+// The responses of a poll are flattened into values by path: the tag, with the index of the component appended for
+// indexed components (i.e. BAT_RSOC@0) and prefixed by the nested containers (i.e. PVI_DC_POWER[1].PVI_VALUE@0).
+// A window emits min, max, mean, last and the sample count of every numeric path, non-numeric values (booleans, strings
+// and timestamps) are summarized by the last value and the number of changes. Windows are aligned by time.Truncate to
+// multiples of their size since the zero time (January 1, year 1 UTC), so the windows of multiple instances match.
+// Sizes dividing a day start at the same times as if aligned to the unix epoch, windows of a day start at midnight UTC
+// and not at the local midnight.
+//
+// The windows are printed by the telegraf command, the snmp agent and the counters use every poll as is.
+package aggregate
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+var ErrInvalidSize = errors.New("invalid window size")
+
+// pathSeparator separates the containers of a path
+const pathSeparator = "."
+
+// Flatten returns the values of the responses by path, error responses are skipped
+func Flatten(responses []rscp.Message) map[string]interface{} {
+	r := map[string]interface{}{}
+	flatten(r, responses, "", "")
+	return r
+}
+
+// flatten adds the values of the messages with the prefix and suffix of the path
+func flatten(r map[string]interface{}, messages []rscp.Message, prefix, suffix string) {
+	for _, m := range messages {
+		if _, isError := m.Value.(rscp.RscpError); isError {
+			continue
+		}
+		sub, isContainer := m.Value.([]rscp.Message)
+		if !isContainer {
+			r[prefix+m.Tag.String()+suffix] = m.Value
+			continue
+		}
+		if n, ok := rscp.NamespaceByContainer(m.Tag); ok {
+			if index := rscp.FindTag(sub, n.IndexTag); index != nil {
+				if i, err := index.Float64(); err == nil {
+					flatten(r, without(sub, n.IndexTag), prefix, rscp.QueryIndexSeparator+strconv.Itoa(int(i))+suffix)
+					continue
+				}
+			}
+		}
+		p := prefix + m.Tag.String()
+		// containers of a value with an index (i.e. PVI_INDEX & PVI_VALUE)
+		for _, s := range sub {
+			if strings.HasSuffix(s.Tag.String(), "_INDEX") {
+				if i, err := s.Float64(); err == nil {
+					p += "[" + strconv.Itoa(int(i)) + "]"
+					sub = without(sub, s.Tag)
+					break
+				}
+			}
+		}
+		flatten(r, sub, p+pathSeparator, suffix)
+	}
+}
+
+// without returns the messages without the tag
+func without(messages []rscp.Message, tag rscp.Tag) []rscp.Message {
+	r := make([]rscp.Message, 0, len(messages))
+	for _, m := range messages {
+		if m.Tag != tag {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// Aggregate of the values of a path within a window
+type Aggregate struct {
+	Path string `json:"path"`
+	// start of the window
+	Start time.Time `json:"start"`
+	// number of samples
+	Count int `json:"count"`
+	// if all values were numbers, Min, Max, Mean and Last are set
+	Numeric bool    `json:"numeric"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Mean    float64 `json:"mean"`
+	Last    float64 `json:"last"`
+	// last value of non-numeric values
+	Value interface{} `json:"value,omitempty"`
+	// number of changes of non-numeric values within the window
+	Changes int `json:"changes,omitempty"`
+}
+
+// series of a path within the current window
+type series struct {
+	count   int
+	numeric bool
+	min     float64
+	max     float64
+	sum     float64
+	last    float64
+	value   interface{}
+	changes int
+}
+
+// add adds a value to the series
+func (s *series) add(v interface{}) {
+	f, numeric := number(v)
+	if s.count == 0 {
+		s.numeric, s.min, s.max = numeric, f, f
+	} else if !reflect.DeepEqual(s.value, v) {
+		s.changes++
+	}
+	s.count++
+	s.value = v
+	if !numeric {
+		// a path changing from numeric to non-numeric is summarized as non-numeric
+		s.numeric = false
+		return
+	}
+	if f < s.min {
+		s.min = f
+	}
+	if f > s.max {
+		s.max = f
+	}
+	s.sum += f
+	s.last = f
+}
+
+// number returns the value as float64 if it's a number
+func number(v interface{}) (float64, bool) {
+	switch v.(type) {
+	case int8, uint8, int16, uint16, int32, uint32, int64, uint64, float32, float64:
+		f, err := rscp.Message{Value: v}.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Window aggregates the values of the current window
+type Window struct {
+	size   time.Duration
+	start  time.Time
+	series map[string]*series
+}
+
+// NewWindow creates a window of the size
+func NewWindow(size time.Duration) (*Window, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return &Window{size: size, series: map[string]*series{}}, nil
+}
+
+// Add adds the values of the responses sampled at the time,
+// returns the aggregates of the previous window if the time starts a new window.
+func (w *Window) Add(t time.Time, responses []rscp.Message) []Aggregate {
+	return w.AddValues(t, Flatten(responses))
+}
+
+// AddValues adds the values by path sampled at the time (i.e. computed tags),
+// returns the aggregates of the previous window if the time starts a new window.
+func (w *Window) AddValues(t time.Time, values map[string]interface{}) []Aggregate {
+	var r []Aggregate
+	if start := t.Truncate(w.size); !start.Equal(w.start) {
+		r = w.Flush()
+		w.start = start
+	}
+	for p, v := range values {
+		s, ok := w.series[p]
+		if !ok {
+			s = &series{}
+			w.series[p] = s
+		}
+		s.add(v)
+	}
+	return r
+}
+
+// Flush returns the aggregates of the current window sorted by path and starts a new empty window
+// (i.e. to emit the partial window on shutdown).
+func (w *Window) Flush() []Aggregate {
+	r := make([]Aggregate, 0, len(w.series))
+	for p, s := range w.series {
+		a := Aggregate{Path: p, Start: w.start, Count: s.count, Numeric: s.numeric}
+		if s.numeric {
+			a.Min, a.Max, a.Mean, a.Last = s.min, s.max, s.sum/float64(s.count), s.last
+		} else {
+			a.Value, a.Changes = s.value, s.changes
+		}
+		r = append(r, a)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Path < r[j].Path })
+	w.series = map[string]*series{}
+	return r
+}
@@ -0,0 +1,77 @@
+package aggregate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestFlatten(t *testing.T) {
+	responses := []rscp.Message{
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1000)},
+		{Tag: rscp.EMS_POWER_BAT, DataType: rscp.Error, Value: rscp.ERR_NOT_AVAILABLE},
+		{Tag: rscp.EMS_GET_POWER_SETTINGS, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.EMS_MAX_CHARGE_POWER, DataType: rscp.Uint32, Value: uint32(4500)},
+		}},
+		{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.PVI_ON_GRID, DataType: rscp.Bool, Value: true},
+			{Tag: rscp.PVI_DC_POWER, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(1)},
+				{Tag: rscp.PVI_VALUE, DataType: rscp.Float32, Value: float32(250)},
+			}},
+		}},
+	}
+	want := map[string]interface{}{
+		"EMS_POWER_PV": int32(1000),
+		"EMS_GET_POWER_SETTINGS.EMS_MAX_CHARGE_POWER": uint32(4500),
+		"PVI_ON_GRID@0":               true,
+		"PVI_DC_POWER[1].PVI_VALUE@0": float32(250),
+	}
+	if diff := deep.Equal(Flatten(responses), want); diff != nil {
+		t.Errorf("Flatten() = %v, want %v\n%s", Flatten(responses), want, diff)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	if _, err := NewWindow(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewWindow(0) error = %v, want %v", err, ErrInvalidSize)
+	}
+	w, err := NewWindow(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	samples := []struct {
+		offset time.Duration
+		values map[string]interface{}
+	}{
+		{10 * time.Second, map[string]interface{}{"P": int32(10), "S": "idle", "B": true}},
+		{30 * time.Second, map[string]interface{}{"P": float32(30), "S": "idle", "B": false}},
+		{50 * time.Second, map[string]interface{}{"P": int32(20), "S": "charging", "B": true}},
+	}
+	for _, s := range samples {
+		if got := w.AddValues(start.Add(s.offset), s.values); len(got) != 0 {
+			t.Fatalf("AddValues() within the window = %v, want none", got)
+		}
+	}
+	got := w.AddValues(start.Add(70*time.Second), map[string]interface{}{"P": int32(0)})
+	want := []Aggregate{
+		{Path: "B", Start: start, Count: 3, Value: true, Changes: 2},
+		{Path: "P", Start: start, Count: 3, Numeric: true, Min: 10, Max: 30, Mean: 20, Last: 20},
+		{Path: "S", Start: start, Count: 3, Value: "charging", Changes: 1},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("AddValues() = %v, want %v\n%s", got, want, diff)
+	}
+	want = []Aggregate{{Path: "P", Start: start.Add(time.Minute), Count: 1, Numeric: true}}
+	if diff := deep.Equal(w.Flush(), want); diff != nil {
+		t.Errorf("Flush() %s", diff)
+	}
+	if got := w.Flush(); len(got) != 0 {
+		t.Errorf("Flush() of an empty window = %v, want none", got)
+	}
+}
@@ -0,0 +1,256 @@
+// Package assertion runs declarative assertions against the values of a live system (i.e. in site monitoring).
+//
+// a suite is defined in a yaml file, every assertion addresses a value by query (i.e. BAT_RSOC@0) and expects it
+// to equal a value, to be within a range or a container to have a number of entries:
+//
+//  name: home
+//  assertions:
+//    - tag: BAT_RSOC@0
+//      within: [5, 100]
+//    - tag: PVI_ON_GRID@0
+//      equals: true
+//    - name: no unconfirmed errors
+//      tag: EMS_STORED_ERRORS
+//      count: 0
+//
+// the values of all assertions are requested at once, the results are reported as JUnit XML or json.
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spali/go-rscp/internal/yamlfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoAssertions       = errors.New("no assertions defined")
+	ErrMissingTag         = errors.New("missing tag")
+	ErrMissingExpectation = errors.New("missing expectation (equals, within or count)")
+	ErrMultipleExpect     = errors.New("only one of equals, within or count allowed")
+	ErrInvalidRange       = errors.New("within requires [min, max] with min <= max")
+	ErrInvalidEquals      = errors.New("equals requires a number, boolean or string")
+	ErrFailed             = errors.New("assertions failed")
+)
+
+// Assertion on a single value
+type Assertion struct {
+	// name in the reports, a description of the assertion if empty
+	Name string `json:"name"`
+	// query of the value (i.e. EMS_MAX_CHARGE_POWER or PM_ACTIVE_PHASES@0)
+	Tag string `json:"tag"`
+	// expected number, boolean or string
+	Equals interface{} `json:"equals"`
+	// expected range [min, max] of a number, inclusive
+	Within []float64 `json:"within"`
+	// expected number of entries of a container (i.e. 0 stored errors)
+	Count *int `json:"count"`
+	// parsed Tag
+	query rscp.Query
+}
+
+// Suite of assertions of a site
+type Suite struct {
+	// name of the suite, the file name without extension if empty
+	Name       string      `json:"name"`
+	Assertions []Assertion `json:"assertions"`
+}
+
+// Load reads the suite from a yaml file
+func Load(path string) (Suite, error) {
+	s := Suite{}
+	if err := yamlfile.Read(path, &s); err != nil {
+		return s, err
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return s, s.check()
+}
+
+// check parses the assertions and sets the default names or fail if invalid
+func (s *Suite) check() error {
+	if len(s.Assertions) == 0 {
+		return ErrNoAssertions
+	}
+	for i := range s.Assertions {
+		if err := s.Assertions[i].check(); err != nil {
+			return fmt.Errorf("assertion %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// check parses the query and validates the expectation
+func (a *Assertion) check() error {
+	if a.Tag == "" {
+		return ErrMissingTag
+	}
+	q, err := rscp.ParseQuery(a.Tag)
+	if err != nil {
+		return err
+	}
+	a.query = q
+	expectations := 0
+	if a.Equals != nil {
+		expectations++
+		switch a.Equals.(type) {
+		case float64, bool, string:
+		default:
+			return fmt.Errorf("%s: %w", a.Tag, ErrInvalidEquals)
+		}
+	}
+	if a.Within != nil {
+		expectations++
+		if len(a.Within) != 2 || a.Within[0] > a.Within[1] {
+			return fmt.Errorf("%s: %w", a.Tag, ErrInvalidRange)
+		}
+	}
+	if a.Count != nil {
+		expectations++
+	}
+	switch {
+	case expectations == 0:
+		return fmt.Errorf("%s: %w", a.Tag, ErrMissingExpectation)
+	case expectations > 1:
+		return fmt.Errorf("%s: %w", a.Tag, ErrMultipleExpect)
+	}
+	if a.Name == "" {
+		a.Name = a.String()
+	}
+	return nil
+}
+
+// String returns a description of the assertion (i.e. "BAT_RSOC@0 within 5..100")
+func (a Assertion) String() string {
+	switch {
+	case a.Within != nil:
+		return fmt.Sprintf("%s within %g..%g", a.Tag, a.Within[0], a.Within[1])
+	case a.Count != nil:
+		return fmt.Sprintf("%s count == %d", a.Tag, *a.Count)
+	}
+	return fmt.Sprintf("%s == %v", a.Tag, a.Equals)
+}
+
+// Status of a result
+type Status string
+
+const (
+	StatusPassed Status = "passed"
+	// the value does not match the expectation
+	StatusFailed Status = "failed"
+	// the value could not be evaluated (i.e. missing or error response)
+	StatusError Status = "error"
+)
+
+// Result of an assertion
+type Result struct {
+	Name   string      `json:"name"`
+	Tag    string      `json:"tag"`
+	Status Status      `json:"status"`
+	Value  interface{} `json:"value,omitempty"`
+	// reason of a failure or error
+	Message string `json:"message,omitempty"`
+}
+
+// Requests returns the requests of the values of all assertions
+func (s Suite) Requests() ([]rscp.Message, error) {
+	queries := make([]rscp.Query, 0, len(s.Assertions))
+	for _, a := range s.Assertions {
+		queries = append(queries, a.query)
+	}
+	return rscp.QueryRequests(queries...)
+}
+
+// Run requests the values and evaluates the assertions, a failed request fails all assertions with an error
+func (s Suite) Run(sender rscp.Sender) Report {
+	r := Report{Suite: s.Name, Time: time.Now()}
+	requests, err := s.Requests()
+	var responses []rscp.Message
+	if err == nil {
+		responses, err = sender.SendMultiple(requests)
+	}
+	if err != nil {
+		for _, a := range s.Assertions {
+			r.Results = append(r.Results, Result{Name: a.Name, Tag: a.Tag, Status: StatusError, Message: err.Error()})
+		}
+	} else {
+		r.Results = s.Evaluate(responses)
+	}
+	r.Duration = time.Since(r.Time)
+	return r
+}
+
+// Evaluate evaluates the assertions with the responses
+func (s Suite) Evaluate(responses []rscp.Message) []Result {
+	r := make([]Result, 0, len(s.Assertions))
+	for _, a := range s.Assertions {
+		r = append(r, a.evaluate(responses))
+	}
+	return r
+}
+
+// evaluate evaluates the assertion with the responses
+func (a Assertion) evaluate(responses []rscp.Message) Result {
+	r := Result{Name: a.Name, Tag: a.Tag, Status: StatusPassed}
+	fail := func(format string, v ...interface{}) Result {
+		r.Status, r.Message = StatusFailed, fmt.Sprintf(format, v...)
+		return r
+	}
+	var err error
+	switch expected := a.Equals.(type) {
+	case bool:
+		var v bool
+		if v, err = a.query.Bool(responses); err == nil {
+			r.Value = v
+			if v != expected {
+				return fail("%v is not %v", v, expected)
+			}
+		}
+	case string:
+		var v string
+		if v, err = a.query.Text(responses); err == nil {
+			r.Value = v
+			if v != expected {
+				return fail("%q is not %q", v, expected)
+			}
+		}
+	case float64:
+		var v float64
+		if v, err = a.query.Float64(responses); err == nil {
+			r.Value = v
+			// values are transferred as float32 or integers
+			if math.Abs(v-expected) > math.Abs(expected)*1e-6 {
+				return fail("%g is not %g", v, expected)
+			}
+		}
+	default:
+		switch {
+		case a.Within != nil:
+			var v float64
+			if v, err = a.query.Float64(responses); err == nil {
+				r.Value = v
+				if v < a.Within[0] || v > a.Within[1] {
+					return fail("%g is not within %g..%g", v, a.Within[0], a.Within[1])
+				}
+			}
+		case a.Count != nil:
+			var ms []rscp.Message
+			if ms, err = a.query.Messages(responses); err == nil {
+				r.Value = len(ms)
+				if len(ms) != *a.Count {
+					return fail("%d entries instead of %d", len(ms), *a.Count)
+				}
+			}
+		}
+	}
+	if err != nil {
+		r.Status, r.Message = StatusError, err.Error()
+	}
+	return r
+}
@@ -0,0 +1,136 @@
+package assertion
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const suiteYAML = `
+assertions:
+  - tag: BAT_RSOC@0
+    within: [5, 100]
+  - tag: PVI_ON_GRID@0
+    equals: true
+  - name: no unconfirmed errors
+    tag: EMS_STORED_ERRORS
+    count: 0
+  - tag: EMS_MAX_CHARGE_POWER
+    equals: 4500
+  - tag: PM_ACTIVE_PHASES@0
+    equals: 7
+  - tag: INFO_SERIAL_NUMBER
+    equals: S10-1
+  - tag: EMS_POWER_PV
+    within: [0, 100]
+`
+
+func load(t *testing.T, yaml string) Suite {
+	path := filepath.Join(t.TempDir(), "home.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return s
+}
+
+func TestSuite_Run(t *testing.T) {
+	s := load(t, suiteYAML)
+	responses := []rscp.Message{
+		{Tag: rscp.EMS_STORED_ERRORS, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.EMS_ERROR_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{}},
+		}},
+		{Tag: rscp.EMS_MAX_CHARGE_POWER, DataType: rscp.Uint32, Value: uint32(4500)},
+		{Tag: rscp.INFO_SERIAL_NUMBER, DataType: rscp.CString, Value: "S10-2"},
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Error, Value: rscp.ERR_NOT_AVAILABLE},
+		{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_RSOC, DataType: rscp.Float32, Value: float32(42.5)},
+		}},
+		{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.PVI_ON_GRID, DataType: rscp.Bool, Value: true},
+		}},
+	}
+	r := s.Run(rscptest.NewSender(responses))
+	for i := range r.Results {
+		// the messages of errors are tested by the query
+		if r.Results[i].Status == StatusError {
+			r.Results[i].Message = ""
+		}
+	}
+	want := []Result{
+		{Name: "BAT_RSOC@0 within 5..100", Tag: "BAT_RSOC@0", Status: StatusPassed, Value: 42.5},
+		{Name: "PVI_ON_GRID@0 == true", Tag: "PVI_ON_GRID@0", Status: StatusPassed, Value: true},
+		{Name: "no unconfirmed errors", Tag: "EMS_STORED_ERRORS", Status: StatusFailed, Value: 1, Message: "1 entries instead of 0"},
+		{Name: "EMS_MAX_CHARGE_POWER == 4500", Tag: "EMS_MAX_CHARGE_POWER", Status: StatusPassed, Value: float64(4500)},
+		{Name: "PM_ACTIVE_PHASES@0 == 7", Tag: "PM_ACTIVE_PHASES@0", Status: StatusError},
+		{Name: "INFO_SERIAL_NUMBER == S10-1", Tag: "INFO_SERIAL_NUMBER", Status: StatusFailed, Value: "S10-2", Message: `"S10-2" is not "S10-1"`},
+		{Name: "EMS_POWER_PV within 0..100", Tag: "EMS_POWER_PV", Status: StatusError},
+	}
+	if diff := deep.Equal(r.Results, want); diff != nil {
+		t.Errorf("Run() = %v, want %v\n%s", r.Results, want, diff)
+	}
+	if r.Suite != "home" || !errors.Is(r.Err(), ErrFailed) {
+		t.Errorf("Run() suite = %s, error = %v", r.Suite, r.Err())
+	}
+
+	var junit bytes.Buffer
+	if err := r.WriteJUnit(&junit); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+	for _, s := range []string{`<testsuite name="home" tests="7" failures="2" errors="2"`, `<failure message="1 entries instead of 0" type="assertion">`} {
+		if !strings.Contains(junit.String(), s) {
+			t.Errorf("WriteJUnit() = %s, missing %s", junit.String(), s)
+		}
+	}
+	var js bytes.Buffer
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if !strings.Contains(js.String(), `"failures": 2`) {
+		t.Errorf("WriteJSON() = %s", js.String())
+	}
+
+	r = s.Run(rscptest.NewSender())
+	if r.Count(StatusError) != len(s.Assertions) {
+		t.Errorf("Run() of a failed request = %v, want all errors", r.Results)
+	}
+}
+
+func TestLoad_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr error
+	}{
+		{"no assertions", "name: x\n", ErrNoAssertions},
+		{"missing tag", "assertions:\n  - equals: 1\n", ErrMissingTag},
+		{"unknown tag", "assertions:\n  - tag: FOO\n    equals: 1\n", rscp.ErrValidTag},
+		{"missing expectation", "assertions:\n  - tag: EMS_POWER_PV\n", ErrMissingExpectation},
+		{"multiple", "assertions:\n  - tag: EMS_POWER_PV\n    equals: 1\n    count: 1\n", ErrMultipleExpect},
+		{"range", "assertions:\n  - tag: EMS_POWER_PV\n    within: [2, 1]\n", ErrInvalidRange},
+		{"equals list", "assertions:\n  - tag: EMS_POWER_PV\n    equals: [1]\n", ErrInvalidEquals},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "site.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
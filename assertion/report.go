@@ -0,0 +1,115 @@
+package assertion
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Report of a run of a suite
+type Report struct {
+	Suite    string
+	Time     time.Time
+	Duration time.Duration
+	Results  []Result
+}
+
+// Count returns the number of results with the status
+func (r Report) Count(s Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Err returns ErrFailed if not all assertions passed
+func (r Report) Err() error {
+	if failed := len(r.Results) - r.Count(StatusPassed); failed > 0 {
+		return fmt.Errorf("%d of %d: %w", failed, len(r.Results), ErrFailed)
+	}
+	return nil
+}
+
+// WriteJSON writes the report as json
+func (r Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(struct {
+		Suite    string    `json:"suite"`
+		Time     time.Time `json:"time"`
+		Duration float64   `json:"duration"`
+		Tests    int       `json:"tests"`
+		Failures int       `json:"failures"`
+		Errors   int       `json:"errors"`
+		Results  []Result  `json:"results"`
+	}{r.Suite, r.Time, r.Duration.Seconds(), len(r.Results), r.Count(StatusFailed), r.Count(StatusError), r.Results})
+}
+
+// junitMessage is the failure or error of a junit test case
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitCase is a test case of the junit format
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+// junitSuite is a test suite of the junit format
+type junitSuite struct {
+	XMLName   xml.Name    `xml:"testsuite"`
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+// WriteJUnit writes the report as JUnit XML, an assertion is a test case of the suite
+func (r Report) WriteJUnit(w io.Writer) error {
+	s := junitSuite{
+		Name:      r.Suite,
+		Tests:     len(r.Results),
+		Failures:  r.Count(StatusFailed),
+		Errors:    r.Count(StatusError),
+		Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		Timestamp: r.Time.Format("2006-01-02T15:04:05"),
+	}
+	for _, res := range r.Results {
+		c := junitCase{Name: res.Name, ClassName: r.Suite, Time: "0"}
+		m := &junitMessage{Message: res.Message, Text: fmt.Sprintf("%s: %s", res.Tag, res.Message)}
+		switch res.Status {
+		case StatusFailed:
+			m.Type = "assertion"
+			c.Failure = m
+		case StatusError:
+			m.Type = "error"
+			c.Error = m
+		case StatusPassed:
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(s); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
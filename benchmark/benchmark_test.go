@@ -0,0 +1,164 @@
+package benchmark
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/fleet"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestCollect(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	requests, err := Requests(start, time.Hour*24*30)
+	if err != nil {
+		t.Fatalf("Requests() error = %v", err)
+	}
+	if span := rscp.FindTag(requests, rscp.DB_REQ_HISTORY_TIME_SPAN); span == nil || span.Value != time.Unix(2592000, 0).UTC() {
+		t.Errorf("Requests() = %v, want span of 30 days", requests)
+	}
+	sum := func(v ...float32) []rscp.Message {
+		tags := []rscp.Tag{rscp.DB_DC_POWER, rscp.DB_CONSUMPTION, rscp.DB_GRID_POWER_IN, rscp.DB_GRID_POWER_OUT,
+			rscp.DB_BAT_POWER_IN, rscp.DB_BAT_POWER_OUT}
+		r := make([]rscp.Message, 0, len(v))
+		for i := range v {
+			r = append(r, rscp.Message{Tag: tags[i], DataType: rscp.Float32, Value: v[i]})
+		}
+		return r
+	}
+	peak := rscp.Message{Tag: rscp.EMS_INSTALLED_PEAK_POWER, DataType: rscp.Uint32, Value: uint32(10000)}
+	history := func(sums ...rscp.Message) rscp.Message {
+		return rscp.Message{Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.DB_SUM_CONTAINER, DataType: rscp.Container, Value: sums},
+		}}
+	}
+	tests := []struct {
+		name      string
+		responses []rscp.Message
+		want      Sample
+		wantErr   bool
+	}{
+		{"sample",
+			[]rscp.Message{peak, history(sum(1200e3, 900e3, 300e3, 600e3, 250e3, 200e3)...)},
+			Sample{Site: "a", PeakPower: 10000, Energies: Energies{1200e3, 900e3, 300e3, 600e3, 250e3, 200e3}},
+			false,
+		},
+		{"missing energies",
+			[]rscp.Message{peak, history(sum(1200e3)...)},
+			Sample{},
+			true,
+		},
+		{"error response",
+			[]rscp.Message{peak, {Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Error, Value: rscp.ERR_NOT_AVAILABLE}},
+			Sample{},
+			true,
+		},
+		{"sending fails",
+			nil,
+			Sample{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Collect(rscptest.NewSender(tt.responses), "a", start, time.Hour*24*30)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Collect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Collect() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	south := &fleet.Orientation{Azimuth: 180, Tilt: 30}
+	site := func(id, region string, lat float64, o *fleet.Orientation) fleet.Site {
+		return fleet.Site{ID: id, Region: region, Location: &fleet.Location{Latitude: lat, Longitude: 8.5}, Orientation: o}
+	}
+	f := fleet.Config{Sites: []fleet.Site{
+		site("a", "north", 47.0, south),
+		site("b", "north", 47.1, &fleet.Orientation{Azimuth: 200, Tilt: 35}),
+		site("c", "north", 47.2, south),
+		// too far away to be a peer
+		site("d", "south", 46.0, south),
+		// east facing
+		site("e", "north", 47.0, &fleet.Orientation{Azimuth: 90, Tilt: 30}),
+		{ID: "f", Region: "south"},
+		{ID: "g", Region: "south"},
+	}}
+	sample := func(kWp, kWh float64) Sample {
+		return Sample{PeakPower: kWp * 1000, Energies: Energies{
+			Production: kWh * 1000, Consumption: 500e3, GridImport: 100e3, GridExport: kWh * 500, BatteryCharge: 50e3, BatteryDischarge: 40e3,
+		}}
+	}
+	samples := map[string]Sample{
+		"a": sample(10, 1000),
+		"b": sample(8, 900),
+		"c": sample(10, 1100),
+		"d": sample(5, 300),
+		"e": sample(10, 500),
+		"f": sample(0, 100),
+	}
+	got := NewReport(Config{}, f, samples, map[string]error{"g": errors.New("timeout")})
+	metrics := func(kWp, kWh float64) Metrics {
+		return newMetrics(sample(kWp, kWh).Energies, kWp*1000)
+	}
+	want := Report{
+		Sites: []SiteReport{
+			{Site: "a", Region: "north", Metrics: metrics(10, 1000), Peers: []string{"b", "c"}, PeerYield: 111.25, Deviation: (100 - 111.25) / 111.25 * 100},
+			{Site: "b", Region: "north", Metrics: metrics(8, 900), Peers: []string{"a", "c"}, PeerYield: 105, Deviation: (112.5 - 105) / 105 * 100},
+			{Site: "c", Region: "north", Metrics: metrics(10, 1100), Peers: []string{"a", "b"}, PeerYield: 106.25, Deviation: (110 - 106.25) / 106.25 * 100},
+			{Site: "d", Region: "south", Metrics: metrics(5, 300)},
+			{Site: "e", Region: "north", Metrics: metrics(10, 500)},
+			{Site: "f", Region: "south", Metrics: metrics(0, 100)},
+			{Site: "g", Region: "south", Error: "timeout"},
+		},
+		Regions: []RegionReport{
+			{Region: "north", Sites: 4, Metrics: newMetrics(Energies{3500e3, 2000e3, 400e3, 1750e3, 200e3, 160e3}, 38000), MedianYield: 105},
+			{Region: "south", Sites: 2, Metrics: newMetrics(Energies{400e3, 1000e3, 200e3, 200e3, 100e3, 80e3}, 5000), MedianYield: 60},
+		},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("NewReport() = %+v, want %+v\n%s", got, want, diff)
+	}
+	// underperforming compared with peers
+	samples["a"] = sample(10, 800)
+	got = NewReport(Config{Threshold: 20}, f, samples, nil)
+	if !got.Sites[0].Underperforming || got.Sites[1].Underperforming || got.Regions[0].Underperforming != 1 {
+		t.Errorf("NewReport() = %+v, want a underperforming", got.Sites)
+	}
+	// not enough peers
+	got = NewReport(Config{MinPeers: 3}, f, samples, nil)
+	if got.Sites[0].Underperforming || got.Sites[0].PeerYield != 0 {
+		t.Errorf("NewReport() = %+v, want no comparison", got.Sites[0])
+	}
+}
+
+func Test_newMetrics(t *testing.T) {
+	got := newMetrics(Energies{Production: 1000e3, Consumption: 800e3, GridImport: 200e3, GridExport: 400e3, BatteryCharge: 100e3, BatteryDischarge: 90e3}, 8000)
+	want := Metrics{PeakPower: 8, Production: 1000, Yield: 125, SelfConsumption: 60, Autarky: 75, BatteryThroughput: 190}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("newMetrics() = %+v, want %+v\n%s", got, want, diff)
+	}
+}
+
+func Test_distance(t *testing.T) {
+	// zurich to bern
+	if d := distance(fleet.Location{Latitude: 47.3769, Longitude: 8.5417}, fleet.Location{Latitude: 46.9480, Longitude: 7.4474}); math.Abs(d-95.5) > 1 {
+		t.Errorf("distance() = %f, want about 95.5km", d)
+	}
+	north := fleet.Orientation{Azimuth: 350}
+	if !isPeer(Config{MaxDistance: 1, MaxAzimuth: 20}, fleet.Site{Location: &fleet.Location{}, Orientation: &north},
+		fleet.Site{Location: &fleet.Location{}, Orientation: &fleet.Orientation{Azimuth: 5}}) {
+		t.Errorf("isPeer() = false, want azimuth wrapped around north")
+	}
+}
@@ -0,0 +1,100 @@
+// Package benchmark compares the performance of the sites of a fleet.
+//
+// the energies of a period are read from the history database of every site (DB_REQ_HISTORY_DATA_DAY) and
+// normalized by the installed peak power (EMS_INSTALLED_PEAK_POWER). Sites yielding clearly less than their
+// peers (sites nearby with a similar orientation of the pv modules) are flagged as underperforming.
+package benchmark
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Energies of a site over a period in Wh as summed up by the history database
+type Energies struct {
+	// DB_DC_POWER
+	Production float64 `json:"production"`
+	// DB_CONSUMPTION
+	Consumption float64 `json:"consumption"`
+	// DB_GRID_POWER_IN, energy taken from the grid
+	GridImport float64 `json:"gridImport"`
+	// DB_GRID_POWER_OUT, energy fed into the grid
+	GridExport float64 `json:"gridExport"`
+	// DB_BAT_POWER_IN
+	BatteryCharge float64 `json:"batteryCharge"`
+	// DB_BAT_POWER_OUT
+	BatteryDischarge float64 `json:"batteryDischarge"`
+}
+
+// add adds the energies of o
+func (e *Energies) add(o Energies) {
+	e.Production += o.Production
+	e.Consumption += o.Consumption
+	e.GridImport += o.GridImport
+	e.GridExport += o.GridExport
+	e.BatteryCharge += o.BatteryCharge
+	e.BatteryDischarge += o.BatteryDischarge
+}
+
+// Sample of a site over a period
+type Sample struct {
+	Site string `json:"site"`
+	// installed peak power in W
+	PeakPower float64  `json:"peakPower"`
+	Energies  Energies `json:"energies"`
+}
+
+// Requests returns the requests required to create a sample of the period
+func Requests(start time.Time, span time.Duration) ([]rscp.Message, error) {
+	history, err := rscp.NewHistoryRequest(start, span, span)
+	if err != nil {
+		return nil, err
+	}
+	return []rscp.Message{*rscp.NewMessage(rscp.EMS_REQ_INSTALLED_PEAK_POWER, nil), *history}, nil
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(site string, responses []rscp.Message) (Sample, error) {
+	s := Sample{Site: site}
+	m := rscp.FindTag(responses, rscp.EMS_INSTALLED_PEAK_POWER)
+	if m == nil {
+		return s, fmt.Errorf("missing %s in response", rscp.EMS_INSTALLED_PEAK_POWER)
+	}
+	var err error
+	if s.PeakPower, err = m.Float64(); err != nil {
+		return s, err
+	}
+	sums, err := rscp.HistorySums(responses)
+	if err != nil {
+		return s, err
+	}
+	for tag, v := range map[rscp.Tag]*float64{
+		rscp.DB_DC_POWER:       &s.Energies.Production,
+		rscp.DB_CONSUMPTION:    &s.Energies.Consumption,
+		rscp.DB_GRID_POWER_IN:  &s.Energies.GridImport,
+		rscp.DB_GRID_POWER_OUT: &s.Energies.GridExport,
+		rscp.DB_BAT_POWER_IN:   &s.Energies.BatteryCharge,
+		rscp.DB_BAT_POWER_OUT:  &s.Energies.BatteryDischarge,
+	} {
+		var exists bool
+		if *v, exists = sums[tag]; !exists {
+			return s, fmt.Errorf("missing %s in response", tag)
+		}
+	}
+	return s, nil
+}
+
+// Collect requests the sample of the site over the period
+func Collect(sender rscp.Sender, site string, start time.Time, span time.Duration) (Sample, error) {
+	requests, err := Requests(start, span)
+	if err != nil {
+		return Sample{Site: site}, err
+	}
+	responses, err := sender.SendMultiple(requests)
+	if err != nil {
+		return Sample{Site: site}, err
+	}
+	return NewSample(site, responses)
+}
@@ -0,0 +1,233 @@
+package benchmark
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spali/go-rscp/fleet"
+)
+
+const (
+	percent = 100
+	kilo    = 1000
+	// mean earth radius in km
+	earthRadius = 6371
+	fullCircle  = 360
+)
+
+// Config of the peer comparison
+type Config struct {
+	// maximum distance in km between two peers
+	MaxDistance float64
+	// maximum difference of the azimuth in degrees between two peers
+	MaxAzimuth float64
+	// maximum difference of the tilt in degrees between two peers
+	MaxTilt float64
+	// minimum number of peers required to compare a site
+	MinPeers int
+	// yield in % below the median of the peers a site is flagged as underperforming
+	Threshold float64
+}
+
+// DefaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var DefaultConfig = Config{
+	MaxDistance: 50,
+	MaxAzimuth:  30,
+	MaxTilt:     15,
+	MinPeers:    2,
+	Threshold:   15,
+}
+
+// check does set default values on missing
+func (c *Config) check() {
+	if c.MaxDistance <= 0 {
+		c.MaxDistance = DefaultConfig.MaxDistance
+	}
+	if c.MaxAzimuth <= 0 {
+		c.MaxAzimuth = DefaultConfig.MaxAzimuth
+	}
+	if c.MaxTilt <= 0 {
+		c.MaxTilt = DefaultConfig.MaxTilt
+	}
+	if c.MinPeers <= 0 {
+		c.MinPeers = DefaultConfig.MinPeers
+	}
+	if c.Threshold <= 0 {
+		c.Threshold = DefaultConfig.Threshold
+	}
+}
+
+// Metrics derived from the energies, energies are in kWh and ratios in percent
+type Metrics struct {
+	// installed peak power in kWp
+	PeakPower  float64 `json:"peakPower"`
+	Production float64 `json:"production"`
+	// normalized yield in kWh per kWp
+	Yield float64 `json:"yield"`
+	// part of the production consumed on site (not fed into the grid)
+	SelfConsumption float64 `json:"selfConsumption"`
+	// part of the consumption not taken from the grid
+	Autarky float64 `json:"autarky"`
+	// energy charged and discharged
+	BatteryThroughput float64 `json:"batteryThroughput"`
+}
+
+// newMetrics derives the metrics from the energies in Wh and the peak power in W
+func newMetrics(e Energies, peakPower float64) Metrics {
+	m := Metrics{
+		PeakPower:         peakPower / kilo,
+		Production:        e.Production / kilo,
+		BatteryThroughput: (e.BatteryCharge + e.BatteryDischarge) / kilo,
+	}
+	if peakPower > 0 {
+		m.Yield = e.Production / peakPower
+	}
+	if e.Production > 0 {
+		m.SelfConsumption = math.Max(0, e.Production-e.GridExport) / e.Production * percent
+	}
+	if e.Consumption > 0 {
+		m.Autarky = math.Max(0, e.Consumption-e.GridImport) / e.Consumption * percent
+	}
+	return m
+}
+
+// SiteReport is the benchmark of a single site
+type SiteReport struct {
+	Site   string `json:"site"`
+	Region string `json:"region,omitempty"`
+	Metrics
+	// sites compared with
+	Peers []string `json:"peers,omitempty"`
+	// median yield of the peers in kWh per kWp
+	PeerYield float64 `json:"peerYield,omitempty"`
+	// deviation of the yield from the peer yield in percent
+	Deviation float64 `json:"deviation,omitempty"`
+	// yield is more than the threshold below the peer yield
+	Underperforming bool `json:"underperforming"`
+	// error if the sample could not be collected
+	Error string `json:"error,omitempty"`
+}
+
+// RegionReport sums up the sites of a region
+type RegionReport struct {
+	Region string `json:"region"`
+	Sites  int    `json:"sites"`
+	Metrics
+	// median yield of the sites in kWh per kWp
+	MedianYield     float64 `json:"medianYield"`
+	Underperforming int     `json:"underperforming"`
+}
+
+// Report of the fleet
+type Report struct {
+	Sites   []SiteReport   `json:"sites"`
+	Regions []RegionReport `json:"regions"`
+}
+
+// NewReport compares the samples of the sites.
+//
+// failed sites are reported with their error, sites without sample nor error are skipped.
+// Only sites with location and orientation are compared with peers.
+func NewReport(c Config, f fleet.Config, samples map[string]Sample, failed map[string]error) Report {
+	c.check()
+	r := Report{Sites: []SiteReport{}, Regions: []RegionReport{}}
+	type region struct {
+		sites           int
+		energies        Energies
+		peakPower       float64
+		yields          []float64
+		underperforming int
+	}
+	regions := map[string]*region{}
+	for _, site := range f.Sites {
+		sr := SiteReport{Site: site.ID, Region: site.Region}
+		if err, exists := failed[site.ID]; exists {
+			sr.Error = err.Error()
+			r.Sites = append(r.Sites, sr)
+			continue
+		}
+		s, sampled := samples[site.ID]
+		if !sampled {
+			continue
+		}
+		sr.Metrics = newMetrics(s.Energies, s.PeakPower)
+		var yields []float64
+		for _, peer := range f.Sites {
+			p, sampled := samples[peer.ID]
+			if peer.ID == site.ID || !sampled || p.PeakPower <= 0 || !isPeer(c, site, peer) {
+				continue
+			}
+			sr.Peers = append(sr.Peers, peer.ID)
+			yields = append(yields, newMetrics(p.Energies, p.PeakPower).Yield)
+		}
+		if len(sr.Peers) >= c.MinPeers && s.PeakPower > 0 {
+			sr.PeerYield = median(yields)
+			if sr.PeerYield > 0 {
+				sr.Deviation = (sr.Yield - sr.PeerYield) / sr.PeerYield * percent
+				sr.Underperforming = sr.Deviation < -c.Threshold
+			}
+		}
+		r.Sites = append(r.Sites, sr)
+		g, exists := regions[site.Region]
+		if !exists {
+			g = &region{}
+			regions[site.Region] = g
+		}
+		g.sites++
+		g.energies.add(s.Energies)
+		g.peakPower += s.PeakPower
+		if s.PeakPower > 0 {
+			g.yields = append(g.yields, sr.Yield)
+		}
+		if sr.Underperforming {
+			g.underperforming++
+		}
+	}
+	for name, g := range regions {
+		r.Regions = append(r.Regions, RegionReport{
+			Region:          name,
+			Sites:           g.sites,
+			Metrics:         newMetrics(g.energies, g.peakPower),
+			MedianYield:     median(g.yields),
+			Underperforming: g.underperforming,
+		})
+	}
+	sort.Slice(r.Regions, func(i, j int) bool { return r.Regions[i].Region < r.Regions[j].Region })
+	return r
+}
+
+// isPeer returns true if both sites are close and their pv modules have a similar orientation
+func isPeer(c Config, a, b fleet.Site) bool {
+	if a.Location == nil || b.Location == nil || a.Orientation == nil || b.Orientation == nil {
+		return false
+	}
+	azimuth := math.Mod(math.Abs(a.Orientation.Azimuth-b.Orientation.Azimuth), fullCircle)
+	azimuth = math.Min(azimuth, fullCircle-azimuth)
+	return distance(*a.Location, *b.Location) <= c.MaxDistance &&
+		azimuth <= c.MaxAzimuth &&
+		math.Abs(a.Orientation.Tilt-b.Orientation.Tilt) <= c.MaxTilt
+}
+
+// distance returns the great-circle distance in km (haversine formula)
+func distance(a, b fleet.Location) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / (fullCircle / 2) }
+	dLat := rad(b.Latitude - a.Latitude)
+	dLon := rad(b.Longitude - a.Longitude)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(a.Latitude))*math.Cos(rad(b.Latitude))*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// median returns the median of the values or 0 if empty
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	v := make([]float64, len(values))
+	copy(v, values)
+	sort.Float64s(v)
+	if n := len(v); n%2 == 0 {
+		return (v[n/2-1] + v[n/2]) / 2
+	}
+	return v[len(v)/2]
+}
@@ -0,0 +1,208 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var testConfig = Config{
+	Tenants:  []Tenant{{"ground floor", 1}, {"first floor", 2}},
+	Interval: time.Minute * 15,
+	Location: time.UTC,
+}
+
+func Test_Config_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Config
+		wantErr error
+	}{
+		{"full config",
+			`{"interval":"5m","location":"UTC","tenants":[{"name":"a","meter":1}]}`,
+			Config{Tenants: []Tenant{{"a", 1}}, Interval: time.Minute * 5, Location: time.UTC},
+			nil,
+		},
+		{"defaults",
+			`{"tenants":[{"name":"a","meter":1}]}`,
+			Config{Tenants: []Tenant{{"a", 1}}, Interval: defaultConfig.Interval, Location: defaultConfig.Location},
+			nil,
+		},
+		{"no tenants",
+			`{}`,
+			Config{},
+			ErrNoTenants,
+		},
+		{"duplicate meter",
+			`{"tenants":[{"name":"a","meter":1},{"name":"b","meter":1}]}`,
+			Config{},
+			ErrDuplicateMeter,
+		},
+		{"missing meter",
+			`{"tenants":[{"name":"a"}]}`,
+			Config{},
+			ErrMissingMeter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			err := json.Unmarshal([]byte(tt.json), &c)
+			if err == nil {
+				err = c.check()
+			}
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(c, tt.want); diff != nil {
+				t.Errorf("UnmarshalJSON() = %v, want %v\n%s", c, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	components := rscp.Components{"PM": {{Namespace: "PM", Index: 0}, {Namespace: "PM", Index: 1}}}
+	if err := testConfig.Resolve(components); !errors.Is(err, rscp.ErrNotDiscovered) {
+		t.Errorf("Resolve() without meter 2 error = %v, want %v", err, rscp.ErrNotDiscovered)
+	}
+	components["PM"] = append(components["PM"], rscp.Component{Namespace: "PM", Index: 2})
+	if err := testConfig.Resolve(components); err != nil {
+		t.Errorf("Resolve() error = %v", err)
+	}
+}
+
+func testResponses(pv, bat, grid, home int32, meter1, meter2 float64) []rscp.Message {
+	pm := func(index uint16, energy float64) rscp.Message {
+		return rscp.Message{Tag: rscp.PM_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PM_INDEX, DataType: rscp.UInt16, Value: index},
+			{Tag: rscp.PM_ENERGY_L1, DataType: rscp.Double64, Value: energy},
+			{Tag: rscp.PM_ENERGY_L2, DataType: rscp.Double64, Value: float64(0)},
+			{Tag: rscp.PM_ENERGY_L3, DataType: rscp.Double64, Value: float64(0)},
+		}}
+	}
+	return []rscp.Message{
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: pv},
+		{Tag: rscp.EMS_POWER_BAT, DataType: rscp.Int32, Value: bat},
+		{Tag: rscp.EMS_POWER_GRID, DataType: rscp.Int32, Value: grid},
+		{Tag: rscp.EMS_POWER_HOME, DataType: rscp.Int32, Value: home},
+		pm(1, meter1),
+		pm(2, meter2),
+	}
+}
+
+func TestNewSample(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		responses []rscp.Message
+		want      Sample
+		wantErr   bool
+	}{
+		{"sample",
+			testResponses(1000, -500, 500, 2000, 100, 200),
+			Sample{Time: now, Meters: map[uint16]float64{1: 100, 2: 200}, PV: 1000, Battery: -500, Grid: 500, Home: 2000},
+			false,
+		},
+		{"missing meter",
+			testResponses(0, 0, 0, 0, 0, 0)[:5],
+			Sample{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSample(now, testConfig, tt.responses)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSample() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("NewSample() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func Test_mix(t *testing.T) {
+	tests := []struct {
+		name                 string
+		sample               Sample
+		solar, battery, grid float64
+	}{
+		{"pv only", Sample{PV: 3000, Battery: 1000, Grid: -1000, Home: 1000}, 1, 0, 0},
+		{"grid only", Sample{Grid: 1000, Home: 1000}, 0, 0, 1},
+		{"mixed", Sample{PV: 1000, Battery: -500, Grid: 500, Home: 2000}, 0.5, 0.25, 0.25},
+		{"no consumption", Sample{}, 0, 0, 1},
+		// the home consumption includes loads not covered by the power flows
+		{"no pv at night", Sample{Battery: -1000, Home: 2000}, 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solar, battery, grid := mix(tt.sample, tt.sample)
+			if solar != tt.solar || battery != tt.battery || grid != tt.grid {
+				t.Errorf("mix() = %v, %v, %v, want %v, %v, %v", solar, battery, grid, tt.solar, tt.battery, tt.grid)
+			}
+		})
+	}
+}
+
+func TestLedger(t *testing.T) {
+	start := time.Date(2021, 1, 31, 23, 45, 0, 0, time.UTC)
+	sender := rscptest.NewSender(
+		testResponses(1000, -500, 500, 2000, 1000, 5000),
+		testResponses(1000, -500, 500, 2000, 2000, 5500),
+		// meter 2 replaced
+		testResponses(1000, -500, 500, 2000, 3000, 10),
+	)
+	l := &Ledger{}
+	for i := 0; i < 3; i++ {
+		if err := Collect(sender, testConfig, l, start.Add(time.Duration(i)*testConfig.Interval)); err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+	}
+	want := map[string]map[string]*Energy{
+		"2021-01": {
+			"ground floor": {Solar: 500, Battery: 250, Grid: 250},
+			"first floor":  {Solar: 250, Battery: 125, Grid: 125},
+		},
+		"2021-02": {
+			"ground floor": {Solar: 500, Battery: 250, Grid: 250},
+		},
+	}
+	if diff := deep.Equal(l.Months, want); diff != nil {
+		t.Errorf("Ledger.Months = %v, want %v\n%s", l.Months, want, diff)
+	}
+	if diff := deep.Equal(l.MonthsAvailable(), []string{"2021-01", "2021-02"}); diff != nil {
+		t.Errorf("Ledger.MonthsAvailable() %s", diff)
+	}
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b, l.Statements(testConfig, "2021-02")); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	wantCSV := "month,tenant,meter,total_kwh,solar_kwh,battery_kwh,grid_kwh,solar_share_percent,battery_share_percent,grid_share_percent\n" +
+		"2021-02,ground floor,1,1.000,0.500,0.250,0.250,50.000,25.000,25.000\n" +
+		"2021-02,first floor,2,0.000,0.000,0.000,0.000,0.000,0.000,0.000\n"
+	if b.String() != wantCSV {
+		t.Errorf("WriteCSV() = %s, want %s", b.String(), wantCSV)
+	}
+	st := l.Statements(testConfig, "2021-01")[0]
+	if math.Abs(st.SolarShare+st.BatteryShare+st.GridShare-100) > 1e-9 {
+		t.Errorf("Statement shares do not sum up to 100%%: %+v", st)
+	}
+}
@@ -0,0 +1,28 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Collect requests a new sample and adds it to the ledger
+func Collect(sender rscp.Sender, c Config, l *Ledger, now time.Time) error {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		s         Sample
+		err       error
+	)
+	if requests, err = Requests(c); err != nil {
+		return err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return err
+	}
+	if s, err = NewSample(now, c, responses); err != nil {
+		return err
+	}
+	l.Add(c, s)
+	return nil
+}
@@ -0,0 +1,130 @@
+// Package billing apportions the energy of a tenant power supply ("Mieterstrom") site to its tenants.
+//
+// every tenant is metered by an additional power meter (PM_INDEX). The energy a tenant consumed in an interval
+// is split into solar, battery and grid energy by the mix of the EMS power flows in the same interval.
+package billing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoTenants      = errors.New("no tenants configured")
+	ErrDuplicateMeter = errors.New("power meter assigned to multiple tenants")
+	ErrMissingMeter   = errors.New("tenant without power meter")
+)
+
+// Tenant maps a tenant to it's power meter
+type Tenant struct {
+	// name of the tenant used in the statements
+	Name string `json:"name"`
+	// PM_INDEX of the power meter measuring the tenant's consumption
+	Meter uint16 `json:"meter"`
+}
+
+// UnmarshalJSON unmarshals the tenant, the meter is required as there is no default power meter of a tenant
+func (t *Tenant) UnmarshalJSON(b []byte) error {
+	type tenant Tenant
+	tmp := struct {
+		*tenant
+		Meter *uint16 `json:"meter"`
+	}{tenant: (*tenant)(t)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.Meter == nil {
+		return fmt.Errorf("%s: %w", t.Name, ErrMissingMeter)
+	}
+	t.Meter = *tmp.Meter
+	return nil
+}
+
+// Config of the tenant billing
+type Config struct {
+	// tenants to bill
+	Tenants []Tenant `json:"tenants"`
+	// interval between two samples
+	Interval time.Duration `json:"-"`
+	// location used to assign intervals to months
+	Location *time.Location `json:"-"`
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	Interval: time.Minute * 15,
+	Location: time.Local,
+}
+
+// UnmarshalJSON unmarshals the config, the interval is expected as duration string (i.e. "15m")
+// and the location as IANA time zone name (i.e. "Europe/Berlin").
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	tmp := struct {
+		*config
+		Interval string `json:"interval"`
+		Location string `json:"location"`
+	}{config: (*config)(c)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.Interval != "" {
+		var err error
+		if c.Interval, err = time.ParseDuration(tmp.Interval); err != nil {
+			return fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+	if tmp.Location != "" {
+		var err error
+		if c.Location, err = time.LoadLocation(tmp.Location); err != nil {
+			return fmt.Errorf("invalid location: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if required
+func (c *Config) check() error {
+	if len(c.Tenants) == 0 {
+		return ErrNoTenants
+	}
+	meters := make(map[uint16]string, len(c.Tenants))
+	for _, t := range c.Tenants {
+		if other, exists := meters[t.Meter]; exists {
+			return fmt.Errorf("meter %d of %s and %s: %w", t.Meter, other, t.Name, ErrDuplicateMeter)
+		}
+		meters[t.Meter] = t.Name
+	}
+	if c.Interval <= 0 {
+		c.Interval = defaultConfig.Interval
+	}
+	if c.Location == nil {
+		c.Location = defaultConfig.Location
+	}
+	return nil
+}
+
+// Resolve fails if the power meter of a tenant isn't discovered
+func (c Config) Resolve(components rscp.Components) error {
+	for _, t := range c.Tenants {
+		if !components.Has("PM", t.Meter) {
+			return fmt.Errorf("meter %d of %s: %w", t.Meter, t.Name, rscp.ErrNotDiscovered)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package billing
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+// monthFormat is the layout used to key the months of the ledger
+const monthFormat = "2006-01"
+
+// Energy apportioned by source in Wh
+type Energy struct {
+	Solar   float64 `json:"solar"`
+	Battery float64 `json:"battery"`
+	Grid    float64 `json:"grid"`
+}
+
+// Total energy of all sources in Wh
+func (e Energy) Total() float64 {
+	return e.Solar + e.Battery + e.Grid
+}
+
+// Ledger accumulates the apportioned energy per month and tenant
+type Ledger struct {
+	// last sample added, used as start of the next interval
+	Last *Sample `json:"last,omitempty"`
+	// apportioned energy by month and tenant
+	Months map[string]map[string]*Energy `json:"months"`
+}
+
+// LoadLedger reads the ledger from a json file, a missing file results in an empty ledger
+func LoadLedger(path string) (*Ledger, error) {
+	l := &Ledger{}
+	if err := jsonfile.Read(path, l); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Save writes the ledger to a json file
+func (l *Ledger) Save(path string) error {
+	return jsonfile.Write(path, l)
+}
+
+// Add apportions the tenant's consumption since the last sample and keeps the sample as start of the next interval.
+//
+// the interval is accounted to the month it starts in.
+// A meter that went backwards (i.e. replaced or reset) is skipped for this interval.
+func (l *Ledger) Add(c Config, s Sample) {
+	last := l.Last
+	l.Last = &s
+	if last == nil {
+		return
+	}
+	if !s.Time.After(last.Time) {
+		log.Warnf("ignoring sample at %s not after last sample at %s", s.Time, last.Time)
+		l.Last = last
+		return
+	}
+	if gap := s.Time.Sub(last.Time); gap > 2*c.Interval {
+		log.Warnf("gap of %s between samples, power mix is averaged over the gap", gap)
+	}
+	solar, battery, grid := mix(*last, s)
+	month := last.Time.In(c.Location).Format(monthFormat)
+	if l.Months == nil {
+		l.Months = map[string]map[string]*Energy{}
+	}
+	if l.Months[month] == nil {
+		l.Months[month] = map[string]*Energy{}
+	}
+	for _, t := range c.Tenants {
+		start, hasStart := last.Meters[t.Meter]
+		end, hasEnd := s.Meters[t.Meter]
+		if !hasStart || !hasEnd {
+			continue
+		}
+		delta := end - start
+		if delta < 0 {
+			log.Warnf("meter %d of %s went backwards from %f to %f, skipping interval", t.Meter, t.Name, start, end)
+			continue
+		}
+		e := l.Months[month][t.Name]
+		if e == nil {
+			e = &Energy{}
+			l.Months[month][t.Name] = e
+		}
+		e.Solar += delta * solar
+		e.Battery += delta * battery
+		e.Grid += delta * grid
+	}
+}
+
+// MonthsAvailable returns the months in the ledger sorted ascending
+func (l *Ledger) MonthsAvailable() []string {
+	months := make([]string, 0, len(l.Months))
+	for m := range l.Months {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	return months
+}
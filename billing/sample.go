@@ -0,0 +1,98 @@
+package billing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Sample is a single reading of the tenant meters and the EMS power flows
+type Sample struct {
+	Time time.Time `json:"time"`
+	// cumulated energy in Wh over all phases by PM_INDEX
+	Meters map[uint16]float64 `json:"meters"`
+	// PV power in W
+	PV float64 `json:"pv"`
+	// battery power in W (-=discharge / +=charge)
+	Battery float64 `json:"battery"`
+	// grid power in W (-=feed in / +=import)
+	Grid float64 `json:"grid"`
+	// home consumption in W
+	Home float64 `json:"home"`
+}
+
+// Requests returns the requests required to create a sample
+func Requests(c Config) ([]rscp.Message, error) {
+	r := [][]interface{}{
+		{rscp.EMS_REQ_POWER_PV},
+		{rscp.EMS_REQ_POWER_BAT},
+		{rscp.EMS_REQ_POWER_GRID},
+		{rscp.EMS_REQ_POWER_HOME},
+	}
+	for _, t := range c.Tenants {
+		r = append(r, []interface{}{rscp.PM_REQ_DATA, rscp.PM_INDEX, t.Meter,
+			rscp.PM_REQ_ENERGY_L1, rscp.PM_REQ_ENERGY_L2, rscp.PM_REQ_ENERGY_L3})
+	}
+	return rscp.CreateRequests(r...)
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(t time.Time, c Config, responses []rscp.Message) (Sample, error) {
+	s := Sample{Time: t, Meters: make(map[uint16]float64, len(c.Tenants))}
+	for tag, v := range map[rscp.Tag]*float64{
+		rscp.EMS_POWER_PV:   &s.PV,
+		rscp.EMS_POWER_BAT:  &s.Battery,
+		rscp.EMS_POWER_GRID: &s.Grid,
+		rscp.EMS_POWER_HOME: &s.Home,
+	} {
+		m := rscp.FindTag(responses, tag)
+		if m == nil {
+			return s, fmt.Errorf("missing %s in response", tag)
+		}
+		var err error
+		if *v, err = m.Float64(); err != nil {
+			return s, err
+		}
+	}
+	for _, tenant := range c.Tenants {
+		pm := rscp.FindIndexed(responses, rscp.PM_DATA, rscp.PM_INDEX, tenant.Meter)
+		if pm == nil {
+			return s, fmt.Errorf("missing %s with %s %d in response", rscp.PM_DATA, rscp.PM_INDEX, tenant.Meter)
+		}
+		for _, tag := range []rscp.Tag{rscp.PM_ENERGY_L1, rscp.PM_ENERGY_L2, rscp.PM_ENERGY_L3} {
+			m := rscp.FindTag(pm.Value.([]rscp.Message), tag)
+			if m == nil {
+				return s, fmt.Errorf("missing %s of meter %d in response", tag, tenant.Meter)
+			}
+			v, err := m.Float64()
+			if err != nil {
+				return s, fmt.Errorf("meter %d: %w", tenant.Meter, err)
+			}
+			s.Meters[tenant.Meter] += v
+		}
+	}
+	return s, nil
+}
+
+// mix returns the fractions of solar, battery and grid energy supplying the home between the two samples.
+//
+// the solar supply is the PV power not charging the battery nor fed in. If the mix can't be determined
+// (i.e. no consumption) everything is accounted as grid energy.
+func mix(a, b Sample) (solar, battery, grid float64) {
+	gridImport := positive((a.Grid + b.Grid) / 2)
+	batDischarge := positive(-(a.Battery + b.Battery) / 2)
+	pv := positive((a.PV+b.PV)/2 - positive((a.Battery+b.Battery)/2) - positive(-(a.Grid+b.Grid)/2))
+	sum := pv + batDischarge + gridImport
+	if sum <= 0 {
+		return 0, 0, 1
+	}
+	return pv / sum, batDischarge / sum, gridImport / sum
+}
+
+func positive(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+package billing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Statement of a tenant for a month, energy values in kWh and shares in percent
+type Statement struct {
+	Month   string  `json:"month"`
+	Tenant  string  `json:"tenant"`
+	Meter   uint16  `json:"meter"`
+	Total   float64 `json:"total"`
+	Solar   float64 `json:"solar"`
+	Battery float64 `json:"battery"`
+	Grid    float64 `json:"grid"`
+	// share of the solar energy consumed directly, without the battery
+	SolarShare float64 `json:"solarShare"`
+	// share of the battery
+	BatteryShare float64 `json:"batteryShare"`
+	// share of the grid
+	GridShare float64 `json:"gridShare"`
+}
+
+const (
+	whPerKWh = 1000
+	percent  = 100
+)
+
+// Statements returns the statements of all configured tenants for the month (format "2006-01")
+func (l *Ledger) Statements(c Config, month string) []Statement {
+	s := make([]Statement, 0, len(c.Tenants))
+	for _, t := range c.Tenants {
+		e := Energy{}
+		if v := l.Months[month][t.Name]; v != nil {
+			e = *v
+		}
+		st := Statement{
+			Month:   month,
+			Tenant:  t.Name,
+			Meter:   t.Meter,
+			Total:   e.Total() / whPerKWh,
+			Solar:   e.Solar / whPerKWh,
+			Battery: e.Battery / whPerKWh,
+			Grid:    e.Grid / whPerKWh,
+		}
+		if total := e.Total(); total > 0 {
+			st.SolarShare = e.Solar / total * percent
+			st.BatteryShare = e.Battery / total * percent
+			st.GridShare = e.Grid / total * percent
+		}
+		s = append(s, st)
+	}
+	return s
+}
+
+// WriteCSV writes the statements as csv including a header
+func WriteCSV(w io.Writer, statements []Statement) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"month", "tenant", "meter",
+		"total_kwh", "solar_kwh", "battery_kwh", "grid_kwh", "solar_share_percent", "battery_share_percent", "grid_share_percent"}); err != nil {
+		return err
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) } //nolint: gomnd
+	for _, s := range statements {
+		if err := cw.Write([]string{s.Month, s.Tenant, fmt.Sprint(s.Meter),
+			f(s.Total), f(s.Solar), f(s.Battery), f(s.Grid), f(s.SolarShare), f(s.BatteryShare), f(s.GridShare)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the statements as json array
+func WriteJSON(w io.Writer, statements []Statement) error {
+	return json.NewEncoder(w).Encode(statements)
+}
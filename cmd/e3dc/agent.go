@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/tunnel"
+)
+
+var agentConf = tunnel.AgentConfig{}
+
+var agentCommand = command{
+	description: "on-site agent tunneling the rscp sessions of the hub to the system behind NAT",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&agentConf.Hub, "hub", "", "host and port of the hub (i.e. hub.example.com:7443)")
+		fs.StringVar(&agentConf.CA, "ca", "", "path of the certificates to verify the hub with (optional, system certificates if empty)")
+		fs.StringVar(&agentConf.Site, "site", "", "id of the site configured on the hub")
+		fs.StringVar(&agentConf.Token, "token", "", "token of the site configured on the hub")
+		fs.StringVar(&agentConf.Device, "device", "", "host and port of the e3dc system (i.e. 192.168.1.10:5033)")
+		fs.DurationVar(&agentConf.Retry, "retry", time.Second*5, "delay between two attempts to connect to the hub")
+	},
+	run: func(fs *flag.FlagSet) error {
+		agent, err := tunnel.NewAgent(agentConf)
+		if err != nil {
+			return err
+		}
+		// info level to always log the connection state
+		if log.GetLevel() < log.InfoLevel {
+			log.SetLevel(log.InfoLevel)
+			log.SetOutput(os.Stderr)
+		}
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		stop := make(chan struct{})
+		go func() {
+			<-interrupt
+			close(stop)
+		}()
+		return agent.Run(stop)
+	},
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/benchmark"
+	"github.com/spali/go-rscp/fleet"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const dateFormat = "2006-01-02"
+
+var benchmarkConf = struct {
+	fleet  string
+	from   string
+	to     string
+	config benchmark.Config
+}{}
+
+var benchmarkCommand = command{
+	description: "compare yield, self-consumption, autarky and battery throughput of all sites of a fleet",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&benchmarkConf.fleet, "fleet", "fleet.json", "path to the fleet config file")
+		fs.StringVar(&benchmarkConf.from, "from", "", "first day of the period (YYYY-MM-DD), 30 days before -to if empty")
+		fs.StringVar(&benchmarkConf.to, "to", "", "day after the period (YYYY-MM-DD), today if empty")
+		fs.Float64Var(&benchmarkConf.config.MaxDistance, "max-distance", benchmark.DefaultConfig.MaxDistance,
+			"maximum distance in km between two peers")
+		fs.Float64Var(&benchmarkConf.config.MaxAzimuth, "max-azimuth", benchmark.DefaultConfig.MaxAzimuth,
+			"maximum difference of the azimuth in degrees between two peers")
+		fs.Float64Var(&benchmarkConf.config.MaxTilt, "max-tilt", benchmark.DefaultConfig.MaxTilt,
+			"maximum difference of the tilt in degrees between two peers")
+		fs.IntVar(&benchmarkConf.config.MinPeers, "min-peers", benchmark.DefaultConfig.MinPeers,
+			"minimum number of peers required to compare a site")
+		fs.Float64Var(&benchmarkConf.config.Threshold, "threshold", benchmark.DefaultConfig.Threshold,
+			"yield in % below the median of the peers a site is flagged as underperforming")
+	},
+	run: runBenchmark,
+}
+
+// benchmarkPeriod returns the period of the flags
+func benchmarkPeriod() (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var err error
+	if benchmarkConf.to != "" {
+		if to, err = time.ParseInLocation(dateFormat, benchmarkConf.to, time.Local); err != nil {
+			return to, to, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+	from := to.AddDate(0, 0, -30) //nolint: gomnd
+	if benchmarkConf.from != "" {
+		if from, err = time.ParseInLocation(dateFormat, benchmarkConf.from, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("period from %s to %s is empty", from.Format(dateFormat), to.Format(dateFormat))
+	}
+	return from, to, nil
+}
+
+// collectBenchmark collects the sample of a single site
+func collectBenchmark(site fleet.Site, from, to time.Time) (benchmark.Sample, error) {
+	c, err := rscp.NewClient(site.ClientConfig())
+	if err != nil {
+		return benchmark.Sample{Site: site.ID}, err
+	}
+	defer func() { _ = c.Disconnect() }()
+	return benchmark.Collect(c, site.ID, from, to.Sub(from))
+}
+
+func runBenchmark(fs *flag.FlagSet) error {
+	from, to, err := benchmarkPeriod()
+	if err != nil {
+		return err
+	}
+	f, err := fleet.Load(benchmarkConf.fleet)
+	if err != nil {
+		return err
+	}
+	samples := map[string]benchmark.Sample{}
+	failed := map[string]error{}
+	for _, site := range f.Sites {
+		s, err := collectBenchmark(site, from, to)
+		if err != nil {
+			log.Errorf("benchmark of site %s failed: %s", site.ID, err)
+			failed[site.ID] = err
+			continue
+		}
+		samples[site.ID] = s
+	}
+	rb, err := json.Marshal(benchmark.NewReport(benchmarkConf.config, f, samples, failed))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", rb)
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/billing"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const monthFormat = "2006-01"
+
+var billingConf = struct {
+	connection connectionConf
+	config     string
+	ledger     string
+	report     string
+	month      string
+	tenant     string
+}{}
+
+var billingCommand = command{
+	description: "apportion the energy of a tenant power supply site to its tenants and print their monthly statements",
+	flags: func(fs *flag.FlagSet) {
+		billingConf.connection.flags(fs)
+		fs.StringVar(&billingConf.config, "config", "billing.json", "path to the billing config file")
+		fs.StringVar(&billingConf.ledger, "ledger", "billing-ledger.json", "path to the ledger file")
+		fs.StringVar(&billingConf.report, "report", "", "print the statements of the ledger instead of collecting, possible values:\n"+
+			"  csv: csv including a header\n"+
+			"  json: json")
+		fs.StringVar(&billingConf.month, "month", "", "month of the statements (YYYY-MM), the previous month if empty")
+		fs.StringVar(&billingConf.tenant, "tenant", "", "name of the tenant to print the statement of, all tenants if empty")
+	},
+	run: runBilling,
+}
+
+// printStatements prints the statements of the month of the ledger
+func printStatements(c billing.Config, l *billing.Ledger) error {
+	now := time.Now().In(c.Location)
+	month := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, c.Location).Format(monthFormat)
+	if billingConf.month != "" {
+		m, err := time.Parse(monthFormat, billingConf.month)
+		if err != nil {
+			return fmt.Errorf("invalid -month: %w", err)
+		}
+		month = m.Format(monthFormat)
+	}
+	statements := l.Statements(c, month)
+	if billingConf.tenant != "" {
+		var tenant []billing.Statement
+		for _, s := range statements {
+			if s.Tenant == billingConf.tenant {
+				tenant = append(tenant, s)
+			}
+		}
+		if len(tenant) == 0 {
+			return fmt.Errorf("tenant %s not configured", billingConf.tenant)
+		}
+		statements = tenant
+	}
+	switch billingConf.report {
+	case "csv":
+		return billing.WriteCSV(os.Stdout, statements)
+	case "json":
+		return billing.WriteJSON(os.Stdout, statements)
+	}
+	return fmt.Errorf("report %s not supported", billingConf.report)
+}
+
+func runBilling(fs *flag.FlagSet) error {
+	c, err := billing.LoadConfig(billingConf.config)
+	if err != nil {
+		return err
+	}
+	l, err := billing.LoadLedger(billingConf.ledger)
+	if err != nil {
+		return err
+	}
+	if billingConf.report != "" {
+		return printStatements(c, l)
+	}
+	client, err := billingConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	components, err := rscp.Discover(client)
+	if err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+	if err := c.Resolve(components); err != nil {
+		return err
+	}
+	// info level to always log the gaps and meter resets
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		if err := billing.Collect(client, c, l, time.Now()); err != nil {
+			logStepError(err)
+		}
+		if err := l.Save(billingConf.ledger); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
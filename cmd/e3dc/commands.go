@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/jnovack/flag"
+	"github.com/sirupsen/logrus"
+)
+
+// command is a sub command of the command line utility (i.e. `e3dc inventory`)
+type command struct {
+	// short description used in the usage
+	description string
+	// usage of the arguments after the flags
+	arguments string
+	// flags registers the command specific flags
+	flags func(fs *flag.FlagSet)
+	// run executes the command after the flags are parsed
+	run func(fs *flag.FlagSet) error
+}
+
+// commands contains all available sub commands
+var commands = map[string]command{
+	"agent":     agentCommand,
+	"benchmark": benchmarkCommand,
+	"billing":   billingCommand,
+	"counter":   counterCommand,
+	"inventory": inventoryCommand,
+	"evcharge":  evchargeCommand,
+	"hub":       hubCommand,
+	"preserve":  preserveCommand,
+	"island":    islandCommand,
+	"lease":     leaseServerCommand,
+	"quality":   qualityCommand,
+	"sim":       simCommand,
+	"snmp":      snmpCommand,
+	"soak":      soakCommand,
+	"stats":     statsCommand,
+	"telegraf":  telegrafCommand,
+	"test":      testCommand,
+}
+
+// printCommands prints the available sub commands
+func printCommands() {
+	if len(commands) == 0 {
+		return
+	}
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "\nCommands (see %s <command> -help):\n", name)
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", n, commands[n].description)
+	}
+}
+
+// runCommand parses the command flags and runs it, returns the exit code
+func runCommand(cmdName string, cmd command, args []string) int {
+	fs := flag.NewFlagSetWithEnvPrefix(name+" "+cmdName, "E3DC", flag.ContinueOnError)
+	var help bool
+	var debug uint
+	fs.BoolVar(&help, "help", false, "output this help")
+	fs.BoolVar(&help, "h", false, "output this help")
+	fs.UintVar(&debug, "debug", 0, "enable set debug messages to stderr by setting log level (0-6)")
+	cmd.flags(fs)
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [options] %s\n\n%s\n\n", name, cmdName, cmd.arguments, cmd.description)
+		fs.PrintDefaults()
+	}
+	fs.Usage = func() {}
+	if err := fs.Parse(args); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		}
+		usage()
+		return 1
+	}
+	if help {
+		usage()
+		return 0
+	}
+	if debug > 0 {
+		logrus.SetLevel(logrus.Level(debug))
+		logrus.SetOutput(os.Stderr)
+	} else {
+		logrus.SetLevel(logrus.PanicLevel)
+	}
+	if err := cmd.run(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/jnovack/flag"
+	"github.com/spali/go-rscp/rscp"
+	"github.com/spali/go-rscp/session"
+)
+
+// connectionConf contains the connection flags of commands working with a single system
+type connectionConf struct {
+	host         string
+	port         uint
+	user         string
+	password     string
+	key          string
+	retries      uint
+	elevated     string
+	elevatedIdle time.Duration
+	audit        string
+}
+
+// flags registers the connection flags, named like the flags of the request mode to share the environment variables
+func (c *connectionConf) flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.host, "host", "", "e3dc server host")
+	fs.UintVar(&c.port, "port", 5033, "e3dc server host port")
+	fs.StringVar(&c.user, "user", "", "e3dc user")
+	fs.StringVar(&c.password, "password", "", "e3dc password (consider using an environment variable)")
+	fs.StringVar(&c.key, "key", "", "rscp key")
+	fs.UintVar(&c.retries, "retries", 0, "retries of a failed request on a new connection, requests which may have been applied are only retried if idempotent")
+	c.elevatedFlags(fs)
+}
+
+// elevatedFlags registers the flags of the elevated session
+func (c *connectionConf) elevatedFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.elevated, "elevated", "", "path to json file with the credentials of the elevated session (i.e. an installer),\n"+
+		"requests requiring a higher auth level than the user are sent with a separate elevated session\n"+
+		"  i.e. {\"user\": \"installer\", \"password\": \"secret\"}")
+	fs.DurationVar(&c.elevatedIdle, "elevated-idle", time.Minute*5, "time after the last elevated request the elevated session is closed")
+	fs.StringVar(&c.audit, "audit", "e3dc-audit.jsonl", "path to the audit log every elevated request is appended to as json line")
+}
+
+// client is the connection to a single system
+type client interface {
+	rscp.Sender
+	Disconnect() error
+}
+
+// auditedSender closes the audit log on disconnect
+type auditedSender struct {
+	*session.Sender
+	audit *os.File
+}
+
+// Disconnect closes the sessions and the audit log
+func (a auditedSender) Disconnect() error {
+	err := a.Sender.Disconnect()
+	if cerr := a.audit.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// newClient checks the flags and creates the client, with elevated credentials a client with separate sessions
+func (c *connectionConf) newClient() (client, error) {
+	switch {
+	case c.host == "":
+		return nil, ErrMissingHost
+	case c.user == "":
+		return nil, ErrMissingUser
+	case c.password == "":
+		return nil, ErrMissingPassword
+	case c.key == "":
+		return nil, ErrMissingKey
+	}
+	config := rscp.ClientConfig{
+		Address:     c.host,
+		Port:        uint16(c.port),
+		Username:    c.user,
+		Password:    c.password,
+		Key:         c.key,
+		UseChecksum: true,
+		Retries:     c.retries,
+	}
+	if c.elevated == "" {
+		return rscp.NewClient(config)
+	}
+	audit, err := os.OpenFile(c.audit, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s, err := session.New(session.Config{
+		Client:      config,
+		Elevated:    session.FileCredentials(c.elevated),
+		IdleTimeout: c.elevatedIdle,
+		Audit:       audit,
+	})
+	if err != nil {
+		_ = audit.Close()
+		return nil, err
+	}
+	return auditedSender{Sender: s, audit: audit}, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/counter"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var counterConf = struct {
+	connection connectionConf
+	config     string
+	computed   string
+	state      string
+	interval   time.Duration
+	report     bool
+}{}
+
+var counterCommand = command{
+	description: "integrate virtual energy counters from polled power values, backfill gaps and reconcile them daily with the history",
+	flags: func(fs *flag.FlagSet) {
+		counterConf.connection.flags(fs)
+		fs.StringVar(&counterConf.config, "config", "counters.json", "path to the counters config file")
+		fs.StringVar(&counterConf.computed, "computed", "", "path to a json file defining computed tags the powers may reference")
+		fs.StringVar(&counterConf.state, "state", "counters-state.json", "path to the counters state file")
+		fs.DurationVar(&counterConf.interval, "interval", time.Second*10, "interval between two samples")
+		fs.BoolVar(&counterConf.report, "report", false, "print the state as json instead of integrating")
+	},
+	run: runCounter,
+}
+
+// reconcileCounters reconciles the previous days once they are complete
+func reconcileCounters(sender rscp.Sender, c counter.Config, s *counter.State, now time.Time) {
+	if _, err := counter.ReconcileDue(sender, c, s, now); err != nil {
+		log.Errorf("reconciliation failed: %s", err)
+	}
+}
+
+// backfillCounters backfills the gaps of the counters from the history
+func backfillCounters(sender rscp.Sender, c counter.Config, s *counter.State, now time.Time) {
+	backfills, err := counter.BackfillGaps(sender, c, s, now)
+	if err != nil {
+		log.Errorf("backfill failed: %s", err)
+	}
+	for _, b := range backfills {
+		log.Infof("counter %s backfilled %.0fWh from %s to %s (%s)", b.Counter, b.Energy,
+			b.From.Format(time.RFC3339), b.To.Format(time.RFC3339), b.Quality)
+	}
+}
+
+func runCounter(fs *flag.FlagSet) error {
+	c, err := counter.LoadConfig(counterConf.config)
+	if err != nil {
+		return err
+	}
+	if counterConf.computed != "" {
+		tags, err := computed.Load(counterConf.computed)
+		if err != nil {
+			return fmt.Errorf("could not load computed tags: %w", err)
+		}
+		if err := c.SetComputed(tags); err != nil {
+			return err
+		}
+	}
+	s, err := counter.LoadState(counterConf.state)
+	if err != nil {
+		return err
+	}
+	if counterConf.report {
+		rb, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", rb)
+		return nil
+	}
+	client, err := counterConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	// info level to always log the deviations
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(counterConf.interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		if err := counter.Step(client, c, s, now); err != nil {
+			logStepError(err)
+		}
+		// backfilled before the reconciliation of the day
+		backfillCounters(client, c, s, now)
+		reconcileCounters(client, c, s, now)
+		if err := s.Save(counterConf.state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -5,44 +5,100 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/counter"
 	"github.com/spali/go-rscp/rscp"
 )
 
 func run() ([]byte, error) {
-	c, err := rscp.NewClient(rscp.ClientConfig{
-		Address:     conf.host,
-		Port:        uint16(conf.port),
-		Username:    conf.user,
-		Password:    conf.password,
-		Key:         conf.key,
-		UseChecksum: true,
-	})
+	cc := conf.elevated
+	cc.host, cc.port, cc.user, cc.password, cc.key, cc.retries = conf.host, conf.port, conf.user, conf.password, conf.key, conf.retries
+	c, err := cc.newClient()
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = c.Disconnect() }()
+	var components rscp.Components
+	discoverIndexes = func(namespace string) ([]uint16, error) {
+		if components == nil {
+			if components, err = rscp.Discover(c); err != nil {
+				return nil, err
+			}
+		}
+		return components.Indexes(namespace), nil
+	}
 	var (
 		rb []byte
 		ms []rscp.Message
 		rs []rscp.Message
 	)
-	if ms, err = unmarshalJSONRequests([]byte(conf.request)); err != nil {
+	if ms, err = unmarshalJSONRequests("$", []byte(conf.request)); err != nil {
 		return nil, err
 	}
+	requested := len(ms)
+	var tags *computed.Tags
+	if conf.computed != "" {
+		if tags, err = computed.Load(conf.computed); err != nil {
+			return nil, fmt.Errorf("could not load computed tags: %w", err)
+		}
+		var deps []rscp.Message
+		if deps, err = tags.Requests(); err != nil {
+			return nil, err
+		}
+		ms = append(ms, deps...)
+	}
+	var counters *counter.Config
+	if conf.counters != "" {
+		var cc counter.Config
+		if cc, err = counter.LoadConfig(conf.counters); err != nil {
+			return nil, fmt.Errorf("could not load counters: %w", err)
+		}
+		counters = &cc
+		if tags != nil {
+			// the powers may reference the computed tags
+			if err = counters.SetComputed(tags); err != nil {
+				return nil, fmt.Errorf("could not load counters: %w", err)
+			}
+		}
+		var deps []rscp.Message
+		if deps, err = counters.Requests(); err != nil {
+			return nil, err
+		}
+		ms = append(ms, deps...)
+	}
 	if conf.splitrequests {
 		rs = make([]rscp.Message, len(ms))
 		for i := range ms {
-			var r *rscp.Message
-			if r, err = c.Send(ms[i]); err != nil {
-				return nil, err
+			var r []rscp.Message
+			if r, err = c.SendMultiple(ms[i : i+1]); err != nil {
+				// the requests before were applied, the remaining aren't sent to not act on a partial state
+				return nil, fmt.Errorf("request $[%d] failed, %d of %d requests sent: %w", i, i, len(ms), err)
 			}
-			rs[i] = *r
+			rs[i] = r[0]
 		}
 	} else if rs, err = c.SendMultiple(ms); err != nil {
 		return nil, err
 	}
+	var values map[string]float64
+	if tags != nil {
+		// the tags computed are output even if others failed
+		if values, err = tags.Evaluate(rs); err != nil {
+			logrus.Warnf("computed tags: %s", err)
+		}
+	}
+	var totals map[string]float64
+	if counters != nil {
+		if totals, err = integrateCounters(*counters, rs); err != nil {
+			return nil, err
+		}
+	}
+	// hide the responses to the requests added for the computed tags and counters
+	if (tags != nil || counters != nil) && len(rs) == len(ms) {
+		rs = rs[:requested]
+	}
 	switch conf.output {
 	case "json":
 		if rb, err = json.Marshal(rs); err != nil {
@@ -56,13 +112,47 @@ func run() ([]byte, error) {
 		if rb, err = json.Marshal(NewJSONMergedMessages(rs)); err != nil {
 			return nil, err
 		}
+	case "jsonstable":
+		if rb, err = json.Marshal(NewJSONStableMessages(rs, conf.indexkeys)); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("output %s not supported", conf.output)
 	}
+	if tags != nil {
+		if rb, err = appendJSONComputed(rb, conf.output, tags.Names(), values); err != nil {
+			return nil, err
+		}
+	}
+	if counters != nil {
+		return appendJSONComputed(rb, conf.output, counters.Names(), totals)
+	}
 	return rb, nil
 }
 
+// integrateCounters integrates the powers of the responses up to now and returns the totals of the counters
+func integrateCounters(c counter.Config, responses []rscp.Message) (map[string]float64, error) {
+	s, err := counter.LoadState(conf.counterstate)
+	if err != nil {
+		return nil, err
+	}
+	powers, err := c.Powers(responses)
+	if err != nil {
+		return nil, err
+	}
+	s.Add(c, time.Now(), powers)
+	if err := s.Save(conf.counterstate); err != nil {
+		return nil, err
+	}
+	return s.Totals(), nil
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, isCommand := commands[os.Args[1]]; isCommand {
+			os.Exit(runCommand(os.Args[1], cmd, os.Args[2:]))
+		}
+	}
 	switch fs, err := parseFlags(); {
 	case conf.help:
 		printUsage(fs)
@@ -39,9 +39,16 @@ type config struct {
 	password      string
 	key           string
 	request       string
+	computed      string
+	counters      string
+	counterstate  string
 	output        string
 	debug         uint
 	splitrequests bool
+	retries       uint
+	indexkeys     bool
+	// flags of the elevated session, the connection is configured by the flags above
+	elevated connectionConf
 }
 
 var conf = config{}
@@ -58,7 +65,9 @@ func printVersion() {
 
 func printUsage(fs *flag.FlagSet) {
 	fmt.Fprintf(os.Stderr, "Usage: %s [options] 'json request'\n", name)
+	fmt.Fprintf(os.Stderr, "       %s <command> [options]\n", name)
 	fs.PrintDefaults()
+	printCommands()
 }
 
 func parseFlags() (*flag.FlagSet, error) {
@@ -78,10 +87,22 @@ func parseFlags() (*flag.FlagSet, error) {
 		"  jsonsimple: array with simple objects using tag as key for the value\n"+
 		"  jsonmerged: merges the the result of all responses into a single object\n"+
 		"              using the tag as keys.\n"+
-		"              requests that return the same key multiple times, will result in an array")
+		"              containers returned multiple times result in an array,\n"+
+		"              of other tags returned multiple times the last value is used\n"+
+		"  jsonstable: like jsonmerged, but tags which can be repeated (i.e. BAT_DATA) are always an array,\n"+
+		"              the shape doesn't depend on the number of components")
+	conf.elevated.elevatedFlags(fs)
+	fs.BoolVar(&conf.indexkeys, "indexkeys", false, "key the repeated containers of jsonstable by their index (i.e. BAT_INDEX) instead of an array")
+	fs.StringVar(&conf.computed, "computed", "", "path to json file defining computed tags by name and expression\n"+
+		"  i.e. {\"SURPLUS\": \"EMS_POWER_PV - EMS_POWER_HOME\"}")
+	fs.StringVar(&conf.counters, "counters", "", "path to json file defining energy counters integrated from power values (see counter command),\n"+
+		"the total energies in Wh are added to the output like computed tags")
+	fs.StringVar(&conf.counterstate, "counterstate", "counters-state.json", "path to the state file of the counters")
 	fs.UintVar(&conf.debug, "debug", 0, "enable set debug messages to stderr by setting log level (0-6)")
 	fs.BoolVar(&conf.splitrequests, "splitrequests", false, "split the request array to multiple requests.\n"+
 		"this can help if the server sends a timeout on big requests")
+	fs.UintVar(&conf.retries, "retries", 0, "retries of a failed request on a new connection.\n"+
+		"requests which may have been applied are only retried if they set a value, never if they trigger an action (i.e. SYS_REQ_SYSTEM_REBOOT)")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return fs, fmt.Errorf("%w%s", ErrFlagError, err)
 	}
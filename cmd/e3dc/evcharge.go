@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/evcharge"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var ErrMissingEnergy = errors.New("missing energy argument")
+
+var evchargeConf = struct {
+	connection connectionConf
+	lease      leaseConf
+	config     string
+	energy     float64
+	deadline   string
+	forecast   string
+	prices     string
+	interval   time.Duration
+	dryRun     bool
+}{}
+
+var evchargeCommand = command{
+	description: "charge the car with the energy until the departure time using pv surplus, battery and the grid",
+	flags: func(fs *flag.FlagSet) {
+		evchargeConf.connection.flags(fs)
+		evchargeConf.lease.flags(fs)
+		fs.StringVar(&evchargeConf.config, "wallbox", "", "path to the wallbox config file (optional)")
+		fs.Float64Var(&evchargeConf.energy, "energy", 0, "energy to charge in Wh")
+		fs.StringVar(&evchargeConf.deadline, "deadline", "07:00", "departure time as 15:04 (next occurrence) or RFC3339")
+		fs.StringVar(&evchargeConf.forecast, "forecast", "", "path to a json file with the pv surplus forecast (optional, re-read on every step)\n"+
+			"  i.e. [{\"start\": \"2021-06-01T10:00:00Z\", \"surplus\": 5000}]")
+		fs.StringVar(&evchargeConf.prices, "prices", "", "path to a json file with the grid prices (optional, re-read on every step)\n"+
+			"  i.e. [{\"start\": \"2021-06-01T22:00:00Z\", \"price\": 0.2}]")
+		fs.DurationVar(&evchargeConf.interval, "interval", time.Minute, "interval between two steps")
+		fs.BoolVar(&evchargeConf.dryRun, "dryrun", false, "only log the plan without applying it")
+	},
+	run: runEVCharge,
+}
+
+// parseDeadline parses the deadline as time of day (next occurrence after now) or RFC3339
+func parseDeadline(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		d := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !d.After(now) {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("invalid deadline %s: %w", s, err)
+	}
+	return t, nil
+}
+
+// readOptional reads the json file if the path is set
+func readOptional(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+	return jsonfile.Read(path, v)
+}
+
+func runEVCharge(fs *flag.FlagSet) error {
+	if evchargeConf.energy <= 0 {
+		return ErrMissingEnergy
+	}
+	ctl := &evcharge.Controller{DryRun: evchargeConf.dryRun}
+	var err error
+	if evchargeConf.config != "" {
+		if ctl.Config, err = evcharge.LoadConfig(evchargeConf.config); err != nil {
+			return err
+		}
+	} else if ctl.Config, err = evcharge.DefaultConfig(); err != nil {
+		return err
+	}
+	ctl.Goal.Energy = evchargeConf.energy
+	if ctl.Goal.Deadline, err = parseDeadline(evchargeConf.deadline, time.Now()); err != nil {
+		return err
+	}
+	client, err := evchargeConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	components, err := rscp.Discover(client)
+	if err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+	if err := ctl.Config.Resolve(components); err != nil {
+		return err
+	}
+	sender, release := evchargeConf.lease.sender(client)
+	defer release()
+	// info level to always log the plan changes
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(evchargeConf.interval)
+	defer ticker.Stop()
+	for {
+		if err := readOptional(evchargeConf.forecast, &ctl.Forecast); err != nil {
+			log.Errorf("could not read forecast: %s", err)
+		}
+		if err := readOptional(evchargeConf.prices, &ctl.Prices); err != nil {
+			log.Errorf("could not read prices: %s", err)
+		}
+		now := time.Now()
+		p, err := ctl.Step(sender, now)
+		if err != nil {
+			logStepError(err)
+		}
+		if err == nil && !now.Before(ctl.Goal.Deadline) {
+			if p.Remaining > 0 {
+				return fmt.Errorf("%.0f Wh not charged until the deadline", p.Remaining)
+			}
+			return nil
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
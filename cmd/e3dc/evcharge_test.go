@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseDeadline(t *testing.T) {
+	now := time.Date(2021, 6, 1, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		deadline string
+		want     time.Time
+		wantErr  bool
+	}{
+		{"07:00", time.Date(2021, 6, 2, 7, 0, 0, 0, time.UTC), false},
+		{"20:30", time.Date(2021, 6, 1, 20, 30, 0, 0, time.UTC), false},
+		{"2021-06-03T06:00:00Z", time.Date(2021, 6, 3, 6, 0, 0, 0, time.UTC), false},
+		{"tomorrow", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deadline, func(t *testing.T) {
+			got, err := parseDeadline(tt.deadline, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDeadline() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseDeadline() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/tunnel"
+)
+
+var hubConf = struct {
+	config string
+}{}
+
+var hubCommand = command{
+	description: "central hub the agents connect to, serving the systems of the sites on local ports",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&hubConf.config, "hub", "hub.json", "path to the hub config file")
+	},
+	run: func(fs *flag.FlagSet) error {
+		c, err := tunnel.LoadHubConfig(hubConf.config)
+		if err != nil {
+			return err
+		}
+		hub, err := tunnel.ListenHub(c)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = hub.Close() }()
+		// info level to always log the connection state
+		if log.GetLevel() < log.InfoLevel {
+			log.SetLevel(log.InfoLevel)
+			log.SetOutput(os.Stderr)
+		}
+		log.Infof("hub listening for agents on %s", hub.Addr())
+		for _, s := range c.Sites {
+			log.Infof("site %s served on %s", s.ID, hub.SiteAddr(s.ID))
+		}
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt)
+		<-stop
+		return nil
+	},
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/fleet"
+	"github.com/spali/go-rscp/inventory"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var inventoryConf = struct {
+	fleet   string
+	history string
+	collect bool
+	report  string
+}{}
+
+var inventoryCommand = command{
+	description: "collect firmware versions and serial numbers of all sites of a fleet",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&inventoryConf.fleet, "fleet", "fleet.json", "path to the fleet config file")
+		fs.StringVar(&inventoryConf.history, "history", "inventory.json", "path to the inventory history file")
+		fs.BoolVar(&inventoryConf.collect, "collect", true, "collect the inventory of all sites before reporting")
+		fs.StringVar(&inventoryConf.report, "report", "versions", "control the output, possible values:\n"+
+			"  versions: sites grouped by the value of each inventory key\n"+
+			"  changes:  changes between the collected inventories of each site\n"+
+			"  latest:   latest inventory of each site")
+	},
+	run: runInventory,
+}
+
+// inventoryReports maps the -report values to the report of the history
+var inventoryReports = map[string]func(h *inventory.History) interface{}{
+	"versions": func(h *inventory.History) interface{} { return h.GroupByVersion() },
+	"changes":  func(h *inventory.History) interface{} { return h.Changes() },
+	"latest":   func(h *inventory.History) interface{} { return h.Latest() },
+}
+
+// collectInventory collects the inventory of a single site
+func collectInventory(site fleet.Site) inventory.Snapshot {
+	s := inventory.Snapshot{Site: site.ID, Time: time.Now().UTC()}
+	c, err := rscp.NewClient(site.ClientConfig())
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	defer func() { _ = c.Disconnect() }()
+	if s.Items, err = inventory.Collect(c, nil); err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+func runInventory(fs *flag.FlagSet) error {
+	// the report is checked before the fleet is polled
+	report, ok := inventoryReports[inventoryConf.report]
+	if !ok {
+		return fmt.Errorf("report %s not supported", inventoryConf.report)
+	}
+	var (
+		h   *inventory.History
+		err error
+	)
+	if h, err = inventory.LoadHistory(inventoryConf.history); err != nil {
+		return err
+	}
+	if inventoryConf.collect {
+		var f fleet.Config
+		if f, err = fleet.Load(inventoryConf.fleet); err != nil {
+			return err
+		}
+		for _, site := range f.Sites {
+			s := collectInventory(site)
+			if s.Error != "" {
+				log.Errorf("inventory of site %s failed: %s", site.ID, s.Error)
+			}
+			h.Add(s)
+		}
+		if err := h.Save(inventoryConf.history); err != nil {
+			return err
+		}
+	}
+	var rb []byte
+	if rb, err = json.Marshal(report(h)); err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", rb)
+	return nil
+}
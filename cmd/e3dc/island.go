@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/island"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var islandConf = struct {
+	connection connectionConf
+	lease      leaseConf
+	config     string
+	state      string
+	interval   time.Duration
+	dryRun     bool
+}{}
+
+var islandCommand = command{
+	description: "shed loads in tiers while the system runs as island grid",
+	flags: func(fs *flag.FlagSet) {
+		islandConf.connection.flags(fs)
+		islandConf.lease.flags(fs)
+		fs.StringVar(&islandConf.config, "tiers", "island.json", "path to the tier config file")
+		fs.StringVar(&islandConf.state, "state", "island-state.json", "path to the manager state file")
+		fs.DurationVar(&islandConf.interval, "interval", time.Second*10, "interval between two steps")
+		fs.BoolVar(&islandConf.dryRun, "dryrun", false, "only log the changes without applying them")
+	},
+	run: runIsland,
+}
+
+func runIsland(fs *flag.FlagSet) error {
+	c, err := island.LoadConfig(islandConf.config)
+	if err != nil {
+		return err
+	}
+	s, err := island.LoadState(islandConf.state)
+	if err != nil {
+		return err
+	}
+	client, err := islandConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	components, err := rscp.Discover(client)
+	if err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+	if err := c.Resolve(components); err != nil {
+		return err
+	}
+	sender, release := islandConf.lease.sender(client)
+	defer release()
+	// info level to always log the changes
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(islandConf.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := island.Step(sender, c, s, time.Now(), islandConf.dryRun); err != nil {
+			logStepError(err)
+		}
+		if err := s.Save(islandConf.state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spali/go-rscp/rscp"
 )
@@ -10,39 +15,166 @@ import (
 var (
 	ErrInputNotAnArray   = errors.New("request input has always to be an in an array")
 	ErrInputInvalidTuple = errors.New("request contains an invalid tuple")
+	ErrInputInvalidIndex = errors.New("request contains an invalid index")
+	ErrInputNoNamespace  = errors.New("index syntax is only supported on namespace containers")
+	ErrInputUnknownTag   = errors.New("unknown tag")
+	ErrInputMissingIndex = errors.New("container is missing its index")
 )
 
-func unmarshalJSONValue(b []byte, m *rscp.Message) error {
+// indexSeparator separates the index from the tag (i.e. "BAT_REQ_DATA@0" or "BAT_REQ_DATA@*")
+const indexSeparator = "@"
+
+// allIndexes expands the request to all discovered indexes
+const allIndexes = "*"
+
+// discoverIndexes returns the available indexes of the namespace, used to expand "@*"
+var discoverIndexes = func(namespace string) ([]uint16, error) {
+	return nil, fmt.Errorf("discovery of %s not available", namespace)
+}
+
+// warnInput reports a questionable request input, the request is sent anyway
+var warnInput = func(err error) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+}
+
+// maxFragmentLength limits the length of the json fragment shown in errors
+const maxFragmentLength = 60
+
+// maxSuggestions limits the number of tags suggested for an unknown tag
+const maxSuggestions = 3
+
+// inputError locates an error within the request input
+type inputError struct {
+	// json path of the offending element (i.e. $[1][0])
+	path string
+	// offending json, shortened
+	fragment string
+	// hint how to fix the error
+	hint string
+	err  error
+}
+
+func (e *inputError) Error() string {
+	s := fmt.Sprintf("request %s %s: %s", e.path, e.fragment, e.err)
+	if e.hint != "" {
+		s += " (" + e.hint + ")"
+	}
+	return s
+}
+
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// newInputError locates the error at the path, an already located error is returned unchanged
+func newInputError(path string, b []byte, err error, hint string) error {
+	var ie *inputError
+	if errors.As(err, &ie) {
+		return err
+	}
+	f := bytes.Buffer{}
+	if json.Compact(&f, b) != nil {
+		f.Reset()
+		f.Write(bytes.TrimSpace(b))
+	}
+	fragment := f.String()
+	if len(fragment) > maxFragmentLength {
+		fragment = fragment[:maxFragmentLength] + "..."
+	}
+	return &inputError{path: path, fragment: fragment, hint: hint, err: err}
+}
+
+// indexPath appends the array index to the path
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// unmarshalJSONTag unmarshals the tag and suggests similar tags if unknown
+func unmarshalJSONTag(path string, b []byte, t *rscp.Tag) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return newInputError(path, b, ErrInputUnknownTag, "a tag has to be a string")
+	}
+	var err error
+	if *t, err = rscp.TagString(name); err != nil {
+		hint := ""
+		if suggestions := rscp.SuggestTags(name, maxSuggestions); len(suggestions) > 0 {
+			names := make([]string, len(suggestions))
+			for i, s := range suggestions {
+				names[i] = s.String()
+			}
+			hint = "did you mean " + strings.Join(names, ", ") + "?"
+		}
+		return newInputError(path, b, ErrInputUnknownTag, hint)
+	}
+	return nil
+}
+
+// checkJSONRequest checks the message is a request and warns if a namespace container is missing its index,
+// recursive checks the nested messages too. Nested messages may be data tags without a request tag
+// (i.e. HA_DATAPOINT_INDEX within HA_REQ_COMMAND_ACTUATOR), only responses to a request tag are rejected there.
+func checkJSONRequest(path string, b []byte, m rscp.Message, nested bool, recursive bool) error {
+	if r := m.Tag.RequestTag(); r != m.Tag && (!nested || r.IsATag()) {
+		hint := "responses can't be sent"
+		if r.IsATag() {
+			hint = fmt.Sprintf("did you mean %s?", r)
+		}
+		return newInputError(path, b, fmt.Errorf("%s: %w", m.Tag, rscp.ErrNotARequestTag), hint)
+	}
+	values, _ := m.Value.([]rscp.Message)
+	if ns, ok := rscp.NamespaceByContainer(m.Tag); ok {
+		found := false
+		for _, v := range values {
+			found = found || v.Tag == ns.IndexTag
+		}
+		if !found {
+			warnInput(newInputError(path, b, fmt.Errorf("%s: %w", m.Tag, ErrInputMissingIndex),
+				fmt.Sprintf(`add ["%s", 0] to the container or use "%s%s0"`, ns.IndexTag, m.Tag, indexSeparator)))
+		}
+	}
+	if recursive {
+		for i, v := range values {
+			vb, _ := json.Marshal(v)
+			if err := checkJSONRequest(indexPath(path+".Value", i), vb, v, true, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func unmarshalJSONValue(path string, b []byte, m *rscp.Message) error {
 	if m.DataType == rscp.Container {
 		var err error
-		if m.Value, err = unmarshalJSONRequests(b); err != nil {
+		if m.Value, err = unmarshalJSONMessages(path, b, true); err != nil {
 			return err
 		}
 		return nil
 	}
 	if err := m.UnmarshalJSONValue(b); err != nil {
-		return err
+		return newInputError(path, b, err, fmt.Sprintf("%s expects a value of data type %s", m.Tag, m.DataType))
 	}
 	return nil
 }
 
+// unmarshalJSONRequest unmarshals a single request at the path of the input
 //nolint: gomnd
-func unmarshalJSONRequest(b []byte, m *rscp.Message) error {
+func unmarshalJSONRequest(path string, b []byte, m *rscp.Message) error {
 	if isJSONEmpty(b) {
-		return ErrInputInvalidTuple
+		return newInputError(path, b, ErrInputInvalidTuple, "empty request")
 	}
 	if isJSONArray(b) {
 		// parse tuple
 		t := []json.RawMessage{}
 		if err := json.Unmarshal(b, &t); err != nil {
-			return err
+			return newInputError(path, b, err, "")
 		}
 		l := len(t)
 		if l < 1 || l > 3 {
-			return ErrInputInvalidTuple
+			return newInputError(path, b, ErrInputInvalidTuple, `expected ["TAG"], ["TAG", value] or ["TAG", "DataType", value]`)
 		}
 		// parse tag
-		if err := json.Unmarshal(t[0], &(m.Tag)); err != nil {
+		if err := unmarshalJSONTag(indexPath(path, 0), t[0], &(m.Tag)); err != nil {
 			return err
 		}
 		if l > 1 {
@@ -53,7 +185,7 @@ func unmarshalJSONRequest(b []byte, m *rscp.Message) error {
 				// infer data type
 				m.DataType = m.Tag.DataType()
 				// unmarshal value
-				if err := unmarshalJSONValue(t[1], m); err != nil {
+				if err := unmarshalJSONValue(indexPath(path, 1), t[1], m); err != nil {
 					return err
 				}
 			}
@@ -63,7 +195,7 @@ func unmarshalJSONRequest(b []byte, m *rscp.Message) error {
 		}
 		if l == 3 {
 			// unmarshal value
-			if err := unmarshalJSONValue(t[2], m); err != nil {
+			if err := unmarshalJSONValue(indexPath(path, 2), t[2], m); err != nil {
 				return err
 			}
 		}
@@ -71,33 +203,154 @@ func unmarshalJSONRequest(b []byte, m *rscp.Message) error {
 	}
 	if isJSONString(b) {
 		// parse tag
-		if err := json.Unmarshal(b, &(m.Tag)); err != nil {
+		if err := unmarshalJSONTag(path, b, &(m.Tag)); err != nil {
 			return err
 		}
 		// infer data type
 		m.DataType = m.Tag.DataType()
 		return nil
 	}
+	// check the tag first to provide suggestions
+	o := struct{ Tag json.RawMessage }{}
+	if json.Unmarshal(b, &o) == nil && o.Tag != nil {
+		var t rscp.Tag
+		if err := unmarshalJSONTag(path+".Tag", o.Tag, &t); err != nil {
+			return err
+		}
+	}
 	// use Message default Unmarshal
 	if err := json.Unmarshal(b, m); err != nil {
-		return err
+		return newInputError(path, b, err, "")
 	}
 	return nil
 }
 
-func unmarshalJSONRequests(b []byte) ([]rscp.Message, error) {
+// unmarshalJSONRequests unmarshals the array of requests at the path of the input (the input itself is "$")
+func unmarshalJSONRequests(path string, b []byte) ([]rscp.Message, error) {
+	return unmarshalJSONMessages(path, b, false)
+}
+
+// unmarshalJSONMessages unmarshals the array of messages at the path of the input, nested within a container or not
+func unmarshalJSONMessages(path string, b []byte, nested bool) ([]rscp.Message, error) {
 	if !isJSONArray(b) {
-		return nil, ErrInputNotAnArray
+		return nil, newInputError(path, b, ErrInputNotAnArray, "")
 	}
 	r := []json.RawMessage{}
 	if err := json.Unmarshal(b, &r); err != nil {
-		return nil, err
+		return nil, newInputError(path, b, err, "")
 	}
-	m := make([]rscp.Message, len(r))
+	m := make([]rscp.Message, 0, len(r))
 	for i, v := range r {
-		if err := unmarshalJSONRequest(v, &m[i]); err != nil {
+		p := indexPath(path, i)
+		b, index, err := splitJSONIndex(v)
+		if err != nil {
+			return nil, newInputError(p, v, err, "")
+		}
+		if index == "" {
+			mi := rscp.Message{}
+			if err := unmarshalJSONRequest(p, b, &mi); err != nil {
+				return nil, err
+			}
+			if err := checkJSONRequest(p, b, mi, nested, isJSONObject(b)); err != nil {
+				return nil, err
+			}
+			m = append(m, mi)
+			continue
+		}
+		mi, err := unmarshalJSONIndexedRequests(p, b, index)
+		if err != nil {
+			return nil, newInputError(p, v, err, "")
+		}
+		for _, r := range mi {
+			if err := checkJSONRequest(p, v, r, nested, false); err != nil {
+				return nil, err
+			}
+		}
+		m = append(m, mi...)
+	}
+	return m, nil
+}
+
+// splitJSONIndex strips the index from the tag of a string or tuple request and returns it separately
+func splitJSONIndex(b []byte) ([]byte, string, error) {
+	var (
+		tag string
+		t   []json.RawMessage
+	)
+	switch {
+	case isJSONString(b):
+		if err := json.Unmarshal(b, &tag); err != nil {
+			return nil, "", err
+		}
+	case isJSONArray(b) && !isJSONEmpty(b):
+		// errors are left to unmarshalJSONRequest
+		if json.Unmarshal(b, &t) != nil || len(t) == 0 || !isJSONString(t[0]) {
+			return b, "", nil
+		}
+		if err := json.Unmarshal(t[0], &tag); err != nil {
+			return nil, "", err
+		}
+	default:
+		return b, "", nil
+	}
+	i := strings.Index(tag, indexSeparator)
+	if i < 0 {
+		return b, "", nil
+	}
+	index := tag[i+len(indexSeparator):]
+	if index == "" {
+		return nil, "", fmt.Errorf("%s: %w", tag, ErrInputInvalidIndex)
+	}
+	tb, err := json.Marshal(tag[:i])
+	if err != nil {
+		return nil, "", err
+	}
+	if t == nil {
+		return tb, index, nil
+	}
+	t[0] = tb
+	if b, err = json.Marshal(t); err != nil {
+		return nil, "", err
+	}
+	return b, index, nil
+}
+
+// unmarshalJSONIndexedRequests unmarshals the request of a namespace container
+// and inserts the index tag as first element, expanded to one request per index on "*".
+func unmarshalJSONIndexedRequests(path string, b []byte, index string) ([]rscp.Message, error) {
+	tmpl := rscp.Message{}
+	if err := unmarshalJSONRequest(path, b, &tmpl); err != nil {
+		return nil, err
+	}
+	ns, ok := rscp.NamespaceByContainer(tmpl.Tag)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", tmpl.Tag, ErrInputNoNamespace)
+	}
+	var indexes []uint16
+	if index == allIndexes {
+		var err error
+		if indexes, err = discoverIndexes(ns.Name); err != nil {
+			return nil, err
+		}
+	} else {
+		i, err := strconv.ParseUint(index, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%s%s%s: %w", tmpl.Tag, indexSeparator, index, ErrInputInvalidIndex)
+		}
+		indexes = []uint16{uint16(i)}
+	}
+	values, _ := tmpl.Value.([]rscp.Message)
+	m := make([]rscp.Message, 0, len(indexes))
+	for _, i := range indexes {
+		im, err := ns.NewIndexMessage(i)
+		if err != nil {
 			return nil, err
 		}
+		m = append(m, rscp.Message{
+			Tag:      tmpl.Tag,
+			DataType: rscp.Container,
+			Value:    append([]rscp.Message{*im}, values...),
+		})
 	}
 	return m, nil
 }
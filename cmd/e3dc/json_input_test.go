@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -134,7 +135,7 @@ func Test_unmarshalJSONRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := rscp.Message{}
-			err := unmarshalJSONRequest([]byte(tt.message), &m)
+			err := unmarshalJSONRequest("$", []byte(tt.message), &m)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("unmarshalJSONRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -147,7 +148,9 @@ func Test_unmarshalJSONRequest(t *testing.T) {
 }
 
 func Test_unmarshalJSONRequests(t *testing.T) {
-
+	discoverIndexes = func(namespace string) ([]uint16, error) {
+		return map[string][]uint16{"PVI": {0, 1}}[namespace], nil
+	}
 	tests := []struct {
 		name    string
 		message string
@@ -169,15 +172,69 @@ func Test_unmarshalJSONRequests(t *testing.T) {
 			[]rscp.Message{{Tag: rscp.INFO_REQ_MAC_ADDRESS}, {Tag: rscp.INFO_REQ_UTC_TIME}},
 			false,
 		},
+		{`index`,
+			`["BAT_REQ_DATA@1",["BAT_REQ_DATA@2",["BAT_REQ_RSOC"]],["WB_REQ_DATA@0",["WB_REQ_STATUS"]]]`,
+			[]rscp.Message{
+				{Tag: rscp.BAT_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(1)}}},
+				{Tag: rscp.BAT_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(2)},
+					{Tag: rscp.BAT_REQ_RSOC},
+				}},
+				{Tag: rscp.WB_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.WB_INDEX, DataType: rscp.UChar8, Value: uint8(0)},
+					{Tag: rscp.WB_REQ_STATUS},
+				}},
+			},
+			false,
+		},
+		{`all indexes`,
+			`[["PVI_REQ_DATA@*",["PVI_REQ_TYPE"]]]`,
+			[]rscp.Message{
+				{Tag: rscp.PVI_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+					{Tag: rscp.PVI_REQ_TYPE},
+				}},
+				{Tag: rscp.PVI_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(1)},
+					{Tag: rscp.PVI_REQ_TYPE},
+				}},
+			},
+			false,
+		},
+		{`index on non namespace container`,
+			`["INFO_REQ_UTC_TIME@0"]`,
+			nil,
+			true,
+		},
+		{`invalid index`,
+			`["BAT_REQ_DATA@x"]`,
+			nil,
+			true,
+		},
+		{`empty index`,
+			`["BAT_REQ_DATA@"]`,
+			nil,
+			true,
+		},
 		{`array of messages`,
 			`[{ "Tag": "INFO_REQ_MAC_ADDRESS" }, { "Tag": "INFO_REQ_UTC_TIME" }]`,
 			[]rscp.Message{{Tag: rscp.INFO_REQ_MAC_ADDRESS}, {Tag: rscp.INFO_REQ_UTC_TIME}},
 			false,
 		},
+		{`data tags within container`,
+			`[["HA_REQ_COMMAND_ACTUATOR", [["HA_DATAPOINT_INDEX", 1], ["HA_REQ_COMMAND", 1]]]]`,
+			[]rscp.Message{
+				{Tag: rscp.HA_REQ_COMMAND_ACTUATOR, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.HA_DATAPOINT_INDEX, DataType: rscp.UInt16, Value: uint16(1)},
+					{Tag: rscp.HA_REQ_COMMAND, DataType: rscp.CString, Value: "1"},
+				}},
+			},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := unmarshalJSONRequests([]byte(tt.message))
+			got, err := unmarshalJSONRequests("$", []byte(tt.message))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("unmarshalJSONRequests() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -188,3 +245,81 @@ func Test_unmarshalJSONRequests(t *testing.T) {
 		})
 	}
 }
+
+func Test_unmarshalJSONRequests_diagnostics(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantErr error
+		want    string
+	}{
+		{"misspelled tag",
+			`["INFO_REQ_UTC_TIME", ["EMS_REQ_POWR_PV"]]`,
+			ErrInputUnknownTag,
+			`request $[1][0] "EMS_REQ_POWR_PV": unknown tag (did you mean EMS_REQ_POWER_PV?)`,
+		},
+		{"misspelled nested tag",
+			`[["BAT_REQ_DATA", [["BAT_INDEX", 0], "BAT_REQ_RSCO"]]]`,
+			ErrInputUnknownTag,
+			`request $[0][1][1] "BAT_REQ_RSCO": unknown tag (did you mean BAT_REQ_RSOC, BAT_REQ_INFO?)`,
+		},
+		{"misspelled object tag",
+			`[{"Tag": "INFO_REQ_UTC_TIM"}]`,
+			ErrInputUnknownTag,
+			`request $[0].Tag "INFO_REQ_UTC_TIM": unknown tag (did you mean INFO_REQ_UTC_TIME?)`,
+		},
+		{"response tag",
+			`["EMS_POWER_PV"]`,
+			rscp.ErrNotARequestTag,
+			`request $[0] "EMS_POWER_PV": EMS_POWER_PV: tag is not a request tag (did you mean EMS_REQ_POWER_PV?)`,
+		},
+		{"nested response tag",
+			`[["BAT_REQ_DATA", [["BAT_INDEX", 0], "BAT_RSOC"]]]`,
+			rscp.ErrNotARequestTag,
+			`request $[0][1][1] "BAT_RSOC": BAT_RSOC: tag is not a request tag (did you mean BAT_REQ_RSOC?)`,
+		},
+		{"response container with index",
+			`["BAT_DATA@0"]`,
+			rscp.ErrNotARequestTag,
+			`request $[0] "BAT_DATA@0": BAT_DATA: tag is not a request tag (did you mean BAT_REQ_DATA?)`,
+		},
+		{"invalid tuple",
+			`["INFO_REQ_UTC_TIME", ["EMS_REQ_POWER_PV", "None", null, 1]]`,
+			ErrInputInvalidTuple,
+			`request $[1] ["EMS_REQ_POWER_PV","None",null,1]: request contains an invalid tuple ` +
+				`(expected ["TAG"], ["TAG", value] or ["TAG", "DataType", value])`,
+		},
+		{"shortened fragment",
+			`{"some":"object which is not an array of requests and is way too long to be shown"}`,
+			ErrInputNotAnArray,
+			`request $ {"some":"object which is not an array of requests and is way...: request input has always to be an in an array`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unmarshalJSONRequests("$", []byte(tt.message))
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Fatalf("unmarshalJSONRequests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("unmarshalJSONRequests() error = %s\nwant %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unmarshalJSONRequests_warnings(t *testing.T) {
+	warnings := []error{}
+	defer func(w func(error)) { warnInput = w }(warnInput)
+	warnInput = func(err error) { warnings = append(warnings, err) }
+	// the container without index is sent anyway
+	got, err := unmarshalJSONRequests("$", []byte(`[["BAT_REQ_DATA", ["BAT_REQ_RSOC"]]]`))
+	if err != nil || len(got) != 1 {
+		t.Fatalf("unmarshalJSONRequests() = %v, error = %v", got, err)
+	}
+	want := `request $[0] ["BAT_REQ_DATA",["BAT_REQ_RSOC"]]: BAT_REQ_DATA: container is missing its index ` +
+		`(add ["BAT_INDEX", 0] to the container or use "BAT_REQ_DATA@0")`
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrInputMissingIndex) || warnings[0].Error() != want {
+		t.Errorf("warnings = %v, want %s", warnings, want)
+	}
+}
@@ -7,34 +7,83 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spali/go-rscp/rscp"
 )
 
 type JSONMessage map[rscp.Tag]interface{}
 
+// NewJSONMergedMessages merges the messages into a single object using the tags as keys,
+// containers occurring multiple times result in an array, of other tags occurring multiple times the last value is used.
 func NewJSONMergedMessages(messages []rscp.Message) JSONMessage {
 	jm := JSONMessage{}
-	var arrVal []JSONMessage
 	for _, message := range messages {
-		if messages, isContainer := message.Value.([]rscp.Message); isContainer {
-			if _, exists := jm[message.Tag]; exists && arrVal == nil {
-				arrVal = []JSONMessage{jm[message.Tag].(JSONMessage)}
+		if nested, isContainer := message.Value.([]rscp.Message); isContainer {
+			v := NewJSONMergedMessages(nested)
+			switch existing := jm[message.Tag].(type) {
+			case JSONMessage:
+				jm[message.Tag] = []JSONMessage{existing, v}
+			case []JSONMessage:
+				jm[message.Tag] = append(existing, v)
+			default:
+				jm[message.Tag] = v
 			}
-			if arrVal != nil {
-				arrVal = append(arrVal, NewJSONMergedMessages(messages))
-			} else {
-				jm[message.Tag] = NewJSONMergedMessages(messages)
+			continue
+		}
+		jm[message.Tag] = message.Value
+	}
+	return jm
+}
+
+// NewJSONStableMessages merges the messages like NewJSONMergedMessages, but the shape doesn't depend on the data.
+//
+// repeatable tags (see rscp.Tag.IsRepeatable) are always arrays, even if they occur once.
+// With keyIndex, containers identified by an index tag (i.e. BAT_DATA by BAT_INDEX) are objects keyed by the index instead,
+// a container without index is keyed by its position (i.e. "#1").
+// Other tags occur once by the protocol, if not the last value is used.
+func NewJSONStableMessages(messages []rscp.Message, keyIndex bool) JSONMessage {
+	jm := JSONMessage{}
+	for _, message := range messages {
+		var v interface{} = message.Value
+		if nested, isContainer := message.Value.([]rscp.Message); isContainer {
+			v = NewJSONStableMessages(nested, keyIndex)
+		}
+		if !message.Tag.IsRepeatable() {
+			if _, exists := jm[message.Tag]; exists {
+				logrus.Debugf("%s is not repeatable but occurred multiple times, using the last value", message.Tag)
+			}
+			jm[message.Tag] = v
+			continue
+		}
+		if indexTag, indexed := message.Tag.RepeatIndexTag(); keyIndex && indexed {
+			objects, _ := jm[message.Tag].(map[string]interface{})
+			if objects == nil {
+				objects = map[string]interface{}{}
+				jm[message.Tag] = objects
 			}
-			if arrVal != nil {
-				jm[message.Tag] = arrVal
+			key, found := indexKey(message, indexTag)
+			if !found {
+				key = fmt.Sprintf("#%d", len(objects))
 			}
-		} else {
-			jm[message.Tag] = message.Value
+			objects[key] = v
+			continue
 		}
+		a, _ := jm[message.Tag].([]interface{})
+		jm[message.Tag] = append(a, v)
 	}
 	return jm
 }
 
+// indexKey returns the value of the index tag within the container as key
+func indexKey(container rscp.Message, indexTag rscp.Tag) (string, bool) {
+	nested, _ := container.Value.([]rscp.Message)
+	index := rscp.FindTag(nested, indexTag)
+	if index == nil || index.DataType == rscp.Error {
+		return "", false
+	}
+	return fmt.Sprint(index.Value), true
+}
+
 // NewJSONSimpleMessage returns the message as simplified json
 func NewJSONSimpleMessage(message rscp.Message) JSONMessage {
 	jm := JSONMessage{}
@@ -96,3 +145,50 @@ func (m JSONMessage) MarshalJSON() ([]byte, error) {
 	buffer.WriteString("}")
 	return buffer.Bytes(), nil
 }
+
+// appendJSONComputed appends the computed tags in the format of the output to the marshalled responses
+func appendJSONComputed(b []byte, output string, names []string, values map[string]float64) ([]byte, error) {
+	elements := make([][]byte, 0, len(names))
+	for _, name := range names {
+		v, exists := values[name]
+		if !exists {
+			continue
+		}
+		var (
+			e   []byte
+			err error
+		)
+		switch output {
+		case "json":
+			e, err = json.Marshal(struct {
+				Tag      string
+				DataType rscp.DataType
+				Value    float64
+			}{name, rscp.Double64, v})
+		case "jsonsimple":
+			e, err = json.Marshal(map[string]float64{name: v})
+		default:
+			var nb, vb []byte
+			if nb, err = json.Marshal(name); err == nil {
+				vb, err = json.Marshal(v)
+			}
+			e = []byte(fmt.Sprintf("%s:%s", nb, vb))
+		}
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+	}
+	b = bytes.TrimRight(b, " \t\r\n")
+	if len(elements) == 0 || len(b) < 2 {
+		return b, nil
+	}
+	// insert the elements before the closing bracket of the array or object
+	end := b[len(b)-1]
+	r := append([]byte{}, b[:len(b)-1]...)
+	if !isJSONEmpty(bytes.TrimLeft(r, "[{")) {
+		r = append(r, ',')
+	}
+	r = append(r, bytes.Join(elements, []byte(","))...)
+	return append(r, end), nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -63,6 +64,24 @@ func TestNewJSONMergedMessages(t *testing.T) {
 				},
 			},
 		},
+		{"multiple containers of different tags",
+			[]rscp.Message{
+				batData(0, 80), batData(1, 60),
+				{Tag: rscp.PM_DATA, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.PM_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				}},
+				{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1)},
+				{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(2)},
+			},
+			JSONMessage{
+				rscp.BAT_DATA: []JSONMessage{
+					{rscp.BAT_INDEX: uint16(0), rscp.BAT_RSOC: float32(80)},
+					{rscp.BAT_INDEX: uint16(1), rscp.BAT_RSOC: float32(60)},
+				},
+				rscp.PM_DATA:      JSONMessage{rscp.PM_INDEX: uint16(0)},
+				rscp.EMS_POWER_PV: int32(2),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -74,6 +93,70 @@ func TestNewJSONMergedMessages(t *testing.T) {
 	}
 }
 
+// batData returns a BAT_DATA response of the battery
+func batData(index uint16, rsoc float32) rscp.Message {
+	return rscp.Message{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: index},
+		{Tag: rscp.BAT_RSOC, DataType: rscp.Float32, Value: rsoc},
+	}}
+}
+
+func TestNewJSONStableMessages(t *testing.T) {
+	pmData := rscp.Message{Tag: rscp.PM_DATA, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.PM_INDEX, DataType: rscp.UInt16, Value: uint16(6)},
+		{Tag: rscp.PM_POWER_L1, DataType: rscp.Double64, Value: float64(100)},
+	}}
+	tests := []struct {
+		name     string
+		messages []rscp.Message
+		keyIndex bool
+		want     string
+	}{
+		{"single battery",
+			[]rscp.Message{batData(0, 80), {Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1)}},
+			false,
+			`{"EMS_POWER_PV":1,"BAT_DATA":[{"BAT_INDEX":0,"BAT_RSOC":80}]}`,
+		},
+		{"multiple containers",
+			[]rscp.Message{batData(0, 80), batData(1, 60), pmData},
+			false,
+			`{"BAT_DATA":[{"BAT_INDEX":0,"BAT_RSOC":80},{"BAT_INDEX":1,"BAT_RSOC":60}],"PM_DATA":[{"PM_INDEX":6,"PM_POWER_L1":100}]}`,
+		},
+		{"keyed by index",
+			[]rscp.Message{batData(1, 60), pmData, batData(0, 80)},
+			true,
+			`{"BAT_DATA":{"0":{"BAT_INDEX":0,"BAT_RSOC":80},"1":{"BAT_INDEX":1,"BAT_RSOC":60}},"PM_DATA":{"6":{"PM_INDEX":6,"PM_POWER_L1":100}}}`,
+		},
+		{"keyed by position without index",
+			[]rscp.Message{{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{}}},
+			true,
+			`{"BAT_DATA":{"#0":{}}}`,
+		},
+		{"repeated nested container",
+			[]rscp.Message{{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				{Tag: rscp.BAT_DCB_INFO, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.BAT_DCB_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+					{Tag: rscp.BAT_DCB_SERIALNO, DataType: rscp.CString, Value: "A"},
+				}},
+			}}},
+			false,
+			`{"BAT_DATA":[{"BAT_INDEX":0,"BAT_DCB_INFO":[{"BAT_DCB_INDEX":0,"BAT_DCB_SERIALNO":"A"}]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(NewJSONStableMessages(tt.messages, tt.keyIndex))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("NewJSONStableMessages() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNewJSONSimpleMessages(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -158,3 +241,29 @@ func TestJSONSimpleMessage_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func Test_appendJSONComputed(t *testing.T) {
+	names := []string{"B", "A", "FAILED"}
+	values := map[string]float64{"A": 1.5, "B": -2}
+	tests := []struct {
+		output string
+		json   string
+		want   string
+	}{
+		{"jsonmerged", `{"EMS_POWER_PV":1}`, `{"EMS_POWER_PV":1,"B":-2,"A":1.5}`},
+		{"jsonmerged", `{}`, `{"B":-2,"A":1.5}`},
+		{"jsonsimple", `[{"EMS_POWER_PV":1}]`, `[{"EMS_POWER_PV":1},{"B":-2},{"A":1.5}]`},
+		{"json", `[]`, `[{"Tag":"B","DataType":"Double64","Value":-2},{"Tag":"A","DataType":"Double64","Value":1.5}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			got, err := appendJSONComputed([]byte(tt.json), tt.output, names, values)
+			if err != nil {
+				t.Fatalf("appendJSONComputed() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("appendJSONComputed() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
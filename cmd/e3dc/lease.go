@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/lease"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// leaseConf contains the flags of the active/standby leader election of the control commands
+type leaseConf struct {
+	store  string
+	holder string
+	ttl    time.Duration
+}
+
+// flags registers the lease flags
+func (l *leaseConf) flags(fs *flag.FlagSet) {
+	hostname, _ := os.Hostname()
+	fs.StringVar(&l.store, "lease", "", "shared lease file or lease server url (http://...) to run active/standby, only the leader applies changes (optional)")
+	fs.StringVar(&l.holder, "lease-holder", fmt.Sprintf("%s-%d", hostname, os.Getpid()), "unique name of this instance")
+	fs.DurationVar(&l.ttl, "lease-ttl", time.Second*15, "time a standby takes over after the leader stopped renewing")
+}
+
+// sender wraps the sender to only pass setters while this instance is the leader.
+//
+// returns the function to release the lease.
+func (l *leaseConf) sender(s rscp.Sender) (rscp.Sender, func()) {
+	if l.store == "" {
+		return s, func() {}
+	}
+	e := &lease.Elector{Holder: l.holder, TTL: l.ttl}
+	if strings.HasPrefix(l.store, "http://") || strings.HasPrefix(l.store, "https://") {
+		e.Store = lease.HTTPStore{URL: l.store}
+	} else {
+		e.Store = lease.FileStore{Path: l.store}
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := e.Run(stop); err != nil {
+			log.Errorf("lease: %s", err)
+		}
+	}()
+	return lease.Sender{Sender: s, Elector: e}, func() {
+		close(stop)
+		<-done
+	}
+}
+
+// logStepError logs the error of a control step, rejected changes of a standby are expected
+// but logged too, an instance which never becomes leader (i.e. of an unreachable lease store) is noticed.
+func logStepError(err error) {
+	if errors.Is(err, lease.ErrNotLeader) {
+		log.Infof("standby, changes not applied: %s", err)
+		return
+	}
+	log.Errorf("step failed: %s", err)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/jnovack/flag"
+	"github.com/spali/go-rscp/lease"
+)
+
+var leaseServerConf = struct {
+	listen string
+}{}
+
+var leaseServerCommand = command{
+	description: "serve leases for the active/standby failover of the control commands",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&leaseServerConf.listen, "listen", ":8080", "address to listen on, leases are served at /lease/<name>")
+	},
+	run: func(fs *flag.FlagSet) error {
+		mux := http.NewServeMux()
+		mux.Handle("/lease/", &lease.Server{})
+		return http.ListenAndServe(leaseServerConf.listen, mux)
+	},
+}
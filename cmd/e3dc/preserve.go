@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/preserve"
+)
+
+var preserveConf = struct {
+	connection connectionConf
+	lease      leaseConf
+	policy     string
+	state      string
+	interval   time.Duration
+	dryRun     bool
+}{}
+
+var preserveCommand = command{
+	description: "control the battery by seasonal policies to preserve its longevity",
+	flags: func(fs *flag.FlagSet) {
+		preserveConf.connection.flags(fs)
+		preserveConf.lease.flags(fs)
+		fs.StringVar(&preserveConf.policy, "policy", "preserve.json", "path to the policy config file")
+		fs.StringVar(&preserveConf.state, "state", "preserve-state.json", "path to the controller state file")
+		fs.DurationVar(&preserveConf.interval, "interval", time.Second*15, "interval between two steps (at most 30s to keep an action active)")
+		fs.BoolVar(&preserveConf.dryRun, "dryrun", false, "only log the actions without applying them")
+	},
+	run: runPreserve,
+}
+
+func runPreserve(fs *flag.FlagSet) error {
+	c, err := preserve.LoadConfig(preserveConf.policy)
+	if err != nil {
+		return err
+	}
+	s, err := preserve.LoadState(preserveConf.state)
+	if err != nil {
+		return err
+	}
+	client, err := preserveConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	sender, release := preserveConf.lease.sender(client)
+	defer release()
+	// info level to always log the actions
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(preserveConf.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := preserve.Step(sender, c, s, time.Now(), preserveConf.dryRun); err != nil {
+			logStepError(err)
+		}
+		if err := s.Save(preserveConf.state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/quality"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var qualityConf = struct {
+	connection connectionConf
+	config     string
+	state      string
+	samples    string
+	interval   time.Duration
+	report     string
+	from       string
+	to         string
+}{}
+
+var qualityCommand = command{
+	description: "monitor the grid voltage per phase and report under- and over-voltage events against EN 50160",
+	flags: func(fs *flag.FlagSet) {
+		qualityConf.connection.flags(fs)
+		fs.StringVar(&qualityConf.config, "config", "quality.json", "path to the monitor config file")
+		fs.StringVar(&qualityConf.state, "state", "quality-state.json", "path to the monitor state file")
+		fs.StringVar(&qualityConf.samples, "samples", "", "path to a file every sample is appended to as json line, empty to not log the samples")
+		fs.DurationVar(&qualityConf.interval, "interval", time.Second*10, "interval between two samples")
+		fs.StringVar(&qualityConf.report, "report", "", "print the report of the state instead of monitoring, possible values:\n"+
+			"  text: plain text for a complaint to the grid operator\n"+
+			"  json: json")
+		fs.StringVar(&qualityConf.from, "from", "", "first day of the report (YYYY-MM-DD), 7 days before -to if empty")
+		fs.StringVar(&qualityConf.to, "to", "", "day after the report (YYYY-MM-DD), tomorrow if empty")
+	},
+	run: runQuality,
+}
+
+// qualityPeriod returns the period of the report flags
+func qualityPeriod() (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	var err error
+	if qualityConf.to != "" {
+		if to, err = time.ParseInLocation(dateFormat, qualityConf.to, time.Local); err != nil {
+			return to, to, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+	from := to.AddDate(0, 0, -7) //nolint: gomnd
+	if qualityConf.from != "" {
+		if from, err = time.ParseInLocation(dateFormat, qualityConf.from, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	return from, to, nil
+}
+
+// printQualityReport prints the report of the state
+func printQualityReport(c quality.Config, s *quality.State) error {
+	from, to, err := qualityPeriod()
+	if err != nil {
+		return err
+	}
+	r := s.NewReport(c, from, to)
+	switch qualityConf.report {
+	case "text":
+		return quality.WriteText(os.Stdout, r)
+	case "json":
+		rb, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", rb)
+		return nil
+	}
+	return fmt.Errorf("report %s not supported", qualityConf.report)
+}
+
+// appendSample appends the sample as json line to the file
+func appendSample(path string, sample quality.Sample) error {
+	b, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runQuality(fs *flag.FlagSet) error {
+	c, err := quality.LoadConfig(qualityConf.config)
+	if err != nil {
+		return err
+	}
+	s, err := quality.LoadState(qualityConf.state)
+	if err != nil {
+		return err
+	}
+	if qualityConf.report != "" {
+		return printQualityReport(c, s)
+	}
+	client, err := qualityConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	components, err := rscp.Discover(client)
+	if err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+	if err := c.Resolve(components); err != nil {
+		return err
+	}
+	// info level to always log the events
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(qualityConf.interval)
+	defer ticker.Stop()
+	for {
+		sample, err := quality.Step(client, c, s, time.Now())
+		if err != nil {
+			logStepError(err)
+		} else if qualityConf.samples != "" {
+			if err := appendSample(qualityConf.samples, sample); err != nil {
+				return err
+			}
+		}
+		if err := s.Save(qualityConf.state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
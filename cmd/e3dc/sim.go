@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/simulator"
+)
+
+var simConf = struct {
+	devices  string
+	fleet    string
+	interval time.Duration
+}{}
+
+var simCommand = command{
+	description: "simulated devices on local ports for integration environments, writes the matching fleet config",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&simConf.devices, "devices", "sim.yaml", "path to the yaml file of the simulated devices")
+		fs.StringVar(&simConf.fleet, "fleet", "fleet.json", "path of the fleet config file written for the devices")
+		fs.DurationVar(&simConf.interval, "interval", time.Minute, "interval between two logs of the stats")
+	},
+	run: runSim,
+}
+
+func runSim(fs *flag.FlagSet) error {
+	c, err := simulator.LoadFleetConfig(simConf.devices)
+	if err != nil {
+		return err
+	}
+	f, err := simulator.StartFleet(c)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if err := jsonfile.Write(simConf.fleet, f.Sites()); err != nil {
+		return err
+	}
+	// info level to always log the stats
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	for _, s := range f.Sites().Sites {
+		log.Infof("simulated device %s listening on %s:%d", s.ID, s.Host, s.Port)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(simConf.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+		stats := f.Stats()
+		ids := make([]string, 0, len(stats))
+		for id := range stats {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			log.Infof("simulated device %s: %s", id, stats[id])
+		}
+	}
+}
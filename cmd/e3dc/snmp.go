@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/snmp"
+)
+
+var snmpConf = struct {
+	connection connectionConf
+	config     string
+	computed   string
+	state      string
+	interval   time.Duration
+	mib        bool
+}{}
+
+var snmpCommand = command{
+	description: "SNMP agent serving the telemetry as E3DC MIB with traps on alarm events",
+	flags: func(fs *flag.FlagSet) {
+		snmpConf.connection.flags(fs)
+		fs.StringVar(&snmpConf.config, "agent", "snmp.json", "path to the agent config file")
+		fs.StringVar(&snmpConf.computed, "computed", "", "path to a json file defining computed tags served in the computed tags table")
+		fs.StringVar(&snmpConf.state, "state", "snmp-state.json", "path to the agent state file (engine id and boots of SNMPv3)")
+		fs.DurationVar(&snmpConf.interval, "interval", time.Second*30, "interval between two polls of the system")
+		fs.BoolVar(&snmpConf.mib, "mib", false, "print the E3DC MIB of the config instead of serving")
+	},
+	run: runSNMP,
+}
+
+func runSNMP(fs *flag.FlagSet) error {
+	c, err := snmp.LoadConfig(snmpConf.config)
+	if err != nil {
+		return err
+	}
+	if snmpConf.mib {
+		return snmp.WriteMIB(os.Stdout, c)
+	}
+	s, err := snmp.LoadState(snmpConf.state)
+	if err != nil {
+		return err
+	}
+	// the boots have to be persisted before the agent answers
+	if err := s.Boot(c); err != nil {
+		return err
+	}
+	if err := s.Save(snmpConf.state); err != nil {
+		return err
+	}
+	monitor, err := snmp.NewMonitor(c)
+	if err != nil {
+		return err
+	}
+	if snmpConf.computed != "" {
+		tags, err := computed.Load(snmpConf.computed)
+		if err != nil {
+			return fmt.Errorf("could not load computed tags: %w", err)
+		}
+		monitor.SetComputed(tags)
+	}
+	client, err := snmpConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	agent, err := snmp.Listen(c, s)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = agent.Close() }()
+	// info level to always log the events
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	log.Infof("snmp agent listening on %s", agent.Addr())
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(snmpConf.interval)
+	defer ticker.Stop()
+	for {
+		objects, notifications, err := monitor.Step(client)
+		if err != nil {
+			logStepError(err)
+		}
+		agent.Update(objects)
+		for _, n := range notifications {
+			// failed targets are logged by the agent
+			_ = agent.Notify(n.Trap, n.Objects...)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
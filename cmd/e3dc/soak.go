@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jnovack/flag"
+	"github.com/spali/go-rscp/soak"
+)
+
+var soakConf = struct {
+	profile string
+}{}
+
+var soakCommand = command{
+	description: "run the client against the fault injecting simulator and report leaks and unexplained failures",
+	flags: func(fs *flag.FlagSet) {
+		fs.StringVar(&soakConf.profile, "profile", "short", "soak profile (short or long)")
+	},
+	run: func(fs *flag.FlagSet) error {
+		p, ok := soak.Profiles[soakConf.profile]
+		if !ok {
+			return fmt.Errorf("%s: %w", soakConf.profile, soak.ErrUnknownProfile)
+		}
+		r, runErr := soak.Run(p)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+		return runErr
+	},
+}
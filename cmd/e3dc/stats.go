@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/stats"
+)
+
+var statsConf = struct {
+	connection connectionConf
+	config     string
+	state      string
+	interval   time.Duration
+	report     string
+}{}
+
+var statsCommand = command{
+	description: "collect operating statistics (time in mode, battery utilization) and report them per day or month",
+	flags: func(fs *flag.FlagSet) {
+		statsConf.connection.flags(fs)
+		fs.StringVar(&statsConf.config, "config", "", "path to the statistics config file, empty for the defaults")
+		fs.StringVar(&statsConf.state, "state", "stats-state.json", "path to the statistics state file")
+		fs.DurationVar(&statsConf.interval, "interval", time.Second*10, "interval between two samples")
+		fs.StringVar(&statsConf.report, "report", "", "print the reports of the state as json instead of collecting, possible values:\n"+
+			"  daily: report of every day\n"+
+			"  monthly: report of every month")
+	},
+	run: runStats,
+}
+
+// printStatsReport prints the reports of the state
+func printStatsReport(s *stats.Statistics) error {
+	switch statsConf.report {
+	case "daily":
+		return stats.WriteJSON(os.Stdout, s.DailyReports())
+	case "monthly":
+		return stats.WriteJSON(os.Stdout, s.MonthlyReports())
+	}
+	return fmt.Errorf("report %s not supported", statsConf.report)
+}
+
+func runStats(fs *flag.FlagSet) error {
+	c := stats.DefaultConfig
+	if statsConf.config != "" {
+		var err error
+		if c, err = stats.LoadConfig(statsConf.config); err != nil {
+			return err
+		}
+	}
+	s, err := stats.Load(statsConf.state)
+	if err != nil {
+		return err
+	}
+	if statsConf.report != "" {
+		return printStatsReport(s)
+	}
+	client, err := statsConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	// info level to always log the gaps
+	if log.GetLevel() < log.InfoLevel {
+		log.SetLevel(log.InfoLevel)
+		log.SetOutput(os.Stderr)
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	ticker := time.NewTicker(statsConf.interval)
+	defer ticker.Stop()
+	for {
+		if err := stats.Collect(client, c, s, time.Now()); err != nil {
+			logStepError(err)
+		}
+		if err := s.Save(statsConf.state); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
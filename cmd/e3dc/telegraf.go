@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jnovack/flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/telegraf"
+)
+
+var telegrafConf = struct {
+	connection  connectionConf
+	measurement string
+	device      string
+	computed    string
+	interval    time.Duration
+	window      time.Duration
+}{}
+
+var telegrafCommand = command{
+	description: "execd input plugin of Telegraf printing the tags as InfluxDB line protocol on each signal",
+	arguments:   "TAG...",
+	flags: func(fs *flag.FlagSet) {
+		telegrafConf.connection.flags(fs)
+		fs.StringVar(&telegrafConf.measurement, "measurement", telegraf.DefaultMeasurement, "measurement of the lines")
+		fs.StringVar(&telegrafConf.device, "device", "", "device tag of the lines (default the serial number of the system)")
+		fs.StringVar(&telegrafConf.computed, "computed", "", "path to a json file defining computed tags printed as fields of the system")
+		fs.DurationVar(&telegrafConf.interval, "interval", 0, "interval between two gathers in addition to the signals of Telegraf on stdin, 0 to gather on signals only")
+		fs.DurationVar(&telegrafConf.window, "window", 0, "print the min, max, mean, last value and sample count of every field per window of this size instead of every gather, 0 to print every gather")
+	},
+	run: runTelegraf,
+}
+
+func runTelegraf(fs *flag.FlagSet) error {
+	var tags *computed.Tags
+	if telegrafConf.computed != "" {
+		var err error
+		if tags, err = computed.Load(telegrafConf.computed); err != nil {
+			return fmt.Errorf("could not load computed tags: %w", err)
+		}
+	}
+	input, err := telegraf.New(telegraf.Config{
+		Measurement: telegrafConf.measurement,
+		Device:      telegrafConf.device,
+		Tags:        fs.Args(),
+		Computed:    tags,
+	})
+	if err != nil {
+		return err
+	}
+	client, err := telegrafConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	// stdout is reserved for the lines, Telegraf logs stderr
+	if log.GetLevel() < log.WarnLevel {
+		log.SetLevel(log.WarnLevel)
+		log.SetOutput(os.Stderr)
+	}
+	// every line on stdin is a signal to gather, Telegraf closes stdin on shutdown
+	signals := make(chan struct{})
+	go func() {
+		defer close(signals)
+		s := bufio.NewScanner(os.Stdin)
+		for s.Scan() {
+			signals <- struct{}{}
+		}
+	}()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	var tick <-chan time.Time
+	if telegrafConf.interval > 0 {
+		ticker := time.NewTicker(telegrafConf.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	var summarizer *telegraf.Summarizer
+	if telegrafConf.window > 0 {
+		if summarizer, err = telegraf.NewSummarizer(telegrafConf.measurement, telegrafConf.window); err != nil {
+			return err
+		}
+	}
+	out := bufio.NewWriter(os.Stdout)
+	for {
+		select {
+		case <-stop:
+			return writeSummaries(out, summarizer)
+		case _, ok := <-signals:
+			if !ok {
+				return writeSummaries(out, summarizer)
+			}
+		case <-tick:
+		}
+		now := time.Now()
+		points, err := input.Gather(client, now)
+		if err != nil {
+			log.Errorf("gather failed: %s", err)
+			continue
+		}
+		if summarizer != nil {
+			points = summarizer.Add(now, points)
+		}
+		if err := writePoints(out, points); err != nil {
+			return err
+		}
+	}
+}
+
+// writePoints writes and flushes the points
+func writePoints(out *bufio.Writer, points []telegraf.Point) error {
+	if err := telegraf.WritePoints(out, points); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// writeSummaries writes the summaries of the partial window on shutdown
+func writeSummaries(out *bufio.Writer, summarizer *telegraf.Summarizer) error {
+	if summarizer == nil {
+		return nil
+	}
+	return writePoints(out, summarizer.Flush())
+}
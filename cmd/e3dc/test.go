@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jnovack/flag"
+	"github.com/spali/go-rscp/assertion"
+)
+
+var ErrMissingSuite = errors.New("missing suite argument")
+
+var testConf = struct {
+	connection connectionConf
+	junit      string
+	json       string
+}{}
+
+var testCommand = command{
+	description: "run the assertions of a yaml file against the system, fails if any assertion fails",
+	arguments:   "site.yaml",
+	flags: func(fs *flag.FlagSet) {
+		testConf.connection.flags(fs)
+		fs.StringVar(&testConf.junit, "junit", "", "path the results are written to as JUnit XML (optional)")
+		fs.StringVar(&testConf.json, "json", "", "path the results are written to as json (optional)")
+	},
+	run: runTest,
+}
+
+func runTest(fs *flag.FlagSet) error {
+	if fs.NArg() != 1 {
+		return ErrMissingSuite
+	}
+	suite, err := assertion.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	client, err := testConf.connection.newClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect() }()
+	report := suite.Run(client)
+	for _, r := range report.Results {
+		if r.Message == "" {
+			fmt.Printf("%-6s %s\n", r.Status, r.Name)
+			continue
+		}
+		fmt.Printf("%-6s %s: %s\n", r.Status, r.Name, r.Message)
+	}
+	if testConf.junit != "" {
+		if err := writeReport(testConf.junit, report.WriteJUnit); err != nil {
+			return err
+		}
+	}
+	if testConf.json != "" {
+		if err := writeReport(testConf.json, report.WriteJSON); err != nil {
+			return err
+		}
+	}
+	return report.Err()
+}
+
+// writeReport writes the report to the file at path
+func writeReport(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,176 @@
+// Package computed provides virtual tags computed from the values of other tags.
+//
+// computed tags are defined by name and expression (i.e. "SURPLUS": "EMS_POWER_PV - EMS_POWER_HOME").
+// References are response tags (i.e. EMS_POWER_PV), tags of an indexed component with the index appended
+// (i.e. BAT_RSOC@0) or other computed tags.
+package computed
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNameConflict = errors.New("computed tag name conflicts with a tag")
+	ErrCycle        = errors.New("computed tags reference each other in a cycle")
+	ErrMissingTag   = errors.New("missing tag in response")
+)
+
+// Tag is a computed tag
+type Tag struct {
+	Name       string
+	Expression *Expression
+}
+
+// Tags is a set of computed tags
+type Tags struct {
+	// tags in order of evaluation (dependencies first)
+	tags []Tag
+	// tags required to compute the tags by reference name
+	dependencies map[string]rscp.Query
+	// expressions by name
+	definitions map[string]string
+}
+
+// Load reads the definitions from a json file mapping names to expressions
+func Load(path string) (*Tags, error) {
+	d := map[string]string{}
+	if err := jsonfile.Read(path, &d); err != nil {
+		return nil, err
+	}
+	return New(d)
+}
+
+// New parses the definitions mapping names to expressions
+func New(definitions map[string]string) (*Tags, error) {
+	parsed := make(map[string]*Expression, len(definitions))
+	names := make([]string, 0, len(definitions))
+	for name, s := range definitions {
+		if _, err := rscp.TagString(name); err == nil {
+			return nil, fmt.Errorf("%s: %w", name, ErrNameConflict)
+		}
+		e, err := Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		parsed[name] = e
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	t := &Tags{dependencies: map[string]rscp.Query{}, definitions: make(map[string]string, len(definitions))}
+	for name, s := range definitions {
+		t.definitions[name] = s
+	}
+	// depth first topological sort, state 1 = in progress, 2 = done
+	state := make(map[string]int, len(names))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("%s: %w", strings.Join(append(path, name), " -> "), ErrCycle)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		e := parsed[name]
+		for _, r := range e.References() {
+			if _, isComputed := parsed[r]; isComputed {
+				if err := visit(r, append(path, name)); err != nil {
+					return err
+				}
+				continue
+			}
+			q, err := rscp.ParseQuery(r)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", name, err, ErrUnknownReference)
+			}
+			t.dependencies[r] = q
+		}
+		state[name] = 2
+		t.tags = append(t.tags, Tag{name, e})
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Names returns the names of the computed tags in order of evaluation
+func (t *Tags) Names() []string {
+	n := make([]string, len(t.tags))
+	for i, tag := range t.tags {
+		n[i] = tag.Name
+	}
+	return n
+}
+
+// Definitions returns the expressions of the computed tags by name (i.e. to extend them by further tags)
+func (t *Tags) Definitions() map[string]string {
+	d := make(map[string]string, len(t.definitions))
+	for name, s := range t.definitions {
+		d[name] = s
+	}
+	return d
+}
+
+// Requests returns the requests of all tags required to compute the tags.
+//
+// tags of the same component are requested in one container.
+func (t *Tags) Requests() ([]rscp.Message, error) {
+	refs := make([]string, 0, len(t.dependencies))
+	for r := range t.dependencies {
+		refs = append(refs, r)
+	}
+	sort.Strings(refs)
+	queries := make([]rscp.Query, 0, len(refs))
+	for _, r := range refs {
+		queries = append(queries, t.dependencies[r])
+	}
+	return rscp.QueryRequests(queries...)
+}
+
+// Evaluate computes the tags from the responses.
+//
+// a tag which can't be computed is missing in the result, the first error is returned.
+func (t *Tags) Evaluate(responses []rscp.Message) (map[string]float64, error) {
+	values := make(map[string]float64, len(t.tags))
+	lookup := func(name string) (float64, error) {
+		if v, isComputed := values[name]; isComputed {
+			return v, nil
+		}
+		q, ok := t.dependencies[name]
+		if !ok {
+			// computed tag which failed before
+			return 0, fmt.Errorf("%s: %w", name, ErrMissingTag)
+		}
+		m := q.Find(responses)
+		if m == nil {
+			return 0, fmt.Errorf("%s: %w", name, ErrMissingTag)
+		}
+		v, err := m.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+		return v, nil
+	}
+	var firstErr error
+	for _, tag := range t.tags {
+		v, err := tag.Expression.Evaluate(lookup)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", tag.Name, err)
+			}
+			continue
+		}
+		values[tag.Name] = v
+	}
+	return values, firstErr
+}
@@ -0,0 +1,120 @@
+package computed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions map[string]string
+		want        []string
+		wantErr     error
+	}{
+		{"dependencies first",
+			map[string]string{"A_SURPLUS_KW": "SURPLUS / 1000", "SURPLUS": "EMS_POWER_PV - EMS_POWER_HOME"},
+			[]string{"SURPLUS", "A_SURPLUS_KW"},
+			nil,
+		},
+		{"cycle",
+			map[string]string{"A": "B + 1", "B": "A + 1"},
+			nil,
+			ErrCycle,
+		},
+		{"name of a tag",
+			map[string]string{"EMS_POWER_PV": "1"},
+			nil,
+			ErrNameConflict,
+		},
+		{"unknown tag",
+			map[string]string{"A": "EMS_POWER_XYZ"},
+			nil,
+			ErrUnknownReference,
+		},
+		{"index on a tag without namespace",
+			map[string]string{"A": "EMS_POWER_PV@0"},
+			nil,
+			ErrUnknownReference,
+		},
+		{"syntax error",
+			map[string]string{"A": "1 +"},
+			nil,
+			ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.definitions)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(got.Names(), tt.want); diff != nil {
+				t.Errorf("Names() %s", diff)
+			}
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	tags, err := New(map[string]string{
+		"SURPLUS":     "EMS_POWER_PV - EMS_POWER_HOME - EMS_POWER_WB_ALL",
+		"GRID_IMPORT": "max(EMS_POWER_GRID, 0)",
+		"WB_L1":       "WB_PM_POWER_L1@1",
+		"BAT_POWER":   "BAT_MODULE_VOLTAGE@0 * BAT_CURRENT@0",
+		"BROKEN":      "EMS_POWER_ADD / 0",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	requests, err := tags.Requests()
+	if err != nil {
+		t.Fatalf("Requests() error = %v", err)
+	}
+	wantRequests := []rscp.Message{
+		{Tag: rscp.EMS_REQ_POWER_ADD},
+		{Tag: rscp.EMS_REQ_POWER_GRID},
+		{Tag: rscp.EMS_REQ_POWER_HOME},
+		{Tag: rscp.EMS_REQ_POWER_PV},
+		{Tag: rscp.EMS_REQ_POWER_WB_ALL},
+		{Tag: rscp.BAT_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_REQ_CURRENT},
+			{Tag: rscp.BAT_REQ_MODULE_VOLTAGE},
+		}},
+		{Tag: rscp.WB_REQ_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.WB_INDEX, DataType: rscp.UChar8, Value: uint8(1)},
+			{Tag: rscp.WB_REQ_PM_POWER_L1},
+		}},
+	}
+	if diff := deep.Equal(requests, wantRequests); diff != nil {
+		t.Errorf("Requests() = %v, want %v\n%s", requests, wantRequests, diff)
+	}
+	responses := []rscp.Message{
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(5000)},
+		{Tag: rscp.EMS_POWER_HOME, DataType: rscp.Int32, Value: int32(1000)},
+		{Tag: rscp.EMS_POWER_WB_ALL, DataType: rscp.Int32, Value: int32(1500)},
+		{Tag: rscp.EMS_POWER_GRID, DataType: rscp.Int32, Value: int32(-2500)},
+		{Tag: rscp.EMS_POWER_ADD, DataType: rscp.Int32, Value: int32(0)},
+		{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_MODULE_VOLTAGE, DataType: rscp.Float32, Value: float32(50)},
+			{Tag: rscp.BAT_CURRENT, DataType: rscp.Float32, Value: float32(-10)},
+		}},
+	}
+	got, err := tags.Evaluate(responses)
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Evaluate() error = %v, wantErr %v", err, ErrDivisionByZero)
+	}
+	want := map[string]float64{"SURPLUS": 2500, "GRID_IMPORT": 0, "BAT_POWER": -500}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Evaluate() = %v, want %v\n%s", got, want, diff)
+	}
+}
@@ -0,0 +1,306 @@
+package computed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrSyntax           = errors.New("syntax error")
+	ErrUnknownFunction  = errors.New("unknown function")
+	ErrArgumentCount    = errors.New("wrong number of arguments")
+	ErrDivisionByZero   = errors.New("division by zero")
+	ErrUnknownReference = errors.New("unknown reference")
+)
+
+// Lookup returns the value of a referenced name
+type Lookup func(name string) (float64, error)
+
+// node of the expression tree
+type node interface {
+	eval(lookup Lookup) (float64, error)
+}
+
+type number float64
+
+func (n number) eval(Lookup) (float64, error) {
+	return float64(n), nil
+}
+
+type reference string
+
+func (r reference) eval(lookup Lookup) (float64, error) {
+	return lookup(string(r))
+}
+
+type negation struct {
+	operand node
+}
+
+func (n negation) eval(lookup Lookup) (float64, error) {
+	v, err := n.operand.eval(lookup)
+	return -v, err
+}
+
+type binary struct {
+	operator    byte
+	left, right node
+}
+
+func (b binary) eval(lookup Lookup) (float64, error) {
+	l, err := b.left.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.right.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	switch b.operator {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	default:
+		if r == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return l / r, nil
+	}
+}
+
+// function callable in expressions, minArgs and maxArgs of -1 mean unlimited
+type function struct {
+	minArgs, maxArgs int
+	f                func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"min": {1, -1, func(args []float64) float64 {
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Min(v, a)
+		}
+		return v
+	}},
+	"max": {1, -1, func(args []float64) float64 {
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Max(v, a)
+		}
+		return v
+	}},
+	"abs":   {1, 1, func(args []float64) float64 { return math.Abs(args[0]) }},
+	"round": {1, 1, func(args []float64) float64 { return math.Round(args[0]) }},
+}
+
+type call struct {
+	function function
+	args     []node
+}
+
+func (c call) eval(lookup Lookup) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, a := range c.args {
+		var err error
+		if args[i], err = a.eval(lookup); err != nil {
+			return 0, err
+		}
+	}
+	return c.function.f(args), nil
+}
+
+// Expression is a parsed arithmetic expression.
+//
+// supported are numbers, references to tags or other computed tags, the operators + - * /,
+// parentheses and the functions min, max, abs and round.
+// Example:
+//  max(EMS_POWER_GRID, 0) / 1000
+type Expression struct {
+	source     string
+	root       node
+	references []string
+}
+
+// Parse parses the expression
+func Parse(s string) (*Expression, error) {
+	p := &parser{input: s}
+	p.next()
+	root, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		return nil, p.errorf("unexpected %q", p.token)
+	}
+	return &Expression{source: s, root: root, references: p.references}, nil
+}
+
+// References returns the names referenced by the expression in order of appearance
+func (e *Expression) References() []string {
+	return e.references
+}
+
+// Evaluate evaluates the expression resolving references by lookup
+func (e *Expression) Evaluate(lookup Lookup) (float64, error) {
+	return e.root.eval(lookup)
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// parser is a recursive descent parser with a single token lookahead
+type parser struct {
+	input      string
+	pos        int
+	token      string
+	tokenPos   int
+	references []string
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, p.tokenPos+1, fmt.Sprintf(format, a...))
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || r == '@' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// next advances to the next token, the token is empty at the end of the input
+func (p *parser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	p.tokenPos = p.pos
+	if p.pos >= len(p.input) {
+		p.token = ""
+		return
+	}
+	if !isNameRune(rune(p.input[p.pos])) {
+		p.pos++
+	} else {
+		for p.pos < len(p.input) && isNameRune(rune(p.input[p.pos])) {
+			p.pos++
+		}
+	}
+	p.token = p.input[p.tokenPos:p.pos]
+}
+
+// expression = term { ("+" | "-") term }
+func (p *parser) expression() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "+" || p.token == "-" {
+		op := p.token[0]
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+	return left, nil
+}
+
+// term = unary { ("*" | "/") unary }
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "*" || p.token == "/" {
+		op := p.token[0]
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+	return left, nil
+}
+
+// unary = "-" unary | primary
+func (p *parser) unary() (node, error) {
+	if p.token == "-" {
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negation{operand}, nil
+	}
+	return p.primary()
+}
+
+// primary = number | name | name "(" expression { "," expression } ")" | "(" expression ")"
+func (p *parser) primary() (node, error) {
+	switch t := p.token; {
+	case t == "":
+		return nil, p.errorf("unexpected end of expression")
+	case t == "(":
+		p.next()
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != ")" {
+			return nil, p.errorf("expected ) instead of %q", p.token)
+		}
+		p.next()
+		return n, nil
+	case unicode.IsDigit(rune(t[0])):
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", t)
+		}
+		p.next()
+		return number(v), nil
+	case isNameRune(rune(t[0])):
+		p.next()
+		if p.token == "(" {
+			return p.call(t)
+		}
+		p.references = append(p.references, t)
+		return reference(t), nil
+	default:
+		return nil, p.errorf("unexpected %q", t)
+	}
+}
+
+func (p *parser) call(name string) (node, error) {
+	f, ok := functions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownFunction)
+	}
+	c := call{function: f}
+	p.next()
+	for p.token != ")" {
+		if len(c.args) > 0 {
+			if p.token != "," {
+				return nil, p.errorf("expected , or ) instead of %q", p.token)
+			}
+			p.next()
+		}
+		a, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, a)
+	}
+	p.next()
+	if len(c.args) < f.minArgs || (f.maxArgs >= 0 && len(c.args) > f.maxArgs) {
+		return nil, fmt.Errorf("%s with %d arguments: %w", name, len(c.args), ErrArgumentCount)
+	}
+	return c, nil
+}
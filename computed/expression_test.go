@@ -0,0 +1,65 @@
+package computed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestExpression(t *testing.T) {
+	values := map[string]float64{"A": 3, "B": -4, "BAT_RSOC@0": 50}
+	lookup := func(name string) (float64, error) {
+		v, ok := values[name]
+		if !ok {
+			return 0, ErrUnknownReference
+		}
+		return v, nil
+	}
+	tests := []struct {
+		expression string
+		want       float64
+		references []string
+		wantErr    error
+	}{
+		{"1 + 2 * 3", 7, nil, nil},
+		{"(1 + 2) * 3", 9, nil, nil},
+		{"10 - 4 - 3", 3, nil, nil},
+		{"-A + 1.5", -1.5, []string{"A"}, nil},
+		{"A - -B", -1, []string{"A", "B"}, nil},
+		{"max(B, 0)", 0, []string{"B"}, nil},
+		{"MIN(A, B, 1)", -4, []string{"A", "B"}, nil},
+		{"abs(B) / 2", 2, []string{"B"}, nil},
+		{"round(A / 2)", 2, []string{"A"}, nil},
+		{"BAT_RSOC@0 / 100", 0.5, []string{"BAT_RSOC@0"}, nil},
+		{"A / (B + 4)", 0, []string{"A", "B"}, ErrDivisionByZero},
+		{"C", 0, []string{"C"}, ErrUnknownReference},
+		{"", 0, nil, ErrSyntax},
+		{"1 +", 0, nil, ErrSyntax},
+		{"(1", 0, nil, ErrSyntax},
+		{"1 2", 0, nil, ErrSyntax},
+		{"max(1,", 0, nil, ErrSyntax},
+		{"1 % 2", 0, nil, ErrSyntax},
+		{"sqrt(4)", 0, nil, ErrUnknownFunction},
+		{"abs(1, 2)", 0, nil, ErrArgumentCount},
+		{"max()", 0, nil, ErrArgumentCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := Parse(tt.expression)
+			if err == nil {
+				if diff := deep.Equal(e.References(), tt.references); diff != nil {
+					t.Errorf("References() %s", diff)
+				}
+				var got float64
+				got, err = e.Evaluate(lookup)
+				if err == nil && got != tt.want {
+					t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+				}
+			}
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package counter
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+const (
+	// maxHistorySpan limits the span of a single history request
+	maxHistorySpan = time.Hour * 24
+	// maxBackfills limits the number of backfills kept in the state
+	maxBackfills = 1000
+)
+
+// Gap of a counter not integrated because the interval between two samples exceeded the maximum gap
+type Gap struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Quality of backfilled energy
+type Quality string
+
+// QualityHistory is the energy of the history values at a lower resolution than the samples,
+// the energy of intervals partially within a gap is prorated.
+const QualityHistory Quality = "history"
+
+// Backfill of a gap of a counter
+type Backfill struct {
+	Counter string `json:"counter"`
+	Gap
+	// energy in Wh backfilled
+	Energy float64 `json:"energy"`
+	// quality and resolution in seconds of the backfilled energy
+	Quality    Quality `json:"quality"`
+	Resolution float64 `json:"resolution"`
+}
+
+// gapKey identifies a gap shared by multiple counters
+type gapKey struct {
+	from, to int64
+}
+
+// BackfillGaps backfills the gaps of the counters reconciled with the history by the values of DB_HISTORY_DATA_DAY
+// at the finest interval (rscp.HistoryMinInterval).
+//
+// a gap is backfilled once the history of the interval at its end is complete. The energy is added to the total and
+// days of the counter, accounted by day as backfilled and the time is no longer accounted as gap. Days already
+// reconciled are marked stale to be reconciled again. If a history request fails nothing is backfilled, the gaps
+// are backfilled by the next call.
+func BackfillGaps(sender rscp.Sender, c Config, s *State, now time.Time) ([]Backfill, error) {
+	interval := rscp.HistoryMinInterval
+	histories := map[gapKey][]rscp.HistoryValue{}
+	for _, counter := range c.Counters {
+		v := s.Counters[counter.Name]
+		if counter.Reconcile == "" || v == nil {
+			continue
+		}
+		for _, g := range v.Missing {
+			k := gapKey{g.From.UnixNano(), g.To.UnixNano()}
+			if _, exists := histories[k]; exists || !historyComplete(g, interval, now) {
+				continue
+			}
+			values, err := requestHistory(sender, g, interval)
+			if err != nil {
+				return nil, err
+			}
+			histories[k] = values
+		}
+	}
+	r := []Backfill{}
+	for _, counter := range c.Counters {
+		v := s.Counters[counter.Name]
+		if counter.Reconcile == "" || v == nil {
+			continue
+		}
+		var missing []Gap
+		for _, g := range v.Missing {
+			values, exists := histories[gapKey{g.From.UnixNano(), g.To.UnixNano()}]
+			if !exists {
+				missing = append(missing, g)
+				continue
+			}
+			r = append(r, Backfill{
+				Counter:    counter.Name,
+				Gap:        g,
+				Energy:     v.backfill(c, counter.reconcile, g, values, interval),
+				Quality:    QualityHistory,
+				Resolution: interval.Seconds(),
+			})
+			for day := range daySeconds(c.Location, g.From, g.To) {
+				s.markStale(day)
+			}
+		}
+		v.Missing = missing
+	}
+	s.Backfills = append(s.Backfills, r...)
+	if len(s.Backfills) > maxBackfills {
+		s.Backfills = s.Backfills[len(s.Backfills)-maxBackfills:]
+	}
+	return r, nil
+}
+
+// markStale marks a day already reconciled to be reconciled again
+func (s *State) markStale(day string) {
+	if day > s.Reconciled {
+		return
+	}
+	for _, d := range s.Stale {
+		if d == day {
+			return
+		}
+	}
+	s.Stale = append(s.Stale, day)
+	sort.Strings(s.Stale)
+	if len(s.Stale) > maxDays {
+		s.Stale = s.Stale[len(s.Stale)-maxDays:]
+	}
+}
+
+// historyComplete returns if the history of the interval at the end of the gap is complete
+func historyComplete(g Gap, interval time.Duration, now time.Time) bool {
+	return !now.Before(g.To.Truncate(interval).Add(interval))
+}
+
+// requestHistory requests the history values of the intervals overlapping the gap
+func requestHistory(sender rscp.Sender, g Gap, interval time.Duration) ([]rscp.HistoryValue, error) {
+	var r []rscp.HistoryValue
+	end := g.To.Truncate(interval).Add(interval)
+	for start := g.From.Truncate(interval); start.Before(end); start = start.Add(maxHistorySpan) {
+		span := end.Sub(start)
+		if span > maxHistorySpan {
+			span = maxHistorySpan
+		}
+		request, err := rscp.NewHistoryRequest(start, interval, span)
+		if err != nil {
+			return nil, err
+		}
+		responses, err := sender.SendMultiple([]rscp.Message{*request})
+		if err != nil {
+			return nil, err
+		}
+		values, err := rscp.HistoryValues(responses, start, span)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, values...)
+	}
+	return r, nil
+}
+
+// backfill adds the energy of the history values of the tag within the gap, returns the energy added
+func (v *Value) backfill(c Config, tag rscp.Tag, g Gap, values []rscp.HistoryValue, interval time.Duration) float64 {
+	if v.Days == nil {
+		v.Days = map[string]float64{}
+	}
+	if v.Backfilled == nil {
+		v.Backfilled = map[string]float64{}
+	}
+	total := 0.0
+	for _, hv := range values {
+		e, exists := hv.Values[tag]
+		from, to := hv.Time, hv.Time.Add(interval)
+		if g.From.After(from) {
+			from = g.From
+		}
+		if g.To.Before(to) {
+			to = g.To
+		}
+		if !exists || !to.After(from) {
+			continue
+		}
+		e *= to.Sub(from).Seconds() / interval.Seconds()
+		day := from.In(c.Location).Format(dayFormat)
+		v.Total += e
+		v.Days[day] += e
+		v.Backfilled[day] += e
+		total += e
+	}
+	pruneDays(v.Days)
+	pruneDays(v.Backfilled)
+	v.Gaps = math.Max(0, v.Gaps-g.To.Sub(g.From).Seconds())
+	for day, seconds := range daySeconds(c.Location, g.From, g.To) {
+		if gaps, exists := v.DayGaps[day]; exists && gaps > seconds {
+			v.DayGaps[day] = gaps - seconds
+		} else {
+			delete(v.DayGaps, day)
+		}
+	}
+	return total
+}
@@ -0,0 +1,196 @@
+// Package counter provides virtual energy counters integrated from polled power values.
+//
+// the power of a counter is a computed expression (i.e. "EMS_POWER_BAT" or "PM_POWER_L1@0"), the energy between two samples
+// is integrated by the trapezoidal rule. Intervals longer than the maximum gap (i.e. polling was down) are not integrated
+// but accounted as gap, and backfilled from the values of DB_HISTORY_DATA_DAY if the counter is reconciled.
+// The energy is accounted per day and reconciled daily against the sums of DB_HISTORY_DATA_DAY.
+package counter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoCounters       = errors.New("no counters configured")
+	ErrMissingName      = errors.New("counter without name")
+	ErrInvalidDirection = errors.New("invalid direction")
+	ErrInvalidReconcile = errors.New("not a DB_HISTORY_DATA_DAY sum tag")
+	ErrNameConflict     = errors.New("counter name conflicts with a computed tag")
+)
+
+// Direction of the power integrated
+type Direction string
+
+// all directions
+const (
+	// signed power, negative power decrements the counter
+	DirectionBoth Direction = ""
+	// only positive power
+	DirectionPositive Direction = "positive"
+	// only negative power, counted as positive energy
+	DirectionNegative Direction = "negative"
+)
+
+// Counter is a virtual energy counter
+type Counter struct {
+	// name of the counter, must not conflict with a tag
+	Name string `json:"name"`
+	// computed expression of the power in W
+	Power string `json:"power"`
+	// direction of the power integrated
+	Direction Direction `json:"direction"`
+	// DB_HISTORY_DATA_DAY sum tag (i.e. DB_BAT_POWER_IN) the daily energy is reconciled with, empty to not reconcile
+	Reconcile string `json:"reconcile"`
+	// parsed Reconcile
+	reconcile rscp.Tag
+}
+
+// Config of the counters
+type Config struct {
+	Counters []Counter `json:"counters"`
+	// intervals between two samples longer than this are not integrated
+	MaxGap time.Duration `json:"-"`
+	// deviation in % of the daily energy from the history a reconciliation is flagged
+	Tolerance float64 `json:"tolerance"`
+	// location used to assign the energy to days
+	Location *time.Location `json:"-"`
+	// computed tags the powers may reference, nil if none
+	computed *computed.Tags
+	// powers of the counters as computed tags
+	tags *computed.Tags
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	MaxGap:    time.Minute * 5,
+	Tolerance: 5,
+	Location:  time.Local,
+}
+
+// UnmarshalJSON unmarshals the config, the maximum gap is expected as duration string (i.e. "5m")
+// and the location as IANA time zone name (i.e. "Europe/Berlin").
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	tmp := struct {
+		*config
+		MaxGap   string `json:"maxGap"`
+		Location string `json:"location"`
+	}{config: (*config)(c)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.MaxGap != "" {
+		var err error
+		if c.MaxGap, err = time.ParseDuration(tmp.MaxGap); err != nil {
+			return fmt.Errorf("invalid max gap: %w", err)
+		}
+	}
+	if tmp.Location != "" {
+		var err error
+		if c.Location, err = time.LoadLocation(tmp.Location); err != nil {
+			return fmt.Errorf("invalid location: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// isHistorySum returns true if the tag is a sum of the history data
+func isHistorySum(tag rscp.Tag) bool {
+	for _, t := range rscp.HistorySumTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	if len(c.Counters) == 0 {
+		return ErrNoCounters
+	}
+	powers := map[string]string{}
+	if c.computed != nil {
+		powers = c.computed.Definitions()
+	}
+	for i := range c.Counters {
+		counter := &c.Counters[i]
+		if counter.Name == "" {
+			return fmt.Errorf("counter at index %d: %w", i, ErrMissingName)
+		}
+		switch counter.Direction {
+		case DirectionBoth, DirectionPositive, DirectionNegative:
+		default:
+			return fmt.Errorf("counter %s %q: %w", counter.Name, counter.Direction, ErrInvalidDirection)
+		}
+		if counter.Reconcile != "" {
+			tag, err := rscp.TagString(counter.Reconcile)
+			if err != nil || !isHistorySum(tag) {
+				return fmt.Errorf("counter %s %s: %w", counter.Name, counter.Reconcile, ErrInvalidReconcile)
+			}
+			counter.reconcile = tag
+		}
+		if _, exists := powers[counter.Name]; exists {
+			return fmt.Errorf("counter %s: %w", counter.Name, ErrNameConflict)
+		}
+		powers[counter.Name] = counter.Power
+	}
+	var err error
+	if c.tags, err = computed.New(powers); err != nil {
+		return err
+	}
+	if c.MaxGap <= 0 {
+		c.MaxGap = defaultConfig.MaxGap
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = defaultConfig.Tolerance
+	}
+	if c.Location == nil {
+		c.Location = defaultConfig.Location
+	}
+	return nil
+}
+
+// SetComputed makes the computed tags available to the powers of the counters (i.e. "SURPLUS" defined as
+// "EMS_POWER_PV - EMS_POWER_HOME")
+func (c *Config) SetComputed(t *computed.Tags) error {
+	c.computed = t
+	return c.check()
+}
+
+// Requests returns the requests of all tags required to compute the powers of the counters
+func (c Config) Requests() ([]rscp.Message, error) {
+	return c.tags.Requests()
+}
+
+// Powers computes the powers of the counters and the computed tags from the responses, a power which can't be
+// computed is missing
+func (c Config) Powers(responses []rscp.Message) (map[string]float64, error) {
+	return c.tags.Evaluate(responses)
+}
+
+// Names returns the names of the counters in the order of the config
+func (c Config) Names() []string {
+	names := make([]string, len(c.Counters))
+	for i, counter := range c.Counters {
+		names[i] = counter.Name
+	}
+	return names
+}
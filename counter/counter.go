@@ -0,0 +1,360 @@
+package counter
+
+import (
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const (
+	dayFormat = "2006-01-02"
+	// maxDays limits the number of days kept per counter
+	maxDays = 62
+	// maxReconciliations limits the number of reconciliations kept in the state
+	maxReconciliations = 1000
+	// maxMissing limits the number of gaps kept per counter until backfilled
+	maxMissing = 100
+	// fullPercent is the reference in percent
+	fullPercent = 100
+	// maxDeferral limits the time after the end of a day its reconciliation waits for samples and backfills
+	maxDeferral = time.Hour * 24
+)
+
+// Value of a counter
+type Value struct {
+	// energy in Wh since the counter was started
+	Total float64 `json:"total"`
+	// energy in Wh by day (YYYY-MM-DD), limited to the most recent days
+	Days map[string]float64 `json:"days"`
+	// time in seconds not integrated because of gaps
+	Gaps float64 `json:"gaps"`
+	// time in seconds by day not integrated because of gaps, limited to the most recent days
+	DayGaps map[string]float64 `json:"day_gaps,omitempty"`
+	// gaps not backfilled yet, limited to the most recent ones
+	Missing []Gap `json:"missing,omitempty"`
+	// energy in Wh by day backfilled from the history at a lower resolution (included in Days)
+	Backfilled map[string]float64 `json:"backfilled,omitempty"`
+	// time and power in W of the last sample
+	Last  time.Time `json:"last"`
+	Power float64   `json:"power"`
+}
+
+// energy returns the energy in Wh of the direction between two powers in W over the hours
+func energy(d Direction, p1, p2, hours float64) float64 {
+	switch d {
+	case DirectionBoth:
+		return (p1 + p2) / 2 * hours
+	case DirectionNegative:
+		p1, p2 = -p1, -p2
+	case DirectionPositive:
+	}
+	switch {
+	case p1 >= 0 && p2 >= 0:
+		return (p1 + p2) / 2 * hours
+	case p1 <= 0 && p2 <= 0:
+		return 0
+	}
+	// the sign changed, only the triangle up to or from the zero crossing is positive
+	positive, negative := math.Max(p1, p2), -math.Min(p1, p2)
+	return positive / 2 * hours * positive / (positive + negative)
+}
+
+// integrate adds the energy between two samples of the same day
+func (v *Value) integrate(d Direction, from time.Time, p1 float64, to time.Time, p2 float64, day string) {
+	e := energy(d, p1, p2, to.Sub(from).Hours())
+	v.Total += e
+	v.Days[day] += e
+	pruneDays(v.Days)
+}
+
+// pruneDays removes the oldest days exceeding the days kept
+func pruneDays(days map[string]float64) {
+	if len(days) <= maxDays {
+		return
+	}
+	keys := make([]string, 0, len(days))
+	for d := range days {
+		keys = append(keys, d)
+	}
+	sort.Strings(keys)
+	for _, d := range keys[:len(keys)-maxDays] {
+		delete(days, d)
+	}
+}
+
+// daySeconds returns the seconds of the interval within each day of the location
+func daySeconds(loc *time.Location, from, to time.Time) map[string]float64 {
+	r := map[string]float64{}
+	for from.Before(to) {
+		f := from.In(loc)
+		end := time.Date(f.Year(), f.Month(), f.Day()+1, 0, 0, 0, 0, loc)
+		if end.After(to) {
+			end = to
+		}
+		r[f.Format(dayFormat)] += end.Sub(from).Seconds()
+		from = end
+	}
+	return r
+}
+
+// add integrates the power up to the sample, an interval crossing midnight is split between the days
+func (v *Value) add(c Config, d Direction, t time.Time, p float64) {
+	if v.Days == nil {
+		v.Days = map[string]float64{}
+	}
+	last, lastPower := v.Last, v.Power
+	v.Last, v.Power = t, p
+	switch dt := t.Sub(last); {
+	case last.IsZero() || dt <= 0:
+		// first sample or the clock went backwards
+		return
+	case dt > c.MaxGap:
+		v.Gaps += dt.Seconds()
+		if v.DayGaps == nil {
+			v.DayGaps = map[string]float64{}
+		}
+		for day, seconds := range daySeconds(c.Location, last, t) {
+			v.DayGaps[day] += seconds
+		}
+		pruneDays(v.DayGaps)
+		v.Missing = append(v.Missing, Gap{From: last, To: t})
+		if len(v.Missing) > maxMissing {
+			v.Missing = v.Missing[len(v.Missing)-maxMissing:]
+		}
+		return
+	}
+	l := last.In(c.Location)
+	midnight := time.Date(l.Year(), l.Month(), l.Day()+1, 0, 0, 0, 0, c.Location)
+	if t.After(midnight) {
+		// linear interpolation of the power at midnight
+		pm := lastPower + (p-lastPower)*midnight.Sub(last).Seconds()/t.Sub(last).Seconds()
+		v.integrate(d, last, lastPower, midnight, pm, l.Format(dayFormat))
+		last, lastPower = midnight, pm
+	}
+	v.integrate(d, last, lastPower, t, p, last.In(c.Location).Format(dayFormat))
+}
+
+// Reconciliation of the energy of a day with the history
+type Reconciliation struct {
+	Day     string `json:"day"`
+	Counter string `json:"counter"`
+	// energy in Wh integrated
+	Integrated float64 `json:"integrated"`
+	// energy in Wh of the history
+	Reference float64 `json:"reference"`
+	// deviation of the integrated energy from the reference in %
+	Deviation float64 `json:"deviation"`
+	// deviation exceeds the tolerance
+	Deviates bool `json:"deviates"`
+	// time in seconds of the day not integrated because of gaps
+	Gaps float64 `json:"gaps"`
+}
+
+// State of the counters, persisted between runs
+type State struct {
+	Counters map[string]*Value `json:"counters"`
+	// reconciliations, limited to the most recent ones
+	Reconciliations []Reconciliation `json:"reconciliations"`
+	// backfills of gaps, limited to the most recent ones
+	Backfills []Backfill `json:"backfills,omitempty"`
+	// last day reconciled
+	Reconciled string `json:"reconciled"`
+	// days reconciled before a backfill changed their energy, reconciled again
+	Stale []string `json:"stale,omitempty"`
+}
+
+// LoadState reads the state from a json file, a missing file results in an empty state
+func LoadState(path string) (*State, error) {
+	s := &State{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to a json file
+func (s *State) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// Add integrates the powers of the counters up to the time, counters without power are skipped
+func (s *State) Add(c Config, t time.Time, powers map[string]float64) {
+	if s.Counters == nil {
+		s.Counters = map[string]*Value{}
+	}
+	for _, counter := range c.Counters {
+		p, exists := powers[counter.Name]
+		if !exists {
+			continue
+		}
+		v := s.Counters[counter.Name]
+		if v == nil {
+			v = &Value{}
+			s.Counters[counter.Name] = v
+		}
+		v.add(c, counter.Direction, t, p)
+	}
+}
+
+// Totals returns the total energy in Wh of every counter
+func (s *State) Totals() map[string]float64 {
+	totals := make(map[string]float64, len(s.Counters))
+	for name, v := range s.Counters {
+		totals[name] = v.Total
+	}
+	return totals
+}
+
+// Step requests the powers of the counters and integrates them up to now
+func Step(sender rscp.Sender, c Config, s *State, now time.Time) error {
+	requests, err := c.Requests()
+	if err != nil {
+		return err
+	}
+	responses, err := sender.SendMultiple(requests)
+	if err != nil {
+		return err
+	}
+	powers, err := c.Powers(responses)
+	// integrate the powers available even if some failed
+	s.Add(c, now, powers)
+	return err
+}
+
+// ReconcileDue reconciles the previous days not reconciled yet once they are complete, and again the days
+// changed by a backfill after their reconciliation.
+//
+// a day is complete once every reconciled counter has a sample after the day and no gap within the day waits
+// for its backfill, so gaps crossing midnight are backfilled before. An incomplete day is reconciled anyway
+// after maxDeferral. Returns the reconciliations up to a failed one.
+func ReconcileDue(sender rscp.Sender, c Config, s *State, now time.Time) ([]Reconciliation, error) {
+	r := []Reconciliation{}
+	n := now.In(c.Location)
+	// the day before yesterday may still be deferred
+	for i := -2; i < 0; i++ {
+		start := time.Date(n.Year(), n.Month(), n.Day()+i, 0, 0, 0, 0, c.Location)
+		end := time.Date(n.Year(), n.Month(), n.Day()+i+1, 0, 0, 0, 0, c.Location)
+		if s.Reconciled >= start.Format(dayFormat) || !s.complete(c, start, end, now) {
+			continue
+		}
+		rc, err := Reconcile(sender, c, s, start)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, rc...)
+	}
+	for _, day := range append([]string{}, s.Stale...) {
+		start, err := time.ParseInLocation(dayFormat, day, c.Location)
+		if err != nil {
+			return r, err
+		}
+		if !s.complete(c, start, start.AddDate(0, 0, 1), now) {
+			continue
+		}
+		rc, err := Reconcile(sender, c, s, start)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, rc...)
+	}
+	return r, nil
+}
+
+// complete returns if the day from start to end is complete for the reconciliation or deferred long enough
+func (s *State) complete(c Config, start, end, now time.Time) bool {
+	if !now.Before(end.Add(maxDeferral)) {
+		return true
+	}
+	for _, counter := range c.Counters {
+		v := s.Counters[counter.Name]
+		if counter.Reconcile == "" || v == nil {
+			continue
+		}
+		if v.Last.Before(end) {
+			return false
+		}
+		for _, g := range v.Missing {
+			if g.From.Before(end) && g.To.After(start) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Reconcile compares the energy of the day with the sums of the history.
+//
+// the history is requested for the day starting at midnight in the location of the config.
+func Reconcile(sender rscp.Sender, c Config, s *State, day time.Time) ([]Reconciliation, error) {
+	d := day.In(c.Location)
+	start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, c.Location)
+	span := time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, c.Location).Sub(start)
+	request, err := rscp.NewHistoryRequest(start, span, span)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := sender.SendMultiple([]rscp.Message{*request})
+	if err != nil {
+		return nil, err
+	}
+	sums, err := rscp.HistorySums(responses)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcile(c, start.Format(dayFormat), sums), nil
+}
+
+// reconcile compares the energy of the day with the sums of the history, replacing a previous reconciliation of the day
+func (s *State) reconcile(c Config, day string, sums map[rscp.Tag]float64) []Reconciliation {
+	r := []Reconciliation{}
+	for _, counter := range c.Counters {
+		reference, exists := sums[counter.reconcile]
+		if counter.Reconcile == "" || !exists {
+			continue
+		}
+		rc := Reconciliation{Day: day, Counter: counter.Name, Reference: reference}
+		if v := s.Counters[counter.Name]; v != nil {
+			rc.Integrated, rc.Gaps = v.Days[day], v.DayGaps[day]
+		}
+		if reference != 0 {
+			rc.Deviation = (rc.Integrated - reference) / math.Abs(reference) * fullPercent
+		}
+		rc.Deviates = math.Abs(rc.Deviation) > c.Tolerance
+		if rc.Deviates {
+			log.Warnf("counter %s of %s deviates by %.1f%% (%.0fWh integrated, %s %.0fWh)",
+				counter.Name, day, rc.Deviation, rc.Integrated, counter.Reconcile, reference)
+		}
+		r = append(r, rc)
+	}
+	counters := map[string]bool{}
+	for _, rc := range r {
+		counters[rc.Counter] = true
+	}
+	kept := s.Reconciliations[:0]
+	for _, rc := range s.Reconciliations {
+		if rc.Day != day || !counters[rc.Counter] {
+			kept = append(kept, rc)
+		}
+	}
+	s.Reconciliations = append(kept, r...)
+	if len(s.Reconciliations) > maxReconciliations {
+		s.Reconciliations = s.Reconciliations[len(s.Reconciliations)-maxReconciliations:]
+	}
+	if day > s.Reconciled {
+		s.Reconciled = day
+	}
+	stale := s.Stale[:0]
+	for _, d := range s.Stale {
+		if d != day {
+			stale = append(stale, d)
+		}
+	}
+	s.Stale = stale
+	return r
+}
@@ -0,0 +1,334 @@
+package counter
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr error
+	}{
+		{"defaults", `{"counters": [{"name": "BAT", "power": "EMS_POWER_BAT"}]}`, nil},
+		{"no counters", `{}`, ErrNoCounters},
+		{"missing name", `{"counters": [{"power": "EMS_POWER_BAT"}]}`, ErrMissingName},
+		{"direction", `{"counters": [{"name": "BAT", "power": "EMS_POWER_BAT", "direction": "up"}]}`, ErrInvalidDirection},
+		{"reconcile", `{"counters": [{"name": "BAT", "power": "EMS_POWER_BAT", "reconcile": "EMS_POWER_BAT"}]}`, ErrInvalidReconcile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "counters.json")
+			if err := ioutil.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadConfig(path)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.MaxGap != defaultConfig.MaxGap || got.Tolerance != defaultConfig.Tolerance || got.Location != defaultConfig.Location {
+				t.Errorf("LoadConfig() = %+v, want defaults", got)
+			}
+		})
+	}
+}
+
+func TestEnergy(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction Direction
+		p1, p2    float64
+		want      float64
+	}{
+		{"both", DirectionBoth, 1000, -1000, 0},
+		{"positive", DirectionPositive, 1000, 2000, 1500},
+		{"positive of negative", DirectionPositive, -1000, -2000, 0},
+		{"positive crossing zero", DirectionPositive, 3000, -1000, 1125},
+		{"negative crossing zero", DirectionNegative, 3000, -1000, 125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := energy(tt.direction, tt.p1, tt.p2, 1); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("energy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testConfig(t *testing.T, counters ...Counter) Config {
+	t.Helper()
+	c := Config{Counters: counters, Location: time.UTC}
+	if err := c.check(); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestState_Add(t *testing.T) {
+	c := testConfig(t,
+		Counter{Name: "BAT_IN", Power: "EMS_POWER_BAT", Direction: DirectionPositive},
+		Counter{Name: "BAT_OUT", Power: "EMS_POWER_BAT", Direction: DirectionNegative},
+	)
+	t0 := time.Date(2021, 6, 1, 23, 58, 0, 0, time.UTC)
+	s := &State{}
+	for _, sample := range []struct {
+		minutes float64
+		power   float64
+	}{
+		{0, 600},
+		// crosses midnight, 1200W at midnight
+		{4, 1800},
+		// gap
+		{20, 1800},
+		{21, -600},
+	} {
+		s.Add(c, t0.Add(time.Duration(sample.minutes*float64(time.Minute))), map[string]float64{"BAT_IN": sample.power, "BAT_OUT": sample.power})
+	}
+	in, out := s.Counters["BAT_IN"], s.Counters["BAT_OUT"]
+	// 2min of 600W up to 1200W, 2min of 1200W up to 1800W and 45s of 1800W down to 0W
+	wantIn := map[string]float64{"2021-06-01": 30, "2021-06-02": 50 + 11.25}
+	if diff := deep.Equal(in.Days, wantIn); diff != nil {
+		t.Errorf("Add() days = %v, want %v\n%s", in.Days, wantIn, diff)
+	}
+	if in.Total != 91.25 || in.Gaps != 16*60 {
+		t.Errorf("Add() = %+v, want total 91.25 and gaps 960", in)
+	}
+	if diff := deep.Equal(in.DayGaps, map[string]float64{"2021-06-02": 960}); diff != nil {
+		t.Errorf("Add() day gaps %s", diff)
+	}
+	// 15s of 0W up to 600W
+	if out.Total != 1.25 {
+		t.Errorf("Add() total = %v, want 1.25", out.Total)
+	}
+	// without power
+	s.Add(c, t0.Add(time.Hour), map[string]float64{})
+	if in.Total != 91.25 {
+		t.Errorf("Add() without power changed total to %v", in.Total)
+	}
+}
+
+func TestStep(t *testing.T) {
+	c := testConfig(t, Counter{Name: "BAT_IN", Power: "EMS_POWER_BAT", Direction: DirectionPositive})
+	sender := rscptest.NewSender([]rscp.Message{{Tag: rscp.EMS_POWER_BAT, DataType: rscp.Int32, Value: int32(3600)}})
+	s := &State{}
+	t0 := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{t0, t0.Add(time.Minute)} {
+		if err := Step(sender, c, s, now); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+	}
+	if diff := deep.Equal(s.Totals(), map[string]float64{"BAT_IN": 60}); diff != nil {
+		t.Errorf("Totals() %s", diff)
+	}
+}
+
+func TestConfig_SetComputed(t *testing.T) {
+	tags, err := computed.New(map[string]string{"SURPLUS": "EMS_POWER_PV - EMS_POWER_HOME"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Config{Counters: []Counter{{Name: "EXPORT", Power: "SURPLUS", Direction: DirectionPositive}}, Location: time.UTC}
+	if err := c.SetComputed(tags); err != nil {
+		t.Fatalf("SetComputed() error = %v", err)
+	}
+	sender := rscptest.NewSender([]rscp.Message{
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(5000)},
+		{Tag: rscp.EMS_POWER_HOME, DataType: rscp.Int32, Value: int32(1400)},
+	})
+	s := &State{}
+	t0 := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{t0, t0.Add(time.Minute)} {
+		if err := Step(sender, c, s, now); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+	}
+	if diff := deep.Equal(s.Totals(), map[string]float64{"EXPORT": 60}); diff != nil {
+		t.Errorf("Totals() %s", diff)
+	}
+	c = testConfig(t, Counter{Name: "SURPLUS", Power: "EMS_POWER_PV"})
+	if err := c.SetComputed(tags); !errors.Is(err, ErrNameConflict) {
+		t.Errorf("SetComputed() error = %v, want %v", err, ErrNameConflict)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	c := testConfig(t,
+		Counter{Name: "BAT_IN", Power: "EMS_POWER_BAT", Direction: DirectionPositive, Reconcile: "DB_BAT_POWER_IN"},
+		Counter{Name: "BAT_OUT", Power: "EMS_POWER_BAT", Direction: DirectionNegative, Reconcile: "DB_BAT_POWER_OUT"},
+		Counter{Name: "PV", Power: "EMS_POWER_PV"},
+	)
+	s := &State{Counters: map[string]*Value{
+		"BAT_IN":  {Days: map[string]float64{"2021-06-01": 1020}, Gaps: 120, DayGaps: map[string]float64{"2021-05-31": 60, "2021-06-01": 60}},
+		"BAT_OUT": {Days: map[string]float64{"2021-06-01": 1100}},
+	}}
+	sender := rscptest.NewSender([]rscp.Message{{Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.DB_SUM_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.DB_BAT_POWER_IN, DataType: rscp.Float32, Value: float32(1000)},
+			{Tag: rscp.DB_BAT_POWER_OUT, DataType: rscp.Float32, Value: float32(1000)},
+		}},
+	}}})
+	got, err := Reconcile(sender, c, s, time.Date(2021, 6, 1, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := []Reconciliation{
+		{Day: "2021-06-01", Counter: "BAT_IN", Integrated: 1020, Reference: 1000, Deviation: 2, Gaps: 60},
+		{Day: "2021-06-01", Counter: "BAT_OUT", Integrated: 1100, Reference: 1000, Deviation: 10, Deviates: true},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Reconcile() = %+v, want %+v\n%s", got, want, diff)
+	}
+	if s.Reconciled != "2021-06-01" || len(s.Reconciliations) != 2 {
+		t.Errorf("Reconcile() state = %+v", s)
+	}
+	if _, err := Reconcile(rscptest.NewSender([]rscp.Message{}), c, s, time.Now()); !errors.Is(err, rscp.ErrMissingHistory) {
+		t.Errorf("Reconcile() error = %v, want %v", err, rscp.ErrMissingHistory)
+	}
+}
+
+func TestReconcileDue(t *testing.T) {
+	c := testConfig(t, Counter{Name: "BAT_IN", Power: "EMS_POWER_BAT", Direction: DirectionPositive, Reconcile: "DB_BAT_POWER_IN"})
+	midnight := time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC)
+	s := &State{Reconciled: "2021-05-31"}
+	// gap crossing midnight
+	for _, t := range []time.Time{midnight.Add(-time.Minute * 30), midnight.Add(time.Minute * 10)} {
+		s.Add(c, t, map[string]float64{"BAT_IN": 0})
+	}
+	if diff := deep.Equal(s.Counters["BAT_IN"].DayGaps, map[string]float64{"2021-06-01": 1800, "2021-06-02": 600}); diff != nil {
+		t.Errorf("Add() day gaps %s", diff)
+	}
+	sender := rscptest.NewSender([]rscp.Message{{Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.DB_SUM_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.DB_BAT_POWER_IN, DataType: rscp.Float32, Value: float32(1000)},
+		}},
+	}}})
+	tests := []struct {
+		name       string
+		now        time.Time
+		reconciled string
+	}{
+		{"waiting for the backfill", midnight.Add(time.Minute * 10), "2021-05-31"},
+		{"backfilled", midnight.Add(time.Minute * 20), "2021-06-01"},
+		{"already reconciled", midnight.Add(time.Minute * 30), "2021-06-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "backfilled" {
+				s.Counters["BAT_IN"].Missing = nil
+			}
+			if _, err := ReconcileDue(sender, c, s, tt.now); err != nil {
+				t.Fatalf("ReconcileDue() error = %v", err)
+			}
+			if s.Reconciled != tt.reconciled {
+				t.Errorf("ReconcileDue() reconciled %s, want %s", s.Reconciled, tt.reconciled)
+			}
+		})
+	}
+	if len(sender.Requests) != 1 || len(s.Reconciliations) != 1 || s.Reconciliations[0].Gaps != 1800 {
+		t.Errorf("ReconcileDue() = %+v with %d requests", s.Reconciliations, len(sender.Requests))
+	}
+	// deferred at most a day by a gap not backfilled
+	s = &State{Reconciled: "2021-05-31", Counters: map[string]*Value{
+		"BAT_IN": {Last: midnight, Missing: []Gap{{From: midnight.Add(-time.Hour), To: midnight}}},
+	}}
+	for _, now := range []time.Time{midnight.Add(maxDeferral - time.Second), midnight.Add(maxDeferral)} {
+		if _, err := ReconcileDue(sender, c, s, now); err != nil {
+			t.Fatalf("ReconcileDue() error = %v", err)
+		}
+	}
+	if s.Reconciled != "2021-06-01" || len(s.Reconciliations) != 1 {
+		t.Errorf("ReconcileDue() deferred = %+v", s.Reconciliations)
+	}
+}
+
+func TestBackfillGaps(t *testing.T) {
+	c := testConfig(t,
+		Counter{Name: "BAT_IN", Power: "EMS_POWER_BAT", Direction: DirectionPositive, Reconcile: "DB_BAT_POWER_IN"},
+		Counter{Name: "PV", Power: "EMS_POWER_PV"},
+	)
+	t0 := time.Date(2021, 6, 1, 12, 5, 0, 0, time.UTC)
+	// the day was reconciled before the backfill
+	s := &State{Reconciled: "2021-06-01", Reconciliations: []Reconciliation{{Day: "2021-06-01", Counter: "BAT_IN", Reference: 300}}}
+	for _, t := range []time.Time{t0, t0.Add(time.Minute * 45)} {
+		s.Add(c, t, map[string]float64{"BAT_IN": 0, "PV": 0})
+	}
+	gap := Gap{From: t0, To: t0.Add(time.Minute * 45)}
+	if diff := deep.Equal(s.Counters["BAT_IN"].Missing, []Gap{gap}); diff != nil {
+		t.Fatalf("Add() missing %s", diff)
+	}
+	// 100Wh in each interval of the hour
+	var values []rscp.Message
+	for _, position := range []float32{0, 25, 50, 75} {
+		values = append(values, rscp.Message{Tag: rscp.DB_VALUE_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.DB_GRAPH_INDEX, DataType: rscp.Float32, Value: position},
+			{Tag: rscp.DB_BAT_POWER_IN, DataType: rscp.Float32, Value: float32(100)},
+		}})
+	}
+	sender := rscptest.NewSender([]rscp.Message{{Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Container, Value: values}})
+
+	// the history of the last interval isn't complete yet
+	if got, err := BackfillGaps(sender, c, s, gap.To.Add(time.Minute*5)); err != nil || len(got) != 0 || len(sender.Requests) != 0 {
+		t.Fatalf("BackfillGaps() = %v, %v with %d requests, want none", got, err, len(sender.Requests))
+	}
+	got, err := BackfillGaps(sender, c, s, gap.To.Add(time.Minute*10))
+	if err != nil {
+		t.Fatalf("BackfillGaps() error = %v", err)
+	}
+	for i := range got {
+		got[i].Energy = math.Round(got[i].Energy*1000) / 1000
+	}
+	// 10 of 15 minutes of the first and 5 of 15 minutes of the last interval
+	want := []Backfill{{Counter: "BAT_IN", Gap: gap, Energy: 300, Quality: QualityHistory, Resolution: 900}}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("BackfillGaps() = %+v, want %+v\n%s", got, want, diff)
+	}
+	request, _ := rscp.NewHistoryRequest(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), time.Minute*15, time.Hour)
+	if diff := deep.Equal(sender.Requests, [][]rscp.Message{{*request}}); diff != nil {
+		t.Errorf("BackfillGaps() requests %s", diff)
+	}
+	in := s.Counters["BAT_IN"]
+	if math.Abs(in.Days["2021-06-01"]-300) > 1e-9 || math.Abs(in.Backfilled["2021-06-01"]-300) > 1e-9 ||
+		in.Gaps != 0 || len(in.DayGaps) != 0 || len(in.Missing) != 0 || len(s.Backfills) != 1 {
+		t.Errorf("BackfillGaps() = %+v", in)
+	}
+	// counters without history keep the gap
+	if len(s.Counters["PV"].Missing) != 1 {
+		t.Errorf("BackfillGaps() PV = %+v", s.Counters["PV"])
+	}
+	// the changed day is reconciled again
+	if diff := deep.Equal(s.Stale, []string{"2021-06-01"}); diff != nil {
+		t.Fatalf("BackfillGaps() stale %s", diff)
+	}
+	sums := rscptest.NewSender([]rscp.Message{{Tag: rscp.DB_HISTORY_DATA_DAY, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.DB_SUM_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.DB_BAT_POWER_IN, DataType: rscp.Float32, Value: float32(300)},
+		}},
+	}}})
+	if _, err := ReconcileDue(sums, c, s, time.Date(2021, 6, 3, 1, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("ReconcileDue() error = %v", err)
+	}
+	if len(s.Stale) != 0 || len(s.Reconciliations) != 1 || math.Abs(s.Reconciliations[0].Integrated-300) > 1e-9 {
+		t.Errorf("ReconcileDue() of stale day = %+v, stale %v", s.Reconciliations, s.Stale)
+	}
+
+	s.Add(c, gap.To.Add(time.Hour), map[string]float64{"BAT_IN": 0})
+	if _, err := BackfillGaps(rscptest.NewSender([]rscp.Message{}), c, s, gap.To.Add(time.Hour*2)); !errors.Is(err, rscp.ErrMissingHistory) {
+		t.Errorf("BackfillGaps() error = %v, want %v", err, rscp.ErrMissingHistory)
+	}
+	if len(s.Counters["BAT_IN"].Missing) != 1 {
+		t.Errorf("BackfillGaps() failed removed the gap %+v", s.Counters["BAT_IN"])
+	}
+}
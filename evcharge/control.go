@@ -0,0 +1,181 @@
+package evcharge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// externDataLen is the length of the WB_EXTERN_DATA of WB_REQ_SET_EXTERN
+const externDataLen = 6
+
+// Status of the wallbox and home battery
+type Status struct {
+	// energy charged in the current session in Wh
+	Charged float64
+	// state of charge of the home battery in %
+	SoC float64
+}
+
+// wallboxNamespace returns the namespace of the wallbox tags
+func wallboxNamespace() rscp.Namespace {
+	n, _ := rscp.NamespaceByName("WB")
+	return n
+}
+
+// Resolve sets the wallbox to the first discovered one if not configured, fails if the wallbox isn't discovered
+func (c *Config) Resolve(components rscp.Components) error {
+	index, err := components.Resolve(wallboxNamespace().Name, c.Wallbox)
+	if err != nil {
+		return err
+	}
+	c.Wallbox = &index
+	return nil
+}
+
+// wallbox returns the WB_INDEX of the resolved wallbox
+func (c Config) wallbox() (uint16, error) {
+	if c.Wallbox == nil {
+		return 0, ErrUnresolved
+	}
+	return *c.Wallbox, nil
+}
+
+// Requests returns the requests required to read the status
+func Requests(c Config) ([]rscp.Message, error) {
+	index, err := c.wallbox()
+	if err != nil {
+		return nil, err
+	}
+	wb, err := wallboxNamespace().NewRequest(index, rscp.WB_REQ_EXTERN_DATA_ALL)
+	if err != nil {
+		return nil, err
+	}
+	return []rscp.Message{*wb, *rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil)}, nil
+}
+
+// NewStatus reads the status from the responses of the requests returned by Requests
+func NewStatus(c Config, responses []rscp.Message) (Status, error) {
+	s := Status{}
+	index, err := c.wallbox()
+	if err != nil {
+		return s, err
+	}
+	n := wallboxNamespace()
+	wb := rscp.FindIndexed(responses, n.ResponseContainer, n.IndexTag, index)
+	if wb == nil {
+		return s, fmt.Errorf("missing %s with %s %d in response", n.ResponseContainer, n.IndexTag, index)
+	}
+	all := rscp.FindTag(wb.Value.([]rscp.Message), rscp.WB_EXTERN_DATA_ALL)
+	if all == nil || all.DataType != rscp.Container {
+		return s, fmt.Errorf("missing %s in response", rscp.WB_EXTERN_DATA_ALL)
+	}
+	// byte 3-6: uint32 energy in Wh
+	data := rscp.FindTag(all.Value.([]rscp.Message), rscp.WB_EXTERN_DATA)
+	if data == nil {
+		return s, fmt.Errorf("missing %s in %s", rscp.WB_EXTERN_DATA, rscp.WB_EXTERN_DATA_ALL)
+	}
+	b, ok := data.Value.([]byte)
+	if !ok || len(b) < 6 { //nolint: gomnd
+		return s, fmt.Errorf("%s %v: %w", rscp.WB_EXTERN_DATA_ALL, data.Value, rscp.ErrDataTypeValueMismatch)
+	}
+	s.Charged = float64(binary.LittleEndian.Uint32(b[2:6]))
+	soc := rscp.FindTag(responses, rscp.EMS_BAT_SOC)
+	if soc == nil {
+		return s, fmt.Errorf("missing %s in response", rscp.EMS_BAT_SOC)
+	}
+	if s.SoC, err = soc.Float64(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// SetRequests returns the requests to apply the slot
+func SetRequests(c Config, s Slot) ([]rscp.Message, error) {
+	index, err := c.wallbox()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, externDataLen)
+	data[0] = byte(s.Mode)
+	data[1] = s.Current
+	wb, err := wallboxNamespace().NewRequest(index,
+		rscp.WB_REQ_SET_EXTERN, rscp.WB_EXTERN_DATA, data, rscp.WB_EXTERN_DATA_LEN, uint8(externDataLen))
+	if err != nil {
+		return nil, err
+	}
+	var batteryToCar uint8
+	if s.BatteryToCar {
+		batteryToCar = 1
+	}
+	return []rscp.Message{*wb, *rscp.NewMessage(rscp.EMS_REQ_SET_BATTERY_TO_CAR_MODE, batteryToCar)}, nil
+}
+
+// Controller re-plans and applies the charging on every step
+type Controller struct {
+	Config   Config
+	Goal     Goal
+	Forecast []Forecast
+	Prices   []Price
+	// only log the plan without applying it
+	DryRun bool
+	// slot applied last
+	applied *Slot
+}
+
+// Step reads the status, re-plans and applies the current slot.
+//
+// after the deadline or when the goal is reached the wallbox is set to charge from the pv surplus only.
+func (ctl *Controller) Step(sender rscp.Sender, now time.Time) (Plan, error) {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		status    Status
+		err       error
+	)
+	if requests, err = Requests(ctl.Config); err != nil {
+		return Plan{}, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return Plan{}, err
+	}
+	if status, err = NewStatus(ctl.Config, responses); err != nil {
+		return Plan{}, err
+	}
+	p := NewPlan(ctl.Config, now, ctl.Goal, status.Charged, status.SoC, ctl.Forecast, ctl.Prices)
+	s, ok := p.Current()
+	if !ok || p.Remaining <= 0 {
+		s = Slot{Start: now, Mode: ModeSun, Current: ctl.Config.MaxCurrent}
+	}
+	if p.Shortfall > 0 {
+		log.Warnf("%.0f Wh of %.0f Wh can't be charged until %s", p.Shortfall, p.Remaining, ctl.Goal.Deadline)
+	}
+	if ctl.applied != nil && ctl.applied.Mode == s.Mode && ctl.applied.Current == s.Current &&
+		ctl.applied.BatteryToCar == s.BatteryToCar {
+		return p, nil
+	}
+	log.Infof("charged %.0f Wh, remaining %.0f Wh: %s mode at %dA, battery to car %t",
+		status.Charged, p.Remaining, s.Mode, s.Current, s.BatteryToCar)
+	if ctl.DryRun {
+		ctl.applied = &s
+		return p, nil
+	}
+	if requests, err = SetRequests(ctl.Config, s); err != nil {
+		return p, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return p, err
+	}
+	// 0xFF if battery to car can't be activated (i.e. battery before car is active)
+	if m := rscp.FindTag(responses, rscp.EMS_SET_BATTERY_TO_CAR_MODE); m == nil || m.DataType == rscp.Error || m.Value == uint8(0xFF) {
+		return p, fmt.Errorf("%s not accepted: %v", rscp.EMS_REQ_SET_BATTERY_TO_CAR_MODE, responses)
+	}
+	if m := rscp.FindTag(responses, rscp.WB_SET_EXTERN); m == nil || m.DataType == rscp.Error {
+		return p, fmt.Errorf("%s not accepted: %v", rscp.WB_REQ_SET_EXTERN, responses)
+	}
+	ctl.applied = &s
+	return p, nil
+}
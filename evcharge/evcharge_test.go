@@ -0,0 +1,174 @@
+package evcharge
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// testConfig charges with 230V * 3 phases * 6..16A = 4140..11040W in hourly slots
+var testConfig = Config{Phases: 3, Voltage: 230, MinCurrent: 6, MaxCurrent: 16, SlotDuration: time.Hour}
+
+var start = time.Date(2021, 6, 1, 18, 0, 0, 0, time.UTC)
+
+func hour(h int) time.Time {
+	return start.Add(time.Duration(h) * time.Hour)
+}
+
+func summary(p Plan) [][]float64 {
+	s := [][]float64{}
+	for _, slot := range p.Slots {
+		s = append(s, []float64{math.Round(slot.Solar), math.Round(slot.Battery), math.Round(slot.Grid), float64(slot.Mode), float64(slot.Current)})
+	}
+	return s
+}
+
+func TestNewPlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		goal      Goal
+		charged   float64
+		soc       float64
+		forecast  []Forecast
+		prices    []Price
+		want      [][]float64
+		shortfall float64
+	}{
+		{"grid as late as possible",
+			testConfig, Goal{10000, hour(3)}, 0, 0, nil, nil,
+			[][]float64{{0, 0, 0, 1, 16}, {0, 0, 0, 1, 16}, {0, 0, 10000, 2, 15}},
+			0,
+		},
+		{"already charged",
+			testConfig, Goal{10000, hour(2)}, 10000, 0, nil, nil,
+			[][]float64{{0, 0, 0, 1, 16}, {0, 0, 0, 1, 16}},
+			0,
+		},
+		{"solar first, surplus below minimum current ignored",
+			testConfig, Goal{10000, hour(3)}, 0, 0,
+			[]Forecast{{hour(0), 5000}, {hour(1), 3000}, {hour(2), 0}}, nil,
+			[][]float64{{5000, 0, 0, 1, 16}, {0, 0, 0, 1, 16}, {0, 0, 5000, 2, 8}},
+			0,
+		},
+		{"battery to car keeps the reserve",
+			Config{Phases: 3, Voltage: 230, MinCurrent: 6, MaxCurrent: 16, SlotDuration: time.Hour, BatteryCapacity: 10000, BatteryReserve: 20},
+			Goal{10000, hour(2)}, 0, 50, nil, nil,
+			[][]float64{{0, 3000, 0, 2, 6}, {0, 0, 7000, 2, 11}},
+			0,
+		},
+		{"cheapest slots",
+			testConfig, Goal{15000, hour(3)}, 0, 0, nil,
+			[]Price{{hour(0), 0.2}, {hour(1), 0.1}, {hour(2), 0.3}},
+			[][]float64{{0, 0, 3960, 2, 6}, {0, 0, 11040, 2, 16}, {0, 0, 0, 1, 16}},
+			0,
+		},
+		{"required power in the first slot",
+			testConfig, Goal{20000, hour(2)}, 0, 0, []Forecast{{hour(1), 11040}},
+			[]Price{{hour(0), 0.3}, {hour(1), 0.1}},
+			[][]float64{{0, 0, 8960, 2, 13}, {11040, 0, 0, 1, 16}},
+			0,
+		},
+		{"shortfall",
+			testConfig, Goal{25000, hour(2)}, 0, 0, nil, nil,
+			[][]float64{{0, 0, 11040, 2, 16}, {0, 0, 11040, 2, 16}},
+			2920,
+		},
+		{"deadline passed",
+			testConfig, Goal{1000, hour(0)}, 0, 0, nil, nil,
+			[][]float64{},
+			1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlan(tt.config, start, tt.goal, tt.charged, tt.soc, tt.forecast, tt.prices)
+			if diff := deep.Equal(summary(p), tt.want); diff != nil {
+				t.Errorf("NewPlan() = %v, want %v\n%s", summary(p), tt.want, diff)
+			}
+			if math.Round(p.Shortfall) != tt.shortfall {
+				t.Errorf("NewPlan() shortfall = %v, want %v", p.Shortfall, tt.shortfall)
+			}
+		})
+	}
+}
+
+// simulator of a wallbox charging the car
+type simulator struct {
+	now          time.Time
+	charged      float64
+	surplus      float64
+	mode         Mode
+	current      uint8
+	batteryToCar bool
+}
+
+func (s *simulator) SendMultiple(requests []rscp.Message) ([]rscp.Message, error) {
+	wb := requests[0].Value.([]rscp.Message)
+	if wb[1].Tag == rscp.WB_REQ_SET_EXTERN {
+		data := wb[1].Value.([]rscp.Message)[0].Value.([]byte)
+		s.mode, s.current = Mode(data[0]), data[1]
+		s.batteryToCar = requests[1].Value.(uint8) == 1
+		return []rscp.Message{
+			{Tag: rscp.WB_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.WB_INDEX, DataType: rscp.UChar8, Value: uint8(0)},
+				{Tag: rscp.WB_SET_EXTERN, DataType: rscp.None},
+			}},
+			{Tag: rscp.EMS_SET_BATTERY_TO_CAR_MODE, DataType: rscp.UChar8, Value: requests[1].Value},
+		}, nil
+	}
+	data := make([]byte, 7)
+	binary.LittleEndian.PutUint32(data[2:6], uint32(s.charged))
+	return []rscp.Message{
+		{Tag: rscp.WB_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.WB_INDEX, DataType: rscp.UChar8, Value: uint8(0)},
+			{Tag: rscp.WB_EXTERN_DATA_ALL, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.WB_EXTERN_DATA, DataType: rscp.ByteArray, Value: data},
+				{Tag: rscp.WB_EXTERN_DATA_LEN, DataType: rscp.UChar8, Value: uint8(7)},
+			}},
+		}},
+		{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: uint8(0)},
+	}, nil
+}
+
+func (s *simulator) advance(c Config, d time.Duration) {
+	power := c.power(s.current)
+	if s.mode == ModeSun {
+		power = math.Min(power, s.surplus)
+		if power < c.power(c.MinCurrent) {
+			power = 0
+		}
+	}
+	s.charged += power * d.Hours()
+	s.now = s.now.Add(d)
+}
+
+func TestController_Step(t *testing.T) {
+	c := Config{Phases: 3, Voltage: 230, MinCurrent: 6, MaxCurrent: 16, SlotDuration: time.Minute * 15}
+	// the forecasted surplus does not show up, the goal has to be reached from the grid
+	ctl := &Controller{Config: c, Goal: Goal{30000, hour(6)}, Forecast: []Forecast{{hour(0), 11040}}}
+	sim := &simulator{now: start}
+	if _, err := ctl.Step(sim, sim.now); !errors.Is(err, ErrUnresolved) {
+		t.Fatalf("Step() without wallbox error = %v, want %v", err, ErrUnresolved)
+	}
+	if err := ctl.Config.Resolve(rscp.Components{"WB": {{Namespace: "WB", Index: 0}}}); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	for sim.now.Before(ctl.Goal.Deadline) {
+		if _, err := ctl.Step(sim, sim.now); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		sim.advance(c, time.Minute)
+	}
+	if sim.charged < ctl.Goal.Energy {
+		t.Errorf("charged %.0f Wh until the deadline, want %.0f Wh", sim.charged, ctl.Goal.Energy)
+	}
+	if sim.mode != ModeSun || sim.batteryToCar {
+		t.Errorf("after the goal is reached mode = %s, battery to car %t, want sun mode", sim.mode, sim.batteryToCar)
+	}
+}
@@ -0,0 +1,334 @@
+// Package evcharge plans the charging of an electric vehicle to reach a target energy until a departure time.
+//
+// the plan prefers the forecasted pv surplus, then the home battery (battery to car) and charges the rest
+// from the grid in the cheapest slots. The plan is recalculated on every step with the energy actually charged,
+// as soon as the target can't be reached otherwise the wallbox charges from the grid with the required power.
+package evcharge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+var (
+	ErrInvalidCurrent = errors.New("invalid charge current")
+	ErrInvalidPhases  = errors.New("invalid number of phases")
+	ErrInvalidMode    = errors.New("invalid wallbox mode")
+	ErrUnresolved     = errors.New("wallbox not resolved")
+)
+
+// Mode of the wallbox
+type Mode uint8
+
+// all wallbox modes as defined by WB_REQ_SET_EXTERN
+const (
+	// charge from the pv surplus only
+	ModeSun Mode = 1
+	// charge from pv, battery and grid
+	ModeMixed Mode = 2
+)
+
+// String converter function for Mode
+func (m Mode) String() string {
+	switch m {
+	case ModeSun:
+		return "sun"
+	case ModeMixed:
+		return "mixed"
+	default:
+		return fmt.Sprintf("Mode(%d)", m)
+	}
+}
+
+// MarshalJSON marshals the mode by name
+func (m Mode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON unmarshals the mode by name
+func (m *Mode) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	for _, mode := range []Mode{ModeSun, ModeMixed} {
+		if mode.String() == name {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("%q: %w", name, ErrInvalidMode)
+}
+
+// Config of the wallbox and home battery
+type Config struct {
+	// WB_INDEX of the wallbox, nil to use the first discovered wallbox (see Resolve)
+	Wallbox *uint16 `json:"wallbox"`
+	// number of phases used to charge
+	Phases uint8 `json:"phases"`
+	// voltage of a phase in V
+	Voltage float64 `json:"voltage"`
+	// minimum charge current in A
+	MinCurrent uint8 `json:"minCurrent"`
+	// maximum charge current in A
+	MaxCurrent uint8 `json:"maxCurrent"`
+	// usable capacity of the home battery in Wh, 0 disables battery to car
+	BatteryCapacity float64 `json:"batteryCapacity"`
+	// state of charge in % kept in the home battery
+	BatteryReserve float64 `json:"batteryReserve"`
+	// duration of a plan slot
+	SlotDuration time.Duration `json:"-"`
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	Phases:       3,
+	Voltage:      230,
+	MinCurrent:   6,
+	MaxCurrent:   16,
+	SlotDuration: time.Minute * 15,
+}
+
+// epsilon is the energy in Wh considered as rounding error
+const epsilon = 1e-6
+
+// maxWallboxCurrent is the maximum current supported by WB_REQ_SET_EXTERN
+const maxWallboxCurrent = 32
+
+// UnmarshalJSON unmarshals the config, the slot duration is expected as duration string (i.e. "15m")
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	tmp := struct {
+		*config
+		SlotDuration string `json:"slotDuration"`
+	}{config: (*config)(c)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.SlotDuration != "" {
+		var err error
+		if c.SlotDuration, err = time.ParseDuration(tmp.SlotDuration); err != nil {
+			return fmt.Errorf("invalid slot duration: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// DefaultConfig returns the config with all default values
+func DefaultConfig() (Config, error) {
+	c := Config{}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	if c.Phases == 0 {
+		c.Phases = defaultConfig.Phases
+	}
+	if c.Phases > 3 { //nolint: gomnd
+		return fmt.Errorf("%d: %w", c.Phases, ErrInvalidPhases)
+	}
+	if c.Voltage <= 0 {
+		c.Voltage = defaultConfig.Voltage
+	}
+	if c.MinCurrent == 0 {
+		c.MinCurrent = defaultConfig.MinCurrent
+	}
+	if c.MaxCurrent == 0 {
+		c.MaxCurrent = defaultConfig.MaxCurrent
+	}
+	if c.MinCurrent > c.MaxCurrent || c.MaxCurrent > maxWallboxCurrent {
+		return fmt.Errorf("%dA - %dA: %w", c.MinCurrent, c.MaxCurrent, ErrInvalidCurrent)
+	}
+	if c.SlotDuration <= 0 {
+		c.SlotDuration = defaultConfig.SlotDuration
+	}
+	return nil
+}
+
+// power returns the charge power in W at the current
+func (c Config) power(current uint8) float64 {
+	return float64(c.Phases) * c.Voltage * float64(current)
+}
+
+// current returns the current in A required to charge with the power, limited to the allowed currents
+func (c Config) current(power float64) uint8 {
+	i := math.Ceil(power / (float64(c.Phases) * c.Voltage))
+	return uint8(math.Max(float64(c.MinCurrent), math.Min(float64(c.MaxCurrent), i)))
+}
+
+// Goal of the charging
+type Goal struct {
+	// energy to charge in Wh
+	Energy float64 `json:"energy"`
+	// time the energy has to be charged
+	Deadline time.Time `json:"deadline"`
+}
+
+// Forecast of the pv surplus in W available for the car, starting at the time until the next forecast
+type Forecast struct {
+	Start   time.Time `json:"start"`
+	Surplus float64   `json:"surplus"`
+}
+
+// Price of the grid energy, starting at the time until the next price
+type Price struct {
+	Start time.Time `json:"start"`
+	Price float64   `json:"price"`
+}
+
+// at returns the value of the series valid at the time, sorted ascending by start
+func at(starts []time.Time, t time.Time) int {
+	i := sort.Search(len(starts), func(i int) bool { return starts[i].After(t) })
+	return i - 1
+}
+
+// Slot of the plan
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Mode  Mode      `json:"mode"`
+	// charge current limit in A
+	Current uint8 `json:"current"`
+	// home battery discharges into the car
+	BatteryToCar bool `json:"batteryToCar"`
+	// planned energy by source in Wh
+	Solar   float64 `json:"solar"`
+	Battery float64 `json:"battery"`
+	Grid    float64 `json:"grid"`
+}
+
+// Energy planned in Wh
+func (s Slot) Energy() float64 {
+	return s.Solar + s.Battery + s.Grid
+}
+
+// Plan of the charging until the deadline
+type Plan struct {
+	Slots []Slot `json:"slots"`
+	// energy left to charge in Wh
+	Remaining float64 `json:"remaining"`
+	// energy in Wh which can't be charged until the deadline
+	Shortfall float64 `json:"shortfall"`
+}
+
+// Current returns the slot to apply now
+func (p Plan) Current() (Slot, bool) {
+	if len(p.Slots) == 0 {
+		return Slot{}, false
+	}
+	return p.Slots[0], true
+}
+
+// NewPlan plans the charging of the energy not charged yet.
+//
+// soc is the state of charge of the home battery in %, forecast and prices are optional and sorted by start.
+// Without prices the grid energy is planned as late as possible to use the surplus not forecasted.
+func NewPlan(c Config, now time.Time, g Goal, charged, soc float64, forecast []Forecast, prices []Price) Plan {
+	p := Plan{Remaining: math.Max(0, g.Energy-charged)}
+	if !now.Before(g.Deadline) {
+		p.Shortfall = p.Remaining
+		return p
+	}
+	for start := now; start.Before(g.Deadline); start = start.Add(c.SlotDuration) {
+		end := start.Add(c.SlotDuration)
+		if end.After(g.Deadline) {
+			end = g.Deadline
+		}
+		p.Slots = append(p.Slots, Slot{Start: start, End: end, Mode: ModeSun, Current: c.MaxCurrent})
+	}
+	hours := func(s Slot) float64 { return s.End.Sub(s.Start).Hours() }
+	capacity := func(s Slot) float64 { return c.power(c.MaxCurrent)*hours(s) - s.Energy() }
+	remaining := p.Remaining
+
+	// energy required in the first slot, otherwise the goal can't be reached even at maximum power.
+	// It's charged from the grid as the forecasted surplus is uncertain.
+	if required := remaining - c.power(c.MaxCurrent)*g.Deadline.Sub(p.Slots[0].End).Hours(); required > 0 {
+		p.Slots[0].Grid = math.Min(required, capacity(p.Slots[0]))
+		remaining -= p.Slots[0].Grid
+	}
+
+	// pv surplus below the minimum current can't be used
+	starts := make([]time.Time, len(forecast))
+	for i, f := range forecast {
+		starts[i] = f.Start
+	}
+	for i := range p.Slots {
+		s := &p.Slots[i]
+		f := at(starts, s.Start)
+		if f < 0 || forecast[f].Surplus < c.power(c.MinCurrent) {
+			continue
+		}
+		s.Solar = math.Min(remaining, math.Min(forecast[f].Surplus*hours(*s), capacity(*s)))
+		remaining -= s.Solar
+	}
+
+	if c.BatteryCapacity > 0 {
+		battery := math.Max(0, soc-c.BatteryReserve) / 100 * c.BatteryCapacity //nolint: gomnd
+		for i := range p.Slots {
+			s := &p.Slots[i]
+			e := math.Min(math.Min(remaining, battery), capacity(*s))
+			if e <= 0 {
+				continue
+			}
+			s.Battery = e
+			battery -= e
+			remaining -= e
+		}
+	}
+
+	// cheapest slots first, without prices the latest slots
+	order := make([]int, len(p.Slots))
+	for i := range order {
+		order[i] = len(p.Slots) - 1 - i
+	}
+	priceStarts := make([]time.Time, len(prices))
+	for i, pr := range prices {
+		priceStarts[i] = pr.Start
+	}
+	price := func(s Slot) float64 {
+		if i := at(priceStarts, s.Start); i >= 0 {
+			return prices[i].Price
+		}
+		return 0
+	}
+	sort.SliceStable(order, func(a, b int) bool { return price(p.Slots[order[a]]) < price(p.Slots[order[b]]) })
+	for _, i := range order {
+		s := &p.Slots[i]
+		e := math.Min(remaining, capacity(*s))
+		if e <= 0 {
+			continue
+		}
+		s.Grid += e
+		remaining -= e
+	}
+	if remaining > epsilon {
+		p.Shortfall = remaining
+	}
+
+	for i := range p.Slots {
+		s := &p.Slots[i]
+		s.BatteryToCar = s.Battery > 0
+		if s.Battery > 0 || s.Grid > 0 {
+			s.Mode = ModeMixed
+			s.Current = c.current(s.Energy() / hours(*s))
+		}
+	}
+	return p
+}
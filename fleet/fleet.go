@@ -0,0 +1,134 @@
+// Package fleet contains the configuration of multiple E3DC systems (sites) used by the fleet tooling.
+package fleet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoSites       = errors.New("no sites configured")
+	ErrMissingSiteID = errors.New("site without id")
+	ErrDuplicateSite = errors.New("duplicate site id")
+)
+
+// Site is a single E3DC system of the fleet
+type Site struct {
+	// unique id of the site
+	ID string `json:"id"`
+	// e3dc server host
+	Host string `json:"host"`
+	// e3dc server host port
+	Port uint16 `json:"port,omitempty"`
+	// e3dc user
+	User string `json:"user"`
+	// e3dc password
+	Password string `json:"password"`
+	// rscp key
+	Key string `json:"key"`
+	// retries of a failed request, requests which may have been applied are only retried if idempotent
+	Retries uint `json:"retries,omitempty"`
+	// region used to group the sites in reports (i.e. "south")
+	Region string `json:"region,omitempty"`
+	// location of the site, used to find peers of similar irradiation
+	Location *Location `json:"location,omitempty"`
+	// orientation of the pv modules
+	Orientation *Orientation `json:"orientation,omitempty"`
+}
+
+// Location in decimal degrees
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Orientation of the pv modules in degrees
+type Orientation struct {
+	// compass direction the modules face (i.e. 180 = south)
+	Azimuth float64 `json:"azimuth"`
+	// angle to the horizontal (0 = flat)
+	Tilt float64 `json:"tilt"`
+}
+
+// Config of the fleet
+type Config struct {
+	// values used for every site not defining it's own (except the id, location and orientation)
+	Defaults Site `json:"defaults"`
+	// all sites of the fleet
+	Sites []Site `json:"sites"`
+}
+
+// Load reads the fleet config from a json file
+func Load(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if required
+func (c *Config) check() error {
+	if len(c.Sites) == 0 {
+		return ErrNoSites
+	}
+	ids := map[string]bool{}
+	for i := range c.Sites {
+		s := &c.Sites[i]
+		if s.ID == "" {
+			return fmt.Errorf("site at index %d: %w", i, ErrMissingSiteID)
+		}
+		if ids[s.ID] {
+			return fmt.Errorf("%s: %w", s.ID, ErrDuplicateSite)
+		}
+		ids[s.ID] = true
+		if s.Host == "" {
+			s.Host = c.Defaults.Host
+		}
+		if s.Port == 0 {
+			s.Port = c.Defaults.Port
+		}
+		if s.User == "" {
+			s.User = c.Defaults.User
+		}
+		if s.Password == "" {
+			s.Password = c.Defaults.Password
+		}
+		if s.Key == "" {
+			s.Key = c.Defaults.Key
+		}
+		if s.Retries == 0 {
+			s.Retries = c.Defaults.Retries
+		}
+		if s.Region == "" {
+			s.Region = c.Defaults.Region
+		}
+	}
+	return nil
+}
+
+// Site returns the site with the given id
+func (c Config) Site(id string) (Site, bool) {
+	for _, s := range c.Sites {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Site{}, false
+}
+
+// ClientConfig returns the client config to connect to the site
+func (s Site) ClientConfig() rscp.ClientConfig {
+	return rscp.ClientConfig{
+		Address:     s.Host,
+		Port:        s.Port,
+		Username:    s.User,
+		Password:    s.Password,
+		Key:         s.Key,
+		UseChecksum: true,
+		Retries:     s.Retries,
+	}
+}
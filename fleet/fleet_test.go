@@ -0,0 +1,58 @@
+package fleet
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    []Site
+		wantErr error
+	}{
+		{"defaults applied",
+			Config{
+				Defaults: Site{Host: "ignored", User: "user", Password: "password", Key: "key", Region: "north"},
+				Sites: []Site{{ID: "a", Host: "10.0.0.1"}, {ID: "b", Host: "10.0.0.2", Port: 5034, User: "other", Region: "south",
+					Location: &Location{47.4, 8.5}, Orientation: &Orientation{180, 30}}},
+			},
+			[]Site{
+				{ID: "a", Host: "10.0.0.1", User: "user", Password: "password", Key: "key", Region: "north"},
+				{ID: "b", Host: "10.0.0.2", Port: 5034, User: "other", Password: "password", Key: "key", Region: "south",
+					Location: &Location{47.4, 8.5}, Orientation: &Orientation{180, 30}},
+			},
+			nil,
+		},
+		{"no sites", Config{}, nil, ErrNoSites},
+		{"missing id", Config{Sites: []Site{{Host: "a"}}}, nil, ErrMissingSiteID},
+		{"duplicate id", Config{Sites: []Site{{ID: "a"}, {ID: "a"}}}, nil, ErrDuplicateSite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fleet.json")
+			if err := jsonfile.Write(path, tt.config); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(got.Sites, tt.want); diff != nil {
+				t.Errorf("Load() = %v, want %v\n%s", got.Sites, tt.want, diff)
+			}
+			if s, ok := got.Site("b"); !ok || s.ClientConfig().Port != 5034 {
+				t.Errorf("Site() = %v, %v", s, ok)
+			}
+		})
+	}
+}
@@ -10,4 +10,5 @@ require (
 	github.com/jnovack/flag v1.16.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spali/go-slicereader v0.0.0-20201122145524-8e262e1a5127
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,50 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Read unmarshals the json file at path into v.
+//
+// returns an error wrapping os.ErrNotExist if the file does not exist.
+func Read(path string, v interface{}) error {
+	var (
+		b   []byte
+		err error
+	)
+	if b, err = ioutil.ReadFile(path); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Write marshals v as json and atomically replaces the file at path.
+func Write(path string, v interface{}) error {
+	var (
+		b   []byte
+		err error
+	)
+	if b, err = json.MarshalIndent(v, "", "  "); err != nil {
+		return err
+	}
+	var f *os.File
+	if f, err = ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp"); err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
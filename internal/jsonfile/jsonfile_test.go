@@ -0,0 +1,35 @@
+package jsonfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestWriteRead(t *testing.T) {
+	type data struct {
+		Name  string
+		Value float64
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := Read(path, &data{}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Read() error = %v, want %v", err, os.ErrNotExist)
+	}
+	want := data{"test", 1.5}
+	if err := Write(path, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got data
+	if err := Read(path, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Read() = %v, want %v\n%s", got, want, diff)
+	}
+	if m, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp")); len(m) > 0 {
+		t.Errorf("temporary files left: %v", m)
+	}
+}
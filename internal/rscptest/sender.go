@@ -0,0 +1,40 @@
+// Package rscptest provides a sender with canned responses for the tests of packages using a rscp.Sender.
+package rscptest
+
+import (
+	"errors"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// ErrNoResponse is returned for a call without canned responses
+var ErrNoResponse = errors.New("no response")
+
+// Sender answers the calls in order with the canned responses, the last responses answer all further calls.
+// A nil entry fails the call with ErrNoResponse, as does a sender without responses.
+type Sender struct {
+	Responses [][]rscp.Message
+	// requests of every call
+	Requests [][]rscp.Message
+}
+
+// NewSender returns a sender answering the calls in order with the responses
+func NewSender(responses ...[]rscp.Message) *Sender {
+	return &Sender{Responses: responses}
+}
+
+// SendMultiple records the requests and returns the next responses
+func (s *Sender) SendMultiple(requests []rscp.Message) ([]rscp.Message, error) {
+	s.Requests = append(s.Requests, requests)
+	if len(s.Responses) == 0 {
+		return nil, ErrNoResponse
+	}
+	r := s.Responses[0]
+	if len(s.Responses) > 1 {
+		s.Responses = s.Responses[1:]
+	}
+	if r == nil {
+		return nil, ErrNoResponse
+	}
+	return r, nil
+}
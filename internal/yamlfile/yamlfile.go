@@ -0,0 +1,123 @@
+// Package yamlfile reads yaml files into the json struct tags of the config types.
+//
+// the document is parsed by gopkg.in/yaml.v3, converted to json and unmarshalled with encoding/json, so the json
+// struct tags and unmarshallers apply and numbers are only unmarshalled into numeric fields (quote numeric strings).
+// Multiple documents are rejected.
+package yamlfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrSyntax      = errors.New("yaml syntax error")
+	ErrUnsupported = errors.New("unsupported yaml")
+)
+
+// Read unmarshals the yaml file at path into v.
+//
+// returns an error wrapping os.ErrNotExist if the file does not exist.
+func Read(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Unmarshal unmarshals the yaml document into v
+func Unmarshal(b []byte, v interface{}) error {
+	doc, err := Parse(b)
+	if err != nil {
+		return err
+	}
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(j, v)
+}
+
+// Parse parses the yaml document into maps, slices and scalars (string, bool, int64, uint64, float64 or nil),
+// timestamps are kept as strings.
+func Parse(b []byte) (interface{}, error) {
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	var n yaml.Node
+	if err := d.Decode(&n); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", err, ErrSyntax)
+	}
+	var next yaml.Node
+	if err := d.Decode(&next); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("multiple documents: %w", ErrUnsupported)
+	}
+	timestampsAsStrings(&n)
+	var doc interface{}
+	if err := n.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", err, ErrSyntax)
+	}
+	return normalize(doc)
+}
+
+// timestampsAsStrings tags the implicit timestamps as strings, to keep their text instead of decoding a time
+func timestampsAsStrings(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!timestamp" && n.Style&yaml.TaggedStyle == 0 {
+		n.Tag = "!!str"
+	}
+	for _, c := range n.Content {
+		timestampsAsStrings(c)
+	}
+}
+
+// normalize converts the decoded values to the types of json, integers to int64 (or uint64 if out of range)
+func normalize(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			n, err := normalize(e)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = n
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			switch k.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+				return nil, fmt.Errorf("complex key %v: %w", k, ErrUnsupported)
+			}
+			n, err := normalize(e)
+			if err != nil {
+				return nil, err
+			}
+			r[fmt.Sprint(k)] = n
+		}
+		return r, nil
+	case []interface{}:
+		for i, e := range v {
+			n, err := normalize(e)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = n
+		}
+		return v, nil
+	case int:
+		return int64(v), nil
+	}
+	return v, nil
+}
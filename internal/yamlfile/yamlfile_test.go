@@ -0,0 +1,121 @@
+package yamlfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    interface{}
+		wantErr error
+	}{
+		{"empty", "# nothing\n", nil, nil},
+		{"scalars", `
+---
+string: text with spaces, commas and a:colon # comment
+quoted: "a # b\t"
+escaped: "a \" # b"
+single: 'it''s'
+int: 42
+hex: 0x1f
+float: -1.5
+bool: true
+none: ~
+empty:
+block: |
+  line
+`, map[string]interface{}{
+			"string":  "text with spaces, commas and a:colon",
+			"quoted":  "a # b\t",
+			"escaped": "a \" # b",
+			"single":  "it's",
+			"int":     int64(42),
+			"hex":     int64(31),
+			"float":   -1.5,
+			"bool":    true,
+			"none":    nil,
+			"empty":   nil,
+			"block":   "line\n",
+		}, nil},
+		{"nested", `
+defaults:
+  user: admin
+devices:
+- id: a
+  layout:
+    batteries: 2
+  tags: [x, "y", 3]
+-   id: b
+    flow: {key: value, list: [1, 2]}
+- plain
+-
+  - nested
+`, map[string]interface{}{
+			"defaults": map[string]interface{}{"user": "admin"},
+			"devices": []interface{}{
+				map[string]interface{}{
+					"id":     "a",
+					"layout": map[string]interface{}{"batteries": int64(2)},
+					"tags":   []interface{}{"x", "y", int64(3)},
+				},
+				map[string]interface{}{
+					"id":   "b",
+					"flow": map[string]interface{}{"key": "value", "list": []interface{}{int64(1), int64(2)}},
+				},
+				"plain",
+				[]interface{}{"nested"},
+			},
+		}, nil},
+		{"indented sequence", "list:\n  - 1\n  - 2\n", map[string]interface{}{"list": []interface{}{int64(1), int64(2)}}, nil},
+		{"bad indentation", "a: 1\n  b: 2\n", nil, ErrSyntax},
+		{"duplicate key", "a: 1\na: 2\n", nil, ErrSyntax},
+		{"unterminated", "a: \"b\n", nil, ErrSyntax},
+		{"unclosed flow", "a: [1, 2\n", nil, ErrSyntax},
+		{"tab", "a:\n\tb: 1\n", nil, ErrSyntax},
+		{"anchor", "a: &x 1\nb: *x\n", map[string]interface{}{"a": int64(1), "b": int64(1)}, nil},
+		{"timestamp", "a: 2021-06-01\n", map[string]interface{}{"a": "2021-06-01"}, nil},
+		{"numeric key", "1: a\n", map[string]interface{}{"1": "a"}, nil},
+		{"documents", "a: 1\n---\nb: 2\n", nil, ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.yaml))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Parse() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	type data struct {
+		Name   string   `json:"name"`
+		Value  float64  `json:"value"`
+		Values []uint16 `json:"values"`
+	}
+	path := filepath.Join(t.TempDir(), "data.yaml")
+	if err := Read(path, &data{}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Read() error = %v, want %v", err, os.ErrNotExist)
+	}
+	if err := os.WriteFile(path, []byte("name: test\nvalue: 1.5\nvalues: [1, 2]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var got data
+	if err := Read(path, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := data{"test", 1.5, []uint16{1, 2}}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Read() = %v, want %v\n%s", got, want, diff)
+	}
+}
@@ -0,0 +1,170 @@
+package inventory
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+// Snapshot is the inventory of a site at a point in time
+type Snapshot struct {
+	Site  string    `json:"site"`
+	Time  time.Time `json:"time"`
+	Items []Item    `json:"items,omitempty"`
+	// error if the inventory could not be collected
+	Error string `json:"error,omitempty"`
+}
+
+// History of all collected snapshots
+type History struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// LoadHistory reads the history from a json file, a missing file results in an empty history
+func LoadHistory(path string) (*History, error) {
+	h := &History{}
+	if err := jsonfile.Read(path, h); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Save writes the history to a json file
+func (h *History) Save(path string) error {
+	return jsonfile.Write(path, h)
+}
+
+// Add appends the snapshot to the history
+func (h *History) Add(s Snapshot) {
+	h.Snapshots = append(h.Snapshots, s)
+}
+
+// Latest returns the latest successful snapshot of every site sorted by site
+func (h *History) Latest() []Snapshot {
+	latest := map[string]Snapshot{}
+	for _, s := range h.Snapshots {
+		if s.Error != "" {
+			continue
+		}
+		if l, exists := latest[s.Site]; !exists || s.Time.After(l.Time) {
+			latest[s.Site] = s
+		}
+	}
+	r := make([]Snapshot, 0, len(latest))
+	for _, s := range latest {
+		r = append(r, s)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Site < r[j].Site })
+	return r
+}
+
+// Group of sites sharing the same value of an inventory key
+type Group struct {
+	Component string   `json:"component"`
+	Key       string   `json:"key"`
+	Value     string   `json:"value"`
+	Sites     []string `json:"sites"`
+}
+
+// GroupByVersion groups the latest snapshot of every site by the values of each inventory key.
+//
+// the groups of a key are sorted by the amount of sites descending, so outdated sites usually are found in the last groups.
+func (h *History) GroupByVersion() []Group {
+	type groupKey struct{ component, key, value string }
+	groups := map[groupKey]map[string]bool{}
+	for _, s := range h.Latest() {
+		for _, i := range s.Items {
+			k := groupKey{i.Component, i.Key, i.Value}
+			if groups[k] == nil {
+				groups[k] = map[string]bool{}
+			}
+			groups[k][s.Site] = true
+		}
+	}
+	r := make([]Group, 0, len(groups))
+	for k, sites := range groups {
+		g := Group{Component: k.component, Key: k.key, Value: k.value}
+		for s := range sites {
+			g.Sites = append(g.Sites, s)
+		}
+		sort.Strings(g.Sites)
+		r = append(r, g)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		switch {
+		case r[i].Component != r[j].Component:
+			return r[i].Component < r[j].Component
+		case r[i].Key != r[j].Key:
+			return r[i].Key < r[j].Key
+		case len(r[i].Sites) != len(r[j].Sites):
+			return len(r[i].Sites) > len(r[j].Sites)
+		}
+		return r[i].Value < r[j].Value
+	})
+	return r
+}
+
+// Change of an inventory value between two snapshots of a site
+type Change struct {
+	Site      string    `json:"site"`
+	Time      time.Time `json:"time"`
+	Component string    `json:"component"`
+	Index     uint16    `json:"index"`
+	Key       string    `json:"key"`
+	Old       string    `json:"old,omitempty"`
+	New       string    `json:"new,omitempty"`
+}
+
+// Changes returns all changes between the consecutive successful snapshots of every site
+func (h *History) Changes() []Change {
+	type itemKey struct {
+		component string
+		index     uint16
+		key       string
+	}
+	last := map[string]map[itemKey]string{}
+	snapshots := make([]Snapshot, len(h.Snapshots))
+	copy(snapshots, h.Snapshots)
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	changes := []Change{}
+	for _, s := range snapshots {
+		if s.Error != "" {
+			continue
+		}
+		current := map[itemKey]string{}
+		for _, i := range s.Items {
+			current[itemKey{i.Component, i.Index, i.Key}] = i.Value
+		}
+		if prev, exists := last[s.Site]; exists {
+			for k, v := range current {
+				if old, existed := prev[k]; !existed || old != v {
+					changes = append(changes, Change{s.Site, s.Time, k.component, k.index, k.key, old, v})
+				}
+			}
+			for k, v := range prev {
+				if _, exists := current[k]; !exists {
+					changes = append(changes, Change{s.Site, s.Time, k.component, k.index, k.key, v, ""})
+				}
+			}
+		}
+		last[s.Site] = current
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		switch {
+		case !a.Time.Equal(b.Time):
+			return a.Time.Before(b.Time)
+		case a.Site != b.Site:
+			return a.Site < b.Site
+		case a.Component != b.Component:
+			return a.Component < b.Component
+		case a.Index != b.Index:
+			return a.Index < b.Index
+		}
+		return a.Key < b.Key
+	})
+	return changes
+}
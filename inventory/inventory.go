@@ -0,0 +1,182 @@
+// Package inventory collects firmware versions and serial numbers of all components of a E3DC system.
+package inventory
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Item is a single inventory value of a component
+type Item struct {
+	// namespace of the component (i.e. BAT, PVI)
+	Component string `json:"component"`
+	// index of the component within the namespace
+	Index uint16 `json:"index"`
+	// tag name of the value, with the module name or dcb index appended for repeated values
+	Key string `json:"key"`
+	// value formatted as string
+	Value string `json:"value"`
+}
+
+// componentRequests defines the requests of the components by namespace
+var componentRequests = map[string][]interface{}{
+	"BAT":  {rscp.BAT_REQ_DEVICE_NAME, rscp.BAT_REQ_DCB_COUNT},
+	"DCDC": {rscp.DCDC_REQ_FIRMWARE_VERSION, rscp.DCDC_REQ_FPGA_FIRMWARE, rscp.DCDC_REQ_SERIAL_NUMBER, rscp.DCDC_REQ_BOARD_VERSION},
+	"PM":   {rscp.PM_REQ_FIRMWARE_VERSION, rscp.PM_REQ_DEVICE_ID, rscp.PM_REQ_TYPE},
+	"PVI":  {rscp.PVI_REQ_VERSION, rscp.PVI_REQ_SERIAL_NUMBER, rscp.PVI_REQ_TYPE},
+	"WB":   {rscp.WB_REQ_APP_SOFTWARE, rscp.WB_REQ_BOOTLOADER_SOFTWARE, rscp.WB_REQ_HW_VERSION, rscp.WB_REQ_DEVICE_ID},
+}
+
+// systemRequests are the requests not bound to an indexed component
+var systemRequests = []rscp.Tag{
+	rscp.INFO_REQ_SERIAL_NUMBER,
+	rscp.INFO_REQ_SW_RELEASE,
+	rscp.INFO_REQ_MODULES_SW_VERSIONS,
+	rscp.UM_REQ_UPDATE_STATUS,
+}
+
+// inventoryTags are the response tags collected as inventory items
+var inventoryTags = map[rscp.Tag]bool{
+	rscp.INFO_SERIAL_NUMBER:     true,
+	rscp.INFO_SW_RELEASE:        true,
+	rscp.UM_UPDATE_STATUS:       true,
+	rscp.BAT_DEVICE_NAME:        true,
+	rscp.DCDC_FIRMWARE_VERSION:  true,
+	rscp.DCDC_FPGA_FIRMWARE:     true,
+	rscp.DCDC_SERIAL_NUMBER:     true,
+	rscp.DCDC_BOARD_VERSION:     true,
+	rscp.PM_FIRMWARE_VERSION:    true,
+	rscp.PM_DEVICE_ID:           true,
+	rscp.PM_TYPE:                true,
+	rscp.PVI_VERSION_MAIN:       true,
+	rscp.PVI_VERSION_PIC:        true,
+	rscp.PVI_SERIAL_NUMBER:      true,
+	rscp.PVI_TYPE:               true,
+	rscp.WB_APP_SOFTWARE:        true,
+	rscp.WB_BOOTLOADER_SOFTWARE: true,
+	rscp.WB_HW_VERSION:          true,
+	rscp.WB_DEVICE_ID:           true,
+}
+
+// dcbTags are the inventory tags of a dcb within BAT_DCB_INFO
+var dcbTags = map[rscp.Tag]bool{
+	rscp.BAT_DCB_FW_VERSION:  true,
+	rscp.BAT_DCB_SERIALNO:    true,
+	rscp.BAT_DCB_PCB_VERSION: true,
+}
+
+// Collect requests the inventory of all components.
+//
+// if components is nil, the components are discovered first.
+// Every component is requested separately, a failing component is logged and skipped.
+func Collect(sender rscp.Sender, components rscp.Components) ([]Item, error) {
+	var err error
+	if components == nil {
+		if components, err = rscp.Discover(sender); err != nil {
+			return nil, err
+		}
+	}
+	items := []Item{}
+	system := make([]rscp.Message, 0, len(systemRequests))
+	for _, t := range systemRequests {
+		system = append(system, *rscp.NewMessage(t, nil))
+	}
+	responses, err := sender.SendMultiple(system)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, extract("INFO", 0, responses)...)
+	for _, n := range rscp.Namespaces {
+		for _, i := range components.Indexes(n.Name) {
+			var req *rscp.Message
+			if req, err = n.NewRequest(i, componentRequests[n.Name]...); err != nil {
+				return nil, err
+			}
+			if responses, err = sender.SendMultiple([]rscp.Message{*req}); err != nil {
+				log.Warnf("skipping inventory of %s %d: %s", n.Name, i, err)
+				continue
+			}
+			items = append(items, extract(n.Name, i, responses)...)
+			if n.Name == "BAT" {
+				items = append(items, collectDCBs(sender, n, i, responses)...)
+			}
+		}
+	}
+	return items, nil
+}
+
+// collectDCBs requests the info of every dcb of the battery up to the BAT_DCB_COUNT within the responses,
+// a failing request is logged and skipped.
+func collectDCBs(sender rscp.Sender, n rscp.Namespace, index uint16, responses []rscp.Message) []Item {
+	c := rscp.FindIndexed(responses, n.ResponseContainer, n.IndexTag, index)
+	if c == nil {
+		return nil
+	}
+	sub, _ := c.Value.([]rscp.Message)
+	count := rscp.FindTag(sub, rscp.BAT_DCB_COUNT)
+	if count == nil {
+		return nil
+	}
+	dcbs, err := count.Float64()
+	if err != nil {
+		log.Warnf("skipping dcb inventory of %s %d: %s", n.Name, index, err)
+		return nil
+	}
+	values := []interface{}{}
+	for d := 0; d < int(dcbs); d++ {
+		values = append(values, rscp.BAT_REQ_DCB_INFO, uint16(d))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	req, err := n.NewRequest(index, values...)
+	if err == nil {
+		responses, err = sender.SendMultiple([]rscp.Message{*req})
+	}
+	if err != nil {
+		log.Warnf("skipping dcb inventory of %s %d: %s", n.Name, index, err)
+		return nil
+	}
+	return extract(n.Name, index, responses)
+}
+
+// extract returns the inventory items found in the responses (recursive through containers)
+func extract(component string, index uint16, responses []rscp.Message) []Item {
+	items := []Item{}
+	var walk func(ms []rscp.Message)
+	walk = func(ms []rscp.Message) {
+		for _, m := range ms {
+			switch {
+			case m.Tag == rscp.INFO_MODULE_SW_VERSION:
+				sub, _ := m.Value.([]rscp.Message)
+				module, version := rscp.FindTag(sub, rscp.INFO_MODULE), rscp.FindTag(sub, rscp.INFO_VERSION)
+				if module != nil && version != nil {
+					items = append(items, Item{component, index, fmt.Sprintf("%s/%v", m.Tag, module.Value), fmt.Sprint(version.Value)})
+				}
+			case m.Tag == rscp.BAT_DCB_INFO:
+				sub, _ := m.Value.([]rscp.Message)
+				dcb := rscp.FindTag(sub, rscp.BAT_DCB_INDEX)
+				if dcb == nil {
+					continue
+				}
+				for _, v := range sub {
+					if dcbTags[v.Tag] && v.DataType != rscp.Error {
+						items = append(items, Item{component, index, fmt.Sprintf("%s/%v", v.Tag, dcb.Value), fmt.Sprint(v.Value)})
+					}
+				}
+			case m.DataType == rscp.Container:
+				if sub, ok := m.Value.([]rscp.Message); ok {
+					walk(sub)
+				}
+			case m.DataType == rscp.Error:
+				log.Debugf("%s %d: %s returned %v", component, index, m.Tag, m.Value)
+			case inventoryTags[m.Tag]:
+				items = append(items, Item{component, index, m.Tag.String(), fmt.Sprint(m.Value)})
+			}
+		}
+	}
+	walk(responses)
+	return items
+}
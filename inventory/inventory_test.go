@@ -0,0 +1,118 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestCollect(t *testing.T) {
+	sender := rscptest.NewSender(
+		[]rscp.Message{
+			{Tag: rscp.INFO_SERIAL_NUMBER, DataType: rscp.CString, Value: "S10-123"},
+			{Tag: rscp.INFO_SW_RELEASE, DataType: rscp.CString, Value: "S10_2021_01"},
+			{Tag: rscp.INFO_MODULES_SW_VERSIONS, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.INFO_MODULE_SW_VERSION, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.INFO_MODULE, DataType: rscp.CString, Value: "EMS"},
+					{Tag: rscp.INFO_VERSION, DataType: rscp.CString, Value: "1.2"},
+				}},
+			}},
+			{Tag: rscp.UM_UPDATE_STATUS, DataType: rscp.Int32, Value: int32(0)},
+		},
+		[]rscp.Message{
+			{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				{Tag: rscp.BAT_DEVICE_NAME, DataType: rscp.CString, Value: "BAT"},
+				{Tag: rscp.BAT_DCB_COUNT, DataType: rscp.UChar8, Value: uint8(2)},
+			}},
+		},
+		[]rscp.Message{
+			{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				{Tag: rscp.BAT_DCB_INFO, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.BAT_DCB_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+					{Tag: rscp.BAT_DCB_FW_VERSION, DataType: rscp.Uint32, Value: uint32(10)},
+					{Tag: rscp.BAT_DCB_SERIALNO, DataType: rscp.Uint32, Value: uint32(111)},
+				}},
+				{Tag: rscp.BAT_DCB_INFO, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.BAT_DCB_INDEX, DataType: rscp.UInt16, Value: uint16(1)},
+					{Tag: rscp.BAT_DCB_FW_VERSION, DataType: rscp.Uint32, Value: uint32(11)},
+					{Tag: rscp.BAT_DCB_SERIALNO, DataType: rscp.Uint32, Value: uint32(222)},
+				}},
+			}},
+		},
+		[]rscp.Message{
+			{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				{Tag: rscp.PVI_VERSION, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.PVI_VERSION_MAIN, DataType: rscp.CString, Value: "3.0"},
+				}},
+				{Tag: rscp.PVI_SERIAL_NUMBER, DataType: rscp.Error, Value: rscp.ERR_NOT_AVAILABLE},
+			}},
+		},
+		// the wallbox times out
+		nil,
+	)
+	got, err := Collect(sender, rscp.Components{
+		"BAT": {{Namespace: "BAT", Index: 0}},
+		"PVI": {{Namespace: "PVI", Index: 0}},
+		"WB":  {{Namespace: "WB", Index: 0}},
+	})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	want := []Item{
+		{"INFO", 0, "INFO_SERIAL_NUMBER", "S10-123"},
+		{"INFO", 0, "INFO_SW_RELEASE", "S10_2021_01"},
+		{"INFO", 0, "INFO_MODULE_SW_VERSION/EMS", "1.2"},
+		{"INFO", 0, "UM_UPDATE_STATUS", "0"},
+		{"BAT", 0, "BAT_DEVICE_NAME", "BAT"},
+		{"BAT", 0, "BAT_DCB_FW_VERSION/0", "10"},
+		{"BAT", 0, "BAT_DCB_SERIALNO/0", "111"},
+		{"BAT", 0, "BAT_DCB_FW_VERSION/1", "11"},
+		{"BAT", 0, "BAT_DCB_SERIALNO/1", "222"},
+		{"PVI", 0, "PVI_VERSION_MAIN", "3.0"},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Collect() = %v, want %v\n%s", got, want, diff)
+	}
+	// the info of each dcb is requested
+	dcbs, _ := rscp.CreateRequest(rscp.BAT_REQ_DATA, rscp.BAT_INDEX, uint16(0),
+		rscp.BAT_REQ_DCB_INFO, uint16(0), rscp.BAT_REQ_DCB_INFO, uint16(1))
+	if diff := deep.Equal(sender.Requests[2], []rscp.Message{*dcbs}); diff != nil {
+		t.Errorf("Collect() dcb requests %s", diff)
+	}
+	if _, err := Collect(rscptest.NewSender(), nil); err == nil {
+		t.Errorf("Collect() expected error when discovery fails")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := &History{}
+	h.Add(Snapshot{Site: "a", Time: t0, Items: []Item{{"INFO", 0, "INFO_SW_RELEASE", "v1"}, {"PM", 0, "PM_FIRMWARE_VERSION", "1"}}})
+	h.Add(Snapshot{Site: "b", Time: t0, Items: []Item{{"INFO", 0, "INFO_SW_RELEASE", "v1"}}})
+	h.Add(Snapshot{Site: "c", Time: t0, Items: []Item{{"INFO", 0, "INFO_SW_RELEASE", "v1"}}})
+	h.Add(Snapshot{Site: "a", Time: t0.Add(time.Hour), Items: []Item{{"INFO", 0, "INFO_SW_RELEASE", "v2"}}})
+	h.Add(Snapshot{Site: "b", Time: t0.Add(time.Hour), Items: []Item{{"INFO", 0, "INFO_SW_RELEASE", "v2"}}})
+	h.Add(Snapshot{Site: "c", Time: t0.Add(time.Hour), Error: "timeout"})
+
+	wantGroups := []Group{
+		{"INFO", "INFO_SW_RELEASE", "v2", []string{"a", "b"}},
+		{"INFO", "INFO_SW_RELEASE", "v1", []string{"c"}},
+	}
+	if diff := deep.Equal(h.GroupByVersion(), wantGroups); diff != nil {
+		t.Errorf("GroupByVersion() = %v, want %v\n%s", h.GroupByVersion(), wantGroups, diff)
+	}
+	wantChanges := []Change{
+		{"a", t0.Add(time.Hour), "INFO", 0, "INFO_SW_RELEASE", "v1", "v2"},
+		{"a", t0.Add(time.Hour), "PM", 0, "PM_FIRMWARE_VERSION", "1", ""},
+		{"b", t0.Add(time.Hour), "INFO", 0, "INFO_SW_RELEASE", "v1", "v2"},
+	}
+	if diff := deep.Equal(h.Changes(), wantChanges); diff != nil {
+		t.Errorf("Changes() = %v, want %v\n%s", h.Changes(), wantChanges, diff)
+	}
+}
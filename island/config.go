@@ -0,0 +1,145 @@
+// Package island manages the loads while the system runs as island grid.
+//
+// when EP_IS_ISLAND_GRID becomes true the battery is the only source besides the pv, the manager sheds the loads
+// in tiers as the state of charge drops by switching off home automation actuators and stopping or limiting the wallbox.
+// A tier is restored when the state of charge recovered by the hysteresis, all tiers are restored
+// as soon as EP_IS_GRID_CONNECTED returns. Actuators already off before their tier was shed are left off,
+// the charging of the wallbox is only toggled back on if the manager stopped it.
+package island
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spali/go-rscp/evcharge"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoTiers      = errors.New("no tiers configured")
+	ErrInvalidSoC   = errors.New("state of charge out of range")
+	ErrNoWallbox    = errors.New("tier limits the wallbox but no wallbox is configured")
+	ErrInvalidLimit = errors.New("wallbox current limit exceeds the restore current")
+)
+
+// fullPercent is the upper bound of the state of charge
+const fullPercent = 100
+
+// Tier of loads shed together
+type Tier struct {
+	// name used in the logs
+	Name string `json:"name"`
+	// state of charge in % at or below the tier is shed, 100 sheds the tier as soon as the island grid starts
+	SoC float64 `json:"soc"`
+	// HA_DATAPOINT_INDEX of the actuators switched off
+	Actuators []uint16 `json:"actuators"`
+	// charge current limit in A of the wallbox, 0 switches to sun mode and stops a charging car, nil leaves the wallbox untouched
+	WallboxCurrent *uint8 `json:"wallboxCurrent"`
+}
+
+// Wallbox controlled by the tiers
+type Wallbox struct {
+	// WB_INDEX of the wallbox, nil to use the first discovered wallbox (see Config.Resolve)
+	Index *uint16 `json:"index"`
+	// mode restored after the island grid
+	RestoreMode evcharge.Mode `json:"restoreMode"`
+	// charge current in A restored after the island grid
+	RestoreCurrent uint8 `json:"restoreCurrent"`
+}
+
+// Config of the load manager
+type Config struct {
+	// tiers ordered by the state of charge they are shed at
+	Tiers []Tier `json:"tiers"`
+	// state of charge in % above the tier the state of charge has to recover to restore the tier
+	Hysteresis float64 `json:"hysteresis"`
+	// HA_REQ_COMMAND to switch an actuator off
+	OffCommand string `json:"offCommand"`
+	// HA_REQ_COMMAND to switch an actuator on
+	OnCommand string `json:"onCommand"`
+	// wallbox controlled by the tiers, nil if none
+	Wallbox *Wallbox `json:"wallbox"`
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	Hysteresis: 5,
+	OffCommand: "off",
+	OnCommand:  "on",
+	Wallbox: &Wallbox{
+		RestoreMode:    evcharge.ModeMixed,
+		RestoreCurrent: 16,
+	},
+}
+
+// index returns the WB_INDEX of the resolved wallbox
+func (w Wallbox) index() (uint16, error) {
+	if w.Index == nil {
+		return 0, evcharge.ErrUnresolved
+	}
+	return *w.Index, nil
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	if len(c.Tiers) == 0 {
+		return ErrNoTiers
+	}
+	if c.Hysteresis <= 0 {
+		c.Hysteresis = defaultConfig.Hysteresis
+	}
+	if c.OffCommand == "" {
+		c.OffCommand = defaultConfig.OffCommand
+	}
+	if c.OnCommand == "" {
+		c.OnCommand = defaultConfig.OnCommand
+	}
+	if c.Wallbox != nil {
+		if c.Wallbox.RestoreMode == 0 {
+			c.Wallbox.RestoreMode = defaultConfig.Wallbox.RestoreMode
+		}
+		if c.Wallbox.RestoreCurrent == 0 {
+			c.Wallbox.RestoreCurrent = defaultConfig.Wallbox.RestoreCurrent
+		}
+	}
+	for _, t := range c.Tiers {
+		if t.SoC < 0 || t.SoC > fullPercent {
+			return fmt.Errorf("tier %s %f%%: %w", t.Name, t.SoC, ErrInvalidSoC)
+		}
+		if t.WallboxCurrent == nil {
+			continue
+		}
+		if c.Wallbox == nil {
+			return fmt.Errorf("tier %s: %w", t.Name, ErrNoWallbox)
+		}
+		if *t.WallboxCurrent > c.Wallbox.RestoreCurrent {
+			return fmt.Errorf("tier %s %dA: %w", t.Name, *t.WallboxCurrent, ErrInvalidLimit)
+		}
+	}
+	return nil
+}
+
+// Resolve sets the wallbox to the first discovered one if its index is not configured,
+// fails if the wallbox isn't discovered.
+func (c *Config) Resolve(components rscp.Components) error {
+	if c.Wallbox == nil {
+		return nil
+	}
+	index, err := components.Resolve("WB", c.Wallbox.Index)
+	if err != nil {
+		return err
+	}
+	c.Wallbox.Index = &index
+	return nil
+}
@@ -0,0 +1,286 @@
+package island
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/evcharge"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func current(a uint8) *uint8 { return &a }
+
+func index(i uint16) *uint16 { return &i }
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Config
+		wantErr error
+	}{
+		{"defaults",
+			`{"tiers":[{"name":"comfort","soc":100,"actuators":[1,2],"wallboxCurrent":0}],"wallbox":{}}`,
+			Config{
+				Tiers:      []Tier{{Name: "comfort", SoC: 100, Actuators: []uint16{1, 2}, WallboxCurrent: current(0)}},
+				Hysteresis: 5, OffCommand: "off", OnCommand: "on",
+				Wallbox: &Wallbox{RestoreMode: evcharge.ModeMixed, RestoreCurrent: 16},
+			},
+			nil,
+		},
+		{"restore mode",
+			`{"tiers":[{"soc":50}],"wallbox":{"index":1,"restoreMode":"sun","restoreCurrent":10}}`,
+			Config{
+				Tiers: []Tier{{SoC: 50}}, Hysteresis: 5, OffCommand: "off", OnCommand: "on",
+				Wallbox: &Wallbox{Index: index(1), RestoreMode: evcharge.ModeSun, RestoreCurrent: 10},
+			},
+			nil,
+		},
+		{"no tiers", `{}`, Config{}, ErrNoTiers},
+		{"invalid soc", `{"tiers":[{"soc":101}]}`, Config{}, ErrInvalidSoC},
+		{"no wallbox", `{"tiers":[{"soc":50,"wallboxCurrent":6}]}`, Config{}, ErrNoWallbox},
+		{"invalid limit", `{"tiers":[{"soc":50,"wallboxCurrent":20}],"wallbox":{}}`, Config{}, ErrInvalidLimit},
+		{"invalid mode", `{"tiers":[{"soc":50}],"wallbox":{"restoreMode":"fast"}}`, Config{}, evcharge.ErrInvalidMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			err := json.Unmarshal([]byte(tt.json), &c)
+			if err == nil {
+				err = c.check()
+			}
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(c, tt.want); diff != nil {
+				t.Errorf("UnmarshalJSON() = %v, want %v\n%s", c, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	components := rscp.Components{"WB": {{Namespace: "WB", Index: 2}}}
+	c := Config{Wallbox: &Wallbox{}}
+	if err := c.Resolve(components); err != nil || c.Wallbox.Index == nil || *c.Wallbox.Index != 2 {
+		t.Errorf("Resolve() = %v, error = %v, want the discovered wallbox 2", c.Wallbox.Index, err)
+	}
+	c = Config{Wallbox: &Wallbox{Index: index(0)}}
+	if err := c.Resolve(components); !errors.Is(err, rscp.ErrNotDiscovered) {
+		t.Errorf("Resolve() of an unknown wallbox error = %v, want %v", err, rscp.ErrNotDiscovered)
+	}
+}
+
+var testConfig = Config{
+	Tiers: []Tier{
+		{Name: "comfort", SoC: 100, Actuators: []uint16{1}, WallboxCurrent: current(6)},
+		{Name: "heating", SoC: 50, Actuators: []uint16{2, 3}},
+		{Name: "essential", SoC: 20, Actuators: []uint16{3, 4}, WallboxCurrent: current(0)},
+	},
+	Hysteresis: 5, OffCommand: "off", OnCommand: "on",
+	Wallbox: &Wallbox{Index: index(0), RestoreMode: evcharge.ModeMixed, RestoreCurrent: 16},
+}
+
+// system is a fake system recording the commands
+type system struct {
+	island    bool
+	soc       float64
+	actuators map[uint16]string
+	wallbox   []byte
+	// car charges, toggled by WB_REQ_SET_EXTERN
+	charging bool
+	toggles  int
+	sent     int
+	// reject the commands
+	fail bool
+}
+
+func (s *system) SendMultiple(requests []rscp.Message) ([]rscp.Message, error) {
+	if requests[0].Tag == rscp.EP_REQ_IS_ISLAND_GRID {
+		return []rscp.Message{
+			{Tag: rscp.EP_IS_ISLAND_GRID, DataType: rscp.Bool, Value: s.island},
+			{Tag: rscp.EP_IS_GRID_CONNECTED, DataType: rscp.Bool, Value: !s.island},
+			{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: uint8(s.soc)},
+		}, nil
+	}
+	if requests[0].Tag == rscp.HA_REQ_ACTUATOR_STATES {
+		datapoints := []rscp.Message{}
+		for a, command := range s.actuators {
+			state := int8('1')
+			if command == "off" {
+				state = '2'
+			}
+			datapoints = append(datapoints, rscp.Message{Tag: rscp.HA_DATAPOINT, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.HA_DATAPOINT_INDEX, DataType: rscp.UInt16, Value: a},
+				{Tag: rscp.HA_DATAPOINT_STATE, DataType: rscp.Char8, Value: state},
+			}})
+		}
+		return []rscp.Message{{Tag: rscp.HA_ACTUATOR_STATES, DataType: rscp.Container, Value: datapoints}}, nil
+	}
+	if requests[0].Tag == rscp.WB_REQ_DATA && requests[0].Value.([]rscp.Message)[1].Tag == rscp.WB_REQ_EXTERN_DATA_ALG {
+		var charging uint8
+		if s.charging {
+			charging = 1
+		}
+		return []rscp.Message{{Tag: rscp.WB_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.WB_INDEX, DataType: rscp.UChar8, Value: uint8(0)},
+			{Tag: rscp.WB_EXTERN_DATA_ALG, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.WB_EXTERN_DATA, DataType: rscp.ByteArray, Value: []byte{0, 0, charging, 1, 3, 0, 0}},
+				{Tag: rscp.WB_EXTERN_DATA_LEN, DataType: rscp.UChar8, Value: uint8(7)},
+			}},
+		}}}, nil
+	}
+	s.sent++
+	responses := []rscp.Message{}
+	for _, r := range requests {
+		switch r.Tag {
+		case rscp.HA_REQ_COMMAND_ACTUATOR:
+			v := r.Value.([]rscp.Message)
+			if !s.fail {
+				s.actuators[v[0].Value.(uint16)] = v[1].Value.(string)
+			}
+			responses = append(responses, rscp.Message{Tag: rscp.HA_COMMAND_ACTUATOR, DataType: rscp.Bool, Value: !s.fail})
+		case rscp.WB_REQ_DATA:
+			v := r.Value.([]rscp.Message)
+			data := v[1].Value.([]rscp.Message)[0].Value.([]byte)
+			if data[4] > 0 {
+				s.charging = !s.charging
+				s.toggles++
+			} else {
+				s.wallbox = data
+			}
+			responses = append(responses, rscp.Message{Tag: rscp.WB_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.WB_SET_EXTERN, DataType: rscp.UChar8, Value: uint8(0)},
+			}})
+		case rscp.EMS_REQ_SET_BATTERY_TO_CAR_MODE:
+			responses = append(responses, rscp.Message{Tag: rscp.EMS_SET_BATTERY_TO_CAR_MODE, DataType: rscp.UChar8, Value: uint8(1)})
+		}
+	}
+	return responses, nil
+}
+
+func TestStep(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	// actuator 4 is off before the shedding
+	sys := &system{soc: 80, actuators: map[uint16]string{4: "off"}, charging: true}
+	state := &State{}
+	type want struct {
+		shed      []string
+		actuators map[uint16]string
+		mode      evcharge.Mode
+		current   uint8
+		charging  bool
+	}
+	steps := []struct {
+		name   string
+		island bool
+		soc    float64
+		want   want
+	}{
+		{"grid", false, 80, want{nil, map[uint16]string{4: "off"}, 0, 0, true}},
+		{"island starts", true, 80,
+			want{[]string{"comfort"}, map[uint16]string{1: "off", 4: "off"}, evcharge.ModeMixed, 6, true}},
+		{"heating shed", true, 50,
+			want{[]string{"comfort", "heating"}, map[uint16]string{1: "off", 2: "off", 3: "off", 4: "off"}, evcharge.ModeMixed, 6, true}},
+		{"within hysteresis", true, 54,
+			want{[]string{"comfort", "heating"}, map[uint16]string{1: "off", 2: "off", 3: "off", 4: "off"}, evcharge.ModeMixed, 6, true}},
+		{"essential shed", true, 20,
+			want{[]string{"comfort", "heating", "essential"},
+				map[uint16]string{1: "off", 2: "off", 3: "off", 4: "off"}, evcharge.ModeSun, 16, false}},
+		{"essential restored", true, 26,
+			want{[]string{"comfort", "heating"},
+				map[uint16]string{1: "off", 2: "off", 3: "off", 4: "off"}, evcharge.ModeMixed, 6, true}},
+		{"heating restored", true, 60,
+			want{[]string{"comfort"},
+				map[uint16]string{1: "off", 2: "on", 3: "on", 4: "off"}, evcharge.ModeMixed, 6, true}},
+		{"grid returns", false, 60,
+			want{nil, map[uint16]string{1: "on", 2: "on", 3: "on", 4: "off"}, evcharge.ModeMixed, 16, true}},
+	}
+	for _, s := range steps {
+		sys.island, sys.soc = s.island, s.soc
+		now = now.Add(time.Minute)
+		if _, err := Step(sys, testConfig, state, now, false); err != nil {
+			t.Fatalf("%s: Step() error = %v", s.name, err)
+		}
+		got := want{state.Shed, sys.actuators, 0, 0, sys.charging}
+		if len(sys.wallbox) > 1 {
+			got.mode, got.current = evcharge.Mode(sys.wallbox[0]), sys.wallbox[1]
+		}
+		if diff := deep.Equal(got, s.want); diff != nil {
+			t.Errorf("%s: got %+v, want %+v\n%s", s.name, got, s.want, diff)
+		}
+	}
+	actions := []Action{}
+	for _, e := range state.Events {
+		actions = append(actions, e.Action)
+	}
+	wantActions := []Action{ActionIsland, ActionShed, ActionShed, ActionShed, ActionRestore, ActionRestore, ActionRestore, ActionGrid}
+	if diff := deep.Equal(actions, wantActions); diff != nil {
+		t.Errorf("Events = %v, want %v\n%s", actions, wantActions, diff)
+	}
+	if sys.toggles != 2 {
+		t.Errorf("charging toggled %d times, want stopped and resumed once", sys.toggles)
+	}
+	// nothing changes, nothing is sent
+	sent := sys.sent
+	if _, err := Step(sys, testConfig, state, now, false); err != nil || sys.sent != sent {
+		t.Errorf("Step() error = %v, sent %d commands, want none", err, sys.sent-sent)
+	}
+}
+
+func TestStep_idleWallbox(t *testing.T) {
+	sys := &system{island: true, soc: 10, actuators: map[uint16]string{}}
+	state := &State{}
+	ch, err := Step(sys, testConfig, state, time.Now(), false)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if ch.Toggle || sys.toggles != 0 || state.WallboxStopped {
+		t.Errorf("Step() = %+v, toggled %d times a car not charging", ch, sys.toggles)
+	}
+	sys.island = false
+	if _, err := Step(sys, testConfig, state, time.Now(), false); err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if sys.toggles != 0 || sys.charging {
+		t.Errorf("restore toggled %d times, charging %t, want untouched", sys.toggles, sys.charging)
+	}
+}
+
+func TestStep_dryRun(t *testing.T) {
+	sys := &system{island: true, soc: 10, actuators: map[uint16]string{}}
+	state := &State{}
+	ch, err := Step(sys, testConfig, state, time.Now(), true)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if sys.sent != 0 || len(ch.Off) != 4 || ch.Wallbox == nil || ch.Wallbox.Mode != evcharge.ModeSun {
+		t.Errorf("Step() = %+v, sent %d", ch, sys.sent)
+	}
+	if len(state.Events) != 4 || !state.Events[1].DryRun {
+		t.Errorf("Events = %+v", state.Events)
+	}
+}
+
+func TestStep_retry(t *testing.T) {
+	sys := &system{island: true, soc: 80, actuators: map[uint16]string{}, fail: true}
+	state := &State{}
+	if _, err := Step(sys, testConfig, state, time.Now(), false); err == nil {
+		t.Fatalf("Step() expected error")
+	}
+	sys.fail = false
+	ch, err := Step(sys, testConfig, state, time.Now(), false)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if diff := deep.Equal(ch.Off, []uint16{1}); diff != nil || sys.actuators[1] != "off" {
+		t.Errorf("Step() = %+v, actuators %v", ch, sys.actuators)
+	}
+}
@@ -0,0 +1,544 @@
+package island
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/evcharge"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Action of an event
+type Action string
+
+// all actions of the events
+const (
+	// the system switched to island grid
+	ActionIsland Action = "island"
+	// the grid connection returned, all tiers are restored
+	ActionGrid Action = "grid"
+	// a tier was shed
+	ActionShed Action = "shed"
+	// a tier was restored
+	ActionRestore Action = "restore"
+)
+
+// maxEvents limits the number of events kept in the state
+const maxEvents = 1000
+
+// externDataLen is the length of the WB_EXTERN_DATA of WB_REQ_SET_EXTERN
+const externDataLen = 6
+
+// Sample is a single reading of the grid and battery state
+type Sample struct {
+	Time time.Time `json:"time"`
+	// EP_IS_ISLAND_GRID
+	Island bool `json:"island"`
+	// EP_IS_GRID_CONNECTED
+	GridConnected bool `json:"gridConnected"`
+	// battery state of charge in %
+	SoC float64 `json:"soc"`
+}
+
+// Requests returns the requests required to create a sample
+func Requests() ([]rscp.Message, error) {
+	return rscp.CreateRequests(
+		[]interface{}{rscp.EP_REQ_IS_ISLAND_GRID},
+		[]interface{}{rscp.EP_REQ_IS_GRID_CONNECTED},
+		[]interface{}{rscp.EMS_REQ_BAT_SOC},
+	)
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(t time.Time, responses []rscp.Message) (Sample, error) {
+	s := Sample{Time: t}
+	for tag, v := range map[rscp.Tag]*bool{
+		rscp.EP_IS_ISLAND_GRID:    &s.Island,
+		rscp.EP_IS_GRID_CONNECTED: &s.GridConnected,
+	} {
+		m := rscp.FindTag(responses, tag)
+		if m == nil {
+			return s, fmt.Errorf("missing %s in response", tag)
+		}
+		b, ok := m.Value.(bool)
+		if !ok {
+			return s, fmt.Errorf("%s %v: %w", tag, m.Value, rscp.ErrDataTypeValueMismatch)
+		}
+		*v = b
+	}
+	m := rscp.FindTag(responses, rscp.EMS_BAT_SOC)
+	if m == nil {
+		return s, fmt.Errorf("missing %s in response", rscp.EMS_BAT_SOC)
+	}
+	var err error
+	if s.SoC, err = m.Float64(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Event is a change of the island grid or a tier
+type Event struct {
+	Time   time.Time `json:"time"`
+	Action Action    `json:"action"`
+	// tier shed or restored, empty for island and grid
+	Tier   string  `json:"tier,omitempty"`
+	SoC    float64 `json:"soc"`
+	DryRun bool    `json:"dryRun,omitempty"`
+}
+
+// State of the manager, persisted between runs
+type State struct {
+	// system runs as island grid
+	Island bool `json:"island"`
+	// names of the tiers currently shed
+	Shed []string `json:"shed"`
+	// actuators switched off
+	Off []uint16 `json:"off"`
+	// actuators of shed tiers found off before the shedding, left untouched and not switched on by the restore
+	Kept []uint16 `json:"kept,omitempty"`
+	// wallbox current limit applied, nil if not limited
+	WallboxCurrent *uint8 `json:"wallboxCurrent"`
+	// charging of the wallbox was stopped by the manager and is resumed on restore
+	WallboxStopped bool `json:"wallboxStopped,omitempty"`
+	// changes, limited to the most recent ones
+	Events []Event `json:"events"`
+}
+
+// LoadState reads the state from a json file, a missing file results in an empty state
+func LoadState(path string) (*State, error) {
+	s := &State{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to a json file
+func (s *State) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// isShed returns if the tier is shed
+func (s *State) isShed(name string) bool {
+	for _, n := range s.Shed {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// record appends the event and logs it
+func (s *State) record(e Event) {
+	if e.Tier != "" {
+		log.Infof("%s tier %q at %.0f%%", e.Action, e.Tier, e.SoC)
+	} else {
+		log.Infof("%s at %.0f%%", e.Action, e.SoC)
+	}
+	s.Events = append(s.Events, e)
+	if len(s.Events) > maxEvents {
+		s.Events = s.Events[len(s.Events)-maxEvents:]
+	}
+}
+
+// Decide updates the shed tiers for the sample.
+//
+// while the grid is neither connected nor island grid (i.e. during switching) the tiers are kept.
+func (s *State) Decide(c Config, sample Sample, dryRun bool) {
+	event := func(a Action, tier string) {
+		s.record(Event{Time: sample.Time, Action: a, Tier: tier, SoC: sample.SoC, DryRun: dryRun})
+	}
+	switch {
+	case sample.Island:
+		if !s.Island {
+			s.Island = true
+			event(ActionIsland, "")
+		}
+		shed := []string{}
+		for _, t := range c.Tiers {
+			switch {
+			case !s.isShed(t.Name) && sample.SoC <= t.SoC:
+				event(ActionShed, t.Name)
+			case s.isShed(t.Name) && sample.SoC > t.SoC+c.Hysteresis:
+				event(ActionRestore, t.Name)
+				continue
+			case !s.isShed(t.Name):
+				continue
+			}
+			shed = append(shed, t.Name)
+		}
+		s.Shed = shed
+	case s.Island && sample.GridConnected:
+		s.Island = false
+		for _, n := range s.Shed {
+			event(ActionRestore, n)
+		}
+		s.Shed = nil
+		event(ActionGrid, "")
+	}
+}
+
+// Changes required to apply the shed tiers
+type Changes struct {
+	// actuators to switch off
+	Off []uint16
+	// actuators to switch on
+	On []uint16
+	// wallbox setting to apply, nil if unchanged
+	Wallbox *evcharge.Slot
+	// toggle the charging of the wallbox to stop or resume it
+	Toggle bool
+	// charging of the wallbox is stopped after the changes
+	stop bool
+	// charging of the wallbox was stopped by the manager before the changes
+	stopped bool
+	// wallbox current limit after the changes, nil if not limited
+	wallboxCurrent *uint8
+	// actuators switched off after the changes
+	off []uint16
+	// actuators found off before the shedding after the changes
+	kept []uint16
+}
+
+// Empty returns if nothing has to be changed
+func (ch Changes) Empty() bool {
+	return len(ch.Off) == 0 && len(ch.On) == 0 && ch.Wallbox == nil
+}
+
+// Changes compares the shed tiers with the applied actuators and wallbox limit
+func (s *State) Changes(c Config) Changes {
+	ch := Changes{}
+	off := map[uint16]bool{}
+	for _, t := range c.Tiers {
+		if !s.isShed(t.Name) {
+			continue
+		}
+		for _, a := range t.Actuators {
+			off[a] = true
+		}
+		if t.WallboxCurrent != nil && (ch.wallboxCurrent == nil || *t.WallboxCurrent < *ch.wallboxCurrent) {
+			ch.wallboxCurrent = t.WallboxCurrent
+		}
+	}
+	applied := map[uint16]bool{}
+	for _, a := range s.Off {
+		applied[a] = true
+		if !off[a] {
+			ch.On = append(ch.On, a)
+		}
+	}
+	kept := map[uint16]bool{}
+	for _, a := range s.Kept {
+		kept[a] = true
+	}
+	for a := range off {
+		if kept[a] {
+			ch.kept = append(ch.kept, a)
+			continue
+		}
+		ch.off = append(ch.off, a)
+		if !applied[a] {
+			ch.Off = append(ch.Off, a)
+		}
+	}
+	sortActuators(ch.off)
+	sortActuators(ch.Off)
+	sortActuators(ch.kept)
+	current, previous := ch.wallboxCurrent, s.WallboxCurrent
+	ch.stop, ch.stopped = current != nil && *current == 0, s.WallboxStopped
+	if c.Wallbox == nil || (current == nil && previous == nil) || (current != nil && previous != nil && *current == *previous) {
+		return ch
+	}
+	switch {
+	case current == nil:
+		ch.Wallbox = &evcharge.Slot{Mode: c.Wallbox.RestoreMode, Current: c.Wallbox.RestoreCurrent}
+	case *current == 0:
+		// sun mode charges from the pv surplus only, a charging car is stopped by toggling the charging
+		ch.Wallbox = &evcharge.Slot{Mode: evcharge.ModeSun, Current: c.Wallbox.RestoreCurrent}
+	default:
+		ch.Wallbox = &evcharge.Slot{Mode: evcharge.ModeMixed, Current: *current}
+	}
+	return ch
+}
+
+// sortActuators sorts the actuators ascending
+func sortActuators(a []uint16) {
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+}
+
+// keep leaves the actuators to switch off which are already off untouched, the restore doesn't switch them on
+func (ch *Changes) keep(on map[uint16]bool) {
+	kept := map[uint16]bool{}
+	var switched []uint16
+	for _, a := range ch.Off {
+		if isOn, known := on[a]; known && !isOn {
+			kept[a] = true
+			ch.kept = append(ch.kept, a)
+			continue
+		}
+		switched = append(switched, a)
+	}
+	var off []uint16
+	for _, a := range ch.off {
+		if !kept[a] {
+			off = append(off, a)
+		}
+	}
+	ch.Off, ch.off = switched, off
+	sortActuators(ch.kept)
+}
+
+// actuatorStates returns if the actuators are on by HA_DATAPOINT_INDEX,
+// actuators with an unknown state (i.e. groups) are missing.
+func actuatorStates(responses []rscp.Message) (map[uint16]bool, error) {
+	states := rscp.FindTag(responses, rscp.HA_ACTUATOR_STATES)
+	if states == nil {
+		return nil, fmt.Errorf("missing %s in response", rscp.HA_ACTUATOR_STATES)
+	}
+	datapoints, ok := states.Value.([]rscp.Message)
+	if !ok {
+		return nil, fmt.Errorf("%s %v: %w", rscp.HA_ACTUATOR_STATES, states.Value, rscp.ErrDataTypeValueMismatch)
+	}
+	on := map[uint16]bool{}
+	for _, dp := range datapoints {
+		values, ok := dp.Value.([]rscp.Message)
+		if dp.Tag != rscp.HA_DATAPOINT || !ok {
+			continue
+		}
+		index, state := rscp.FindTag(values, rscp.HA_DATAPOINT_INDEX), rscp.FindTag(values, rscp.HA_DATAPOINT_STATE)
+		if index == nil || state == nil {
+			continue
+		}
+		i, ierr := index.Float64()
+		s, serr := state.Float64()
+		if ierr != nil || serr != nil {
+			continue
+		}
+		// '1' is on and '2' off
+		switch s {
+		case '1':
+			on[uint16(i)] = true
+		case '2':
+			on[uint16(i)] = false
+		}
+	}
+	return on, nil
+}
+
+// apply marks the changes as applied
+func (s *State) apply(ch Changes) {
+	s.Off = ch.off
+	s.Kept = ch.kept
+	s.WallboxCurrent = ch.wallboxCurrent
+	s.WallboxStopped = ch.stopped
+}
+
+// wallboxNamespace returns the namespace of the wallbox tags
+func wallboxNamespace() rscp.Namespace {
+	n, _ := rscp.NamespaceByName("WB")
+	return n
+}
+
+// chargingRequests returns the requests required to read if the car charges
+func chargingRequests(c Config) ([]rscp.Message, error) {
+	index, err := c.Wallbox.index()
+	if err != nil {
+		return nil, err
+	}
+	wb, err := wallboxNamespace().NewRequest(index, rscp.WB_REQ_EXTERN_DATA_ALG)
+	if err != nil {
+		return nil, err
+	}
+	return []rscp.Message{*wb}, nil
+}
+
+// charging returns if the car charges from the responses of the requests returned by chargingRequests
+func charging(c Config, responses []rscp.Message) (bool, error) {
+	index, err := c.Wallbox.index()
+	if err != nil {
+		return false, err
+	}
+	n := wallboxNamespace()
+	wb := rscp.FindIndexed(responses, n.ResponseContainer, n.IndexTag, index)
+	if wb == nil {
+		return false, fmt.Errorf("missing %s with %s %d in response", n.ResponseContainer, n.IndexTag, index)
+	}
+	alg := rscp.FindTag(wb.Value.([]rscp.Message), rscp.WB_EXTERN_DATA_ALG)
+	if alg == nil || alg.DataType != rscp.Container {
+		return false, fmt.Errorf("missing %s in response", rscp.WB_EXTERN_DATA_ALG)
+	}
+	// byte 3: 1 if the car charges
+	data := rscp.FindTag(alg.Value.([]rscp.Message), rscp.WB_EXTERN_DATA)
+	if data == nil {
+		return false, fmt.Errorf("missing %s in %s", rscp.WB_EXTERN_DATA, rscp.WB_EXTERN_DATA_ALG)
+	}
+	b, ok := data.Value.([]byte)
+	if !ok || len(b) < 3 { //nolint: gomnd
+		return false, fmt.Errorf("%s %v: %w", rscp.WB_EXTERN_DATA_ALG, data.Value, rscp.ErrDataTypeValueMismatch)
+	}
+	return b[2] == 1, nil
+}
+
+// toggle decides if the charging has to be toggled, stopped only if the car charges and resumed only if it was
+// stopped by the manager and the car doesn't charge.
+func (ch *Changes) toggle(charging bool) {
+	switch {
+	case ch.stop && !ch.stopped:
+		ch.Toggle, ch.stopped = charging, charging
+	case !ch.stop && ch.stopped:
+		ch.Toggle, ch.stopped = !charging, false
+	}
+}
+
+// toggleRequests returns the requests to toggle the charging of the wallbox.
+//
+// the toggle isn't idempotent, it's sent on its own to keep the other changes retryable.
+func toggleRequests(c Config, ch Changes) ([]rscp.Message, error) {
+	index, err := c.Wallbox.index()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, externDataLen)
+	data[0] = byte(ch.Wallbox.Mode)
+	data[1] = ch.Wallbox.Current
+	// byte 5 toggles the charging of the type 2 connector
+	data[4] = 1
+	wb, err := wallboxNamespace().NewRequest(index,
+		rscp.WB_REQ_SET_EXTERN, rscp.WB_EXTERN_DATA, data, rscp.WB_EXTERN_DATA_LEN, uint8(externDataLen))
+	if err != nil {
+		return nil, err
+	}
+	return []rscp.Message{*wb}, nil
+}
+
+// commandRequest returns the request to send the command to the actuator
+func commandRequest(actuator uint16, command string) *rscp.Message {
+	return rscp.NewMessage(rscp.HA_REQ_COMMAND_ACTUATOR, []rscp.Message{
+		*rscp.NewMessage(rscp.HA_DATAPOINT_INDEX, actuator),
+		*rscp.NewMessage(rscp.HA_REQ_COMMAND, command),
+	})
+}
+
+// SetRequests returns the requests to apply the changes
+func SetRequests(c Config, ch Changes) ([]rscp.Message, error) {
+	requests := make([]rscp.Message, 0, len(ch.Off)+len(ch.On))
+	for _, a := range ch.Off {
+		requests = append(requests, *commandRequest(a, c.OffCommand))
+	}
+	for _, a := range ch.On {
+		requests = append(requests, *commandRequest(a, c.OnCommand))
+	}
+	if ch.Wallbox != nil {
+		wb, err := evcharge.SetRequests(evcharge.Config{Wallbox: c.Wallbox.Index}, *ch.Wallbox)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, wb...)
+	}
+	return requests, nil
+}
+
+// checkResponses checks if all changes were accepted
+func checkResponses(ch Changes, responses []rscp.Message) error {
+	accepted := 0
+	for _, m := range responses {
+		if m.Tag == rscp.HA_COMMAND_ACTUATOR && m.Value == true {
+			accepted++
+		}
+	}
+	if n := len(ch.Off) + len(ch.On); accepted != n {
+		return fmt.Errorf("%s accepted %d of %d: %v", rscp.HA_REQ_COMMAND_ACTUATOR, accepted, n, responses)
+	}
+	if ch.Wallbox == nil {
+		return nil
+	}
+	if m := rscp.FindTag(responses, rscp.WB_SET_EXTERN); m == nil || m.DataType == rscp.Error {
+		return fmt.Errorf("%s not accepted: %v", rscp.WB_REQ_SET_EXTERN, responses)
+	}
+	return nil
+}
+
+// Step requests a new sample, decides the shed tiers and applies the changes.
+//
+// changes not accepted are applied again on the next step.
+// With dryRun the changes are only logged.
+func Step(sender rscp.Sender, c Config, s *State, now time.Time, dryRun bool) (Changes, error) {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		sample    Sample
+		err       error
+	)
+	if requests, err = Requests(); err != nil {
+		return Changes{}, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return Changes{}, err
+	}
+	if sample, err = NewSample(now, responses); err != nil {
+		return Changes{}, err
+	}
+	s.Decide(c, sample, dryRun)
+	ch := s.Changes(c)
+	if len(ch.Off) > 0 {
+		// the state before the shedding is restored
+		if responses, err = sender.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.HA_REQ_ACTUATOR_STATES, nil)}); err != nil {
+			return ch, err
+		}
+		var on map[uint16]bool
+		if on, err = actuatorStates(responses); err != nil {
+			return ch, err
+		}
+		ch.keep(on)
+	}
+	if ch.Wallbox != nil && ch.stop != ch.stopped {
+		if requests, err = chargingRequests(c); err != nil {
+			return ch, err
+		}
+		if responses, err = sender.SendMultiple(requests); err != nil {
+			return ch, err
+		}
+		var isCharging bool
+		if isCharging, err = charging(c, responses); err != nil {
+			return ch, err
+		}
+		ch.toggle(isCharging)
+	}
+	if ch.Empty() {
+		s.apply(ch)
+		return ch, nil
+	}
+	log.Infof("switch off %v, switch on %v, wallbox %+v, toggle charging %t", ch.Off, ch.On, ch.Wallbox, ch.Toggle)
+	if dryRun {
+		s.apply(ch)
+		return ch, nil
+	}
+	if requests, err = SetRequests(c, ch); err != nil {
+		return ch, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return ch, err
+	}
+	if err := checkResponses(ch, responses); err != nil {
+		return ch, err
+	}
+	if ch.Toggle {
+		if requests, err = toggleRequests(c, ch); err != nil {
+			return ch, err
+		}
+		if responses, err = sender.SendMultiple(requests); err != nil {
+			return ch, err
+		}
+		if m := rscp.FindTag(responses, rscp.WB_SET_EXTERN); m == nil || m.DataType == rscp.Error {
+			return ch, fmt.Errorf("%s not accepted: %v", rscp.WB_REQ_SET_EXTERN, responses)
+		}
+	}
+	s.apply(ch)
+	return ch, nil
+}
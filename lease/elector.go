@@ -0,0 +1,147 @@
+package lease
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// marginDivisor defines the default margin as fraction of the ttl
+const marginDivisor = 5
+
+// renewDivisor defines the renew interval as fraction of the ttl
+const renewDivisor = 3
+
+// Elector acquires and renews the lease for an instance
+type Elector struct {
+	Store Store
+	// name of the instance, has to be unique among all instances
+	Holder string
+	// time the lease is valid after a renewal
+	TTL time.Duration
+	// time before the lease expires the leader steps down, TTL/5 if 0
+	Margin time.Duration
+	// Now returns the current time, time.Now if nil
+	Now func() time.Time
+
+	mu sync.Mutex
+	// the instance is leader until the deadline
+	deadline time.Time
+	term     uint64
+}
+
+// now returns the current time
+func (e *Elector) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+	return time.Now()
+}
+
+// check validates the settings
+func (e *Elector) check() error {
+	if e.TTL <= 0 || e.Margin < 0 || e.Margin >= e.TTL {
+		return fmt.Errorf("ttl %s margin %s: %w", e.TTL, e.Margin, ErrInvalidTTL)
+	}
+	if e.Store == nil {
+		return ErrInvalidStore
+	}
+	return nil
+}
+
+// margin returns the margin before the expiry
+func (e *Elector) margin() time.Duration {
+	if e.Margin > 0 {
+		return e.Margin
+	}
+	return e.TTL / marginDivisor
+}
+
+// Renew acquires or renews the lease and returns if the instance is the leader.
+//
+// the validity is measured from before the store is contacted, so the leader always steps down before the lease expires.
+// On a store error the leader stays leader until its last renewal expires.
+func (e *Elector) Renew() (bool, error) {
+	if err := e.check(); err != nil {
+		return false, err
+	}
+	now := e.now()
+	l, err := e.Store.Acquire(e.Holder, e.TTL, now)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	was := now.Before(e.deadline)
+	if err != nil {
+		return was, err
+	}
+	if l.Holder == e.Holder {
+		e.deadline, e.term = now.Add(e.TTL-e.margin()), l.Term
+	} else {
+		e.deadline = time.Time{}
+	}
+	is := now.Before(e.deadline)
+	switch {
+	case is && !was:
+		log.Infof("%s became leader (term %d)", e.Holder, l.Term)
+	case !is && was:
+		log.Warnf("%s lost the lease to %s (term %d)", e.Holder, l.Holder, l.Term)
+	}
+	return is, nil
+}
+
+// Leader returns if the instance is the leader
+func (e *Elector) Leader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.now().Before(e.deadline)
+}
+
+// Term returns the term of the last lease held
+func (e *Elector) Term() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term
+}
+
+// Release steps down and releases the lease for a fast takeover by a standby
+func (e *Elector) Release() error {
+	e.mu.Lock()
+	e.deadline = time.Time{}
+	e.mu.Unlock()
+	return e.Store.Release(e.Holder)
+}
+
+// Run renews the lease every third of the ttl until stop is closed, the lease is released on return
+func (e *Elector) Run(stop <-chan struct{}) error {
+	if err := e.check(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(e.TTL / renewDivisor)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Renew(); err != nil {
+			log.Errorf("lease renewal failed: %s", err)
+		}
+		select {
+		case <-stop:
+			return e.Release()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sender only passes requests containing setters while the instance is the leader
+type Sender struct {
+	Sender  rscp.Sender
+	Elector *Elector
+}
+
+// SendMultiple sends the requests or fails with ErrNotLeader if they contain a setter and the instance is not the leader
+func (s Sender) SendMultiple(messages []rscp.Message) ([]rscp.Message, error) {
+	if rscp.ContainsSetter(messages) && !s.Elector.Leader() {
+		return nil, ErrNotLeader
+	}
+	return s.Sender.SendMultiple(messages)
+}
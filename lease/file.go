@@ -0,0 +1,100 @@
+package lease
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+// lock timing of the file store
+//nolint: gomnd
+var (
+	lockRetry   = time.Millisecond * 10
+	lockTimeout = time.Second
+)
+
+// FileStore stores the lease in a json file on a file system shared by all instances.
+//
+// changes of the lease are serialized by a lock file created exclusively next to it. The lease is granted and
+// expires by the local clock of each host, a clock skew between the hosts larger than the margin of the elector
+// lets a standby take over while the leader still considers itself active, so two instances may be active at once.
+// Use the lease server (HTTPStore) if the clocks of the hosts aren't synchronized.
+type FileStore struct {
+	Path string
+}
+
+// takeover removes the stale lock left over by a crashed instance.
+//
+// the lock is renamed to a unique name first, so only one of the instances racing for it removes it,
+// and restored if it was replaced by a fresh lock since it was found stale.
+func takeover(path string, stale time.Duration) {
+	hostname, _ := os.Hostname()
+	tmp := fmt.Sprintf("%s.%s-%d-%d", path, hostname, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, tmp); err != nil {
+		return
+	}
+	if fi, err := os.Stat(tmp); err == nil && time.Since(fi.ModTime()) <= stale {
+		// fails if yet another lock was created meanwhile
+		_ = os.Link(tmp, path)
+	}
+	_ = os.Remove(tmp)
+}
+
+// lock creates the lock file and returns the function to remove it,
+// a lock older than stale is left over by a crashed instance and taken over, 0 never takes over.
+func (f FileStore) lock(stale time.Duration) (func(), error) {
+	path := f.Path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		l, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint: gomnd
+		if err == nil {
+			_ = l.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if fi, err := os.Stat(path); err == nil && stale > 0 && time.Since(fi.ModTime()) > stale {
+			takeover(path, stale)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// update applies the change to the lease while holding the lock
+func (f FileStore) update(stale time.Duration, change func(Lease) Lease) (Lease, error) {
+	unlock, err := f.lock(stale)
+	if err != nil {
+		return Lease{}, err
+	}
+	defer unlock()
+	l := Lease{}
+	if err := jsonfile.Read(f.Path, &l); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Lease{}, err
+	}
+	n := change(l)
+	if n == l {
+		return l, nil
+	}
+	return n, jsonfile.Write(f.Path, n)
+}
+
+// Acquire acquires or renews the lease for the holder and returns the lease after the attempt.
+//
+// a lock older than the renew interval of the ttl is taken over, so a crashed instance delays the next renewal at most.
+func (f FileStore) Acquire(holder string, ttl time.Duration, now time.Time) (Lease, error) {
+	return f.update(ttl/renewDivisor, func(l Lease) Lease { return grant(l, holder, ttl, now) })
+}
+
+// Release releases the lease if held by the holder, a stale lock isn't taken over as the lease expires anyway
+func (f FileStore) Release(holder string) error {
+	_, err := f.update(0, func(l Lease) Lease { return release(l, holder) })
+	return err
+}
@@ -0,0 +1,117 @@
+package lease
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// request of the lease server
+type request struct {
+	Holder string `json:"holder"`
+	// ttl in milliseconds, 0 releases the lease
+	TTL int64 `json:"ttl"`
+}
+
+// Server is a minimal lease server as stand-in for an etcd like service.
+//
+// the lease name is the last path element, POST with a json request acquires, renews or releases a lease
+// and responds with the lease after the attempt. The time of the server is used for the expiry.
+type Server struct {
+	// Now returns the current time, time.Now if nil
+	Now    func() time.Time
+	mu     sync.Mutex
+	leases map[string]Lease
+}
+
+// ServeHTTP handles a lease request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	req := request{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Holder == "" || name == "" {
+		http.Error(w, "invalid lease request", http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	s.mu.Lock()
+	if s.leases == nil {
+		s.leases = map[string]Lease{}
+	}
+	if req.TTL > 0 {
+		s.leases[name] = grant(s.leases[name], req.Holder, time.Duration(req.TTL)*time.Millisecond, now)
+	} else {
+		s.leases[name] = release(s.leases[name], req.Holder)
+	}
+	l := s.leases[name]
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(l)
+}
+
+// releaseTimeout limits the time of a release request
+const releaseTimeout = time.Second * 5
+
+// HTTPStore stores the lease on a lease server
+type HTTPStore struct {
+	// URL of the lease including its name (i.e. http://localhost:8080/lease/battery)
+	URL    string
+	Client *http.Client
+}
+
+// post sends the request to the server, a request not answered within the timeout fails
+func (h HTTPStore) post(req request, timeout time.Duration) (Lease, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return Lease{}, err
+	}
+	c := h.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(b))
+	if err != nil {
+		return Lease{}, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(r)
+	if err != nil {
+		return Lease{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Lease{}, fmt.Errorf("lease server %s: %s", h.URL, resp.Status)
+	}
+	l := Lease{}
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return Lease{}, fmt.Errorf("lease server %s: %w", h.URL, err)
+	}
+	return l, nil
+}
+
+// Acquire acquires or renews the lease for the holder and returns the lease after the attempt.
+//
+// the expiry is determined by the clock of the server, now is ignored. The request fails if not answered
+// within the renew interval, so a hanging server doesn't delay the step down of the leader.
+func (h HTTPStore) Acquire(holder string, ttl time.Duration, now time.Time) (Lease, error) {
+	return h.post(request{Holder: holder, TTL: ttl.Milliseconds()}, ttl/renewDivisor)
+}
+
+// Release releases the lease if held by the holder
+func (h HTTPStore) Release(holder string) error {
+	_, err := h.post(request{Holder: holder}, releaseTimeout)
+	return err
+}
@@ -0,0 +1,62 @@
+// Package lease provides a lease based leader election for control daemons running active/standby.
+//
+// all instances keep polling, only the leader holding the lease issues setter requests.
+// The lease is stored in a shared file or on a lease server and has to be renewed within its ttl,
+// a standby takes over as soon as the leader stops renewing. The leader steps down by its own clock
+// a margin before the lease expires, so a leader losing the store never overlaps with its successor.
+package lease
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotLeader    = errors.New("not the leader")
+	ErrInvalidTTL   = errors.New("invalid lease ttl")
+	ErrLockTimeout  = errors.New("timeout waiting for the lease lock")
+	ErrInvalidStore = errors.New("invalid lease store")
+)
+
+// Lease held by an instance
+type Lease struct {
+	// name of the instance holding the lease, empty if released
+	Holder string `json:"holder"`
+	// time the lease expires if not renewed
+	Expires time.Time `json:"expires"`
+	// incremented on every change of the holder, usable as fencing token
+	Term uint64 `json:"term"`
+}
+
+// HeldBy returns if the lease is held by the holder at the time
+func (l Lease) HeldBy(holder string, now time.Time) bool {
+	return l.Holder == holder && l.Expires.After(now)
+}
+
+// grant returns the lease after the holder tried to acquire or renew it
+func grant(l Lease, holder string, ttl time.Duration, now time.Time) Lease {
+	switch {
+	case l.Holder == holder:
+		// renew, the term stays the same as long as nobody else acquired the lease
+		l.Expires = now.Add(ttl)
+	case l.Holder == "" || !l.Expires.After(now):
+		l = Lease{Holder: holder, Expires: now.Add(ttl), Term: l.Term + 1}
+	}
+	return l
+}
+
+// release returns the lease after the holder released it
+func release(l Lease, holder string) Lease {
+	if l.Holder == holder {
+		l.Holder, l.Expires = "", time.Time{}
+	}
+	return l
+}
+
+// Store stores the lease shared by all instances
+type Store interface {
+	// Acquire acquires or renews the lease for the holder and returns the lease after the attempt
+	Acquire(holder string, ttl time.Duration, now time.Time) (Lease, error)
+	// Release releases the lease if held by the holder
+	Release(holder string) error
+}
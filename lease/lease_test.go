@@ -0,0 +1,247 @@
+package lease
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func Test_grant(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ttl := time.Second * 10
+	tests := []struct {
+		name   string
+		lease  Lease
+		holder string
+		want   Lease
+	}{
+		{"free", Lease{}, "a", Lease{"a", now.Add(ttl), 1}},
+		{"renew", Lease{"a", now.Add(time.Second), 1}, "a", Lease{"a", now.Add(ttl), 1}},
+		{"held by other", Lease{"b", now.Add(time.Second), 1}, "a", Lease{"b", now.Add(time.Second), 1}},
+		{"expired", Lease{"b", now, 1}, "a", Lease{"a", now.Add(ttl), 2}},
+		{"released", Lease{"", time.Time{}, 3}, "a", Lease{"a", now.Add(ttl), 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(grant(tt.lease, tt.holder, ttl, now), tt.want); diff != nil {
+				t.Errorf("grant() %s", diff)
+			}
+		})
+	}
+}
+
+// clock is a fake clock shared by all instances
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// partition simulates an instance losing the connection to the store
+type partition struct {
+	Store
+	lost bool
+}
+
+var errPartition = errors.New("store unreachable")
+
+func (p *partition) Acquire(holder string, ttl time.Duration, now time.Time) (Lease, error) {
+	if p.lost {
+		return Lease{}, errPartition
+	}
+	return p.Store.Acquire(holder, ttl, now)
+}
+
+func stores(t *testing.T, c *clock) map[string]func() Store {
+	server := httptest.NewServer(&Server{Now: c.Now})
+	t.Cleanup(server.Close)
+	path := filepath.Join(t.TempDir(), "lease.json")
+	return map[string]func() Store{
+		"file": func() Store { return FileStore{Path: path} },
+		"http": func() Store { return HTTPStore{URL: server.URL + "/lease/battery"} },
+	}
+}
+
+func TestElector_failover(t *testing.T) {
+	c := &clock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ttl := time.Second * 9
+	for name, store := range stores(t, c) {
+		t.Run(name, func(t *testing.T) {
+			pa, pb := &partition{Store: store()}, &partition{Store: store()}
+			a := &Elector{Store: pa, Holder: "a", TTL: ttl, Now: c.Now}
+			b := &Elector{Store: pb, Holder: "b", TTL: ttl, Now: c.Now}
+			if leader, err := a.Renew(); !leader || err != nil {
+				t.Fatalf("a.Renew() = %v, %v, want leader", leader, err)
+			}
+			if leader, err := b.Renew(); leader || err != nil {
+				t.Fatalf("b.Renew() = %v, %v, want standby", leader, err)
+			}
+			term := a.Term()
+			// a loses the store in the middle of the lease, both keep renewing every second
+			var takeover time.Duration
+			for elapsed := time.Duration(0); elapsed < ttl*2; elapsed += time.Second {
+				pa.lost = elapsed >= ttl/3
+				_, errA := a.Renew()
+				if _, err := b.Renew(); err != nil {
+					t.Fatalf("b.Renew() error = %v", err)
+				}
+				if pa.lost && !errors.Is(errA, errPartition) {
+					t.Fatalf("a.Renew() error = %v, want %v", errA, errPartition)
+				}
+				if a.Leader() && b.Leader() {
+					t.Fatalf("split brain after %s", elapsed)
+				}
+				if b.Leader() && takeover == 0 {
+					takeover = elapsed
+				}
+				c.advance(time.Second)
+			}
+			if takeover == 0 || takeover > ttl+ttl/3+time.Second {
+				t.Errorf("takeover after %s, want within the ttl after the last renewal", takeover)
+			}
+			if b.Term() != term+1 {
+				t.Errorf("b.Term() = %d, want %d", b.Term(), term+1)
+			}
+			// a returns and stays standby
+			pa.lost = false
+			if leader, err := a.Renew(); leader || err != nil {
+				t.Errorf("a.Renew() = %v, %v, want standby", leader, err)
+			}
+			// a released lease is taken over immediately
+			if err := b.Release(); err != nil {
+				t.Fatalf("b.Release() error = %v", err)
+			}
+			if leader, err := a.Renew(); !leader || err != nil {
+				t.Errorf("a.Renew() = %v, %v, want leader after release", leader, err)
+			}
+			_ = a.Release()
+		})
+	}
+}
+
+func TestHTTPStore_timeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-done }))
+	defer server.Close()
+	defer close(done)
+	start := time.Now()
+	if _, err := (HTTPStore{URL: server.URL + "/lease/battery"}).Acquire("a", time.Millisecond*300, start); err == nil {
+		t.Fatal("Acquire() of a hanging server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Acquire() failed after %s, want within the renew interval", elapsed)
+	}
+}
+
+func TestFileStore_concurrent(t *testing.T) {
+	s := FileStore{Path: filepath.Join(t.TempDir(), "lease.json")}
+	now := time.Now()
+	holders := make([]string, 20)
+	var wg sync.WaitGroup
+	for i := range holders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l, err := s.Acquire(fmt.Sprintf("instance%d", i), time.Minute, now)
+			if err != nil {
+				t.Errorf("Acquire() error = %v", err)
+			}
+			holders[i] = l.Holder
+		}(i)
+	}
+	wg.Wait()
+	for _, h := range holders {
+		if h != holders[0] {
+			t.Fatalf("split brain, holders %v", holders)
+		}
+	}
+}
+
+func TestFileStore_staleLock(t *testing.T) {
+	dir := t.TempDir()
+	s := FileStore{Path: filepath.Join(dir, "lease.json")}
+	lock := s.Path + ".lock"
+	if err := os.WriteFile(lock, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = time.Millisecond * 50
+	// a fresh lock is kept even if a racing instance renames it
+	takeover(lock, time.Second*5)
+	if _, err := s.Acquire("a", time.Second*15, time.Now()); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("Acquire() with a fresh lock error = %v, want %v", err, ErrLockTimeout)
+	}
+	// a lock older than the renew interval is left over by a crashed instance
+	old := time.Now().Add(-time.Second * 6)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := s.Acquire("a", time.Second*15, time.Now()); err != nil || l.Holder != "a" {
+		t.Errorf("Acquire() with a stale lock = %+v, error = %v", l, err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 1 {
+		t.Errorf("files left over %v, want the lease only", files)
+	}
+}
+
+// recorder records the sent requests
+type recorder struct {
+	sent int
+}
+
+func (r *recorder) SendMultiple(messages []rscp.Message) ([]rscp.Message, error) {
+	r.sent++
+	return nil, nil
+}
+
+func TestSender(t *testing.T) {
+	c := &clock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := FileStore{Path: filepath.Join(t.TempDir(), "lease.json")}
+	a := &Elector{Store: store, Holder: "a", TTL: time.Second * 10, Now: c.Now}
+	b := &Elector{Store: store, Holder: "b", TTL: time.Second * 10, Now: c.Now}
+	_, _ = a.Renew()
+	_, _ = b.Renew()
+	getter := []rscp.Message{*rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil)}
+	setter, _ := rscp.NewSetPowerRequest(rscp.POWER_MODE_IDLE, 0)
+	for _, tt := range []struct {
+		elector  *Elector
+		messages []rscp.Message
+		wantErr  error
+	}{
+		{a, getter, nil},
+		{a, []rscp.Message{*setter}, nil},
+		{b, getter, nil},
+		{b, []rscp.Message{*setter}, ErrNotLeader},
+	} {
+		r := &recorder{}
+		_, err := Sender{Sender: r, Elector: tt.elector}.SendMultiple(tt.messages)
+		if !errors.Is(err, tt.wantErr) || (err == nil) != (r.sent == 1) {
+			t.Errorf("%s: SendMultiple(%v) error = %v, sent %d", tt.elector.Holder, tt.messages, err, r.sent)
+		}
+	}
+	// the leader steps down before the lease expires
+	c.advance(time.Second * 8)
+	if a.Leader() {
+		t.Errorf("a.Leader() = true after %s", time.Second*8)
+	}
+}
@@ -0,0 +1,172 @@
+// Package preserve controls the battery to preserve its longevity.
+//
+// depending on the season the controller keeps a minimum state of charge by grid charging,
+// does periodic maintenance charges to fully charge the battery and avoids long periods at a high state of charge
+// by holding the battery idle while the pv surplus is fed into the grid.
+// All actions are applied by EMS_REQ_SET_POWER.
+package preserve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+var (
+	ErrNoPolicies   = errors.New("no policies configured")
+	ErrInvalidMonth = errors.New("invalid month")
+	ErrInvalidSoC   = errors.New("state of charge out of range")
+)
+
+// fullPercent is the upper bound of the state of charge
+const fullPercent = 100
+
+// Policy of a season
+type Policy struct {
+	// name used in the logs
+	Name string `json:"name"`
+	// months the policy applies to, all months if empty
+	Months []time.Month `json:"months"`
+	// minimum state of charge in % enforced by grid charging, 0 disables
+	MinSoC float64 `json:"minSoc"`
+	// state of charge in % above MinSoC the grid charge continues to
+	Hysteresis float64 `json:"hysteresis"`
+	// maximum time without a full charge until a maintenance charge is done, 0 disables
+	MaintenanceInterval time.Duration `json:"-"`
+	// state of charge in % considered as fully charged
+	FullSoC float64 `json:"fullSoc"`
+	// state of charge in % the battery is held idle instead of charged on grid feed-in, 0 disables
+	MaxSoC float64 `json:"maxSoc"`
+	// grid charge power in W
+	ChargePower int32 `json:"chargePower"`
+}
+
+// defaultPolicy defines the default policy values used when not provided by the user.
+//nolint: gomnd
+var defaultPolicy = Policy{
+	Hysteresis:  5,
+	FullSoC:     100,
+	ChargePower: 3000,
+}
+
+// UnmarshalJSON unmarshals the policy, the maintenance interval is expected as duration string (i.e. "336h")
+func (p *Policy) UnmarshalJSON(b []byte) error {
+	type policy Policy
+	tmp := struct {
+		*policy
+		MaintenanceInterval string `json:"maintenanceInterval"`
+	}{policy: (*policy)(p)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.MaintenanceInterval != "" {
+		var err error
+		if p.MaintenanceInterval, err = time.ParseDuration(tmp.MaintenanceInterval); err != nil {
+			return fmt.Errorf("invalid maintenance interval: %w", err)
+		}
+	}
+	return nil
+}
+
+// appliesTo returns if the policy applies to the month
+func (p Policy) appliesTo(m time.Month) bool {
+	if len(p.Months) == 0 {
+		return true
+	}
+	for _, pm := range p.Months {
+		if pm == m {
+			return true
+		}
+	}
+	return false
+}
+
+// check does set default values on missing or fail if invalid
+func (p *Policy) check() error {
+	for _, m := range p.Months {
+		if m < time.January || m > time.December {
+			return fmt.Errorf("policy %s month %d: %w", p.Name, m, ErrInvalidMonth)
+		}
+	}
+	if p.Hysteresis <= 0 {
+		p.Hysteresis = defaultPolicy.Hysteresis
+	}
+	if p.FullSoC <= 0 {
+		p.FullSoC = defaultPolicy.FullSoC
+	}
+	if p.ChargePower <= 0 {
+		p.ChargePower = defaultPolicy.ChargePower
+	}
+	for _, soc := range []float64{p.MinSoC, p.FullSoC, p.MaxSoC} {
+		if soc < 0 || soc > fullPercent {
+			return fmt.Errorf("policy %s %f%%: %w", p.Name, soc, ErrInvalidSoC)
+		}
+	}
+	return nil
+}
+
+// Config of the controller
+type Config struct {
+	// policies by priority, the first policy applying to the current month is used
+	Policies []Policy `json:"policies"`
+	// location used to determine the month
+	Location *time.Location `json:"-"`
+}
+
+// UnmarshalJSON unmarshals the config, the location is expected as IANA time zone name (i.e. "Europe/Berlin").
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	tmp := struct {
+		*config
+		Location string `json:"location"`
+	}{config: (*config)(c)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.Location != "" {
+		var err error
+		if c.Location, err = time.LoadLocation(tmp.Location); err != nil {
+			return fmt.Errorf("invalid location: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if required
+func (c *Config) check() error {
+	if len(c.Policies) == 0 {
+		return ErrNoPolicies
+	}
+	for i := range c.Policies {
+		if err := c.Policies[i].check(); err != nil {
+			return err
+		}
+	}
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+	return nil
+}
+
+// Policy returns the policy applying at the time or nil if none applies
+func (c Config) Policy(t time.Time) *Policy {
+	m := t.In(c.Location).Month()
+	for i := range c.Policies {
+		if c.Policies[i].appliesTo(m) {
+			return &c.Policies[i]
+		}
+	}
+	return nil
+}
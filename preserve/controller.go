@@ -0,0 +1,211 @@
+package preserve
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Action of the controller
+type Action string
+
+// all actions of the controller
+const (
+	// normal operation of the EMS
+	ActionAuto Action = "auto"
+	// grid charge to restore the minimum state of charge
+	ActionMinimum Action = "minimum"
+	// grid charge to fully charge the battery
+	ActionMaintenance Action = "maintenance"
+	// hold the battery idle at the maximum state of charge
+	ActionHold Action = "hold"
+)
+
+// maxEvents limits the number of events kept in the state
+const maxEvents = 1000
+
+// Sample is a single reading of the battery state
+type Sample struct {
+	Time time.Time `json:"time"`
+	// battery state of charge in %
+	SoC float64 `json:"soc"`
+	// grid power in W (-=feed in / +=import)
+	Grid float64 `json:"grid"`
+}
+
+// Requests returns the requests required to create a sample
+func Requests() ([]rscp.Message, error) {
+	return rscp.CreateRequests(
+		[]interface{}{rscp.EMS_REQ_BAT_SOC},
+		[]interface{}{rscp.EMS_REQ_POWER_GRID},
+	)
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(t time.Time, responses []rscp.Message) (Sample, error) {
+	s := Sample{Time: t}
+	for tag, v := range map[rscp.Tag]*float64{
+		rscp.EMS_BAT_SOC:    &s.SoC,
+		rscp.EMS_POWER_GRID: &s.Grid,
+	} {
+		m := rscp.FindTag(responses, tag)
+		if m == nil {
+			return s, fmt.Errorf("missing %s in response", tag)
+		}
+		var err error
+		if *v, err = m.Float64(); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// Event is a change of the action
+type Event struct {
+	Time   time.Time `json:"time"`
+	Policy string    `json:"policy"`
+	From   Action    `json:"from"`
+	To     Action    `json:"to"`
+	SoC    float64   `json:"soc"`
+	Reason string    `json:"reason"`
+}
+
+// State of the controller, persisted between runs
+type State struct {
+	// action currently applied
+	Action Action `json:"action"`
+	// last time the battery was fully charged
+	LastFull time.Time `json:"lastFull"`
+	// action changes, limited to the most recent ones
+	Events []Event `json:"events"`
+}
+
+// LoadState reads the state from a json file, a missing file results in an empty state
+func LoadState(path string) (*State, error) {
+	s := &State{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to a json file
+func (s *State) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// Decision of the controller for a sample
+type Decision struct {
+	Action Action
+	// policy applied or empty if none applies
+	Policy string
+	Reason string
+	Mode   rscp.PowerMode
+	Power  int32
+}
+
+// Decide determines the action for the sample and records it in the state
+func (s *State) Decide(c Config, sample Sample) Decision {
+	d := s.next(c, sample)
+	s.commit(d, sample)
+	return d
+}
+
+// next determines the action for the sample without recording it
+func (s *State) next(c Config, sample Sample) Decision {
+	p := c.Policy(sample.Time)
+	if p == nil {
+		return Decision{Action: ActionAuto, Mode: rscp.POWER_MODE_AUTO, Reason: "no policy applies"}
+	}
+	d := decide(*p, s, sample)
+	d.Policy = p.Name
+	return d
+}
+
+// commit records the action as applied and logs the change
+func (s *State) commit(d Decision, sample Sample) {
+	if d.Action != s.Action {
+		from := s.Action
+		if from == "" {
+			from = ActionAuto
+		}
+		e := Event{Time: sample.Time, Policy: d.Policy, From: from, To: d.Action, SoC: sample.SoC, Reason: d.Reason}
+		log.Infof("policy %q: %s -> %s at %.0f%% (%s)", e.Policy, e.From, e.To, e.SoC, e.Reason)
+		s.Events = append(s.Events, e)
+		if len(s.Events) > maxEvents {
+			s.Events = s.Events[len(s.Events)-maxEvents:]
+		}
+	}
+	s.Action = d.Action
+}
+
+// decide determines the action of the policy
+func decide(p Policy, s *State, sample Sample) Decision {
+	if sample.SoC >= p.FullSoC || s.LastFull.IsZero() {
+		// the first sample starts the maintenance interval
+		s.LastFull = sample.Time
+	}
+	charge := func(a Action, reason string, args ...interface{}) Decision {
+		return Decision{Action: a, Reason: fmt.Sprintf(reason, args...), Mode: rscp.POWER_MODE_GRID_CHARGE, Power: p.ChargePower}
+	}
+	switch {
+	case s.Action == ActionMaintenance && sample.SoC < p.FullSoC:
+		return charge(ActionMaintenance, "maintenance charge until %.0f%%", p.FullSoC)
+	case p.MaintenanceInterval > 0 && sample.Time.Sub(s.LastFull) >= p.MaintenanceInterval:
+		return charge(ActionMaintenance, "not fully charged since %s", s.LastFull.Format(time.RFC3339))
+	case s.Action == ActionMinimum && sample.SoC < p.MinSoC+p.Hysteresis:
+		return charge(ActionMinimum, "charge until %.0f%%", p.MinSoC+p.Hysteresis)
+	case sample.SoC < p.MinSoC:
+		return charge(ActionMinimum, "below minimum of %.0f%%", p.MinSoC)
+	case p.MaxSoC > 0 && sample.SoC >= p.MaxSoC && sample.Grid < 0:
+		return Decision{Action: ActionHold, Reason: fmt.Sprintf("reached maximum of %.0f%%", p.MaxSoC), Mode: rscp.POWER_MODE_IDLE}
+	}
+	return Decision{Action: ActionAuto, Mode: rscp.POWER_MODE_AUTO, Reason: "within policy"}
+}
+
+// Step requests a new sample, decides and applies the action.
+//
+// any action but auto has to be applied on every step (at least every 30 seconds) to stay active,
+// auto is applied once to leave the previous action immediately. The action is only recorded in the state
+// after the EMS accepted it, so a rejected auto is sent again on the next step.
+// With dryRun the action is only decided and logged.
+func Step(sender rscp.Sender, c Config, s *State, now time.Time, dryRun bool) (Decision, error) {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		sample    Sample
+		err       error
+	)
+	if requests, err = Requests(); err != nil {
+		return Decision{}, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return Decision{}, err
+	}
+	if sample, err = NewSample(now, responses); err != nil {
+		return Decision{}, err
+	}
+	d := s.next(c, sample)
+	if dryRun || (d.Action == ActionAuto && (s.Action == ActionAuto || s.Action == "")) {
+		s.commit(d, sample)
+		return d, nil
+	}
+	var r *rscp.Message
+	if r, err = rscp.NewSetPowerRequest(d.Mode, d.Power); err != nil {
+		return d, err
+	}
+	if responses, err = sender.SendMultiple([]rscp.Message{*r}); err != nil {
+		return d, err
+	}
+	if m := rscp.FindTag(responses, rscp.EMS_SET_POWER); m == nil || m.DataType == rscp.Error {
+		// an active action is applied again on the next step, the EMS returns to auto by itself
+		return d, fmt.Errorf("%s %s %d not accepted: %v", rscp.EMS_REQ_SET_POWER, d.Mode, d.Power, responses)
+	}
+	s.commit(d, sample)
+	return d, nil
+}
@@ -0,0 +1,227 @@
+package preserve
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Config
+		wantErr error
+	}{
+		{"defaults",
+			`{"location":"UTC","policies":[{"name":"winter","months":[11,12,1,2],"minSoc":10,"maintenanceInterval":"336h"}]}`,
+			Config{Location: time.UTC, Policies: []Policy{{
+				Name: "winter", Months: []time.Month{11, 12, 1, 2}, MinSoC: 10, MaintenanceInterval: time.Hour * 336,
+				Hysteresis: 5, FullSoC: 100, ChargePower: 3000,
+			}}},
+			nil,
+		},
+		{"no policies", `{}`, Config{}, ErrNoPolicies},
+		{"invalid month", `{"policies":[{"months":[13]}]}`, Config{}, ErrInvalidMonth},
+		{"invalid soc", `{"policies":[{"maxSoc":101}]}`, Config{}, ErrInvalidSoC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			err := json.Unmarshal([]byte(tt.json), &c)
+			if err == nil {
+				err = c.check()
+			}
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(c, tt.want); diff != nil {
+				t.Errorf("UnmarshalJSON() = %v, want %v\n%s", c, tt.want, diff)
+			}
+		})
+	}
+}
+
+var simConfig = Config{
+	Location: time.UTC,
+	Policies: []Policy{
+		{Name: "winter", Months: []time.Month{11, 12, 1, 2}, MinSoC: 10, Hysteresis: 5, FullSoC: 100,
+			MaintenanceInterval: time.Hour * 24 * 14, ChargePower: 3000},
+		{Name: "summer", Months: []time.Month{5, 6, 7, 8}, MaxSoC: 80, FullSoC: 100, Hysteresis: 5, ChargePower: 3000},
+	},
+}
+
+// simulator is an accelerated simulation of a system with a 10 kWh battery
+type simulator struct {
+	now     time.Time
+	soc     float64
+	grid    float64
+	peakPV  float64
+	mode    rscp.PowerMode
+	power   int32
+	applied time.Time
+	// set power requests are rejected
+	reject bool
+}
+
+const (
+	simCapacity = 10000.0
+	simMaxPower = 3000.0
+	simHome     = 500.0
+)
+
+func (s *simulator) SendMultiple(requests []rscp.Message) ([]rscp.Message, error) {
+	if requests[0].Tag == rscp.EMS_REQ_SET_POWER && s.reject {
+		return []rscp.Message{{Tag: rscp.EMS_SET_POWER, DataType: rscp.Error, Value: rscp.ERR_ACCESS_DENIED}}, nil
+	}
+	if requests[0].Tag == rscp.EMS_REQ_SET_POWER {
+		values := requests[0].Value.([]rscp.Message)
+		s.mode = rscp.PowerMode(values[0].Value.(uint8))
+		s.power = values[1].Value.(int32)
+		s.applied = s.now
+		return []rscp.Message{{Tag: rscp.EMS_SET_POWER, DataType: rscp.Int32, Value: s.power}}, nil
+	}
+	return []rscp.Message{
+		{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: uint8(math.Round(s.soc))},
+		{Tag: rscp.EMS_POWER_GRID, DataType: rscp.Int32, Value: int32(s.grid)},
+	}, nil
+}
+
+// advance simulates the system for the duration
+func (s *simulator) advance(d time.Duration) {
+	hour := float64(s.now.Hour()) + float64(s.now.Minute())/60
+	pv := math.Max(0, math.Sin((hour-6)/12*math.Pi)) * s.peakPV
+	mode := s.mode
+	// set power expires after 30 seconds
+	if s.now.Sub(s.applied) > time.Second*30 {
+		mode = rscp.POWER_MODE_AUTO
+	}
+	var battery float64
+	switch mode {
+	case rscp.POWER_MODE_IDLE:
+		battery = 0
+	case rscp.POWER_MODE_GRID_CHARGE:
+		battery = float64(s.power)
+	default:
+		battery = math.Max(-simMaxPower, math.Min(simMaxPower, pv-simHome))
+	}
+	if (battery > 0 && s.soc >= 100) || (battery < 0 && s.soc <= 0) {
+		battery = 0
+	}
+	s.soc = math.Max(0, math.Min(100, s.soc+battery*d.Hours()/simCapacity*100))
+	s.grid = simHome - pv + battery
+	s.now = s.now.Add(d)
+}
+
+func simulate(t *testing.T, start time.Time, days int, peakPV float64, check func(s *simulator, d Decision)) *State {
+	sim := &simulator{now: start, soc: 50, peakPV: peakPV}
+	state := &State{}
+	step := time.Second * 30
+	for sim.now.Before(start.AddDate(0, 0, days)) {
+		d, err := Step(sim, simConfig, state, sim.now, false)
+		if err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		check(sim, d)
+		sim.advance(step)
+	}
+	return state
+}
+
+func TestSimulationWinter(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	minimum := 100.0
+	var lastFull time.Time
+	var maxGap time.Duration
+	state := simulate(t, start, 56, 800, func(s *simulator, d Decision) {
+		if s.now.After(start.Add(time.Hour * 24)) {
+			minimum = math.Min(minimum, s.soc)
+		}
+		if s.soc >= 100 {
+			if !lastFull.IsZero() && s.now.Sub(lastFull) > maxGap {
+				maxGap = s.now.Sub(lastFull)
+			}
+			lastFull = s.now
+		}
+	})
+	if minimum < simConfig.Policies[0].MinSoC-1 {
+		t.Errorf("state of charge dropped to %.1f%%, below the minimum", minimum)
+	}
+	if maxGap == 0 || maxGap > simConfig.Policies[0].MaintenanceInterval+time.Hour*4 {
+		t.Errorf("maximum time between full charges = %s, want <= %s", maxGap, simConfig.Policies[0].MaintenanceInterval)
+	}
+	actions := map[Action]int{}
+	for _, e := range state.Events {
+		actions[e.To]++
+	}
+	if actions[ActionMaintenance] < 3 || actions[ActionMinimum] == 0 {
+		t.Errorf("unexpected actions %v", actions)
+	}
+}
+
+func TestSimulationSummer(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	var maximum float64
+	held := 0
+	simulate(t, start, 30, 8000, func(s *simulator, d Decision) {
+		maximum = math.Max(maximum, s.soc)
+		if d.Action == ActionHold {
+			held++
+		}
+	})
+	if maximum > 82 {
+		t.Errorf("state of charge reached %.1f%%, want <= 82%%", maximum)
+	}
+	if held == 0 {
+		t.Errorf("battery never held at the maximum")
+	}
+}
+
+func TestStep_dryRun(t *testing.T) {
+	sim := &simulator{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), soc: 5}
+	state := &State{}
+	d, err := Step(sim, simConfig, state, sim.now, true)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if d.Action != ActionMinimum || d.Policy != "winter" || !sim.applied.IsZero() {
+		t.Errorf("Step() = %+v, applied at %s", d, sim.applied)
+	}
+	if len(state.Events) != 1 || state.Events[0].From != ActionAuto {
+		t.Errorf("Events = %+v", state.Events)
+	}
+	// no policy applies in march
+	sim.now = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	if d, _ := Step(sim, simConfig, state, sim.now, true); d.Action != ActionAuto || d.Policy != "" {
+		t.Errorf("Step() = %+v, want auto without policy", d)
+	}
+}
+
+func TestStep_rejected(t *testing.T) {
+	// no policy applies in march, the minimum charge has to be left
+	sim := &simulator{now: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), soc: 50, reject: true}
+	state := &State{Action: ActionMinimum}
+	if _, err := Step(sim, simConfig, state, sim.now, false); err == nil {
+		t.Fatal("Step() of a rejected action succeeded")
+	}
+	if state.Action != ActionMinimum || len(state.Events) != 0 {
+		t.Errorf("rejected action recorded, state = %+v", state)
+	}
+	sim.reject = false
+	d, err := Step(sim, simConfig, state, sim.now, false)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if d.Action != ActionAuto || sim.applied.IsZero() || state.Action != ActionAuto || len(state.Events) != 1 {
+		t.Errorf("Step() = %+v, applied at %s, state = %+v, want auto sent again", d, sim.applied, state)
+	}
+}
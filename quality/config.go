@@ -0,0 +1,145 @@
+// Package quality monitors the power quality of the grid connection.
+//
+// the voltage of every phase is polled from the power meters (PM_VOLTAGE_L1..L3) and the inverters (PVI_AC_VOLTAGE),
+// the reactive and apparent power from the inverters. Under- and over-voltages and interruptions are recorded as events
+// with their duration, the 10 minute mean values are checked against the limits of EN 50160 for the supply voltage.
+// The grid frequency is not measured by the system, only the frequency protection settings of the inverters
+// (PVI_FREQUENCY_UNDER/OVER) are checked against the limits of EN 50160.
+package quality
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoSources     = errors.New("no power meter nor inverter configured")
+	ErrInvalidPhases = errors.New("phases out of range")
+	ErrInvalidLimit  = errors.New("limit out of range")
+)
+
+const (
+	// maxPhases is the number of phases of the grid connection
+	maxPhases = 3
+	// fullPercent is the nominal voltage in percent
+	fullPercent = 100
+)
+
+// Config of the monitor, all limits are in percent of the nominal voltage
+type Config struct {
+	// nominal voltage in V
+	Nominal float64 `json:"nominal"`
+	// PM_INDEX of the power meters measuring the voltage, nil to use all discovered power meters (see Resolve)
+	Meters []uint16 `json:"meters"`
+	// PVI_INDEX of the inverters measuring the voltage and the reactive and apparent power,
+	// nil to use all discovered inverters (see Resolve)
+	Inverters []uint16 `json:"inverters"`
+	// number of phases monitored
+	Phases uint8 `json:"phases"`
+	// deviation below the nominal voltage an under-voltage event starts
+	UnderVoltage float64 `json:"underVoltage"`
+	// deviation above the nominal voltage an over-voltage event starts
+	OverVoltage float64 `json:"overVoltage"`
+	// voltage below an interruption starts
+	Interruption float64 `json:"interruption"`
+	// voltage an event has to recover beyond its limit to end
+	Hysteresis float64 `json:"hysteresis"`
+	// deviation the 10 minute means have to stay within for the required share (EN 50160: 10%)
+	MeanTolerance float64 `json:"meanTolerance"`
+	// share of the 10 minute means required within the tolerance (EN 50160: 95%)
+	MeanShare float64 `json:"meanShare"`
+	// deviation below the nominal voltage no 10 minute mean may fall (EN 50160: 15%)
+	MeanLowerLimit float64 `json:"meanLowerLimit"`
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	Nominal:        230,
+	Phases:         3,
+	UnderVoltage:   10,
+	OverVoltage:    10,
+	Interruption:   5,
+	Hysteresis:     2,
+	MeanTolerance:  10,
+	MeanShare:      95,
+	MeanLowerLimit: 15,
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	// nil sources are resolved by the discovery
+	if c.Meters != nil && c.Inverters != nil && len(c.Meters) == 0 && len(c.Inverters) == 0 {
+		return ErrNoSources
+	}
+	if c.Phases > maxPhases {
+		return fmt.Errorf("%d: %w", c.Phases, ErrInvalidPhases)
+	}
+	for _, v := range []struct {
+		value *float64
+		def   float64
+	}{
+		{&c.Nominal, defaultConfig.Nominal},
+		{&c.UnderVoltage, defaultConfig.UnderVoltage},
+		{&c.OverVoltage, defaultConfig.OverVoltage},
+		{&c.Interruption, defaultConfig.Interruption},
+		{&c.Hysteresis, defaultConfig.Hysteresis},
+		{&c.MeanTolerance, defaultConfig.MeanTolerance},
+		{&c.MeanShare, defaultConfig.MeanShare},
+		{&c.MeanLowerLimit, defaultConfig.MeanLowerLimit},
+	} {
+		if *v.value <= 0 {
+			*v.value = v.def
+		}
+	}
+	if c.Phases == 0 {
+		c.Phases = defaultConfig.Phases
+	}
+	if c.UnderVoltage >= fullPercent || c.Interruption >= fullPercent-c.UnderVoltage || c.MeanShare > fullPercent {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// Resolve sets the sources not configured to all discovered ones, fails if a configured source isn't discovered
+// or no source remains.
+func (c *Config) Resolve(components rscp.Components) error {
+	for _, s := range []struct {
+		namespace string
+		indexes   *[]uint16
+	}{
+		{"PM", &c.Meters},
+		{"PVI", &c.Inverters},
+	} {
+		if *s.indexes == nil {
+			*s.indexes = components.Indexes(s.namespace)
+			continue
+		}
+		for _, i := range *s.indexes {
+			if !components.Has(s.namespace, i) {
+				return fmt.Errorf("%s %d: %w", s.namespace, i, rscp.ErrNotDiscovered)
+			}
+		}
+	}
+	if len(c.Meters) == 0 && len(c.Inverters) == 0 {
+		return ErrNoSources
+	}
+	return nil
+}
+
+// limits returns the voltages in V an under-voltage, over-voltage and interruption starts
+func (c Config) limits() (under, over, interruption float64) {
+	return c.Nominal * (1 - c.UnderVoltage/fullPercent), c.Nominal * (1 + c.OverVoltage/fullPercent), c.Nominal * c.Interruption / fullPercent
+}
@@ -0,0 +1,366 @@
+package quality
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const (
+	// window is the aggregation interval of the mean values defined by EN 50160
+	window = time.Minute * 10
+	// maxGap ends the events in progress if no sample was added for longer (i.e. polling was down)
+	maxGap = time.Minute * 5
+	// maxEvents limits the number of events kept in the state
+	maxEvents = 10000
+	// maxWindows limits the number of windows kept in the state (8 weeks)
+	maxWindows = 8 * 7 * 24 * 6
+)
+
+// pmVoltageRequests are the requests of the voltage by phase of a power meter
+var pmVoltageRequests = []rscp.Tag{rscp.PM_REQ_VOLTAGE_L1, rscp.PM_REQ_VOLTAGE_L2, rscp.PM_REQ_VOLTAGE_L3}
+
+// FrequencySettings of an inverter in Hz (PVI_FREQUENCY_UNDER_OVER)
+type FrequencySettings struct {
+	Under float64 `json:"under"`
+	Over  float64 `json:"over"`
+}
+
+// Sample is a single reading of the grid, values are by channel (i.e. PM0/L1 or PVI0/L2)
+type Sample struct {
+	Time time.Time `json:"time"`
+	// voltage in V
+	Voltage map[string]float64 `json:"voltage"`
+	// reactive power in var of the inverters
+	Reactive map[string]float64 `json:"reactive"`
+	// apparent power in VA of the inverters
+	Apparent map[string]float64 `json:"apparent"`
+	// frequency settings by inverter (i.e. PVI0)
+	Frequency map[string]FrequencySettings `json:"frequency"`
+}
+
+// channel returns the name of the phase of a power meter or inverter
+func channel(namespace string, index uint16, phase int) string {
+	return fmt.Sprintf("%s%d/L%d", namespace, index, phase+1)
+}
+
+// Requests returns the requests required to create a sample
+func Requests(c Config) ([]rscp.Message, error) {
+	r := [][]interface{}{}
+	for _, m := range c.Meters {
+		req := []interface{}{rscp.PM_REQ_DATA, rscp.PM_INDEX, m}
+		for p := 0; p < int(c.Phases); p++ {
+			req = append(req, pmVoltageRequests[p])
+		}
+		r = append(r, req)
+	}
+	for _, i := range c.Inverters {
+		req := []interface{}{rscp.PVI_REQ_DATA, rscp.PVI_INDEX, i}
+		for p := 0; p < int(c.Phases); p++ {
+			req = append(req, rscp.PVI_REQ_AC_VOLTAGE, uint8(p), rscp.PVI_REQ_AC_REACTIVEPOWER, uint8(p), rscp.PVI_REQ_AC_APPARENTPOWER, uint8(p))
+		}
+		req = append(req, rscp.PVI_REQ_FREQUENCY_UNDER_OVER)
+		r = append(r, req)
+	}
+	return rscp.CreateRequests(r...)
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(t time.Time, c Config, responses []rscp.Message) (Sample, error) {
+	s := Sample{Time: t, Voltage: map[string]float64{}, Reactive: map[string]float64{}, Apparent: map[string]float64{},
+		Frequency: map[string]FrequencySettings{}}
+	for _, m := range c.Meters {
+		pm := rscp.FindIndexed(responses, rscp.PM_DATA, rscp.PM_INDEX, m)
+		if pm == nil {
+			return s, fmt.Errorf("missing %s with %s %d in response", rscp.PM_DATA, rscp.PM_INDEX, m)
+		}
+		for p := 0; p < int(c.Phases); p++ {
+			tag := pmVoltageRequests[p].ResponseTag()
+			v := rscp.FindTag(pm.Value.([]rscp.Message), tag)
+			if v == nil {
+				return s, fmt.Errorf("missing %s of meter %d in response", tag, m)
+			}
+			var err error
+			if s.Voltage[channel("PM", m, p)], err = v.Float64(); err != nil {
+				return s, fmt.Errorf("meter %d: %w", m, err)
+			}
+		}
+	}
+	for _, i := range c.Inverters {
+		pvi := rscp.FindIndexed(responses, rscp.PVI_DATA, rscp.PVI_INDEX, i)
+		if pvi == nil {
+			return s, fmt.Errorf("missing %s with %s %d in response", rscp.PVI_DATA, rscp.PVI_INDEX, i)
+		}
+		if err := s.addInverter(c, i, pvi.Value.([]rscp.Message)); err != nil {
+			return s, fmt.Errorf("inverter %d: %w", i, err)
+		}
+	}
+	return s, nil
+}
+
+// addInverter adds the values of the PVI_DATA container of an inverter
+func (s *Sample) addInverter(c Config, index uint16, values []rscp.Message) error {
+	for tag, target := range map[rscp.Tag]map[string]float64{
+		rscp.PVI_AC_VOLTAGE:       s.Voltage,
+		rscp.PVI_AC_REACTIVEPOWER: s.Reactive,
+		rscp.PVI_AC_APPARENTPOWER: s.Apparent,
+	} {
+		found := 0
+		for _, m := range values {
+			if m.Tag != tag {
+				continue
+			}
+			if m.DataType == rscp.Error {
+				return fmt.Errorf("%s returned error %v", tag, m.Value)
+			}
+			nested, _ := m.Value.([]rscp.Message)
+			phase, value := rscp.FindTag(nested, rscp.PVI_INDEX), rscp.FindTag(nested, rscp.PVI_VALUE)
+			if phase == nil || value == nil {
+				return fmt.Errorf("missing %s or %s in %s", rscp.PVI_INDEX, rscp.PVI_VALUE, tag)
+			}
+			p, err := phase.Float64()
+			if err != nil {
+				return err
+			}
+			if target[channel("PVI", index, int(p))], err = value.Float64(); err != nil {
+				return err
+			}
+			found++
+		}
+		if found != int(c.Phases) {
+			return fmt.Errorf("%d of %d phases of %s in response", found, c.Phases, tag)
+		}
+	}
+	f := rscp.FindTag(values, rscp.PVI_FREQUENCY_UNDER_OVER)
+	if f == nil {
+		return fmt.Errorf("missing %s in response", rscp.PVI_FREQUENCY_UNDER_OVER)
+	}
+	settings := FrequencySettings{}
+	for tag, v := range map[rscp.Tag]*float64{rscp.PVI_FREQUENCY_UNDER: &settings.Under, rscp.PVI_FREQUENCY_OVER: &settings.Over} {
+		m := rscp.FindTag([]rscp.Message{*f}, tag)
+		if m == nil {
+			return fmt.Errorf("missing %s in response", tag)
+		}
+		var err error
+		if *v, err = m.Float64(); err != nil {
+			return err
+		}
+	}
+	s.Frequency[fmt.Sprintf("PVI%d", index)] = settings
+	return nil
+}
+
+// Kind of a voltage event
+type Kind string
+
+// all kinds of voltage events
+const (
+	KindUnder        Kind = "under"
+	KindOver         Kind = "over"
+	KindInterruption Kind = "interruption"
+)
+
+// Event is a period the voltage of a channel was out of the limits
+type Event struct {
+	Channel string    `json:"channel"`
+	Kind    Kind      `json:"kind"`
+	Start   time.Time `json:"start"`
+	// first sample back within the limits or the last sample before a polling gap
+	End time.Time `json:"end"`
+	// duration in seconds, up to the last sample while in progress
+	Duration float64 `json:"duration"`
+	// lowest voltage of an under-voltage or interruption, highest of an over-voltage
+	Extreme float64 `json:"extreme"`
+	// ended by a polling gap, the real end is unknown
+	Gap bool `json:"gap,omitempty"`
+}
+
+// update updates the event by a sample of the channel
+func (e *Event) update(t time.Time, v float64) {
+	if (e.Kind == KindOver && v > e.Extreme) || (e.Kind != KindOver && v < e.Extreme) {
+		e.Extreme = v
+	}
+	e.Duration = t.Sub(e.Start).Seconds()
+}
+
+// Stat of the values of a window
+type Stat struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	Count int     `json:"count"`
+}
+
+// add adds a value to the stat
+func (s *Stat) add(v float64) {
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+	s.Count++
+	s.Mean += (v - s.Mean) / float64(s.Count)
+}
+
+// Window aggregates the samples of 10 minutes by channel
+type Window struct {
+	Start    time.Time       `json:"start"`
+	Voltage  map[string]Stat `json:"voltage"`
+	Reactive map[string]Stat `json:"reactive"`
+	Apparent map[string]Stat `json:"apparent"`
+}
+
+// State of the monitor, persisted between runs
+type State struct {
+	// events in progress by channel
+	Open map[string]*Event `json:"open"`
+	// ended events, limited to the most recent ones
+	Events []Event `json:"events"`
+	// 10 minute windows, limited to the most recent ones
+	Windows []Window `json:"windows"`
+	// last frequency settings by inverter
+	Frequency map[string]FrequencySettings `json:"frequency"`
+	// time of the last sample
+	Last time.Time `json:"last"`
+}
+
+// LoadState reads the state from a json file, a missing file results in an empty state
+func LoadState(path string) (*State, error) {
+	s := &State{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to a json file
+func (s *State) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// classify returns the kind of event of the voltage or empty if within the limits.
+//
+// an event in progress continues until the voltage recovered beyond its limit by the hysteresis.
+func (c Config) classify(v float64, current Kind) Kind {
+	under, over, interruption := c.limits()
+	h := c.Nominal * c.Hysteresis / fullPercent
+	switch {
+	case v < interruption || (current == KindInterruption && v < interruption+h):
+		return KindInterruption
+	case v < under || (current != "" && current != KindOver && v < under+h):
+		return KindUnder
+	case v > over || (current == KindOver && v > over-h):
+		return KindOver
+	}
+	return ""
+}
+
+// end ends the event in progress of the channel
+func (s *State) end(ch string, t time.Time, gap bool) {
+	e := s.Open[ch]
+	if e == nil {
+		return
+	}
+	delete(s.Open, ch)
+	e.End, e.Gap, e.Duration = t, gap, t.Sub(e.Start).Seconds()
+	log.Infof("%s %s-voltage ended after %.0fs (extreme %.1fV)", e.Channel, e.Kind, e.Duration, e.Extreme)
+	s.Events = append(s.Events, *e)
+	if len(s.Events) > maxEvents {
+		s.Events = s.Events[len(s.Events)-maxEvents:]
+	}
+}
+
+// Add records the events and aggregates the sample
+func (s *State) Add(c Config, sample Sample) {
+	if s.Open == nil {
+		s.Open = map[string]*Event{}
+	}
+	if !s.Last.IsZero() && sample.Time.Sub(s.Last) > maxGap {
+		for ch := range s.Open {
+			s.end(ch, s.Last, true)
+		}
+	}
+	channels := make([]string, 0, len(sample.Voltage))
+	for ch := range sample.Voltage {
+		channels = append(channels, ch)
+	}
+	sort.Strings(channels)
+	for _, ch := range channels {
+		v := sample.Voltage[ch]
+		var current Kind
+		if e := s.Open[ch]; e != nil {
+			current = e.Kind
+		}
+		kind := c.classify(v, current)
+		if current != "" && kind != current {
+			s.end(ch, sample.Time, false)
+		}
+		switch {
+		case kind == "":
+		case kind != current:
+			s.Open[ch] = &Event{Channel: ch, Kind: kind, Start: sample.Time, Extreme: v}
+			log.Infof("%s %s-voltage started at %.1fV", ch, kind, v)
+		default:
+			s.Open[ch].update(sample.Time, v)
+		}
+	}
+	s.aggregate(sample)
+	if len(sample.Frequency) > 0 {
+		s.Frequency = sample.Frequency
+	}
+	s.Last = sample.Time
+}
+
+// aggregate adds the sample to its window
+func (s *State) aggregate(sample Sample) {
+	start := sample.Time.Truncate(window)
+	if n := len(s.Windows); n == 0 || !s.Windows[n-1].Start.Equal(start) {
+		s.Windows = append(s.Windows, Window{Start: start, Voltage: map[string]Stat{}, Reactive: map[string]Stat{}, Apparent: map[string]Stat{}})
+		if len(s.Windows) > maxWindows {
+			s.Windows = s.Windows[len(s.Windows)-maxWindows:]
+		}
+	}
+	w := &s.Windows[len(s.Windows)-1]
+	for _, a := range []struct {
+		values map[string]float64
+		stats  map[string]Stat
+	}{
+		{sample.Voltage, w.Voltage},
+		{sample.Reactive, w.Reactive},
+		{sample.Apparent, w.Apparent},
+	} {
+		for ch, v := range a.values {
+			st := a.stats[ch]
+			st.add(v)
+			a.stats[ch] = st
+		}
+	}
+}
+
+// Step requests a new sample and adds it to the state
+func Step(sender rscp.Sender, c Config, s *State, now time.Time) (Sample, error) {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		sample    Sample
+		err       error
+	)
+	if requests, err = Requests(c); err != nil {
+		return sample, err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return sample, err
+	}
+	if sample, err = NewSample(now, c, responses); err != nil {
+		return sample, err
+	}
+	s.Add(c, sample)
+	return sample, nil
+}
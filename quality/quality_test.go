@@ -0,0 +1,229 @@
+package quality
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    Config
+		wantErr error
+	}{
+		{"defaults",
+			Config{Meters: []uint16{0}},
+			Config{Nominal: 230, Meters: []uint16{0}, Phases: 3, UnderVoltage: 10, OverVoltage: 10, Interruption: 5, Hysteresis: 2,
+				MeanTolerance: 10, MeanShare: 95, MeanLowerLimit: 15},
+			nil,
+		},
+		{"no sources", Config{Meters: []uint16{}, Inverters: []uint16{}}, Config{}, ErrNoSources},
+		{"phases", Config{Inverters: []uint16{0}, Phases: 4}, Config{}, ErrInvalidPhases},
+		{"under voltage", Config{Inverters: []uint16{0}, UnderVoltage: 100}, Config{}, ErrInvalidLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "quality.json")
+			if err := jsonfile.Write(path, tt.config); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadConfig(path)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("LoadConfig() = %+v, want %+v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	components := rscp.Components{
+		"PM":  {{Namespace: "PM", Index: 0}, {Namespace: "PM", Index: 6}},
+		"PVI": {{Namespace: "PVI", Index: 0}},
+	}
+	tests := []struct {
+		name    string
+		config  Config
+		want    Config
+		wantErr error
+	}{
+		{"discovered", Config{}, Config{Meters: []uint16{0, 6}, Inverters: []uint16{0}}, nil},
+		{"configured", Config{Meters: []uint16{6}, Inverters: []uint16{}}, Config{Meters: []uint16{6}, Inverters: []uint16{}}, nil},
+		{"not discovered", Config{Meters: []uint16{1}}, Config{}, rscp.ErrNotDiscovered},
+		{"no sources", Config{Meters: []uint16{}, Inverters: []uint16{}}, Config{}, ErrNoSources},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			err := c.Resolve(components)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if diff := deep.Equal(c, tt.want); diff != nil {
+				t.Errorf("Resolve() = %+v, want %+v\n%s", c, tt.want, diff)
+			}
+		})
+	}
+}
+
+// pviValue returns a PVI_INDEX & PVI_VALUE container
+func pviValue(tag rscp.Tag, phase uint16, v float32) rscp.Message {
+	return rscp.Message{Tag: tag, DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: phase},
+		{Tag: rscp.PVI_VALUE, DataType: rscp.Float32, Value: v},
+	}}
+}
+
+func TestStep(t *testing.T) {
+	c := Config{Meters: []uint16{1}, Inverters: []uint16{0}, Phases: 2}
+	if err := c.check(); err != nil {
+		t.Fatal(err)
+	}
+	requests, err := Requests(c)
+	if err != nil {
+		t.Fatalf("Requests() error = %v", err)
+	}
+	if len(requests) != 2 || len(requests[1].Value.([]rscp.Message)) != 1+2*3+1 {
+		t.Errorf("Requests() = %v", requests)
+	}
+	responses := []rscp.Message{
+		{Tag: rscp.PM_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PM_INDEX, DataType: rscp.UInt16, Value: uint16(1)},
+			{Tag: rscp.PM_VOLTAGE_L1, DataType: rscp.Float32, Value: float32(231)},
+			{Tag: rscp.PM_VOLTAGE_L2, DataType: rscp.Float32, Value: float32(200)},
+		}},
+		{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			pviValue(rscp.PVI_AC_VOLTAGE, 0, 232),
+			pviValue(rscp.PVI_AC_REACTIVEPOWER, 0, 100),
+			pviValue(rscp.PVI_AC_APPARENTPOWER, 0, 1000),
+			pviValue(rscp.PVI_AC_VOLTAGE, 1, 201),
+			pviValue(rscp.PVI_AC_REACTIVEPOWER, 1, -50),
+			pviValue(rscp.PVI_AC_APPARENTPOWER, 1, 900),
+			{Tag: rscp.PVI_FREQUENCY_UNDER_OVER, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.PVI_FREQUENCY_UNDER, DataType: rscp.Float32, Value: float32(47.5)},
+				{Tag: rscp.PVI_FREQUENCY_OVER, DataType: rscp.Float32, Value: float32(51.5)},
+			}},
+		}},
+	}
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := &State{}
+	got, err := Step(rscptest.NewSender(responses), c, s, now)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	want := Sample{
+		Time:      now,
+		Voltage:   map[string]float64{"PM1/L1": 231, "PM1/L2": 200, "PVI0/L1": 232, "PVI0/L2": 201},
+		Reactive:  map[string]float64{"PVI0/L1": 100, "PVI0/L2": -50},
+		Apparent:  map[string]float64{"PVI0/L1": 1000, "PVI0/L2": 900},
+		Frequency: map[string]FrequencySettings{"PVI0": {47.5, 51.5}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Step() = %v, want %v\n%s", got, want, diff)
+	}
+	if len(s.Open) != 2 || s.Open["PM1/L2"].Kind != KindUnder || len(s.Windows) != 1 {
+		t.Errorf("Step() state = %+v", s)
+	}
+	// an inverter with a single phase
+	responses[1].Value = responses[1].Value.([]rscp.Message)[:4]
+	if _, err := Step(rscptest.NewSender(responses), c, s, now); err == nil {
+		t.Errorf("Step() expected error on missing phase")
+	}
+}
+
+func TestState(t *testing.T) {
+	c := Config{Meters: []uint16{0}, Phases: 1}
+	if err := c.check(); err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := &State{}
+	// one sample every minute, the voltages of the minutes not listed are nominal
+	voltages := map[int]float64{
+		// under-voltage, ends after recovering by the hysteresis
+		10: 205, 11: 200, 12: 209, 13: 212,
+		// interruption turning into an under-voltage
+		30: 5, 31: 100,
+		// over-voltage before a polling gap
+		50: 260,
+	}
+	for m := 0; m < 24*60; m++ {
+		if m > 50 && m < 60 {
+			continue
+		}
+		v, exists := voltages[m]
+		if !exists {
+			v = 230
+		}
+		s.Add(c, Sample{Time: t0.Add(time.Duration(m) * time.Minute), Voltage: map[string]float64{"PM0/L1": v}})
+	}
+	minute := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+	want := []Event{
+		{Channel: "PM0/L1", Kind: KindUnder, Start: minute(10), End: minute(13), Duration: 180, Extreme: 200},
+		{Channel: "PM0/L1", Kind: KindInterruption, Start: minute(30), End: minute(31), Duration: 60, Extreme: 5},
+		{Channel: "PM0/L1", Kind: KindUnder, Start: minute(31), End: minute(32), Duration: 60, Extreme: 100},
+		{Channel: "PM0/L1", Kind: KindOver, Start: minute(50), End: minute(50), Duration: 0, Extreme: 260, Gap: true},
+	}
+	if diff := deep.Equal(s.Events, want); diff != nil {
+		t.Errorf("Add() events = %+v, want %+v\n%s", s.Events, want, diff)
+	}
+	if len(s.Windows) != 144 {
+		t.Errorf("Add() %d windows, want 144", len(s.Windows))
+	}
+	// under-voltage in progress at the end of the period
+	s.Add(c, Sample{Time: minute(24 * 60), Voltage: map[string]float64{"PM0/L1": 150}})
+
+	r := s.NewReport(c, t0, minute(24*60+1))
+	if len(r.Channels) != 1 || len(r.Events) != 5 {
+		t.Fatalf("NewReport() = %+v", r)
+	}
+	cr := r.Channels[0]
+	// the windows with the interruption, the over-voltage and the last sample are out of the limits
+	if cr.Windows != 145 || cr.OutsideLimits != 3 || cr.Compliant || cr.Min != 5 || cr.Max != 260 || cr.Events[KindUnder] != 3 {
+		t.Errorf("NewReport() channel = %+v", cr)
+	}
+	if cr.WithinTolerance != float64(145-3)/145*100 {
+		t.Errorf("NewReport() within tolerance = %f", cr.WithinTolerance)
+	}
+	r = s.NewReport(c, minute(60), minute(24*60))
+	if len(r.Events) != 0 || !r.Channels[0].Compliant {
+		t.Errorf("NewReport() = %+v, want compliant without events", r)
+	}
+	b := &bytes.Buffer{}
+	if err := WriteText(b, s.NewReport(c, t0, minute(24*60+1))); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, want := range []string{"PM0/L1  2021-06-01 00:50:00  2021-06-01 00:50:00 (polling gap)", "in progress", "interruption"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteText() = %s, want %q", b, want)
+		}
+	}
+}
+
+func TestNewReport_frequency(t *testing.T) {
+	s := &State{Frequency: map[string]FrequencySettings{"PVI0": {47.5, 51.5}, "PVI1": {49.8, 50.2}}}
+	r := s.NewReport(Config{}, time.Time{}, time.Now())
+	want := []FrequencyReport{{"PVI0", FrequencySettings{47.5, 51.5}, false}, {"PVI1", FrequencySettings{49.8, 50.2}, true}}
+	if diff := deep.Equal(r.Frequency, want); diff != nil {
+		t.Errorf("NewReport() = %+v, want %+v\n%s", r.Frequency, want, diff)
+	}
+}
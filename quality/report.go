@@ -0,0 +1,207 @@
+package quality
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// nominal grid frequency in Hz and the band EN 50160 requires during 99.5% of a year
+const (
+	nominalFrequency   = 50
+	frequencyTolerance = 0.5
+)
+
+// ChannelReport is the voltage quality of a channel, voltages in V
+type ChannelReport struct {
+	Channel string  `json:"channel"`
+	Samples int     `json:"samples"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Mean    float64 `json:"mean"`
+	// number of 10 minute means
+	Windows int `json:"windows"`
+	// share in % of the 10 minute means within the tolerance
+	WithinTolerance float64 `json:"withinTolerance"`
+	// 10 minute means above the tolerance or below the lower limit
+	OutsideLimits int `json:"outsideLimits"`
+	// share and limits of the 10 minute means comply with EN 50160
+	Compliant bool `json:"compliant"`
+	// number and total duration in seconds of the events by kind
+	Events   map[Kind]int     `json:"events"`
+	Duration map[Kind]float64 `json:"duration"`
+}
+
+// PowerReport is the reactive and apparent power of an inverter phase
+type PowerReport struct {
+	Channel  string `json:"channel"`
+	Reactive Stat   `json:"reactive"`
+	Apparent Stat   `json:"apparent"`
+}
+
+// FrequencyReport are the frequency settings of an inverter
+type FrequencyReport struct {
+	Inverter string `json:"inverter"`
+	FrequencySettings
+	// the inverter disconnects within the band EN 50160 requires during 99.5% of a year (49.5-50.5 Hz)
+	TripsWithinNormalRange bool `json:"tripsWithinNormalRange"`
+}
+
+// Report of the power quality within a period
+type Report struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Nominal float64   `json:"nominal"`
+	// limits of the 10 minute means in %
+	MeanTolerance  float64           `json:"meanTolerance"`
+	MeanShare      float64           `json:"meanShare"`
+	MeanLowerLimit float64           `json:"meanLowerLimit"`
+	Channels       []ChannelReport   `json:"channels"`
+	Power          []PowerReport     `json:"power"`
+	Frequency      []FrequencyReport `json:"frequency"`
+	// events started within the period including the ones in progress
+	Events []Event `json:"events"`
+}
+
+// NewReport creates the report of the windows and events starting within the period
+func (s *State) NewReport(c Config, from, to time.Time) Report {
+	r := Report{From: from, To: to, Nominal: c.Nominal, MeanTolerance: c.MeanTolerance, MeanShare: c.MeanShare, MeanLowerLimit: c.MeanLowerLimit,
+		Channels: []ChannelReport{}, Power: []PowerReport{}, Frequency: []FrequencyReport{}, Events: []Event{}}
+	within := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	channels := map[string]*ChannelReport{}
+	power := map[string]*PowerReport{}
+	upper := c.Nominal * (1 + c.MeanTolerance/fullPercent)
+	lower := c.Nominal * (1 - c.MeanTolerance/fullPercent)
+	lowerLimit := c.Nominal * (1 - c.MeanLowerLimit/fullPercent)
+	for _, w := range s.Windows {
+		if !within(w.Start) {
+			continue
+		}
+		for ch, st := range w.Voltage {
+			cr := channels[ch]
+			if cr == nil {
+				cr = &ChannelReport{Channel: ch, Min: st.Min, Max: st.Max, Events: map[Kind]int{}, Duration: map[Kind]float64{}}
+				channels[ch] = cr
+			}
+			cr.Min, cr.Max = math.Min(cr.Min, st.Min), math.Max(cr.Max, st.Max)
+			cr.Mean += (st.Mean - cr.Mean) * float64(st.Count) / float64(cr.Samples+st.Count)
+			cr.Samples += st.Count
+			cr.Windows++
+			if st.Mean >= lower && st.Mean <= upper {
+				cr.WithinTolerance++
+			}
+			if st.Mean > upper || st.Mean < lowerLimit {
+				cr.OutsideLimits++
+			}
+		}
+		for ch, st := range w.Reactive {
+			pr := power[ch]
+			if pr == nil {
+				pr = &PowerReport{Channel: ch}
+				power[ch] = pr
+			}
+			pr.Reactive = merge(pr.Reactive, st)
+			pr.Apparent = merge(pr.Apparent, w.Apparent[ch])
+		}
+	}
+	events := append([]Event{}, s.Events...)
+	for _, e := range s.Open {
+		events = append(events, *e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].Channel < events[j].Channel
+	})
+	for _, e := range events {
+		if !within(e.Start) {
+			continue
+		}
+		r.Events = append(r.Events, e)
+		if cr := channels[e.Channel]; cr != nil {
+			cr.Events[e.Kind]++
+			cr.Duration[e.Kind] += e.Duration
+		}
+	}
+	for _, cr := range channels {
+		cr.WithinTolerance = cr.WithinTolerance / float64(cr.Windows) * fullPercent
+		cr.Compliant = cr.WithinTolerance >= c.MeanShare && cr.OutsideLimits == 0
+		r.Channels = append(r.Channels, *cr)
+	}
+	sort.Slice(r.Channels, func(i, j int) bool { return r.Channels[i].Channel < r.Channels[j].Channel })
+	for _, pr := range power {
+		r.Power = append(r.Power, *pr)
+	}
+	sort.Slice(r.Power, func(i, j int) bool { return r.Power[i].Channel < r.Power[j].Channel })
+	for inverter, f := range s.Frequency {
+		r.Frequency = append(r.Frequency, FrequencyReport{
+			Inverter:               inverter,
+			FrequencySettings:      f,
+			TripsWithinNormalRange: f.Under > nominalFrequency-frequencyTolerance || f.Over < nominalFrequency+frequencyTolerance,
+		})
+	}
+	sort.Slice(r.Frequency, func(i, j int) bool { return r.Frequency[i].Inverter < r.Frequency[j].Inverter })
+	return r
+}
+
+// merge returns the stat of both stats
+func merge(a, b Stat) Stat {
+	switch {
+	case b.Count == 0:
+		return a
+	case a.Count == 0:
+		return b
+	}
+	return Stat{
+		Min:   math.Min(a.Min, b.Min),
+		Max:   math.Max(a.Max, b.Max),
+		Mean:  a.Mean + (b.Mean-a.Mean)*float64(b.Count)/float64(a.Count+b.Count),
+		Count: a.Count + b.Count,
+	}
+}
+
+// WriteText writes the report as plain text (i.e. to attach to a complaint to the grid operator)
+func WriteText(w io.Writer, r Report) error {
+	const timeFormat = "2006-01-02 15:04:05"
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint: gomnd
+	p := func(format string, args ...interface{}) { fmt.Fprintf(tw, format+"\n", args...) }
+	p("Power quality report %s - %s", r.From.Format(timeFormat), r.To.Format(timeFormat))
+	p("Nominal voltage %.0f V, EN 50160: %.0f%% of the 10 minute means within ±%.0f%%, all within +%.0f%%/-%.0f%%",
+		r.Nominal, r.MeanShare, r.MeanTolerance, r.MeanTolerance, r.MeanLowerLimit)
+	p("")
+	p("Voltage\tmin V\tmax V\tmean V\t10 min means\twithin tolerance\toutside limits\tcompliant")
+	for _, c := range r.Channels {
+		p("%s\t%.1f\t%.1f\t%.1f\t%d\t%.2f%%\t%d\t%t", c.Channel, c.Min, c.Max, c.Mean, c.Windows, c.WithinTolerance, c.OutsideLimits, c.Compliant)
+	}
+	p("")
+	p("Events\tstart\tend\tduration\tkind\textreme V")
+	for _, e := range r.Events {
+		end := "in progress"
+		if !e.End.IsZero() {
+			end = e.End.Format(timeFormat)
+		}
+		if e.Gap {
+			end += " (polling gap)"
+		}
+		p("%s\t%s\t%s\t%s\t%s\t%.1f", e.Channel, e.Start.Format(timeFormat), end, time.Duration(e.Duration*float64(time.Second)), e.Kind, e.Extreme)
+	}
+	if len(r.Power) > 0 {
+		p("")
+		p("Power\treactive mean var\treactive max var\tapparent mean VA\tapparent max VA")
+		for _, pr := range r.Power {
+			p("%s\t%.0f\t%.0f\t%.0f\t%.0f", pr.Channel, pr.Reactive.Mean, pr.Reactive.Max, pr.Apparent.Mean, pr.Apparent.Max)
+		}
+	}
+	if len(r.Frequency) > 0 {
+		p("")
+		p("Frequency protection\tunder Hz\tover Hz\ttrips within 49.5-50.5 Hz")
+		for _, f := range r.Frequency {
+			p("%s\t%.2f\t%.2f\t%t", f.Inverter, f.Under, f.Over, f.TripsWithinNormalRange)
+		}
+	}
+	return tw.Flush()
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strings"
 	"time"
 
 	"errors"
 
-	"github.com/azihsoyn/rijndael256"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,6 +19,7 @@ type Client struct {
 	connectionString string
 	isConnected      bool
 	isAuthenticated  bool
+	authLevel        AuthLevel
 	conn             net.Conn
 	encrypter        cipher.BlockMode
 	decrypter        cipher.BlockMode
@@ -31,40 +32,41 @@ func NewClient(config ClientConfig) (*Client, error) {
 	if err := config.check(); err != nil {
 		return nil, err
 	}
-	key := createAESKey(config.Key)
-	initIV := newIV()
-	cipherBlock, _ := rijndael256.NewCipher(key[:]) // implementation does not return an error
 	// Intitialize the Client structure.
 	c := &Client{
 		config:           config,
 		connectionString: fmt.Sprintf("%s:%d", config.Address, config.Port),
 		isConnected:      false,
 		isAuthenticated:  false,
-		encrypter:        cipher.NewCBCEncrypter(cipherBlock, initIV[:]),
-		decrypter:        cipher.NewCBCDecrypter(cipherBlock, initIV[:]),
 	}
+	c.resetCipher()
 	return c, nil
 }
 
-// send message
-func (c *Client) send(messages []Message) error {
+// resetCipher starts new cipher block chains for a new connection
+func (c *Client) resetCipher() {
+	c.encrypter, c.decrypter = NewCipherModes(c.config.Key)
+}
+
+// send message, returns if any data may have reached the server
+func (c *Client) send(messages []Message) (bool, error) {
 	if err := validateRequests(messages); err != nil {
-		return err
+		return false, err
 	}
 	var (
 		msg []byte
 		err error
 	)
 	if msg, err = Write(&c.encrypter, messages, c.config.UseChecksum.(bool)); err != nil {
-		return err
+		return false, err
 	}
 	if err := c.conn.SetWriteDeadline(time.Now().Add(c.config.SendTimeout)); err != nil {
-		return err
+		return false, err
 	}
-	if _, err := c.conn.Write(msg); err != nil {
-		return err
+	if n, err := c.conn.Write(msg); err != nil {
+		return n > 0, err
 	}
-	return nil
+	return true, nil
 }
 
 // receive listens for a response and decodes the response
@@ -78,6 +80,8 @@ func (c *Client) receive() ([]Message, error) {
 	var frameSize uint32
 	var dataSize uint16
 	var m []Message
+	// received data not decrypted yet, only complete blocks can be decrypted
+	var pending []byte
 
 	for i, new := 0, make([]byte, uint32(RSCP_CRYPT_BLOCK_SIZE)*uint32(c.config.ReceiveBufferBlockSize)); ; {
 		var err error
@@ -86,7 +90,15 @@ func (c *Client) receive() ([]Message, error) {
 		} else if i == 0 {
 			return nil, ErrRscpInvalidFrameLength
 		}
-		switch m, err = Read(&c.decrypter, &buf, &crcFlag, &frameSize, &dataSize, new[:i]); {
+		pending = append(pending, new[:i]...)
+		complete := len(pending) - len(pending)%int(RSCP_CRYPT_BLOCK_SIZE)
+		if complete == 0 {
+			// block not complete
+			continue
+		}
+		data := pending[:complete]
+		pending = pending[complete:]
+		switch m, err = Read(&c.decrypter, &buf, &crcFlag, &frameSize, &dataSize, data); {
 		case errors.Is(err, ErrRscpInvalidFrameLength):
 			// frame not complete
 			continue
@@ -112,6 +124,7 @@ func (c *Client) connect() error {
 	}
 	c.conn = conn
 	c.isConnected = true
+	c.resetCipher()
 	log.Infof("successfully connected to %s", c.conn.RemoteAddr())
 	return nil
 }
@@ -129,7 +142,7 @@ func (c *Client) authenticate() error {
 			log.SetLevel(orgLogLevel)
 		}
 		return err
-	} else if err := c.send([]Message{*msg}); err != nil {
+	} else if _, err := c.send([]Message{*msg}); err != nil {
 		if orgLogLevel < RequiredAuthLogLevel {
 			log.SetLevel(orgLogLevel)
 		}
@@ -166,24 +179,53 @@ func (c *Client) authenticate() error {
 		}
 	}
 	c.isAuthenticated = true
-	log.Infof("successfully authenticated (level: %s)", AuthLevel(messages[0].Value.(uint8)))
+	c.authLevel = AuthLevel(messages[0].Value.(uint8))
+	log.Infof("successfully authenticated (level: %s)", c.authLevel)
 	return nil
 }
 
-// Disconnect the client
-func (c *Client) Disconnect() error {
+// close closes the connection, a new connection is established by the next request
+func (c *Client) close() error {
 	c.isAuthenticated = false
 	c.isConnected = false
+	if c.conn == nil {
+		return nil
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn.Close()
+}
 
-	if c.isConnected && c.conn != nil {
-		if err := c.conn.Close(); err != nil {
+// Disconnect the client
+func (c *Client) Disconnect() error {
+	if err := c.close(); err != nil {
+		return err
+	}
+	log.Info("disconnected")
+	return nil
+}
+
+// Connect connects and authenticates the client if not done yet
+func (c *Client) Connect() error {
+	if !c.isConnected {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+	if !c.isAuthenticated {
+		if err := c.authenticate(); err != nil {
+			_ = c.close()
 			return err
 		}
 	}
-	log.Info("disconnected")
 	return nil
 }
 
+// AuthLevel returns the auth level granted by the last authentication, AUTH_LEVEL_NO_AUTH if not authenticated yet
+func (c *Client) AuthLevel() AuthLevel {
+	return c.authLevel
+}
+
 // Send a message and return the response.
 //
 // connects and authenticates the first time used.
@@ -201,26 +243,81 @@ func (c *Client) Send(request Message) (*Message, error) {
 // Send multiple messages in one round-trip and return the response.
 //
 // connects and authenticates the first time used.
+// After a failed round-trip the connection is closed, because the cipher block chain is out of sync
+// with the server (i.e. after a partial frame), the next request reconnects.
+// The round-trip is retried up to the configured retries on a new connection, but requests changing the system
+// which may have been applied by the failed attempt are only retried if idempotent. Otherwise an OutcomeUnknownError
+// is returned.
+// Requests missing in the responses (see MissingRequests) are re-requested up to the configured partial retries,
+// if still missing the responses received are returned with a PartialResponseError.
 func (c *Client) SendMultiple(requests []Message) ([]Message, error) {
-	if !c.isConnected {
-		if err := c.connect(); err != nil {
-			return nil, err
+	if err := validateRequests(requests); err != nil {
+		return nil, err
+	}
+	responses, err := c.sendMultiple(requests)
+	if err != nil {
+		return nil, err
+	}
+	return c.recoverPartial(requests, responses)
+}
+
+// recoverPartial re-requests the requests missing in the responses and merges the responses,
+// requests which may trigger an action twice are not re-requested.
+func (c *Client) recoverPartial(requests []Message, responses []Message) ([]Message, error) {
+	missing := MissingRequests(requests, responses)
+	for attempt := 0; len(missing) > 0; attempt++ {
+		if attempt >= c.config.PartialRetries || RequestsRetrySafety(missing) == RetryUnsafe {
+			return responses, &PartialResponseError{Missing: missing}
+		}
+		log.Warnf("re-requesting missing parts of partial response (%d/%d): %s", attempt+1, c.config.PartialRetries,
+			strings.Join(describeRequests(missing, ""), ", "))
+		recovered, err := c.sendMultiple(missing)
+		if err != nil {
+			return responses, &PartialResponseError{Missing: missing, Err: err}
 		}
+		responses = MergeResponses(requests, responses, recovered)
+		missing = MissingRequests(requests, responses)
 	}
-	if !c.isAuthenticated {
-		if err := c.authenticate(); err != nil {
+	return responses, nil
+}
+
+// sendMultiple sends the requests with retries of failed round-trips
+func (c *Client) sendMultiple(requests []Message) ([]Message, error) {
+	safety := RequestsRetrySafety(requests)
+	// requests of any attempt may have reached the server
+	sent := false
+	for attempt := uint(0); ; attempt++ {
+		responses, attemptSent, err := c.roundTrip(requests)
+		if err == nil {
+			return responses, nil
+		}
+		sent = sent || attemptSent
+		if sent && safety != RetrySafe {
+			err = &OutcomeUnknownError{Safety: safety, Err: err}
+		}
+		if attempt >= c.config.Retries || (sent && safety == RetryUnsafe) {
 			return nil, err
 		}
+		log.Warnf("retrying failed round-trip (%d/%d): %s", attempt+1, c.config.Retries, err)
 	}
-	if err := c.send(requests); err != nil {
-		return nil, err
+}
+
+// roundTrip sends the requests and receives the responses, returns if the requests may have reached the server
+func (c *Client) roundTrip(requests []Message) ([]Message, bool, error) {
+	if err := c.Connect(); err != nil {
+		return nil, false, err
+	}
+	if sent, err := c.send(requests); err != nil {
+		_ = c.close()
+		return nil, sent, err
 	}
 	var (
 		responses []Message
 		err       error
 	)
 	if responses, err = c.receive(); err != nil {
-		return nil, err
+		_ = c.close()
+		return nil, true, err
 	}
-	return responses, nil
+	return responses, true, nil
 }
@@ -30,6 +30,10 @@ type ClientConfig struct {
 	UseChecksum interface{}
 	// amount of blocks of the receiving buffer size
 	ReceiveBufferBlockSize uint16
+	// retries of a failed round-trip on a new connection, requests are only retried if safe to retry (see RetrySafety)
+	Retries uint
+	// re-requests of the missing parts of a partial response (see MissingRequests), default 2 if 0, negative disables them
+	PartialRetries int
 }
 
 // defaultClientConfig defines the default config values used when not provided by the user.
@@ -42,6 +46,7 @@ var defaultClientConfig = ClientConfig{
 	ReceiveTimeout:         time.Second * 3,
 	ReceiveBufferBlockSize: 1,
 	UseChecksum:            true,
+	PartialRetries:         2,
 }
 
 // check does set default values on missing or fail if required
@@ -86,5 +91,8 @@ func (c *ClientConfig) check() error {
 	if c.UseChecksum == nil {
 		c.UseChecksum = defaultClientConfig.UseChecksum
 	}
+	if c.PartialRetries == 0 {
+		c.PartialRetries = defaultClientConfig.PartialRetries
+	}
 	return nil
 }
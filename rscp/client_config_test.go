@@ -23,17 +23,22 @@ func Test_check(t *testing.T) {
 		},
 		{"valid minimal config",
 			ClientConfig{Address: "127.0.0.1", Username: "testuser", Password: "testpassword", Key: "testkey"},
-			ClientConfig{Address: "127.0.0.1", Port: defaultClientConfig.Port, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: defaultClientConfig.HeartbeatInterval, ConnectionTimeout: defaultClientConfig.ConnectionTimeout, SendTimeout: defaultClientConfig.SendTimeout, ReceiveTimeout: defaultClientConfig.ReceiveTimeout, ReceiveBufferBlockSize: defaultClientConfig.ReceiveBufferBlockSize, UseChecksum: defaultClientConfig.UseChecksum},
+			ClientConfig{Address: "127.0.0.1", Port: defaultClientConfig.Port, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: defaultClientConfig.HeartbeatInterval, ConnectionTimeout: defaultClientConfig.ConnectionTimeout, SendTimeout: defaultClientConfig.SendTimeout, ReceiveTimeout: defaultClientConfig.ReceiveTimeout, ReceiveBufferBlockSize: defaultClientConfig.ReceiveBufferBlockSize, UseChecksum: defaultClientConfig.UseChecksum, PartialRetries: defaultClientConfig.PartialRetries},
 			nil,
 		},
 		{"valid full config overriding all defaults",
-			ClientConfig{Address: "127.0.0.1", Port: 5555, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: time.Second * 60, ConnectionTimeout: 1, SendTimeout: 1, ReceiveTimeout: 1, ReceiveBufferBlockSize: 1, UseChecksum: false},
-			ClientConfig{Address: "127.0.0.1", Port: 5555, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: time.Second * 60, ConnectionTimeout: 1, SendTimeout: 1, ReceiveTimeout: 1, ReceiveBufferBlockSize: 1, UseChecksum: false},
+			ClientConfig{Address: "127.0.0.1", Port: 5555, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: time.Second * 60, ConnectionTimeout: 1, SendTimeout: 1, ReceiveTimeout: 1, ReceiveBufferBlockSize: 1, UseChecksum: false, PartialRetries: 5},
+			ClientConfig{Address: "127.0.0.1", Port: 5555, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: time.Second * 60, ConnectionTimeout: 1, SendTimeout: 1, ReceiveTimeout: 1, ReceiveBufferBlockSize: 1, UseChecksum: false, PartialRetries: 5},
 			nil,
 		},
 		{"autofix values",
 			ClientConfig{Address: "127.0.0.1", Port: 0, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: -1, ConnectionTimeout: -1, SendTimeout: -1, ReceiveTimeout: -1, ReceiveBufferBlockSize: RSCP_FRAME_MAX_BLOCK_SIZE + 1},
-			ClientConfig{Address: "127.0.0.1", Port: defaultClientConfig.Port, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: defaultClientConfig.HeartbeatInterval, ConnectionTimeout: defaultClientConfig.ConnectionTimeout, SendTimeout: defaultClientConfig.SendTimeout, ReceiveTimeout: defaultClientConfig.ReceiveTimeout, ReceiveBufferBlockSize: defaultClientConfig.ReceiveBufferBlockSize, UseChecksum: defaultClientConfig.UseChecksum},
+			ClientConfig{Address: "127.0.0.1", Port: defaultClientConfig.Port, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: defaultClientConfig.HeartbeatInterval, ConnectionTimeout: defaultClientConfig.ConnectionTimeout, SendTimeout: defaultClientConfig.SendTimeout, ReceiveTimeout: defaultClientConfig.ReceiveTimeout, ReceiveBufferBlockSize: defaultClientConfig.ReceiveBufferBlockSize, UseChecksum: defaultClientConfig.UseChecksum, PartialRetries: defaultClientConfig.PartialRetries},
+			nil,
+		},
+		{"disabled partial retries",
+			ClientConfig{Address: "127.0.0.1", Username: "testuser", Password: "testpassword", Key: "testkey", PartialRetries: -1},
+			ClientConfig{Address: "127.0.0.1", Port: defaultClientConfig.Port, Username: "testuser", Password: "testpassword", Key: "testkey", HeartbeatInterval: defaultClientConfig.HeartbeatInterval, ConnectionTimeout: defaultClientConfig.ConnectionTimeout, SendTimeout: defaultClientConfig.SendTimeout, ReceiveTimeout: defaultClientConfig.ReceiveTimeout, ReceiveBufferBlockSize: defaultClientConfig.ReceiveBufferBlockSize, UseChecksum: defaultClientConfig.UseChecksum, PartialRetries: -1},
 			nil,
 		},
 		{"wrong UseChecksum type",
@@ -2,6 +2,7 @@ package rscp
 
 import (
 	"bytes"
+	"crypto/cipher"
 
 	"github.com/azihsoyn/rijndael256"
 )
@@ -34,3 +35,13 @@ func newIV() IV {
 	copy(iv[:], bytes.Repeat([]byte{RSCP_CRYPT_IV_PADDING}, int(RSCP_CRYPT_BLOCK_SIZE))[:RSCP_CRYPT_BLOCK_SIZE])
 	return iv
 }
+
+// NewCipherModes returns the encrypter and decrypter of a new connection for the key.
+//
+// the cipher block chains of both directions start with the initial IV on every connection.
+func NewCipherModes(key string) (encrypter, decrypter cipher.BlockMode) {
+	k := createAESKey(key)
+	block, _ := rijndael256.NewCipher(k[:]) // implementation does not return an error
+	iv := newIV()
+	return cipher.NewCBCEncrypter(block, iv[:]), cipher.NewCBCDecrypter(block, iv[:])
+}
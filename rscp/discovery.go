@@ -0,0 +1,153 @@
+package rscp
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNotDiscovered is returned if a component required by a config isn't discovered
+var ErrNotDiscovered = errors.New("component not discovered")
+
+// DiscoveryMaxIndex is the highest index probed per namespace
+const DiscoveryMaxIndex uint16 = 32
+
+// offsets of the tags within the device state container of all namespaces (i.e. BAT_DEVICE_CONNECTED - BAT_DEVICE_STATE)
+const (
+	deviceConnectedOffset Tag = 1
+	deviceWorkingOffset   Tag = 2
+	deviceInServiceOffset Tag = 3
+)
+
+// Component is a discovered indexed component
+type Component struct {
+	Namespace string `json:"namespace"`
+	Index     uint16 `json:"index"`
+	// value of the namespace's type tag formatted as string (i.e. the device name of a battery)
+	Type      string `json:"type,omitempty"`
+	Connected bool   `json:"connected"`
+	Working   bool   `json:"working"`
+	InService bool   `json:"inService"`
+}
+
+// Components maps the namespace name to the discovered components
+type Components map[string][]Component
+
+// Indexes returns the indexes of the discovered components of the namespace
+func (c Components) Indexes(namespace string) []uint16 {
+	r := make([]uint16, 0, len(c[namespace]))
+	for _, comp := range c[namespace] {
+		r = append(r, comp.Index)
+	}
+	return r
+}
+
+// Has returns true if the component of the namespace with the index is discovered
+func (c Components) Has(namespace string, index uint16) bool {
+	for _, comp := range c[namespace] {
+		if comp.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the index if the component is discovered, or the index of the first discovered component
+// of the namespace if index is nil.
+func (c Components) Resolve(namespace string, index *uint16) (uint16, error) {
+	if index != nil {
+		if !c.Has(namespace, *index) {
+			return 0, fmt.Errorf("%s %d: %w", namespace, *index, ErrNotDiscovered)
+		}
+		return *index, nil
+	}
+	if len(c[namespace]) == 0 {
+		return 0, fmt.Errorf("%s: %w", namespace, ErrNotDiscovered)
+	}
+	return c[namespace][0].Index, nil
+}
+
+// Sender sends multiple requests in one round-trip (implemented by Client)
+type Sender interface {
+	SendMultiple(requests []Message) ([]Message, error)
+}
+
+// Discover probes all indexes up to DiscoveryMaxIndex of all namespaces, unavailable indexes are skipped.
+func Discover(s Sender) (Components, error) {
+	return discover(s, Namespaces)
+}
+
+// Discover probes all indexes up to DiscoveryMaxIndex of all namespaces, unavailable indexes are skipped.
+func (c *Client) Discover() (Components, error) {
+	return Discover(c)
+}
+
+// discover probes all indexes of the namespaces, an unavailable index doesn't end the namespace
+// as the indexes are not necessarily contiguous (i.e. an additional power meter at index 6).
+func discover(s Sender, namespaces []Namespace) (Components, error) {
+	r := Components{}
+	for _, n := range namespaces {
+		r[n.Name] = []Component{}
+		for i := uint16(0); i <= DiscoveryMaxIndex; i++ {
+			c, err := probe(s, n, i)
+			if err != nil {
+				return nil, err
+			}
+			if c == nil {
+				continue
+			}
+			log.Debugf("discovered %s %d (%s)", n.Name, i, c.Type)
+			r[n.Name] = append(r[n.Name], *c)
+		}
+	}
+	return r, nil
+}
+
+// probe requests the device state of the component at the index, returns nil if the component is not available.
+func probe(s Sender, n Namespace, index uint16) (*Component, error) {
+	values := []interface{}{n.DeviceStateTag}
+	if n.TypeTag != 0 {
+		values = append(values, n.TypeTag)
+	}
+	req, err := n.NewRequest(index, values...)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.SendMultiple([]Message{*req})
+	if err != nil {
+		return nil, fmt.Errorf("probing %s %d: %w", n.Name, index, err)
+	}
+	if len(responses) == 0 || responses[0].DataType == Error {
+		return nil, nil
+	}
+	sub, isContainer := responses[0].Value.([]Message)
+	if !isContainer {
+		return nil, nil
+	}
+	state := FindTag(sub, n.DeviceStateTag.ResponseTag())
+	if state == nil || state.DataType != Container {
+		return nil, nil
+	}
+	c := &Component{Namespace: n.Name, Index: index}
+	for _, m := range state.Value.([]Message) {
+		v, isBool := m.Value.(bool)
+		if !isBool {
+			continue
+		}
+		switch m.Tag - state.Tag {
+		case deviceConnectedOffset:
+			c.Connected = v
+		case deviceWorkingOffset:
+			c.Working = v
+		case deviceInServiceOffset:
+			c.InService = v
+		}
+	}
+	if n.TypeTag != 0 {
+		if t := FindTag(sub, n.TypeTag.ResponseTag()); t != nil && t.DataType != Error {
+			c.Type = fmt.Sprint(t.Value)
+		}
+	}
+	return c, nil
+}
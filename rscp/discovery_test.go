@@ -0,0 +1,110 @@
+package rscp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// fakeDiscoverySender answers device state requests for the available indexes of each namespace
+type fakeDiscoverySender map[string][]int
+
+func (f fakeDiscoverySender) available(namespace string, index int) bool {
+	for _, i := range f[namespace] {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fakeDiscoverySender) SendMultiple(requests []Message) ([]Message, error) {
+	n, _ := NamespaceByContainer(requests[0].Tag)
+	i, _ := requests[0].Value.([]Message)[0].Float64()
+	index := requests[0].Value.([]Message)[0]
+	if !f.available(n.Name, int(i)) {
+		if n.Name == "PM" {
+			// some namespaces return an error for the whole container
+			return []Message{{n.ResponseContainer, Error, ERR_NOT_AVAILABLE}}, nil
+		}
+		return []Message{{n.ResponseContainer, Container, []Message{
+			index,
+			{n.DeviceStateTag.ResponseTag(), Error, ERR_NOT_AVAILABLE},
+		}}}, nil
+	}
+	state := n.DeviceStateTag.ResponseTag()
+	r := []Message{index, {state, Container, []Message{
+		{state + deviceConnectedOffset, Bool, true},
+		{state + deviceWorkingOffset, Bool, i == 0},
+		{state + deviceInServiceOffset, Bool, false},
+	}}}
+	if n.TypeTag != 0 {
+		r = append(r, Message{n.TypeTag.ResponseTag(), CString, "type"})
+	}
+	return []Message{{n.ResponseContainer, Container, r}}, nil
+}
+
+func Test_discover(t *testing.T) {
+	// the additional power meter at index 6 is found behind the gap
+	got, err := discover(fakeDiscoverySender{"BAT": {0, 1}, "PM": {0, 6}}, Namespaces)
+	if err != nil {
+		t.Fatalf("discover() error = %v", err)
+	}
+	want := Components{
+		"BAT": {
+			{Namespace: "BAT", Index: 0, Type: "type", Connected: true, Working: true},
+			{Namespace: "BAT", Index: 1, Type: "type", Connected: true},
+		},
+		"DCDC": {},
+		"PM": {
+			{Namespace: "PM", Index: 0, Type: "type", Connected: true, Working: true},
+			{Namespace: "PM", Index: 6, Type: "type", Connected: true},
+		},
+		"PVI":  {},
+		"WB":   {},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("discover() = %v, want %v\n%s", got, want, diff)
+	}
+	if diff := deep.Equal(got.Indexes("BAT"), []uint16{0, 1}); diff != nil {
+		t.Errorf("Indexes() %s", diff)
+	}
+}
+
+func TestComponents_Resolve(t *testing.T) {
+	c := Components{"PM": {{Namespace: "PM", Index: 0}, {Namespace: "PM", Index: 6}}, "WB": {}}
+	index := func(i uint16) *uint16 { return &i }
+	tests := []struct {
+		name      string
+		namespace string
+		index     *uint16
+		want      uint16
+		wantErr   error
+	}{
+		{"first", "PM", nil, 0, nil},
+		{"configured", "PM", index(6), 6, nil},
+		{"not discovered", "PM", index(1), 0, ErrNotDiscovered},
+		{"none discovered", "WB", nil, 0, ErrNotDiscovered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Resolve(tt.namespace, tt.index)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Resolve() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+type failingSender struct{}
+
+func (failingSender) SendMultiple(requests []Message) ([]Message, error) {
+	return nil, errors.New("timeout")
+}
+
+func Test_discover_error(t *testing.T) {
+	if _, err := discover(failingSender{}, Namespaces); err == nil {
+		t.Errorf("discover() expected error")
+	}
+}
@@ -0,0 +1,66 @@
+package rscp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EMSStatus is the decoded bitfield of the EMS_STATUS response
+type EMSStatus uint32
+
+// all EMS_STATUS bits as constant
+//nolint: golint,stylecheck
+const (
+	// charging of the batteries is blocked
+	EMS_STATUS_CHARGE_BLOCKED EMSStatus = 1 << iota
+	// discharging of the batteries is blocked
+	EMS_STATUS_DISCHARGE_BLOCKED
+	// emergency power mode is possible
+	EMS_STATUS_EMERGENCY_POWER_POSSIBLE
+	// weather based charging holds back charge capacity
+	EMS_STATUS_WEATHER_REGULATED_CHARGE
+	// derating limit is reached
+	EMS_STATUS_DERATING
+	// charge idle period is active
+	EMS_STATUS_CHARGE_IDLE_PERIOD
+	// discharge idle period is active
+	EMS_STATUS_DISCHARGE_IDLE_PERIOD
+)
+
+var emsStatusNames = []string{
+	"CHARGE_BLOCKED",
+	"DISCHARGE_BLOCKED",
+	"EMERGENCY_POWER_POSSIBLE",
+	"WEATHER_REGULATED_CHARGE",
+	"DERATING",
+	"CHARGE_IDLE_PERIOD",
+	"DISCHARGE_IDLE_PERIOD",
+}
+
+// NewEMSStatus decodes the EMS_STATUS response value
+func NewEMSStatus(m Message) (EMSStatus, error) {
+	if m.Tag != EMS_STATUS {
+		return 0, fmt.Errorf("expected %s got %s: %w", EMS_STATUS, m.Tag, ErrValidTag)
+	}
+	v, err := m.Float64()
+	if err != nil {
+		return 0, err
+	}
+	return EMSStatus(v), nil
+}
+
+// Has returns if all bits of flag are set
+func (s EMSStatus) Has(flag EMSStatus) bool {
+	return s&flag == flag
+}
+
+// String converter function for EMSStatus, lists the names of all set bits
+func (s EMSStatus) String() string {
+	names := []string{}
+	for i, n := range emsStatusNames {
+		if s.Has(1 << i) {
+			names = append(names, n)
+		}
+	}
+	return strings.Join(names, "|")
+}
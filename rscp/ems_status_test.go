@@ -0,0 +1,33 @@
+package rscp
+
+import (
+	"testing"
+)
+
+func TestNewEMSStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    Message
+		want       EMSStatus
+		wantString string
+		wantErr    bool
+	}{
+		{"nothing set", Message{EMS_STATUS, Uint32, uint32(0)}, 0, "", false},
+		{"charge blocked and idle", Message{EMS_STATUS, Uint32, uint32(0b100001)},
+			EMS_STATUS_CHARGE_BLOCKED | EMS_STATUS_CHARGE_IDLE_PERIOD, "CHARGE_BLOCKED|CHARGE_IDLE_PERIOD", false},
+		{"documented data type", Message{EMS_STATUS, UChar8, uint8(2)}, EMS_STATUS_DISCHARGE_BLOCKED, "DISCHARGE_BLOCKED", false},
+		{"wrong tag", Message{EMS_MODE, UChar8, uint8(2)}, 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEMSStatus(tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEMSStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || got.String() != tt.wantString {
+				t.Errorf("NewEMSStatus() = %v (%d), want %v (%d)", got, got, tt.wantString, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ var ErrTagDataTypeMismatch = errors.New("tag data type does not match data type"
 var ErrDataTypeValueMismatch = errors.New("value does not match data type")
 var ErrValidTag = errors.New("not a valid tag")
 var ErrMissingValue = errors.New("missing value")
+var ErrNotANumber = errors.New("value is not a number")
 
 var ErrJSONUnmarshal = errors.New("json unmarshal error")
 
@@ -20,3 +21,8 @@ var ErrRscpProtVersionMismatch = errors.New("ERR_PROT_VERSION_MISMATCH")
 var ErrRscpInvalidFrameLength = errors.New("ERR_INVALID_FRAME_LENGTH")
 var ErrRscpInvalidCrc = errors.New("ERR_INVALID_CRC")
 var ErrRscpDataLimitExceeded = errors.New("ERR_DATA_LIMIT_EXCEEDED")
+var ErrMissingHistory = errors.New("missing history data in response")
+var ErrOutcomeUnknown = errors.New("outcome of the request unknown")
+var ErrInvalidQuery = errors.New("invalid query")
+var ErrErrorResponse = errors.New("error response")
+var ErrPartialResponse = errors.New("partial response")
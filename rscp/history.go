@@ -0,0 +1,112 @@
+package rscp
+
+import (
+	"fmt"
+	"time"
+)
+
+// HistorySumTags are the energies in Wh of the DB_SUM_CONTAINER and DB_VALUE_CONTAINER of the history data
+var HistorySumTags = []Tag{
+	DB_BAT_POWER_IN,
+	DB_BAT_POWER_OUT,
+	DB_DC_POWER,
+	DB_GRID_POWER_IN,
+	DB_GRID_POWER_OUT,
+	DB_CONSUMPTION,
+	DB_PM_0_POWER,
+	DB_PM_1_POWER,
+}
+
+// NewHistoryRequest creates the DB_REQ_HISTORY_DATA_DAY request of the period starting at start.
+//
+// the interval and span are durations sent as timestamp since the epoch. The response contains a DB_SUM_CONTAINER
+// with the energies of the whole span and a DB_VALUE_CONTAINER per interval.
+func NewHistoryRequest(start time.Time, interval, span time.Duration) (*Message, error) {
+	return CreateRequest(DB_REQ_HISTORY_DATA_DAY,
+		DB_REQ_HISTORY_TIME_START, start.UTC(),
+		DB_REQ_HISTORY_TIME_INTERVAL, time.Unix(int64(interval/time.Second), 0).UTC(),
+		DB_REQ_HISTORY_TIME_SPAN, time.Unix(int64(span/time.Second), 0).UTC(),
+	)
+}
+
+// HistoryMinInterval is the finest interval of the history data supported by the systems
+const HistoryMinInterval = time.Minute * 15
+
+// historyFullPercent is the span of DB_GRAPH_INDEX in percent
+const historyFullPercent = 100
+
+// history returns the values of the DB_HISTORY_DATA_DAY response
+func history(responses []Message) ([]Message, error) {
+	history := FindTag(responses, DB_HISTORY_DATA_DAY)
+	switch {
+	case history == nil:
+		return nil, fmt.Errorf("%s: %w", DB_HISTORY_DATA_DAY, ErrMissingHistory)
+	case history.DataType == Error:
+		return nil, fmt.Errorf("%s returned error %v: %w", DB_HISTORY_DATA_DAY, history.Value, ErrMissingHistory)
+	}
+	values, _ := history.Value.([]Message)
+	return values, nil
+}
+
+// historyValues returns the numeric values of a container of the history data by tag
+func historyValues(container []Message) map[Tag]float64 {
+	r := map[Tag]float64{}
+	for _, m := range container {
+		if v, err := m.Float64(); err == nil {
+			r[m.Tag] = v
+		}
+	}
+	return r
+}
+
+// HistorySums returns the values of the DB_SUM_CONTAINER of the DB_HISTORY_DATA_DAY response by tag
+func HistorySums(responses []Message) (map[Tag]float64, error) {
+	values, err := history(responses)
+	if err != nil {
+		return nil, err
+	}
+	sum := FindTag(values, DB_SUM_CONTAINER)
+	if sum == nil {
+		return nil, fmt.Errorf("%s: %w", DB_SUM_CONTAINER, ErrMissingHistory)
+	}
+	return historyValues(sum.Value.([]Message)), nil
+}
+
+// HistoryValue is the DB_VALUE_CONTAINER of an interval of the history data
+type HistoryValue struct {
+	// start of the interval
+	Time time.Time
+	// values by tag (i.e. the energies in Wh of HistorySumTags)
+	Values map[Tag]float64
+}
+
+// HistoryValues returns the values of the DB_VALUE_CONTAINER of the DB_HISTORY_DATA_DAY response to the request
+// starting at start with the span (see NewHistoryRequest).
+//
+// the start of an interval is derived from DB_GRAPH_INDEX, the position within the span in percent.
+func HistoryValues(responses []Message, start time.Time, span time.Duration) ([]HistoryValue, error) {
+	values, err := history(responses)
+	if err != nil {
+		return nil, err
+	}
+	var r []HistoryValue
+	for _, m := range values {
+		if m.Tag != DB_VALUE_CONTAINER {
+			continue
+		}
+		container, _ := m.Value.([]Message)
+		v := historyValues(container)
+		position, exists := v[DB_GRAPH_INDEX]
+		if !exists {
+			return nil, fmt.Errorf("%s without %s: %w", DB_VALUE_CONTAINER, DB_GRAPH_INDEX, ErrMissingHistory)
+		}
+		delete(v, DB_GRAPH_INDEX)
+		// the position is a float32, rounded to the second
+		offset := (time.Duration(float64(span) * position / historyFullPercent)).Round(time.Second)
+		r = append(r, HistoryValue{Time: start.Add(offset), Values: v})
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("%s: %w", DB_VALUE_CONTAINER, ErrMissingHistory)
+	}
+	return r, nil
+}
@@ -0,0 +1,90 @@
+package rscp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestNewHistoryRequest(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.FixedZone("CEST", 7200))
+	got, err := NewHistoryRequest(start, time.Hour, time.Hour*24)
+	if err != nil {
+		t.Fatalf("NewHistoryRequest() error = %v", err)
+	}
+	want := &Message{DB_REQ_HISTORY_DATA_DAY, Container, []Message{
+		{DB_REQ_HISTORY_TIME_START, Timestamp, start.UTC()},
+		{DB_REQ_HISTORY_TIME_INTERVAL, Timestamp, time.Unix(3600, 0).UTC()},
+		{DB_REQ_HISTORY_TIME_SPAN, Timestamp, time.Unix(86400, 0).UTC()},
+	}}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("NewHistoryRequest() = %v, want %v\n%s", got, want, diff)
+	}
+}
+
+func TestHistorySums(t *testing.T) {
+	responses := []Message{{DB_HISTORY_DATA_DAY, Container, []Message{
+		{DB_SUM_CONTAINER, Container, []Message{
+			{DB_GRAPH_INDEX, Float32, float32(0)},
+			{DB_BAT_POWER_IN, Float32, float32(1500)},
+			{DB_DC_POWER, Float32, float32(20000)},
+		}},
+		{DB_VALUE_CONTAINER, Container, []Message{
+			{DB_BAT_POWER_IN, Float32, float32(100)},
+		}},
+	}}}
+	got, err := HistorySums(responses)
+	if err != nil {
+		t.Fatalf("HistorySums() error = %v", err)
+	}
+	want := map[Tag]float64{DB_GRAPH_INDEX: 0, DB_BAT_POWER_IN: 1500, DB_DC_POWER: 20000}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("HistorySums() = %v, want %v\n%s", got, want, diff)
+	}
+	for _, r := range [][]Message{
+		nil,
+		{{DB_HISTORY_DATA_DAY, Error, ERR_NOT_AVAILABLE}},
+		{{DB_HISTORY_DATA_DAY, Container, []Message{}}},
+	} {
+		if _, err := HistorySums(r); !errors.Is(err, ErrMissingHistory) {
+			t.Errorf("HistorySums(%v) error = %v, want %v", r, err, ErrMissingHistory)
+		}
+	}
+}
+
+func TestHistoryValues(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	responses := []Message{{DB_HISTORY_DATA_DAY, Container, []Message{
+		{DB_SUM_CONTAINER, Container, []Message{{DB_BAT_POWER_IN, Float32, float32(300)}}},
+		{DB_VALUE_CONTAINER, Container, []Message{
+			{DB_GRAPH_INDEX, Float32, float32(0)},
+			{DB_BAT_POWER_IN, Float32, float32(100)},
+		}},
+		{DB_VALUE_CONTAINER, Container, []Message{
+			{DB_GRAPH_INDEX, Float32, float32(100.0 / 3)},
+			{DB_BAT_POWER_IN, Float32, float32(200)},
+		}},
+	}}}
+	got, err := HistoryValues(responses, start, time.Minute*45)
+	if err != nil {
+		t.Fatalf("HistoryValues() error = %v", err)
+	}
+	want := []HistoryValue{
+		{start, map[Tag]float64{DB_BAT_POWER_IN: 100}},
+		{start.Add(time.Minute * 15), map[Tag]float64{DB_BAT_POWER_IN: 200}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("HistoryValues() = %v, want %v\n%s", got, want, diff)
+	}
+	for _, r := range [][]Message{
+		{{DB_HISTORY_DATA_DAY, Error, ERR_NOT_AVAILABLE}},
+		{{DB_HISTORY_DATA_DAY, Container, []Message{}}},
+		{{DB_HISTORY_DATA_DAY, Container, []Message{{DB_VALUE_CONTAINER, Container, []Message{}}}}},
+	} {
+		if _, err := HistoryValues(r, start, time.Hour); !errors.Is(err, ErrMissingHistory) {
+			t.Errorf("HistoryValues(%v) error = %v, want %v", r, err, ErrMissingHistory)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package rscp
+
+import (
+	"fmt"
+)
+
+// FindTag returns the first message with the given tag (recursive depth first through containers)
+// or nil if the tag is not found.
+func FindTag(messages []Message, tag Tag) *Message {
+	for i := range messages {
+		if messages[i].Tag == tag {
+			return &messages[i]
+		}
+		if sub, isContainer := messages[i].Value.([]Message); isContainer {
+			if m := FindTag(sub, tag); m != nil {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// FindIndexed returns the top level container with the given tag containing the index tag with the given index value
+// (i.e. the PM_DATA container with PM_INDEX 1) or nil if not found.
+func FindIndexed(messages []Message, tag Tag, indexTag Tag, index uint16) *Message {
+	for i := range messages {
+		if messages[i].Tag != tag {
+			continue
+		}
+		sub, isContainer := messages[i].Value.([]Message)
+		if !isContainer {
+			continue
+		}
+		for _, s := range sub {
+			if s.Tag != indexTag {
+				continue
+			}
+			if v, err := s.Float64(); err == nil && v == float64(index) {
+				return &messages[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Float64 returns the value of the message converted to a float64.
+//
+// booleans are converted to 0 or 1, an error response results in an error.
+func (m Message) Float64() (float64, error) {
+	switch v := m.Value.(type) {
+	default:
+		return 0, fmt.Errorf("%s of type %T: %w", m.Tag, m.Value, ErrNotANumber)
+	case RscpError:
+		return 0, fmt.Errorf("%s returned error %s: %w", m.Tag, v, ErrNotANumber)
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case int8:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+}
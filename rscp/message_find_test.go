@@ -0,0 +1,94 @@
+package rscp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+var findTestMessages = []Message{
+	{EMS_POWER_PV, Int32, int32(1000)},
+	{PM_DATA, Container, []Message{
+		{PM_INDEX, UInt16, uint16(0)},
+		{PM_ENERGY_L1, Double64, float64(10)},
+	}},
+	{PM_DATA, Container, []Message{
+		{PM_INDEX, UInt16, uint16(1)},
+		{PM_ENERGY_L1, Double64, float64(20)},
+	}},
+}
+
+func TestFindTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  Tag
+		want *Message
+	}{
+		{"top level",
+			EMS_POWER_PV,
+			&Message{EMS_POWER_PV, Int32, int32(1000)},
+		},
+		{"first nested",
+			PM_ENERGY_L1,
+			&Message{PM_ENERGY_L1, Double64, float64(10)},
+		},
+		{"not found",
+			BAT_RSOC,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(FindTag(findTestMessages, tt.tag), tt.want); diff != nil {
+				t.Errorf("FindTag() = %v, want %v\n%s", FindTag(findTestMessages, tt.tag), tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestFindIndexed(t *testing.T) {
+	tests := []struct {
+		name  string
+		index uint16
+		want  *Message
+	}{
+		{"index 0", 0, &findTestMessages[1]},
+		{"index 1", 1, &findTestMessages[2]},
+		{"missing index", 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindIndexed(findTestMessages, PM_DATA, PM_INDEX, tt.index); got != tt.want {
+				t.Errorf("FindIndexed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage_Float64(t *testing.T) {
+	tests := []struct {
+		name    string
+		message Message
+		want    float64
+		wantErr error
+	}{
+		{"int32", Message{EMS_POWER_PV, Int32, int32(-5)}, -5, nil},
+		{"float32", Message{BAT_RSOC, Float32, float32(0.5)}, 0.5, nil},
+		{"bool", Message{PVI_ON_GRID, Bool, true}, 1, nil},
+		{"string", Message{INFO_SERIAL_NUMBER, CString, "S10"}, 0, ErrNotANumber},
+		{"error", Message{EMS_POWER_PV, Error, ERR_NOT_AVAILABLE}, 0, ErrNotANumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.message.Float64()
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Message.Float64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Message.Float64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
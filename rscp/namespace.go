@@ -0,0 +1,94 @@
+package rscp
+
+import (
+	"fmt"
+)
+
+// Namespace describes the tags of indexed components (i.e. batteries or power meters)
+type Namespace struct {
+	// short name of the namespace (i.e. BAT)
+	Name string
+	// request container (i.e. BAT_REQ_DATA)
+	Container Tag
+	// response container (i.e. BAT_DATA)
+	ResponseContainer Tag
+	// index tag within the containers (i.e. BAT_INDEX)
+	IndexTag Tag
+	// request of the device state (i.e. BAT_REQ_DEVICE_STATE)
+	DeviceStateTag Tag
+	// request identifying the type of the component or 0 if there is none (i.e. BAT_REQ_DEVICE_NAME)
+	TypeTag Tag
+}
+
+// Namespaces contains all namespaces of indexed components
+var Namespaces = []Namespace{
+	{"BAT", BAT_REQ_DATA, BAT_DATA, BAT_INDEX, BAT_REQ_DEVICE_STATE, BAT_REQ_DEVICE_NAME},
+	{"DCDC", DCDC_REQ_DATA, DCDC_DATA, DCDC_INDEX, DCDC_REQ_DEVICE_STATE, 0},
+	{"PM", PM_REQ_DATA, PM_DATA, PM_INDEX, PM_REQ_DEVICE_STATE, PM_REQ_TYPE},
+	{"PVI", PVI_REQ_DATA, PVI_DATA, PVI_INDEX, PVI_REQ_DEVICE_STATE, PVI_REQ_TYPE},
+	{"WB", WB_REQ_DATA, WB_DATA, WB_INDEX, WB_REQ_DEVICE_STATE, 0},
+}
+
+// NamespaceByName returns the namespace with the given name
+func NamespaceByName(name string) (Namespace, bool) {
+	for _, n := range Namespaces {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// NamespaceByContainer returns the namespace of the given request or response container tag
+func NamespaceByContainer(tag Tag) (Namespace, bool) {
+	for _, n := range Namespaces {
+		if n.Container == tag || n.ResponseContainer == tag {
+			return n, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// namespaceShift is the bit offset of the namespace byte within a tag
+const namespaceShift = 24
+
+// NamespaceByTag returns the namespace the given tag belongs to (i.e. BAT for BAT_RSOC)
+func NamespaceByTag(tag Tag) (Namespace, bool) {
+	for _, n := range Namespaces {
+		if tag>>namespaceShift == n.Container>>namespaceShift {
+			return n, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// NewIndexMessage returns the index message of the namespace with the index converted to the data type of the index tag.
+func (n Namespace) NewIndexMessage(index uint16) (*Message, error) {
+	v, err := n.IndexTag.DataType().new(index)
+	if err != nil {
+		return nil, fmt.Errorf("index %d for %s: %w", index, n.IndexTag, err)
+	}
+	m := NewMessage(n.IndexTag, v)
+	// the conversion does not fail on overflows
+	if f, err := m.Float64(); err != nil || f != float64(index) {
+		return nil, fmt.Errorf("index %d out of range for %s: %w", index, n.IndexTag, ErrDataTypeValueMismatch)
+	}
+	return m, nil
+}
+
+// NewRequest creates the request container for the component with the given index.
+// The values are the sub requests of the container as accepted by CreateRequest.
+// Example:
+//  NewRequest(0, BAT_REQ_RSOC, BAT_REQ_DEVICE_STATE)
+func (n Namespace) NewRequest(index uint16, values ...interface{}) (*Message, error) {
+	i, err := n.NewIndexMessage(index)
+	if err != nil {
+		return nil, err
+	}
+	c, err := CreateRequest(append([]interface{}{n.Container}, values...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.Value = append([]Message{*i}, c.Value.([]Message)...)
+	return c, nil
+}
@@ -0,0 +1,81 @@
+package rscp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestNamespace_NewRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		index     uint16
+		values    []interface{}
+		want      *Message
+		wantErr   bool
+	}{
+		{"battery",
+			"BAT", 1, []interface{}{BAT_REQ_RSOC},
+			&Message{BAT_REQ_DATA, Container, []Message{{BAT_INDEX, UInt16, uint16(1)}, {BAT_REQ_RSOC, None, nil}}},
+			false,
+		},
+		{"wallbox index is converted to uint8",
+			"WB", 2, []interface{}{WB_REQ_DEVICE_STATE},
+			&Message{WB_REQ_DATA, Container, []Message{{WB_INDEX, UChar8, uint8(2)}, {WB_REQ_DEVICE_STATE, None, nil}}},
+			false,
+		},
+		{"wallbox index out of range",
+			"WB", 256, nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, _ := NamespaceByName(tt.namespace)
+			got, err := n.NewRequest(tt.index, tt.values...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("NewRequest() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestNamespaceByContainer(t *testing.T) {
+	for _, tag := range []Tag{PM_REQ_DATA, PM_DATA} {
+		if n, ok := NamespaceByContainer(tag); !ok || n.Name != "PM" {
+			t.Errorf("NamespaceByContainer(%s) = %v, %v", tag, n, ok)
+		}
+	}
+	if _, ok := NamespaceByContainer(EMS_REQ_SET_POWER); ok {
+		t.Errorf("NamespaceByContainer(%s) expected not to be found", EMS_REQ_SET_POWER)
+	}
+}
+
+func TestNamespaceByTag(t *testing.T) {
+	for tag, want := range map[Tag]string{BAT_RSOC: "BAT", WB_REQ_STATUS: "WB", PVI_INDEX: "PVI"} {
+		if n, ok := NamespaceByTag(tag); !ok || n.Name != want {
+			t.Errorf("NamespaceByTag(%s) = %v, %v, want %s", tag, n, ok, want)
+		}
+	}
+	if _, ok := NamespaceByTag(EMS_POWER_PV); ok {
+		t.Errorf("NamespaceByTag(%s) expected not to be found", EMS_POWER_PV)
+	}
+}
+
+func TestTag_RequestResponseTag(t *testing.T) {
+	if got := BAT_REQ_DEVICE_STATE.ResponseTag(); got != BAT_DEVICE_STATE {
+		t.Errorf("ResponseTag() = %s, want %s", got, BAT_DEVICE_STATE)
+	}
+	if got := BAT_DEVICE_STATE.RequestTag(); got != BAT_REQ_DEVICE_STATE {
+		t.Errorf("RequestTag() = %s, want %s", got, BAT_REQ_DEVICE_STATE)
+	}
+	if got := BAT_REQ_DEVICE_STATE.RequestTag(); got != BAT_REQ_DEVICE_STATE {
+		t.Errorf("RequestTag() = %s, want %s", got, BAT_REQ_DEVICE_STATE)
+	}
+}
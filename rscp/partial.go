@@ -0,0 +1,213 @@
+package rscp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PartialResponseError is returned if responses to requests are still missing after the missing parts were re-requested,
+// the responses received are returned along with the error.
+type PartialResponseError struct {
+	// requests without a response (see MissingRequests)
+	Missing []Message
+	// error of the last re-request or nil
+	Err error
+}
+
+func (e *PartialResponseError) Error() string {
+	s := fmt.Sprintf("partial response, missing %s", strings.Join(describeRequests(e.Missing, ""), ", "))
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Is matches ErrPartialResponse
+func (e *PartialResponseError) Is(target error) bool {
+	return target == ErrPartialResponse
+}
+
+func (e *PartialResponseError) Unwrap() error {
+	return e.Err
+}
+
+// describeRequests returns the request tags, within components with the index appended (i.e. BAT_REQ_RSOC@0)
+func describeRequests(requests []Message, suffix string) []string {
+	var r []string
+	for _, m := range requests {
+		n, isComponent := NamespaceByContainer(m.Tag)
+		if !isComponent {
+			r = append(r, m.Tag.String()+suffix)
+			continue
+		}
+		nested, _ := m.Value.([]Message)
+		if index := FindTag(nested, n.IndexTag); index != nil {
+			if i, err := index.Float64(); err == nil {
+				r = append(r, describeRequests(without(nested, n.IndexTag), QueryIndexSeparator+strconv.Itoa(int(i)))...)
+				continue
+			}
+		}
+		r = append(r, m.Tag.String()+suffix)
+	}
+	return r
+}
+
+// without returns the messages without the tag
+func without(messages []Message, tag Tag) []Message {
+	r := make([]Message, 0, len(messages))
+	for _, m := range messages {
+		if m.Tag != tag {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// MissingRequests returns the requests without a response (or an error response) within the responses.
+//
+// The requests within the container of an indexed component (i.e. BAT_REQ_DATA) are checked against the response
+// container of the same index, the container is returned with the index and the missing requests only. Within other
+// containers only the container itself is checked, the nested values are answered by tags not derived from the requests.
+// Values which are not answered (i.e. the index of a component) are ignored.
+func MissingRequests(requests []Message, responses []Message) []Message {
+	var r []Message
+	for _, req := range requests {
+		if m, missing := missingRequest(req, responses); missing {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// missingRequest returns the missing part of the request and if there is any
+func missingRequest(req Message, responses []Message) (Message, bool) {
+	rt := req.Tag.ResponseTag()
+	if !req.Tag.isRequest() || !rt.IsATag() {
+		return Message{}, false
+	}
+	n, isComponent := NamespaceByContainer(req.Tag)
+	if !isComponent {
+		return req, findResponse(req, responses) == nil
+	}
+	nested, _ := req.Value.([]Message)
+	index := FindTag(nested, n.IndexTag)
+	if index == nil {
+		return req, findResponse(req, responses) == nil
+	}
+	i, err := index.Float64()
+	if err != nil {
+		return req, findResponse(req, responses) == nil
+	}
+	c := FindIndexed(responses, n.ResponseContainer, n.IndexTag, uint16(i))
+	if c == nil {
+		// an unavailable component is answered by an error response without index,
+		// the container of another index doesn't answer the request
+		return req, !hasErrorResponse(n.ResponseContainer, responses)
+	}
+	sub, _ := c.Value.([]Message)
+	missing := MissingRequests(without(nested, n.IndexTag), sub)
+	if len(missing) == 0 {
+		return Message{}, false
+	}
+	return Message{Tag: req.Tag, DataType: req.DataType, Value: append([]Message{*index}, missing...)}, true
+}
+
+// findResponse returns the response (or error response) to the request within the top level of the responses,
+// the responses to repeatable requests with an index (i.e. PVI_REQ_DC_POWER) are matched by the index.
+func findResponse(req Message, responses []Message) *Message {
+	rt := req.Tag.ResponseTag()
+	indexTag, indexed := rt.RepeatIndexTag()
+	index, err := req.Float64()
+	indexed = indexed && req.Value != nil && err == nil
+	for i := range responses {
+		if responses[i].Tag != rt {
+			continue
+		}
+		if _, isError := responses[i].Value.(RscpError); isError || !indexed {
+			return &responses[i]
+		}
+		sub, _ := responses[i].Value.([]Message)
+		if m := FindTag(sub, indexTag); m != nil {
+			if v, err := m.Float64(); err == nil && v == index {
+				return &responses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// hasErrorResponse returns if the responses contain an error response of the tag
+func hasErrorResponse(tag Tag, responses []Message) bool {
+	for _, m := range responses {
+		if _, isError := m.Value.(RscpError); isError && m.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// answers returns if the message is the response (or error response) to the request
+func answers(req Message, m Message) bool {
+	if n, isComponent := NamespaceByContainer(req.Tag); isComponent {
+		nested, _ := req.Value.([]Message)
+		if index := FindTag(nested, n.IndexTag); index != nil {
+			if i, err := index.Float64(); err == nil {
+				if _, isError := m.Value.(RscpError); isError {
+					return m.Tag == n.ResponseContainer
+				}
+				return FindIndexed([]Message{m}, n.ResponseContainer, n.IndexTag, uint16(i)) != nil
+			}
+		}
+	}
+	return findResponse(req, []Message{m}) != nil
+}
+
+// orderResponses returns the responses in the order of the requests, responses not answering a request are appended
+func orderResponses(requests []Message, responses []Message) []Message {
+	r := make([]Message, 0, len(responses))
+	used := make([]bool, len(responses))
+	for _, req := range requests {
+		for i, m := range responses {
+			if !used[i] && answers(req, m) {
+				r = append(r, m)
+				used[i] = true
+				break
+			}
+		}
+	}
+	for i, m := range responses {
+		if !used[i] {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// MergeResponses returns the responses to the requests with the responses to re-requested missing parts merged
+// in the order of the requests, the values of an indexed component are merged into the existing container of the component.
+func MergeResponses(requests []Message, responses []Message, recovered []Message) []Message {
+	r := append([]Message{}, responses...)
+	for _, m := range recovered {
+		n, isComponent := NamespaceByContainer(m.Tag)
+		sub, isContainer := m.Value.([]Message)
+		if !isComponent || !isContainer {
+			r = append(r, m)
+			continue
+		}
+		index := FindTag(sub, n.IndexTag)
+		if index == nil {
+			r = append(r, m)
+			continue
+		}
+		i, err := index.Float64()
+		c := FindIndexed(r, n.ResponseContainer, n.IndexTag, uint16(i))
+		if err != nil || c == nil {
+			r = append(r, m)
+			continue
+		}
+		existing, _ := c.Value.([]Message)
+		c.Value = append(append([]Message{}, existing...), without(sub, n.IndexTag)...)
+	}
+	return orderResponses(requests, r)
+}
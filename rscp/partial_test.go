@@ -0,0 +1,79 @@
+package rscp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestMissingRequests(t *testing.T) {
+	requests, _ := CreateRequests(
+		[]interface{}{EMS_REQ_POWER_PV},
+		[]interface{}{EMS_REQ_BAT_SOC},
+		[]interface{}{EMS_REQ_GET_POWER_SETTINGS},
+		[]interface{}{BAT_REQ_DATA, BAT_INDEX, uint16(0), BAT_REQ_RSOC, BAT_REQ_MODULE_VOLTAGE},
+		[]interface{}{BAT_REQ_DATA, BAT_INDEX, uint16(1), BAT_REQ_RSOC},
+		[]interface{}{PVI_REQ_DATA, PVI_INDEX, uint16(0), PVI_REQ_DC_POWER, uint8(0), PVI_REQ_DC_POWER, uint8(1)},
+	)
+	responses := []Message{
+		{EMS_POWER_PV, Int32, int32(1000)},
+		{EMS_GET_POWER_SETTINGS, Container, []Message{{EMS_MAX_CHARGE_POWER, Uint32, uint32(4500)}}},
+		{BAT_DATA, Container, []Message{
+			{BAT_INDEX, UInt16, uint16(0)},
+			{BAT_RSOC, Float32, float32(42)},
+		}},
+		// the unavailable battery is answered with an error
+		{BAT_DATA, Error, ERR_NOT_AVAILABLE},
+		{PVI_DATA, Container, []Message{
+			{PVI_INDEX, UInt16, uint16(0)},
+			{PVI_DC_POWER, Container, []Message{{PVI_INDEX, UInt16, uint16(1)}, {PVI_VALUE, Float32, float32(250)}}},
+		}},
+	}
+	got := MissingRequests(requests, responses)
+	want := []Message{
+		{EMS_REQ_BAT_SOC, None, nil},
+		{BAT_REQ_DATA, Container, []Message{{BAT_INDEX, UInt16, uint16(0)}, {BAT_REQ_MODULE_VOLTAGE, None, nil}}},
+		{PVI_REQ_DATA, Container, []Message{{PVI_INDEX, UInt16, uint16(0)}, {PVI_REQ_DC_POWER, UChar8, uint8(0)}}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Fatalf("MissingRequests() = %v, want %v\n%s", got, want, diff)
+	}
+
+	recovered := []Message{
+		{EMS_BAT_SOC, UChar8, uint8(42)},
+		{BAT_DATA, Container, []Message{{BAT_INDEX, UInt16, uint16(0)}, {BAT_MODULE_VOLTAGE, Float32, float32(51)}}},
+		{PVI_DATA, Container, []Message{
+			{PVI_INDEX, UInt16, uint16(0)},
+			{PVI_DC_POWER, Container, []Message{{PVI_INDEX, UInt16, uint16(0)}, {PVI_VALUE, Float32, float32(300)}}},
+		}},
+	}
+	merged := MergeResponses(requests, responses, recovered)
+	if m := MissingRequests(requests, merged); len(m) != 0 {
+		t.Errorf("MissingRequests() of merged responses = %v, want none", m)
+	}
+	var tags []Tag
+	for _, m := range merged {
+		tags = append(tags, m.Tag)
+	}
+	// in the order of the requests with the components merged
+	if diff := deep.Equal(tags, []Tag{EMS_POWER_PV, EMS_BAT_SOC, EMS_GET_POWER_SETTINGS, BAT_DATA, BAT_DATA, PVI_DATA}); diff != nil {
+		t.Errorf("MergeResponses() = %v\n%s", merged, diff)
+	}
+	if len(responses[2].Value.([]Message)) != 2 {
+		t.Errorf("MergeResponses() modified the responses %v", responses)
+	}
+
+	// the container of another index doesn't answer an omitted component
+	omitted := []Message{{BAT_DATA, Container, []Message{{BAT_INDEX, UInt16, uint16(0)}, {BAT_RSOC, Float32, float32(42)}}}}
+	got = MissingRequests(requests[4:5], omitted)
+	if diff := deep.Equal(got, []Message{requests[4]}); diff != nil {
+		t.Errorf("MissingRequests() of omitted component = %v\n%s", got, diff)
+	}
+
+	err := error(&PartialResponseError{Missing: want})
+	if !errors.Is(err, ErrPartialResponse) ||
+		err.Error() != "partial response, missing EMS_REQ_BAT_SOC, BAT_REQ_MODULE_VOLTAGE@0, PVI_REQ_DC_POWER@0" {
+		t.Errorf("PartialResponseError = %s", err)
+	}
+}
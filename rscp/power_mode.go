@@ -0,0 +1,46 @@
+package rscp
+
+import (
+	"fmt"
+)
+
+// PowerMode is the mode of the EMS_REQ_SET_POWER_MODE request
+type PowerMode uint8
+
+// all EMS_REQ_SET_POWER_MODE modes as constant
+//nolint: golint,stylecheck
+const (
+	// normal operation of the EMS
+	POWER_MODE_AUTO PowerMode = iota
+	// battery is neither charged nor discharged
+	POWER_MODE_IDLE
+	// battery is discharged with the given power
+	POWER_MODE_DISCHARGE
+	// battery is charged with the given power from the available pv power
+	POWER_MODE_CHARGE
+	// battery is charged with the given power from the grid
+	POWER_MODE_GRID_CHARGE
+)
+
+var powerModeNames = []string{
+	"AUTO",
+	"IDLE",
+	"DISCHARGE",
+	"CHARGE",
+	"GRID_CHARGE",
+}
+
+// String converter function for PowerMode
+func (m PowerMode) String() string {
+	if int(m) < len(powerModeNames) {
+		return powerModeNames[m]
+	}
+	return fmt.Sprintf("PowerMode(%d)", m)
+}
+
+// NewSetPowerRequest creates the EMS_REQ_SET_POWER request.
+//
+// the request has to be repeated at least every 30 seconds, otherwise the EMS returns to normal operation.
+func NewSetPowerRequest(mode PowerMode, power int32) (*Message, error) {
+	return CreateRequest(EMS_REQ_SET_POWER, EMS_REQ_SET_POWER_MODE, uint8(mode), EMS_REQ_SET_POWER_VALUE, power)
+}
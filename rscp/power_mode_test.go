@@ -0,0 +1,27 @@
+package rscp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestNewSetPowerRequest(t *testing.T) {
+	got, err := NewSetPowerRequest(POWER_MODE_GRID_CHARGE, 3000)
+	if err != nil {
+		t.Fatalf("NewSetPowerRequest() error = %v", err)
+	}
+	want := &Message{EMS_REQ_SET_POWER, Container, []Message{
+		{EMS_REQ_SET_POWER_MODE, UChar8, uint8(4)},
+		{EMS_REQ_SET_POWER_VALUE, Int32, int32(3000)},
+	}}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("NewSetPowerRequest() = %v, want %v\n%s", got, want, diff)
+	}
+	if s := POWER_MODE_GRID_CHARGE.String(); s != "GRID_CHARGE" {
+		t.Errorf("String() = %s, want GRID_CHARGE", s)
+	}
+	if s := PowerMode(9).String(); s != "PowerMode(9)" {
+		t.Errorf("String() = %s, want PowerMode(9)", s)
+	}
+}
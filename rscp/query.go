@@ -0,0 +1,191 @@
+package rscp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QueryIndexSeparator separates the index of a component from the tag of a query (i.e. BAT_RSOC@0)
+const QueryIndexSeparator = "@"
+
+// queryContainers contains the request of the container of the values answered within a container only
+// (i.e. the power settings are answered within EMS_GET_POWER_SETTINGS)
+var queryContainers = map[Tag]Tag{
+	EMS_POWER_LIMITS_USED:                EMS_REQ_GET_POWER_SETTINGS,
+	EMS_MAX_CHARGE_POWER:                 EMS_REQ_GET_POWER_SETTINGS,
+	EMS_MAX_DISCHARGE_POWER:              EMS_REQ_GET_POWER_SETTINGS,
+	EMS_DISCHARGE_START_POWER:            EMS_REQ_GET_POWER_SETTINGS,
+	EMS_POWERSAVE_ENABLED:                EMS_REQ_GET_POWER_SETTINGS,
+	EMS_WEATHER_REGULATED_CHARGE_ENABLED: EMS_REQ_GET_POWER_SETTINGS,
+}
+
+// Query addresses a single value within responses by its response tag (or the tag within its container),
+// within the container of an indexed component if the namespace is set.
+type Query struct {
+	Tag       Tag
+	Namespace *Namespace
+	Index     uint16
+}
+
+// ParseQuery parses a query of a response or request tag with an optional index of the component
+// (i.e. EMS_POWER_PV or PM_ACTIVE_PHASES@0).
+func ParseQuery(s string) (Query, error) {
+	name, index := s, ""
+	if i := strings.Index(s, QueryIndexSeparator); i >= 0 {
+		name, index = s[:i], s[i+len(QueryIndexSeparator):]
+	}
+	tag, err := TagString(name)
+	if err != nil {
+		return Query{}, fmt.Errorf("%s: %w", s, ErrValidTag)
+	}
+	q := Query{Tag: tag.ResponseTag()}
+	if _, contained := queryContainers[tag]; contained {
+		q.Tag = tag
+	}
+	if index == "" {
+		return q, nil
+	}
+	i, err := strconv.ParseUint(index, 10, 16)
+	if err != nil {
+		return Query{}, fmt.Errorf("%s: invalid index: %w", s, ErrInvalidQuery)
+	}
+	ns, ok := NamespaceByTag(tag)
+	if !ok {
+		return Query{}, fmt.Errorf("%s: not a tag of an indexed component: %w", s, ErrInvalidQuery)
+	}
+	q.Namespace, q.Index = &ns, uint16(i)
+	return q, nil
+}
+
+// String returns the query as parsed by ParseQuery
+func (q Query) String() string {
+	if q.Namespace == nil {
+		return q.Tag.String()
+	}
+	return q.Tag.String() + QueryIndexSeparator + strconv.Itoa(int(q.Index))
+}
+
+// request returns the request of the value
+func (q Query) request() Tag {
+	if c, contained := queryContainers[q.Tag]; contained {
+		return c
+	}
+	return q.Tag.RequestTag()
+}
+
+// QueryRequests returns the requests of the values of the queries,
+// the requests of the same component are combined in one container and every value is only requested once.
+func QueryRequests(queries ...Query) ([]Message, error) {
+	type component struct {
+		namespace string
+		index     uint16
+	}
+	var (
+		requests   []Message
+		components []Query
+	)
+	values := map[component][]interface{}{}
+	requested := map[component]map[Tag]bool{}
+	for _, q := range queries {
+		c := component{index: q.Index}
+		if q.Namespace != nil {
+			c.namespace = q.Namespace.Name
+		}
+		if requested[c] == nil {
+			requested[c] = map[Tag]bool{}
+		}
+		if requested[c][q.request()] {
+			continue
+		}
+		requested[c][q.request()] = true
+		if q.Namespace == nil {
+			requests = append(requests, *NewMessage(q.request(), nil))
+			continue
+		}
+		if _, exists := values[c]; !exists {
+			components = append(components, q)
+		}
+		values[c] = append(values[c], q.request())
+	}
+	for _, q := range components {
+		m, err := q.Namespace.NewRequest(q.Index, values[component{q.Namespace.Name, q.Index}]...)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *m)
+	}
+	return requests, nil
+}
+
+// Find returns the message of the value within the responses or nil if not found
+func (q Query) Find(responses []Message) *Message {
+	scope := responses
+	if q.Namespace != nil {
+		c := FindIndexed(responses, q.Namespace.ResponseContainer, q.Namespace.IndexTag, q.Index)
+		if c == nil {
+			return nil
+		}
+		scope, _ = c.Value.([]Message)
+	}
+	return FindTag(scope, q.Tag)
+}
+
+// value returns the value of the query, fails if missing or an error response
+func (q Query) value(responses []Message) (interface{}, error) {
+	m := q.Find(responses)
+	if m == nil {
+		return nil, fmt.Errorf("%s: %w", q, ErrMissingValue)
+	}
+	if e, isError := m.Value.(RscpError); isError {
+		return nil, fmt.Errorf("%s returned error %s: %w", q, e, ErrErrorResponse)
+	}
+	return m.Value, nil
+}
+
+// Float64 returns the value of the query converted to a float64 (see Message.Float64)
+func (q Query) Float64(responses []Message) (float64, error) {
+	if _, err := q.value(responses); err != nil {
+		return 0, err
+	}
+	return q.Find(responses).Float64()
+}
+
+// Bool returns the boolean value of the query, fails if the value is not a boolean
+func (q Query) Bool(responses []Message) (bool, error) {
+	v, err := q.value(responses)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s of type %T: %w", q, v, ErrDataTypeValueMismatch)
+	}
+	return b, nil
+}
+
+// Text returns the string value of the query, fails if the value is not a string
+func (q Query) Text(responses []Message) (string, error) {
+	v, err := q.value(responses)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s of type %T: %w", q, v, ErrDataTypeValueMismatch)
+	}
+	return s, nil
+}
+
+// Messages returns the messages within the container value of the query, fails if the value is not a container
+func (q Query) Messages(responses []Message) ([]Message, error) {
+	v, err := q.value(responses)
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := v.([]Message)
+	if !ok {
+		return nil, fmt.Errorf("%s of type %T: %w", q, v, ErrDataTypeValueMismatch)
+	}
+	return ms, nil
+}
@@ -0,0 +1,107 @@
+package rscp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestParseQuery(t *testing.T) {
+	pm, _ := NamespaceByName("PM")
+	tests := []struct {
+		query   string
+		want    Query
+		wantErr error
+	}{
+		{"EMS_POWER_PV", Query{Tag: EMS_POWER_PV}, nil},
+		{"EMS_REQ_POWER_PV", Query{Tag: EMS_POWER_PV}, nil},
+		{"EMS_MAX_CHARGE_POWER", Query{Tag: EMS_MAX_CHARGE_POWER}, nil},
+		{"PM_ACTIVE_PHASES@1", Query{Tag: PM_ACTIVE_PHASES, Namespace: &pm, Index: 1}, nil},
+		{"UNKNOWN", Query{}, ErrValidTag},
+		{"PM_ACTIVE_PHASES@x", Query{}, ErrInvalidQuery},
+		{"EMS_POWER_PV@0", Query{}, ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ParseQuery(tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("ParseQuery() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestQueryRequests(t *testing.T) {
+	var queries []Query
+	for _, s := range []string{"PM_ACTIVE_PHASES@0", "EMS_POWER_PV", "PM_ENERGY_L1@0", "PM_ENERGY_L1@1", "EMS_POWER_PV",
+		"EMS_MAX_CHARGE_POWER", "EMS_MAX_DISCHARGE_POWER"} {
+		q, _ := ParseQuery(s)
+		queries = append(queries, q)
+	}
+	got, err := QueryRequests(queries...)
+	if err != nil {
+		t.Fatalf("QueryRequests() error = %v", err)
+	}
+	want := []Message{
+		{EMS_REQ_POWER_PV, None, nil},
+		{EMS_REQ_GET_POWER_SETTINGS, None, nil},
+		{PM_REQ_DATA, Container, []Message{
+			{PM_INDEX, UInt16, uint16(0)},
+			{PM_REQ_ACTIVE_PHASES, None, nil},
+			{PM_REQ_ENERGY_L1, None, nil},
+		}},
+		{PM_REQ_DATA, Container, []Message{
+			{PM_INDEX, UInt16, uint16(1)},
+			{PM_REQ_ENERGY_L1, None, nil},
+		}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("QueryRequests() = %v, want %v\n%s", got, want, diff)
+	}
+}
+
+func TestQuery_values(t *testing.T) {
+	responses := append([]Message{
+		{INFO_SERIAL_NUMBER, CString, "S10-1"},
+		{PVI_ON_GRID, Bool, true},
+		{EMS_STORED_ERRORS, Container, []Message{}},
+		{EMS_BAT_SOC, Error, ERR_NOT_AVAILABLE},
+	}, findTestMessages...)
+	query := func(s string) Query {
+		q, err := ParseQuery(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q
+	}
+	if v, err := query("PM_ENERGY_L1@1").Float64(responses); err != nil || v != 20 {
+		t.Errorf("Float64() = %v, %v, want 20", v, err)
+	}
+	if v, err := query("PVI_ON_GRID").Bool(responses); err != nil || !v {
+		t.Errorf("Bool() = %v, %v, want true", v, err)
+	}
+	if v, err := query("INFO_SERIAL_NUMBER").Text(responses); err != nil || v != "S10-1" {
+		t.Errorf("Text() = %v, %v, want S10-1", v, err)
+	}
+	if v, err := query("EMS_STORED_ERRORS").Messages(responses); err != nil || len(v) != 0 {
+		t.Errorf("Messages() = %v, %v, want empty", v, err)
+	}
+	for _, tt := range []struct {
+		query   string
+		err     error
+		compute func(q Query) error
+	}{
+		{"PM_ENERGY_L1@2", ErrMissingValue, func(q Query) error { _, err := q.Float64(responses); return err }},
+		{"EMS_BAT_SOC", ErrErrorResponse, func(q Query) error { _, err := q.Float64(responses); return err }},
+		{"INFO_SERIAL_NUMBER", ErrDataTypeValueMismatch, func(q Query) error { _, err := q.Bool(responses); return err }},
+		{"PVI_ON_GRID", ErrDataTypeValueMismatch, func(q Query) error { _, err := q.Text(responses); return err }},
+	} {
+		if err := tt.compute(query(tt.query)); !errors.Is(err, tt.err) {
+			t.Errorf("%s error = %v, want %v", tt.query, err, tt.err)
+		}
+	}
+}
@@ -126,6 +126,10 @@ func readMessage(buf *bytes.Reader) (*Message, error) {
 	}
 	// read data
 	v := m.DataType.newEmpty(l)
+	if v == nil {
+		// data type None has no data (i.e. requests)
+		return m, nil
+	}
 	if err := read(buf, v, l); err != nil {
 		return nil, fmt.Errorf("reading message %s: %w", m.Tag, err)
 	}
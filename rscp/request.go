@@ -8,6 +8,9 @@ import (
 // must contain a valid tag and data type and the data type must match the value
 func validateRequest(message Message) error {
 	if !message.Tag.isRequest() {
+		if r := message.Tag.RequestTag(); r.IsATag() {
+			return fmt.Errorf("%s (request it by %s): %w", message.Tag, r, ErrNotARequestTag)
+		}
 		return fmt.Errorf("%s: %w", message.Tag, ErrNotARequestTag)
 	}
 	return message.validate()
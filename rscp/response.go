@@ -0,0 +1,33 @@
+package rscp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewResponse creates a response message with the value converted to the data type of the tag (i.e. to answer requests).
+// A nil value results in the zero value of the data type.
+func NewResponse(tag Tag, value interface{}) (*Message, error) {
+	m := &Message{Tag: tag.ResponseTag(), DataType: tag.ResponseTag().DataType()}
+	switch {
+	case m.DataType == None:
+	case value == nil && m.DataType == Container:
+		m.Value = []Message{}
+	case value == nil:
+		v := m.DataType.newEmpty(0)
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+			v = rv.Elem().Interface()
+		}
+		m.Value = v
+	default:
+		v, err := m.DataType.new(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s value %v: %w", m.Tag, value, err)
+		}
+		m.Value = v
+	}
+	if err := m.validateResponse(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,34 @@
+package rscp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestNewResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     Tag
+		value   interface{}
+		want    *Message
+		wantErr bool
+	}{
+		{"converted", EMS_REQ_BAT_SOC, 50, &Message{EMS_BAT_SOC, UChar8, uint8(50)}, false},
+		{"zero value", EMS_POWER_PV, nil, &Message{EMS_POWER_PV, Int32, int32(0)}, false},
+		{"string", INFO_REQ_SERIAL_NUMBER, "S10-123", &Message{INFO_SERIAL_NUMBER, CString, "S10-123"}, false},
+		{"container", BAT_REQ_DATA, nil, &Message{BAT_DATA, Container, []Message{}}, false},
+		{"invalid", EMS_REQ_BAT_SOC, "full", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResponse(tt.tag, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("NewResponse() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
@@ -385,6 +385,9 @@ const (
 	//  1 - Trainingmodus Entladen
 	//  2 - Trainingmodus Laden
 	BAT_TRAINING_MODE Tag = 0x03800021
+	// Dieser Container beinhaltet die Antwort auf ein REQ_DCB_INFO der DCB mit dem Index BAT_DCB_INDEX
+	// und die Werte BAT_DCB_* der DCB.
+	BAT_DCB_INFO Tag = 0x03800042
 	// Kann nur innerhalb eines REQ_BAT_DATA Container verwendet werden!
 	BAT_REQ_RSOC Tag = 0x03000001
 	// Kann nur innerhalb eines REQ_BAT_DATA Container verwendet werden!
@@ -420,7 +423,9 @@ const (
 	// Kann nur innerhalb eines REQ_BAT_DATA Container verwendet werden!
 	BAT_REQ_INFO Tag = 0x03000020
 	// Kann nur innerhalb eines REQ_BAT_DATA Container verwendet werden!
-	BAT_REQ_TRAINING_MODE           Tag = 0x03000021
+	BAT_REQ_TRAINING_MODE Tag = 0x03000021
+	// Kann nur innerhalb eines REQ_BAT_DATA Container verwendet werden! Der Wert ist der Index der DCB (0 bis BAT_DCB_COUNT-1).
+	BAT_REQ_DCB_INFO                Tag = 0x03000042
 	BAT_DCB_INDEX                   Tag = 0x03800100
 	BAT_DCB_LAST_MESSAGE_TIMESTAMP  Tag = 0x03800101
 	BAT_DCB_MAX_CHARGE_VOLTAGE      Tag = 0x03800102
@@ -0,0 +1,52 @@
+package rscp
+
+// installerTags are the request tags changing the installation, they require at least AUTH_LEVEL_INSTALLER.
+// All other requests are available to AUTH_LEVEL_USER.
+var installerTags = []Tag{
+	EMS_REQ_SET_BALANCED_PHASES,
+	EMS_REQ_SET_INSTALLED_PEAK_POWER,
+	EMS_REQ_SET_DERATE_PERCENT,
+	EMS_REQ_START_ADJUST_BATTERY_VOLTAGE,
+	EMS_REQ_CANCEL_ADJUST_BATTERY_VOLTAGE,
+	EMS_REQ_SET_POWER_CONTROL_OFFSET,
+	EMS_REQ_SET_OVERRIDE_AVAILABLE_POWER,
+	EMS_REQ_START_EMERGENCYPOWER_TEST,
+	EMS_REQ_SET_GENERATOR_MODE,
+	PVI_REQ_SET_COS_PHI,
+	DCDC_REQ_FLASH,
+	PM_REQ_SET_PHASE_ELIMINATION,
+	SRV_REQ_ADD_USER,
+	INFO_REQ_SET_IP_ADDRESS,
+	INFO_REQ_SET_SUBNET_MASK,
+	INFO_REQ_SET_DHCP_STATUS,
+	INFO_REQ_SET_GATEWAY,
+	INFO_REQ_SET_DNS,
+	SYS_REQ_SYSTEM_REBOOT,
+	SYS_REQ_RESTART_APPLICATION,
+}
+
+// MinAuthLevel returns the auth level required to send the request tag
+func (t Tag) MinAuthLevel() AuthLevel {
+	for _, v := range installerTags {
+		if v == t {
+			return AUTH_LEVEL_INSTALLER
+		}
+	}
+	return AUTH_LEVEL_USER
+}
+
+// RequestsMinAuthLevel returns the auth level required to send the messages and their nested messages
+func RequestsMinAuthLevel(messages []Message) AuthLevel {
+	l := AUTH_LEVEL_USER
+	for _, m := range messages {
+		if ml := m.Tag.MinAuthLevel(); ml > l {
+			l = ml
+		}
+		if nested, ok := m.Value.([]Message); ok {
+			if ml := RequestsMinAuthLevel(nested); ml > l {
+				l = ml
+			}
+		}
+	}
+	return l
+}
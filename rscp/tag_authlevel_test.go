@@ -0,0 +1,26 @@
+package rscp
+
+import "testing"
+
+func TestRequestsMinAuthLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		want     AuthLevel
+	}{
+		{"getter", []Message{{EMS_REQ_POWER_PV, None, nil}}, AUTH_LEVEL_USER},
+		{"user setter", []Message{{EMS_REQ_SET_POWER, Container, []Message{}}}, AUTH_LEVEL_USER},
+		{"installer setter", []Message{{EMS_REQ_POWER_PV, None, nil}, {EMS_REQ_SET_DERATE_PERCENT, Float32, float32(70)}}, AUTH_LEVEL_INSTALLER},
+		{"nested installer setter",
+			[]Message{{PVI_REQ_DATA, Container, []Message{{PVI_INDEX, UInt16, uint16(0)}, {PVI_REQ_SET_COS_PHI, Container, []Message{}}}}},
+			AUTH_LEVEL_INSTALLER,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequestsMinAuthLevel(tt.messages); got != tt.want {
+				t.Errorf("RequestsMinAuthLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -289,6 +289,7 @@ var dataTypeMap = map[Tag]DataType{
 	BAT_REQ_READY_FOR_SHUTDOWN:               None,
 	BAT_REQ_INFO:                             None,
 	BAT_REQ_TRAINING_MODE:                    None,
+	BAT_REQ_DCB_INFO:                         UInt16,
 	BAT_REQ_DATA:                             Container,
 	BAT_INDEX:                                UInt16,
 	BAT_REQ_DEVICE_STATE:                     None,
@@ -312,6 +313,7 @@ var dataTypeMap = map[Tag]DataType{
 	BAT_READY_FOR_SHUTDOWN:                   Bool,
 	BAT_INFO:                                 Container,
 	BAT_TRAINING_MODE:                        UChar8,
+	BAT_DCB_INFO:                             Container,
 	BAT_DCB_INDEX:                            UInt16,
 	BAT_DCB_LAST_MESSAGE_TIMESTAMP:           Uint64,
 	BAT_DCB_MAX_CHARGE_VOLTAGE:               Float32,
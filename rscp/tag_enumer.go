@@ -8,7 +8,7 @@ import (
 	"fmt"
 )
 
-const _TagName = "RSCP_REQ_AUTHENTICATIONRSCP_AUTHENTICATION_USERRSCP_AUTHENTICATION_PASSWORDRSCP_REQ_USER_LEVELRSCP_REQ_SET_ENCRYPTION_PASSPHRASERSCP_AUTHENTICATIONRSCP_USER_LEVELRSCP_SET_ENCRYPTION_PASSPHRASERSCP_GENERAL_ERROREMS_REQ_POWER_PVEMS_REQ_POWER_BATEMS_REQ_POWER_HOMEEMS_REQ_POWER_GRIDEMS_REQ_POWER_ADDEMS_REQ_AUTARKYEMS_REQ_SELF_CONSUMPTIONEMS_REQ_BAT_SOCEMS_REQ_COUPLING_MODEEMS_REQ_STORED_ERRORSEMS_REQ_MODEEMS_REQ_BALANCED_PHASESEMS_REQ_INSTALLED_PEAK_POWEREMS_REQ_DERATE_AT_PERCENT_VALUEEMS_REQ_DERATE_AT_POWER_VALUEEMS_REQ_ERROR_BUZZER_ENABLEDEMS_REQ_SET_BALANCED_PHASESEMS_REQ_SET_INSTALLED_PEAK_POWEREMS_REQ_SET_DERATE_PERCENTEMS_REQ_SET_ERROR_BUZZER_ENABLEDEMS_REQ_START_ADJUST_BATTERY_VOLTAGEEMS_REQ_CANCEL_ADJUST_BATTERY_VOLTAGEEMS_REQ_ADJUST_BATTERY_VOLTAGE_STATUSEMS_REQ_CONFIRM_ERRORSEMS_REQ_POWER_WB_ALLEMS_REQ_POWER_WB_SOLAREMS_REQ_EXT_SRC_AVAILABLEEMS_REQ_SET_POWEREMS_REQ_SET_POWER_MODEEMS_REQ_SET_POWER_VALUEEMS_REQ_STATUSEMS_REQ_USED_CHARGE_LIMITEMS_REQ_BAT_CHARGE_LIMITEMS_REQ_DCDC_CHARGE_LIMITEMS_REQ_USER_CHARGE_LIMITEMS_REQ_USED_DISCHARGE_LIMITEMS_REQ_BAT_DISCHARGE_LIMITEMS_REQ_DCDC_DISCHARGE_LIMITEMS_REQ_USER_DISCHARGE_LIMITEMS_REQ_SET_POWER_CONTROL_OFFSETEMS_REQ_REMAINING_BAT_CHARGE_POWEREMS_REQ_REMAINING_BAT_DISCHARGE_POWEREMS_REQ_EMERGENCY_POWER_STATUSEMS_REQ_SET_EMERGENCY_POWEREMS_REQ_SET_OVERRIDE_AVAILABLE_POWEREMS_REQ_SET_BATTERY_TO_CAR_MODEEMS_REQ_BATTERY_TO_CAR_MODEEMS_REQ_SET_BATTERY_BEFORE_CAR_MODEEMS_REQ_BATTERY_BEFORE_CAR_MODEEMS_REQ_GET_IDLE_PERIODSEMS_REQ_SET_IDLE_PERIODSEMS_IDLE_PERIODEMS_IDLE_PERIOD_TYPEEMS_IDLE_PERIOD_DAYEMS_IDLE_PERIOD_STARTEMS_IDLE_PERIOD_ENDEMS_IDLE_PERIOD_HOUREMS_IDLE_PERIOD_MINUTEEMS_IDLE_PERIOD_ACTIVEEMS_REQ_IDLE_PERIOD_CHANGE_MARKEREMS_REQ_GET_POWER_SETTINGSEMS_REQ_SET_POWER_SETTINGSEMS_REQ_SETTINGS_CHANGE_MARKEREMS_REQ_GET_MANUAL_CHARGEEMS_REQ_START_MANUAL_CHARGEEMS_REQ_START_EMERGENCYPOWER_TESTEMS_REQ_GET_GENERATOR_STATEEMS_REQ_SET_GENERATOR_MODEEMS_REQ_EMERGENCYPOWER_TEST_STATUSEMS_EPTEST_NEXT_TESTSTARTEMS_EPTEST_START_COUNTEREMS_EPTEST_RUNNINGEMS_REQ_GET_SYS_SPECSEMS_REQ_SYS_STATUSEMS_SYS_SPECEMS_SYS_SPEC_INDEXEMS_SYS_SPEC_NAMEEMS_SYS_SPEC_VALUE_INTEMS_SYS_SPEC_VALUE_STRINGEMS_SYS_STATUSEMS_POWER_LIMITS_USEDEMS_MAX_CHARGE_POWEREMS_MAX_DISCHARGE_POWEREMS_DISCHARGE_START_POWEREMS_POWERSAVE_ENABLEDEMS_WEATHER_REGULATED_CHARGE_ENABLEDEMS_WEATHER_FORECAST_MODEEMS_MANUAL_CHARGE_START_COUNTEREMS_MANUAL_CHARGE_ACTIVEEMS_MANUAL_CHARGE_ENERGY_COUNTEREMS_MANUAL_CHARGE_LASTSTARTEMS_REQ_ALIVEEMS_POWER_PVEMS_POWER_BATEMS_POWER_HOMEEMS_POWER_GRIDEMS_POWER_ADDEMS_AUTARKYEMS_SELF_CONSUMPTIONEMS_BAT_SOCEMS_COUPLING_MODEEMS_STORED_ERRORSEMS_ERROR_CONTAINEREMS_ERROR_TYPEEMS_ERROR_SOURCEEMS_ERROR_MESSAGEEMS_ERROR_CODEEMS_ERROR_TIMESTAMPEMS_MODEEMS_BALANCED_PHASESEMS_INSTALLED_PEAK_POWEREMS_DERATE_AT_PERCENT_VALUEEMS_DERATE_AT_POWER_VALUEEMS_ERROR_BUZZER_ENABLEDEMS_SET_BALANCED_PHASESEMS_SET_INSTALLED_PEAK_POWEREMS_SET_DERATE_PERCENTEMS_SET_ERROR_BUZZER_ENABLEDEMS_START_ADJUST_BATTERY_VOLTAGEEMS_CANCEL_ADJUST_BATTERY_VOLTAGEEMS_ADJUST_BATTERY_VOLTAGE_STATUSEMS_CONFIRM_ERRORSEMS_POWER_WB_ALLEMS_POWER_WB_SOLAREMS_EXT_SRC_AVAILABLEEMS_SET_POWEREMS_STATUSEMS_USED_CHARGE_LIMITEMS_BAT_CHARGE_LIMITEMS_DCDC_CHARGE_LIMITEMS_USER_CHARGE_LIMITEMS_USED_DISCHARGE_LIMITEMS_BAT_DISCHARGE_LIMITEMS_DCDC_DISCHARGE_LIMITEMS_USER_DISCHARGE_LIMITEMS_SET_POWER_CONTROL_OFFSETEMS_REMAINING_BAT_CHARGE_POWEREMS_REMAINING_BAT_DISCHARGE_POWEREMS_EMERGENCY_POWER_STATUSEMS_SET_EMERGENCY_POWEREMS_SET_OVERRIDE_AVAILABLE_POWEREMS_SET_BATTERY_TO_CAR_MODEEMS_BATTERY_TO_CAR_MODEEMS_SET_BATTERY_BEFORE_CAR_MODEEMS_BATTERY_BEFORE_CAR_MODEEMS_GET_IDLE_PERIODSEMS_SET_IDLE_PERIODSEMS_IDLE_PERIOD_CHANGE_MARKEREMS_GET_POWER_SETTINGSEMS_SET_POWER_SETTINGSEMS_SETTINGS_CHANGE_MARKEREMS_GET_MANUAL_CHARGEEMS_START_MANUAL_CHARGEEMS_START_EMERGENCYPOWER_TESTEMS_GET_GENERATOR_STATEEMS_SET_GENERATOR_MODEEMS_EMERGENCYPOWER_TEST_STATUSEMS_GET_SYS_SPECSEMS_RES_POWER_LIMITS_USEDEMS_RES_MAX_CHARGE_POWEREMS_RES_MAX_DISCHARGE_POWEREMS_RES_DISCHARGE_START_POWEREMS_RES_POWERSAVE_ENABLEDEMS_RES_WEATHER_REGULATED_CHARGE_ENABLEDEMS_RES_WEATHER_FORECAST_MODEEMS_ALIVEEMS_GENERAL_ERRORPVI_REQ_ON_GRIDPVI_REQ_STATEPVI_REQ_LAST_ERRORPVI_REQ_TYPEPVI_REQ_COS_PHIPVI_REQ_SET_COS_PHIPVI_COS_PHI_VALUEPVI_COS_PHI_IS_AKTIVPVI_COS_PHI_EXCITEDPVI_REQ_VOLTAGE_MONITORINGPVI_VOLTAGE_MONITORING_THRESHOLD_TOPPVI_VOLTAGE_MONITORING_THRESHOLD_BOTTOMPVI_VOLTAGE_MONITORING_SLOPE_UPPVI_VOLTAGE_MONITORING_SLOPE_DOWNPVI_REQ_FREQUENCY_UNDER_OVERPVI_FREQUENCY_UNDERPVI_FREQUENCY_OVERPVI_REQ_SYSTEM_MODEPVI_REQ_POWER_MODEPVI_REQ_TEMPERATUREPVI_REQ_TEMPERATURE_COUNTPVI_REQ_MAX_TEMPERATUREPVI_REQ_MIN_TEMPERATUREPVI_REQ_DATAPVI_INDEXPVI_VALUEPVI_REQ_DEVICE_STATEPVI_REQ_SERIAL_NUMBERPVI_REQ_VERSIONPVI_VERSION_MAINPVI_VERSION_PICPVI_REQ_AC_MAX_PHASE_COUNTPVI_REQ_AC_POWERPVI_REQ_AC_VOLTAGEPVI_REQ_AC_CURRENTPVI_REQ_AC_APPARENTPOWERPVI_REQ_AC_REACTIVEPOWERPVI_REQ_AC_ENERGY_ALLPVI_REQ_AC_MAX_APPARENTPOWERPVI_REQ_AC_ENERGY_DAYPVI_REQ_AC_ENERGY_GRID_CONSUMPTIONPVI_REQ_DC_MAX_STRING_COUNTPVI_REQ_DC_POWERPVI_REQ_DC_VOLTAGEPVI_REQ_DC_CURRENTPVI_REQ_DC_MAX_POWERPVI_REQ_DC_MAX_VOLTAGEPVI_REQ_DC_MIN_VOLTAGEPVI_REQ_DC_MAX_CURRENTPVI_REQ_DC_MIN_CURRENTPVI_REQ_DC_STRING_ENERGY_ALLPVI_ON_GRIDPVI_STATEPVI_LAST_ERRORPVI_FLASH_FILEPVI_TYPEPVI_COS_PHIPVI_VOLTAGE_MONITORINGPVI_FREQUENCY_UNDER_OVERPVI_SYSTEM_MODEPVI_POWER_MODEPVI_TEMPERATUREPVI_TEMPERATURE_COUNTPVI_MAX_TEMPERATUREPVI_MIN_TEMPERATUREPVI_DATAPVI_DEVICE_STATEPVI_DEVICE_CONNECTEDPVI_DEVICE_WORKINGPVI_DEVICE_IN_SERVICEPVI_SERIAL_NUMBERPVI_VERSIONPVI_AC_MAX_PHASE_COUNTPVI_AC_POWERPVI_AC_VOLTAGEPVI_AC_CURRENTPVI_AC_APPARENTPOWERPVI_AC_REACTIVEPOWERPVI_AC_ENERGY_ALLPVI_AC_MAX_APPARENTPOWERPVI_AC_ENERGY_DAYPVI_AC_ENERGY_GRID_CONSUMPTIONPVI_DC_MAX_STRING_COUNTPVI_DC_POWERPVI_DC_VOLTAGEPVI_DC_CURRENTPVI_DC_MAX_POWERPVI_DC_MAX_VOLTAGEPVI_DC_MIN_VOLTAGEPVI_DC_MAX_CURRENTPVI_DC_MIN_CURRENTPVI_DC_STRING_ENERGY_ALLPVI_GENERAL_ERRORBAT_REQ_RSOCBAT_REQ_MODULE_VOLTAGEBAT_REQ_CURRENTBAT_REQ_MAX_BAT_VOLTAGEBAT_REQ_MAX_CHARGE_CURRENTBAT_REQ_EOD_VOLTAGEBAT_REQ_MAX_DISCHARGE_CURRENTBAT_REQ_CHARGE_CYCLESBAT_REQ_TERMINAL_VOLTAGEBAT_REQ_STATUS_CODEBAT_REQ_ERROR_CODEBAT_REQ_DEVICE_NAMEBAT_REQ_DCB_COUNTBAT_REQ_MAX_DCB_CELL_TEMPERATUREBAT_REQ_MIN_DCB_CELL_TEMPERATUREBAT_REQ_READY_FOR_SHUTDOWNBAT_REQ_INFOBAT_REQ_TRAINING_MODEBAT_REQ_DATABAT_INDEXBAT_REQ_DEVICE_STATEBAT_RSOCBAT_MODULE_VOLTAGEBAT_CURRENTBAT_MAX_BAT_VOLTAGEBAT_MAX_CHARGE_CURRENTBAT_EOD_VOLTAGEBAT_MAX_DISCHARGE_CURRENTBAT_CHARGE_CYCLESBAT_TERMINAL_VOLTAGEBAT_STATUS_CODEBAT_ERROR_CODEBAT_DEVICE_NAMEBAT_DCB_COUNTBAT_MAX_DCB_CELL_TEMPERATUREBAT_MIN_DCB_CELL_TEMPERATUREBAT_DCB_CELL_TEMPERATUREBAT_DCB_CELL_VOLTAGEBAT_READY_FOR_SHUTDOWNBAT_INFOBAT_TRAINING_MODEBAT_DCB_INDEXBAT_DCB_LAST_MESSAGE_TIMESTAMPBAT_DCB_MAX_CHARGE_VOLTAGEBAT_DCB_MAX_CHARGE_CURRENTBAT_DCB_END_OF_DISCHARGEBAT_DCB_MAX_DISCHARGE_CURRENTBAT_DCB_FULL_CHARGE_CAPACITYBAT_DCB_REMAINING_CAPACITYBAT_DCB_SOCBAT_DCB_SOHBAT_DCB_CYCLE_COUNTBAT_DCB_CURRENTBAT_DCB_VOLTAGEBAT_DCB_CURRENT_AVG_30SBAT_DCB_VOLTAGE_AVG_30SBAT_DCB_DESIGN_CAPACITYBAT_DCB_DESIGN_VOLTAGEBAT_DCB_CHARGE_LOW_TEMPERATUREBAT_DCB_CHARGE_HIGH_TEMPERATUREBAT_DCB_MANUFACTURE_DATEBAT_DCB_SERIALNOBAT_DCB_PROTOCOL_VERSIONBAT_DCB_FW_VERSIONBAT_DCB_DATA_TABLE_VERSIONBAT_DCB_PCB_VERSIONBAT_DATABAT_DEVICE_STATEBAT_DEVICE_CONNECTEDBAT_DEVICE_WORKINGBAT_DEVICE_IN_SERVICEBAT_GENERAL_ERRORDCDC_REQ_I_BATDCDC_REQ_U_BATDCDC_REQ_P_BATDCDC_REQ_I_DCLDCDC_REQ_U_DCLDCDC_REQ_P_DCLDCDC_REQ_FIRMWARE_VERSIONDCDC_REQ_FPGA_FIRMWAREDCDC_REQ_SERIAL_NUMBERDCDC_REQ_BOARD_VERSIONDCDC_REQ_FLASH_FILE_LISTDCDC_REQ_IS_FLASHINGDCDC_REQ_FLASHDCDC_REQ_STATUSDCDC_REQ_STATUS_AS_STRINGDCDC_REQ_DATADCDC_INDEXDCDC_REQ_DEVICE_STATEDCDC_I_BATDCDC_U_BATDCDC_P_BATDCDC_I_DCLDCDC_U_DCLDCDC_P_DCLDCDC_FIRMWARE_VERSIONDCDC_FPGA_FIRMWAREDCDC_SERIAL_NUMBERDCDC_BOARD_VERSIONDCDC_FLASH_FILE_LISTDCDC_FLASH_FILEDCDC_IS_FLASHINGDCDC_FLASHDCDC_STATUSDCDC_STATEDCDC_SUBSTATEDCDC_STATUS_AS_STRINGDCDC_STATE_AS_STRINGDCDC_SUBSTATE_AS_STRINGDCDC_DATADCDC_DEVICE_STATEDCDC_DEVICE_CONNECTEDDCDC_DEVICE_WORKINGDCDC_DEVICE_IN_SERVICEDCDC_GENERAL_ERRORPM_REQ_POWER_L1PM_REQ_POWER_L2PM_REQ_POWER_L3PM_REQ_ACTIVE_PHASESPM_REQ_MODEPM_REQ_ENERGY_L1PM_REQ_ENERGY_L2PM_REQ_ENERGY_L3PM_REQ_DEVICE_IDPM_REQ_ERROR_CODEPM_REQ_SET_PHASE_ELIMINATIONPM_REQ_FIRMWARE_VERSIONPM_REQ_VOLTAGE_L1PM_REQ_VOLTAGE_L2PM_REQ_VOLTAGE_L3PM_REQ_TYPEPM_REQ_GET_PHASE_ELIMINATIONPM_REQ_DATAPM_INDEXPM_REQ_DEVICE_STATEPM_POWER_L1PM_POWER_L2PM_POWER_L3PM_ACTIVE_PHASESPM_MODEPM_ENERGY_L1PM_ENERGY_L2PM_ENERGY_L3PM_DEVICE_IDPM_ERROR_CODEPM_SET_PHASE_ELIMINATIONPM_FIRMWARE_VERSIONPM_VOLTAGE_L1PM_VOLTAGE_L2PM_VOLTAGE_L3PM_TYPEPM_GET_PHASE_ELIMINATIONPM_CS_START_TIMEPM_CS_LAST_TIMEPM_CS_SUCC_FRAMES_ALLPM_CS_SUCC_FRAMES_100PM_CS_EXP_FRAMES_ALLPM_CS_EXP_FRAMES_100PM_CS_ERR_FRAMES_ALLPM_CS_ERR_FRAMES_100PM_CS_UNK_FRAMESPM_CS_ERR_FRAMEPM_DATAPM_DEVICE_STATEPM_DEVICE_CONNECTEDPM_DEVICE_WORKINGPM_DEVICE_IN_SERVICEPM_GENERAL_ERRORDB_REQ_HISTORY_DATA_DAYDB_REQ_HISTORY_TIME_STARTDB_REQ_HISTORY_TIME_INTERVALDB_REQ_HISTORY_TIME_SPANDB_REQ_HISTORY_DATA_WEEKDB_REQ_HISTORY_DATA_MONTHDB_REQ_HISTORY_DATA_YEARDB_GRAPH_INDEXDB_BAT_POWER_INDB_BAT_POWER_OUTDB_DC_POWERDB_GRID_POWER_INDB_GRID_POWER_OUTDB_CONSUMPTIONDB_PM_0_POWERDB_PM_1_POWERDB_BAT_CHARGE_LEVELDB_BAT_CYCLE_COUNTDB_CONSUMED_PRODUCTIONDB_AUTARKYDB_SUM_CONTAINERDB_VALUE_CONTAINERDB_HISTORY_DATA_DAYDB_HISTORY_DATA_WEEKDB_HISTORY_DATA_MONTHDB_HISTORY_DATA_YEARDB_PAR_TIME_MINDB_PAR_TIME_MAXDB_PARAM_ROWDB_PARAM_COLUMNDB_PARAM_INDEXDB_PARAM_VALUEDB_PARAM_MAX_ROWSDB_PARAM_TIMEDB_PARAM_VERSIONDB_PARAM_HEADERSRV_REQ_IS_ONLINESRV_REQ_ADD_USERSRV_IS_ONLINESRV_ADD_USERSRV_GENERAL_ERRORHA_REQ_DATAPOINT_LISTHA_REQ_ACTUATOR_STATESHA_REQ_ADD_ACTUATORHA_REQ_REMOVE_ACTUATORHA_REQ_COMMAND_ACTUATORHA_REQ_COMMANDHA_REQ_DESCRIPTIONS_CHANGEHA_REQ_CONFIGURATION_CHANGE_COUNTERHA_REQ_DEVICE_STATEHA_DATAPOINT_LISTHA_DATAPOINTHA_DATAPOINT_INDEXHA_DATAPOINT_TYPEHA_DATAPOINT_NAMEHA_DATAPOINT_DESCRIPTIONSHA_DATAPOINT_DESCRIPTIONHA_DATAPOINT_DESCRIPTION_NAMEHA_DATAPOINT_DESCRIPTION_VALUEHA_ACTUATOR_STATESHA_DATAPOINT_STATEHA_DATAPOINT_MODEHA_DATAPOINT_STATE_TIMESTAMPHA_DATAPOINT_STATE_VALUEHA_DATAPOINT_SUPPLY_QUALITYHA_DATAPOINT_SIGNAL_QUALITYHA_ADD_ACTUATORHA_REMOVE_ACTUATORHA_COMMAND_ACTUATORHA_DESCRIPTIONS_CHANGEHA_CONFIGURATION_CHANGE_COUNTERHA_DEVICE_STATEHA_DEVICE_CONNECTEDHA_DEVICE_WORKINGHA_DEVICE_IN_SERVICEHA_GENERAL_ERRORINFO_REQ_SERIAL_NUMBERINFO_REQ_PRODUCTION_DATEINFO_REQ_MODULES_SW_VERSIONSINFO_REQ_A35_SERIAL_NUMBERINFO_REQ_IP_ADDRESSINFO_REQ_SUBNET_MASKINFO_REQ_MAC_ADDRESSINFO_REQ_GATEWAYINFO_REQ_DNSINFO_REQ_DHCP_STATUSINFO_REQ_TIMEINFO_REQ_UTC_TIMEINFO_REQ_TIME_ZONEINFO_REQ_INFOINFO_REQ_SET_IP_ADDRESSINFO_REQ_SET_SUBNET_MASKINFO_REQ_SET_DHCP_STATUSINFO_REQ_SET_GATEWAYINFO_REQ_SET_DNSINFO_REQ_SET_TIME_ZONEINFO_REQ_SW_RELEASEINFO_SERIAL_NUMBERINFO_PRODUCTION_DATEINFO_MODULES_SW_VERSIONSINFO_MODULE_SW_VERSIONINFO_MODULEINFO_VERSIONINFO_A35_SERIAL_NUMBERINFO_IP_ADDRESSINFO_SUBNET_MASKINFO_MAC_ADDRESSINFO_GATEWAYINFO_DNSINFO_DHCP_STATUSINFO_TIMEINFO_UTC_TIMEINFO_TIME_ZONEINFO_INFOINFO_SET_IP_ADDRESSINFO_SET_SUBNET_MASKINFO_SET_DHCP_STATUSINFO_SET_GATEWAYINFO_SET_DNSINFO_SET_TIMEINFO_SET_TIME_ZONEINFO_SW_RELEASEINFO_GENERAL_ERROREP_REQ_IS_READY_FOR_SWITCHEP_REQ_IS_GRID_CONNECTEDEP_REQ_IS_ISLAND_GRIDEP_REQ_IS_INVALID_STATEEP_REQ_IS_POSSIBLEEP_IS_READY_FOR_SWITCHEP_IS_GRID_CONNECTEDEP_IS_ISLAND_GRIDEP_IS_INVALID_STATEEP_IS_POSSIBLEEP_GENERAL_ERRORSYS_REQ_SYSTEM_REBOOTSYS_REQ_IS_SYSTEM_REBOOTINGSYS_REQ_RESTART_APPLICATIONSYS_SYSTEM_REBOOTSYS_IS_SYSTEM_REBOOTINGSYS_RESTART_APPLICATIONSYS_SCRIPT_FILESYS_GENERAL_ERRORUM_REQ_UPDATE_STATUSUM_REQ_CHECK_FOR_UPDATESUM_UPDATE_STATUSUM_CHECK_FOR_UPDATESUM_GENERAL_ERRORWB_REQ_ENERGY_ALLWB_REQ_ENERGY_SOLARWB_REQ_SOCWB_REQ_STATUSWB_REQ_ERROR_CODEWB_REQ_MODEWB_REQ_APP_SOFTWAREWB_REQ_BOOTLOADER_SOFTWAREWB_REQ_HW_VERSIONWB_REQ_FLASH_VERSIONWB_REQ_DEVICE_IDWB_REQ_PM_POWER_L1WB_REQ_PM_POWER_L2WB_REQ_PM_POWER_L3WB_REQ_PM_ACTIVE_PHASESWB_REQ_PM_MODEWB_REQ_PM_ENERGY_L1WB_REQ_PM_ENERGY_L2WB_REQ_PM_ENERGY_L3WB_REQ_PM_DEVICE_IDWB_REQ_PM_ERROR_CODEWB_REQ_PM_FIRMWARE_VERSIONWB_REQ_DIAG_INFOSWB_REQ_DIAG_WARNINGSWB_REQ_DIAG_ERRORSWB_REQ_DIAG_TEMP_1WB_REQ_DIAG_TEMP_2WB_REQ_PM_DEVICE_STATEWB_REQ_SET_MODEWB_SET_MODEWB_REQ_DATAWB_INDEXWB_MODE_PARAM_MODEWB_MODE_PARAM_MAX_CURRENTWB_REQ_AVAILABLE_SOLAR_POWERWB_POWERWB_STATUS_BITWB_REQ_SET_EXTERNWB_REQ_EXTERN_DATA_SUNWB_REQ_EXTERN_DATA_NETWB_REQ_EXTERN_DATA_ALLWB_REQ_EXTERN_DATA_ALGWB_REQ_SET_BAT_CAPACITYWB_REQ_SET_PARAM_1WB_REQ_SET_PARAM_2WB_REQ_PARAM_2WB_REQ_PARAM_1WB_EXTERN_DATAWB_EXTERN_DATA_LENWB_REQ_DEVICE_STATEWB_ENERGY_ALLWB_ENERGY_SOLARWB_SOCWB_STATUSWB_ERROR_CODEWB_MODEWB_APP_SOFTWAREWB_BOOTLOADER_SOFTWAREWB_HW_VERSIONWB_FLASH_VERSIONWB_DEVICE_IDWB_PM_POWER_L1WB_PM_POWER_L2WB_PM_POWER_L3WB_PM_ACTIVE_PHASESWB_PM_MODEWB_PM_ENERGY_L1WB_PM_ENERGY_L2WB_PM_ENERGY_L3WB_PM_DEVICE_IDWB_PM_ERROR_CODEWB_PM_FIRMWARE_VERSIONWB_DIAG_INFOSWB_DIAG_WARNINGSWB_DIAG_ERRORSWB_DIAG_TEMP_1WB_DIAG_TEMP_2WB_PM_DEVICE_STATEWB_PM_DEVICE_STATE_CONNECTEDWB_PM_DEVICE_STATE_WORKINGWB_PM_DEVICE_STATE_IN_SERVICEWB_DATAWB_AVAILABLE_SOLAR_POWERWB_SET_EXTERNWB_EXTERN_DATA_SUNWB_EXTERN_DATA_NETWB_EXTERN_DATA_ALLWB_EXTERN_DATA_ALGWB_SET_BAT_CAPACITYWB_SET_PARAM_1WB_SET_PARAM_2WB_RSP_PARAM_2WB_RSP_PARAM_1WB_DEVICE_STATEWB_DEVICE_CONNECTEDWB_DEVICE_WORKINGWB_DEVICE_IN_SERVICEWB_GENERAL_ERROR"
+const _TagName = "RSCP_REQ_AUTHENTICATIONRSCP_AUTHENTICATION_USERRSCP_AUTHENTICATION_PASSWORDRSCP_REQ_USER_LEVELRSCP_REQ_SET_ENCRYPTION_PASSPHRASERSCP_AUTHENTICATIONRSCP_USER_LEVELRSCP_SET_ENCRYPTION_PASSPHRASERSCP_GENERAL_ERROREMS_REQ_POWER_PVEMS_REQ_POWER_BATEMS_REQ_POWER_HOMEEMS_REQ_POWER_GRIDEMS_REQ_POWER_ADDEMS_REQ_AUTARKYEMS_REQ_SELF_CONSUMPTIONEMS_REQ_BAT_SOCEMS_REQ_COUPLING_MODEEMS_REQ_STORED_ERRORSEMS_REQ_MODEEMS_REQ_BALANCED_PHASESEMS_REQ_INSTALLED_PEAK_POWEREMS_REQ_DERATE_AT_PERCENT_VALUEEMS_REQ_DERATE_AT_POWER_VALUEEMS_REQ_ERROR_BUZZER_ENABLEDEMS_REQ_SET_BALANCED_PHASESEMS_REQ_SET_INSTALLED_PEAK_POWEREMS_REQ_SET_DERATE_PERCENTEMS_REQ_SET_ERROR_BUZZER_ENABLEDEMS_REQ_START_ADJUST_BATTERY_VOLTAGEEMS_REQ_CANCEL_ADJUST_BATTERY_VOLTAGEEMS_REQ_ADJUST_BATTERY_VOLTAGE_STATUSEMS_REQ_CONFIRM_ERRORSEMS_REQ_POWER_WB_ALLEMS_REQ_POWER_WB_SOLAREMS_REQ_EXT_SRC_AVAILABLEEMS_REQ_SET_POWEREMS_REQ_SET_POWER_MODEEMS_REQ_SET_POWER_VALUEEMS_REQ_STATUSEMS_REQ_USED_CHARGE_LIMITEMS_REQ_BAT_CHARGE_LIMITEMS_REQ_DCDC_CHARGE_LIMITEMS_REQ_USER_CHARGE_LIMITEMS_REQ_USED_DISCHARGE_LIMITEMS_REQ_BAT_DISCHARGE_LIMITEMS_REQ_DCDC_DISCHARGE_LIMITEMS_REQ_USER_DISCHARGE_LIMITEMS_REQ_SET_POWER_CONTROL_OFFSETEMS_REQ_REMAINING_BAT_CHARGE_POWEREMS_REQ_REMAINING_BAT_DISCHARGE_POWEREMS_REQ_EMERGENCY_POWER_STATUSEMS_REQ_SET_EMERGENCY_POWEREMS_REQ_SET_OVERRIDE_AVAILABLE_POWEREMS_REQ_SET_BATTERY_TO_CAR_MODEEMS_REQ_BATTERY_TO_CAR_MODEEMS_REQ_SET_BATTERY_BEFORE_CAR_MODEEMS_REQ_BATTERY_BEFORE_CAR_MODEEMS_REQ_GET_IDLE_PERIODSEMS_REQ_SET_IDLE_PERIODSEMS_IDLE_PERIODEMS_IDLE_PERIOD_TYPEEMS_IDLE_PERIOD_DAYEMS_IDLE_PERIOD_STARTEMS_IDLE_PERIOD_ENDEMS_IDLE_PERIOD_HOUREMS_IDLE_PERIOD_MINUTEEMS_IDLE_PERIOD_ACTIVEEMS_REQ_IDLE_PERIOD_CHANGE_MARKEREMS_REQ_GET_POWER_SETTINGSEMS_REQ_SET_POWER_SETTINGSEMS_REQ_SETTINGS_CHANGE_MARKEREMS_REQ_GET_MANUAL_CHARGEEMS_REQ_START_MANUAL_CHARGEEMS_REQ_START_EMERGENCYPOWER_TESTEMS_REQ_GET_GENERATOR_STATEEMS_REQ_SET_GENERATOR_MODEEMS_REQ_EMERGENCYPOWER_TEST_STATUSEMS_EPTEST_NEXT_TESTSTARTEMS_EPTEST_START_COUNTEREMS_EPTEST_RUNNINGEMS_REQ_GET_SYS_SPECSEMS_REQ_SYS_STATUSEMS_SYS_SPECEMS_SYS_SPEC_INDEXEMS_SYS_SPEC_NAMEEMS_SYS_SPEC_VALUE_INTEMS_SYS_SPEC_VALUE_STRINGEMS_SYS_STATUSEMS_POWER_LIMITS_USEDEMS_MAX_CHARGE_POWEREMS_MAX_DISCHARGE_POWEREMS_DISCHARGE_START_POWEREMS_POWERSAVE_ENABLEDEMS_WEATHER_REGULATED_CHARGE_ENABLEDEMS_WEATHER_FORECAST_MODEEMS_MANUAL_CHARGE_START_COUNTEREMS_MANUAL_CHARGE_ACTIVEEMS_MANUAL_CHARGE_ENERGY_COUNTEREMS_MANUAL_CHARGE_LASTSTARTEMS_REQ_ALIVEEMS_POWER_PVEMS_POWER_BATEMS_POWER_HOMEEMS_POWER_GRIDEMS_POWER_ADDEMS_AUTARKYEMS_SELF_CONSUMPTIONEMS_BAT_SOCEMS_COUPLING_MODEEMS_STORED_ERRORSEMS_ERROR_CONTAINEREMS_ERROR_TYPEEMS_ERROR_SOURCEEMS_ERROR_MESSAGEEMS_ERROR_CODEEMS_ERROR_TIMESTAMPEMS_MODEEMS_BALANCED_PHASESEMS_INSTALLED_PEAK_POWEREMS_DERATE_AT_PERCENT_VALUEEMS_DERATE_AT_POWER_VALUEEMS_ERROR_BUZZER_ENABLEDEMS_SET_BALANCED_PHASESEMS_SET_INSTALLED_PEAK_POWEREMS_SET_DERATE_PERCENTEMS_SET_ERROR_BUZZER_ENABLEDEMS_START_ADJUST_BATTERY_VOLTAGEEMS_CANCEL_ADJUST_BATTERY_VOLTAGEEMS_ADJUST_BATTERY_VOLTAGE_STATUSEMS_CONFIRM_ERRORSEMS_POWER_WB_ALLEMS_POWER_WB_SOLAREMS_EXT_SRC_AVAILABLEEMS_SET_POWEREMS_STATUSEMS_USED_CHARGE_LIMITEMS_BAT_CHARGE_LIMITEMS_DCDC_CHARGE_LIMITEMS_USER_CHARGE_LIMITEMS_USED_DISCHARGE_LIMITEMS_BAT_DISCHARGE_LIMITEMS_DCDC_DISCHARGE_LIMITEMS_USER_DISCHARGE_LIMITEMS_SET_POWER_CONTROL_OFFSETEMS_REMAINING_BAT_CHARGE_POWEREMS_REMAINING_BAT_DISCHARGE_POWEREMS_EMERGENCY_POWER_STATUSEMS_SET_EMERGENCY_POWEREMS_SET_OVERRIDE_AVAILABLE_POWEREMS_SET_BATTERY_TO_CAR_MODEEMS_BATTERY_TO_CAR_MODEEMS_SET_BATTERY_BEFORE_CAR_MODEEMS_BATTERY_BEFORE_CAR_MODEEMS_GET_IDLE_PERIODSEMS_SET_IDLE_PERIODSEMS_IDLE_PERIOD_CHANGE_MARKEREMS_GET_POWER_SETTINGSEMS_SET_POWER_SETTINGSEMS_SETTINGS_CHANGE_MARKEREMS_GET_MANUAL_CHARGEEMS_START_MANUAL_CHARGEEMS_START_EMERGENCYPOWER_TESTEMS_GET_GENERATOR_STATEEMS_SET_GENERATOR_MODEEMS_EMERGENCYPOWER_TEST_STATUSEMS_GET_SYS_SPECSEMS_RES_POWER_LIMITS_USEDEMS_RES_MAX_CHARGE_POWEREMS_RES_MAX_DISCHARGE_POWEREMS_RES_DISCHARGE_START_POWEREMS_RES_POWERSAVE_ENABLEDEMS_RES_WEATHER_REGULATED_CHARGE_ENABLEDEMS_RES_WEATHER_FORECAST_MODEEMS_ALIVEEMS_GENERAL_ERRORPVI_REQ_ON_GRIDPVI_REQ_STATEPVI_REQ_LAST_ERRORPVI_REQ_TYPEPVI_REQ_COS_PHIPVI_REQ_SET_COS_PHIPVI_COS_PHI_VALUEPVI_COS_PHI_IS_AKTIVPVI_COS_PHI_EXCITEDPVI_REQ_VOLTAGE_MONITORINGPVI_VOLTAGE_MONITORING_THRESHOLD_TOPPVI_VOLTAGE_MONITORING_THRESHOLD_BOTTOMPVI_VOLTAGE_MONITORING_SLOPE_UPPVI_VOLTAGE_MONITORING_SLOPE_DOWNPVI_REQ_FREQUENCY_UNDER_OVERPVI_FREQUENCY_UNDERPVI_FREQUENCY_OVERPVI_REQ_SYSTEM_MODEPVI_REQ_POWER_MODEPVI_REQ_TEMPERATUREPVI_REQ_TEMPERATURE_COUNTPVI_REQ_MAX_TEMPERATUREPVI_REQ_MIN_TEMPERATUREPVI_REQ_DATAPVI_INDEXPVI_VALUEPVI_REQ_DEVICE_STATEPVI_REQ_SERIAL_NUMBERPVI_REQ_VERSIONPVI_VERSION_MAINPVI_VERSION_PICPVI_REQ_AC_MAX_PHASE_COUNTPVI_REQ_AC_POWERPVI_REQ_AC_VOLTAGEPVI_REQ_AC_CURRENTPVI_REQ_AC_APPARENTPOWERPVI_REQ_AC_REACTIVEPOWERPVI_REQ_AC_ENERGY_ALLPVI_REQ_AC_MAX_APPARENTPOWERPVI_REQ_AC_ENERGY_DAYPVI_REQ_AC_ENERGY_GRID_CONSUMPTIONPVI_REQ_DC_MAX_STRING_COUNTPVI_REQ_DC_POWERPVI_REQ_DC_VOLTAGEPVI_REQ_DC_CURRENTPVI_REQ_DC_MAX_POWERPVI_REQ_DC_MAX_VOLTAGEPVI_REQ_DC_MIN_VOLTAGEPVI_REQ_DC_MAX_CURRENTPVI_REQ_DC_MIN_CURRENTPVI_REQ_DC_STRING_ENERGY_ALLPVI_ON_GRIDPVI_STATEPVI_LAST_ERRORPVI_FLASH_FILEPVI_TYPEPVI_COS_PHIPVI_VOLTAGE_MONITORINGPVI_FREQUENCY_UNDER_OVERPVI_SYSTEM_MODEPVI_POWER_MODEPVI_TEMPERATUREPVI_TEMPERATURE_COUNTPVI_MAX_TEMPERATUREPVI_MIN_TEMPERATUREPVI_DATAPVI_DEVICE_STATEPVI_DEVICE_CONNECTEDPVI_DEVICE_WORKINGPVI_DEVICE_IN_SERVICEPVI_SERIAL_NUMBERPVI_VERSIONPVI_AC_MAX_PHASE_COUNTPVI_AC_POWERPVI_AC_VOLTAGEPVI_AC_CURRENTPVI_AC_APPARENTPOWERPVI_AC_REACTIVEPOWERPVI_AC_ENERGY_ALLPVI_AC_MAX_APPARENTPOWERPVI_AC_ENERGY_DAYPVI_AC_ENERGY_GRID_CONSUMPTIONPVI_DC_MAX_STRING_COUNTPVI_DC_POWERPVI_DC_VOLTAGEPVI_DC_CURRENTPVI_DC_MAX_POWERPVI_DC_MAX_VOLTAGEPVI_DC_MIN_VOLTAGEPVI_DC_MAX_CURRENTPVI_DC_MIN_CURRENTPVI_DC_STRING_ENERGY_ALLPVI_GENERAL_ERRORBAT_REQ_RSOCBAT_REQ_MODULE_VOLTAGEBAT_REQ_CURRENTBAT_REQ_MAX_BAT_VOLTAGEBAT_REQ_MAX_CHARGE_CURRENTBAT_REQ_EOD_VOLTAGEBAT_REQ_MAX_DISCHARGE_CURRENTBAT_REQ_CHARGE_CYCLESBAT_REQ_TERMINAL_VOLTAGEBAT_REQ_STATUS_CODEBAT_REQ_ERROR_CODEBAT_REQ_DEVICE_NAMEBAT_REQ_DCB_COUNTBAT_REQ_MAX_DCB_CELL_TEMPERATUREBAT_REQ_MIN_DCB_CELL_TEMPERATUREBAT_REQ_READY_FOR_SHUTDOWNBAT_REQ_INFOBAT_REQ_TRAINING_MODEBAT_REQ_DCB_INFOBAT_REQ_DATABAT_INDEXBAT_REQ_DEVICE_STATEBAT_RSOCBAT_MODULE_VOLTAGEBAT_CURRENTBAT_MAX_BAT_VOLTAGEBAT_MAX_CHARGE_CURRENTBAT_EOD_VOLTAGEBAT_MAX_DISCHARGE_CURRENTBAT_CHARGE_CYCLESBAT_TERMINAL_VOLTAGEBAT_STATUS_CODEBAT_ERROR_CODEBAT_DEVICE_NAMEBAT_DCB_COUNTBAT_MAX_DCB_CELL_TEMPERATUREBAT_MIN_DCB_CELL_TEMPERATUREBAT_DCB_CELL_TEMPERATUREBAT_DCB_CELL_VOLTAGEBAT_READY_FOR_SHUTDOWNBAT_INFOBAT_TRAINING_MODEBAT_DCB_INFOBAT_DCB_INDEXBAT_DCB_LAST_MESSAGE_TIMESTAMPBAT_DCB_MAX_CHARGE_VOLTAGEBAT_DCB_MAX_CHARGE_CURRENTBAT_DCB_END_OF_DISCHARGEBAT_DCB_MAX_DISCHARGE_CURRENTBAT_DCB_FULL_CHARGE_CAPACITYBAT_DCB_REMAINING_CAPACITYBAT_DCB_SOCBAT_DCB_SOHBAT_DCB_CYCLE_COUNTBAT_DCB_CURRENTBAT_DCB_VOLTAGEBAT_DCB_CURRENT_AVG_30SBAT_DCB_VOLTAGE_AVG_30SBAT_DCB_DESIGN_CAPACITYBAT_DCB_DESIGN_VOLTAGEBAT_DCB_CHARGE_LOW_TEMPERATUREBAT_DCB_CHARGE_HIGH_TEMPERATUREBAT_DCB_MANUFACTURE_DATEBAT_DCB_SERIALNOBAT_DCB_PROTOCOL_VERSIONBAT_DCB_FW_VERSIONBAT_DCB_DATA_TABLE_VERSIONBAT_DCB_PCB_VERSIONBAT_DATABAT_DEVICE_STATEBAT_DEVICE_CONNECTEDBAT_DEVICE_WORKINGBAT_DEVICE_IN_SERVICEBAT_GENERAL_ERRORDCDC_REQ_I_BATDCDC_REQ_U_BATDCDC_REQ_P_BATDCDC_REQ_I_DCLDCDC_REQ_U_DCLDCDC_REQ_P_DCLDCDC_REQ_FIRMWARE_VERSIONDCDC_REQ_FPGA_FIRMWAREDCDC_REQ_SERIAL_NUMBERDCDC_REQ_BOARD_VERSIONDCDC_REQ_FLASH_FILE_LISTDCDC_REQ_IS_FLASHINGDCDC_REQ_FLASHDCDC_REQ_STATUSDCDC_REQ_STATUS_AS_STRINGDCDC_REQ_DATADCDC_INDEXDCDC_REQ_DEVICE_STATEDCDC_I_BATDCDC_U_BATDCDC_P_BATDCDC_I_DCLDCDC_U_DCLDCDC_P_DCLDCDC_FIRMWARE_VERSIONDCDC_FPGA_FIRMWAREDCDC_SERIAL_NUMBERDCDC_BOARD_VERSIONDCDC_FLASH_FILE_LISTDCDC_FLASH_FILEDCDC_IS_FLASHINGDCDC_FLASHDCDC_STATUSDCDC_STATEDCDC_SUBSTATEDCDC_STATUS_AS_STRINGDCDC_STATE_AS_STRINGDCDC_SUBSTATE_AS_STRINGDCDC_DATADCDC_DEVICE_STATEDCDC_DEVICE_CONNECTEDDCDC_DEVICE_WORKINGDCDC_DEVICE_IN_SERVICEDCDC_GENERAL_ERRORPM_REQ_POWER_L1PM_REQ_POWER_L2PM_REQ_POWER_L3PM_REQ_ACTIVE_PHASESPM_REQ_MODEPM_REQ_ENERGY_L1PM_REQ_ENERGY_L2PM_REQ_ENERGY_L3PM_REQ_DEVICE_IDPM_REQ_ERROR_CODEPM_REQ_SET_PHASE_ELIMINATIONPM_REQ_FIRMWARE_VERSIONPM_REQ_VOLTAGE_L1PM_REQ_VOLTAGE_L2PM_REQ_VOLTAGE_L3PM_REQ_TYPEPM_REQ_GET_PHASE_ELIMINATIONPM_REQ_DATAPM_INDEXPM_REQ_DEVICE_STATEPM_POWER_L1PM_POWER_L2PM_POWER_L3PM_ACTIVE_PHASESPM_MODEPM_ENERGY_L1PM_ENERGY_L2PM_ENERGY_L3PM_DEVICE_IDPM_ERROR_CODEPM_SET_PHASE_ELIMINATIONPM_FIRMWARE_VERSIONPM_VOLTAGE_L1PM_VOLTAGE_L2PM_VOLTAGE_L3PM_TYPEPM_GET_PHASE_ELIMINATIONPM_CS_START_TIMEPM_CS_LAST_TIMEPM_CS_SUCC_FRAMES_ALLPM_CS_SUCC_FRAMES_100PM_CS_EXP_FRAMES_ALLPM_CS_EXP_FRAMES_100PM_CS_ERR_FRAMES_ALLPM_CS_ERR_FRAMES_100PM_CS_UNK_FRAMESPM_CS_ERR_FRAMEPM_DATAPM_DEVICE_STATEPM_DEVICE_CONNECTEDPM_DEVICE_WORKINGPM_DEVICE_IN_SERVICEPM_GENERAL_ERRORDB_REQ_HISTORY_DATA_DAYDB_REQ_HISTORY_TIME_STARTDB_REQ_HISTORY_TIME_INTERVALDB_REQ_HISTORY_TIME_SPANDB_REQ_HISTORY_DATA_WEEKDB_REQ_HISTORY_DATA_MONTHDB_REQ_HISTORY_DATA_YEARDB_GRAPH_INDEXDB_BAT_POWER_INDB_BAT_POWER_OUTDB_DC_POWERDB_GRID_POWER_INDB_GRID_POWER_OUTDB_CONSUMPTIONDB_PM_0_POWERDB_PM_1_POWERDB_BAT_CHARGE_LEVELDB_BAT_CYCLE_COUNTDB_CONSUMED_PRODUCTIONDB_AUTARKYDB_SUM_CONTAINERDB_VALUE_CONTAINERDB_HISTORY_DATA_DAYDB_HISTORY_DATA_WEEKDB_HISTORY_DATA_MONTHDB_HISTORY_DATA_YEARDB_PAR_TIME_MINDB_PAR_TIME_MAXDB_PARAM_ROWDB_PARAM_COLUMNDB_PARAM_INDEXDB_PARAM_VALUEDB_PARAM_MAX_ROWSDB_PARAM_TIMEDB_PARAM_VERSIONDB_PARAM_HEADERSRV_REQ_IS_ONLINESRV_REQ_ADD_USERSRV_IS_ONLINESRV_ADD_USERSRV_GENERAL_ERRORHA_REQ_DATAPOINT_LISTHA_REQ_ACTUATOR_STATESHA_REQ_ADD_ACTUATORHA_REQ_REMOVE_ACTUATORHA_REQ_COMMAND_ACTUATORHA_REQ_COMMANDHA_REQ_DESCRIPTIONS_CHANGEHA_REQ_CONFIGURATION_CHANGE_COUNTERHA_REQ_DEVICE_STATEHA_DATAPOINT_LISTHA_DATAPOINTHA_DATAPOINT_INDEXHA_DATAPOINT_TYPEHA_DATAPOINT_NAMEHA_DATAPOINT_DESCRIPTIONSHA_DATAPOINT_DESCRIPTIONHA_DATAPOINT_DESCRIPTION_NAMEHA_DATAPOINT_DESCRIPTION_VALUEHA_ACTUATOR_STATESHA_DATAPOINT_STATEHA_DATAPOINT_MODEHA_DATAPOINT_STATE_TIMESTAMPHA_DATAPOINT_STATE_VALUEHA_DATAPOINT_SUPPLY_QUALITYHA_DATAPOINT_SIGNAL_QUALITYHA_ADD_ACTUATORHA_REMOVE_ACTUATORHA_COMMAND_ACTUATORHA_DESCRIPTIONS_CHANGEHA_CONFIGURATION_CHANGE_COUNTERHA_DEVICE_STATEHA_DEVICE_CONNECTEDHA_DEVICE_WORKINGHA_DEVICE_IN_SERVICEHA_GENERAL_ERRORINFO_REQ_SERIAL_NUMBERINFO_REQ_PRODUCTION_DATEINFO_REQ_MODULES_SW_VERSIONSINFO_REQ_A35_SERIAL_NUMBERINFO_REQ_IP_ADDRESSINFO_REQ_SUBNET_MASKINFO_REQ_MAC_ADDRESSINFO_REQ_GATEWAYINFO_REQ_DNSINFO_REQ_DHCP_STATUSINFO_REQ_TIMEINFO_REQ_UTC_TIMEINFO_REQ_TIME_ZONEINFO_REQ_INFOINFO_REQ_SET_IP_ADDRESSINFO_REQ_SET_SUBNET_MASKINFO_REQ_SET_DHCP_STATUSINFO_REQ_SET_GATEWAYINFO_REQ_SET_DNSINFO_REQ_SET_TIME_ZONEINFO_REQ_SW_RELEASEINFO_SERIAL_NUMBERINFO_PRODUCTION_DATEINFO_MODULES_SW_VERSIONSINFO_MODULE_SW_VERSIONINFO_MODULEINFO_VERSIONINFO_A35_SERIAL_NUMBERINFO_IP_ADDRESSINFO_SUBNET_MASKINFO_MAC_ADDRESSINFO_GATEWAYINFO_DNSINFO_DHCP_STATUSINFO_TIMEINFO_UTC_TIMEINFO_TIME_ZONEINFO_INFOINFO_SET_IP_ADDRESSINFO_SET_SUBNET_MASKINFO_SET_DHCP_STATUSINFO_SET_GATEWAYINFO_SET_DNSINFO_SET_TIMEINFO_SET_TIME_ZONEINFO_SW_RELEASEINFO_GENERAL_ERROREP_REQ_IS_READY_FOR_SWITCHEP_REQ_IS_GRID_CONNECTEDEP_REQ_IS_ISLAND_GRIDEP_REQ_IS_INVALID_STATEEP_REQ_IS_POSSIBLEEP_IS_READY_FOR_SWITCHEP_IS_GRID_CONNECTEDEP_IS_ISLAND_GRIDEP_IS_INVALID_STATEEP_IS_POSSIBLEEP_GENERAL_ERRORSYS_REQ_SYSTEM_REBOOTSYS_REQ_IS_SYSTEM_REBOOTINGSYS_REQ_RESTART_APPLICATIONSYS_SYSTEM_REBOOTSYS_IS_SYSTEM_REBOOTINGSYS_RESTART_APPLICATIONSYS_SCRIPT_FILESYS_GENERAL_ERRORUM_REQ_UPDATE_STATUSUM_REQ_CHECK_FOR_UPDATESUM_UPDATE_STATUSUM_CHECK_FOR_UPDATESUM_GENERAL_ERRORWB_REQ_ENERGY_ALLWB_REQ_ENERGY_SOLARWB_REQ_SOCWB_REQ_STATUSWB_REQ_ERROR_CODEWB_REQ_MODEWB_REQ_APP_SOFTWAREWB_REQ_BOOTLOADER_SOFTWAREWB_REQ_HW_VERSIONWB_REQ_FLASH_VERSIONWB_REQ_DEVICE_IDWB_REQ_PM_POWER_L1WB_REQ_PM_POWER_L2WB_REQ_PM_POWER_L3WB_REQ_PM_ACTIVE_PHASESWB_REQ_PM_MODEWB_REQ_PM_ENERGY_L1WB_REQ_PM_ENERGY_L2WB_REQ_PM_ENERGY_L3WB_REQ_PM_DEVICE_IDWB_REQ_PM_ERROR_CODEWB_REQ_PM_FIRMWARE_VERSIONWB_REQ_DIAG_INFOSWB_REQ_DIAG_WARNINGSWB_REQ_DIAG_ERRORSWB_REQ_DIAG_TEMP_1WB_REQ_DIAG_TEMP_2WB_REQ_PM_DEVICE_STATEWB_REQ_SET_MODEWB_SET_MODEWB_REQ_DATAWB_INDEXWB_MODE_PARAM_MODEWB_MODE_PARAM_MAX_CURRENTWB_REQ_AVAILABLE_SOLAR_POWERWB_POWERWB_STATUS_BITWB_REQ_SET_EXTERNWB_REQ_EXTERN_DATA_SUNWB_REQ_EXTERN_DATA_NETWB_REQ_EXTERN_DATA_ALLWB_REQ_EXTERN_DATA_ALGWB_REQ_SET_BAT_CAPACITYWB_REQ_SET_PARAM_1WB_REQ_SET_PARAM_2WB_REQ_PARAM_2WB_REQ_PARAM_1WB_EXTERN_DATAWB_EXTERN_DATA_LENWB_REQ_DEVICE_STATEWB_ENERGY_ALLWB_ENERGY_SOLARWB_SOCWB_STATUSWB_ERROR_CODEWB_MODEWB_APP_SOFTWAREWB_BOOTLOADER_SOFTWAREWB_HW_VERSIONWB_FLASH_VERSIONWB_DEVICE_IDWB_PM_POWER_L1WB_PM_POWER_L2WB_PM_POWER_L3WB_PM_ACTIVE_PHASESWB_PM_MODEWB_PM_ENERGY_L1WB_PM_ENERGY_L2WB_PM_ENERGY_L3WB_PM_DEVICE_IDWB_PM_ERROR_CODEWB_PM_FIRMWARE_VERSIONWB_DIAG_INFOSWB_DIAG_WARNINGSWB_DIAG_ERRORSWB_DIAG_TEMP_1WB_DIAG_TEMP_2WB_PM_DEVICE_STATEWB_PM_DEVICE_STATE_CONNECTEDWB_PM_DEVICE_STATE_WORKINGWB_PM_DEVICE_STATE_IN_SERVICEWB_DATAWB_AVAILABLE_SOLAR_POWERWB_SET_EXTERNWB_EXTERN_DATA_SUNWB_EXTERN_DATA_NETWB_EXTERN_DATA_ALLWB_EXTERN_DATA_ALGWB_SET_BAT_CAPACITYWB_SET_PARAM_1WB_SET_PARAM_2WB_RSP_PARAM_2WB_RSP_PARAM_1WB_DEVICE_STATEWB_DEVICE_CONNECTEDWB_DEVICE_WORKINGWB_DEVICE_IN_SERVICEWB_GENERAL_ERROR"
 
 var _TagMap = map[Tag]string{
 	1:         _TagName[0:23],
@@ -298,402 +298,404 @@ var _TagMap = map[Tag]string{
 	50331678:  _TagName[6224:6250],
 	50331680:  _TagName[6250:6262],
 	50331681:  _TagName[6262:6283],
-	50593792:  _TagName[6283:6295],
-	50593793:  _TagName[6295:6304],
-	50724864:  _TagName[6304:6324],
-	58720257:  _TagName[6324:6332],
-	58720258:  _TagName[6332:6350],
-	58720259:  _TagName[6350:6361],
-	58720260:  _TagName[6361:6380],
-	58720261:  _TagName[6380:6402],
-	58720262:  _TagName[6402:6417],
-	58720263:  _TagName[6417:6442],
-	58720264:  _TagName[6442:6459],
-	58720265:  _TagName[6459:6479],
-	58720266:  _TagName[6479:6494],
-	58720267:  _TagName[6494:6508],
-	58720268:  _TagName[6508:6523],
-	58720269:  _TagName[6523:6536],
-	58720278:  _TagName[6536:6564],
-	58720279:  _TagName[6564:6592],
-	58720281:  _TagName[6592:6616],
-	58720283:  _TagName[6616:6636],
-	58720286:  _TagName[6636:6658],
-	58720288:  _TagName[6658:6666],
-	58720289:  _TagName[6666:6683],
-	58720512:  _TagName[6683:6696],
-	58720513:  _TagName[6696:6726],
-	58720514:  _TagName[6726:6752],
-	58720515:  _TagName[6752:6778],
-	58720516:  _TagName[6778:6802],
-	58720517:  _TagName[6802:6831],
-	58720518:  _TagName[6831:6859],
-	58720519:  _TagName[6859:6885],
-	58720520:  _TagName[6885:6896],
-	58720521:  _TagName[6896:6907],
-	58720528:  _TagName[6907:6926],
-	58720529:  _TagName[6926:6941],
-	58720530:  _TagName[6941:6956],
-	58720531:  _TagName[6956:6979],
-	58720532:  _TagName[6979:7002],
-	58720533:  _TagName[7002:7025],
-	58720534:  _TagName[7025:7047],
-	58720535:  _TagName[7047:7077],
-	58720536:  _TagName[7077:7108],
-	58720537:  _TagName[7108:7132],
-	58720544:  _TagName[7132:7148],
-	58720545:  _TagName[7148:7172],
-	58720546:  _TagName[7172:7190],
-	58720547:  _TagName[7190:7216],
-	58720548:  _TagName[7216:7235],
-	58982400:  _TagName[7235:7243],
-	59113472:  _TagName[7243:7259],
-	59113473:  _TagName[7259:7279],
-	59113474:  _TagName[7279:7297],
-	59113475:  _TagName[7297:7318],
-	67108863:  _TagName[7318:7335],
-	67108865:  _TagName[7335:7349],
-	67108866:  _TagName[7349:7363],
-	67108867:  _TagName[7363:7377],
-	67108868:  _TagName[7377:7391],
-	67108869:  _TagName[7391:7405],
-	67108870:  _TagName[7405:7419],
-	67108872:  _TagName[7419:7444],
-	67108873:  _TagName[7444:7466],
-	67108874:  _TagName[7466:7488],
-	67108875:  _TagName[7488:7510],
-	67108876:  _TagName[7510:7534],
-	67108878:  _TagName[7534:7554],
-	67108879:  _TagName[7554:7568],
-	67108880:  _TagName[7568:7583],
-	67108883:  _TagName[7583:7608],
-	67371008:  _TagName[7608:7621],
-	67371009:  _TagName[7621:7631],
-	67502080:  _TagName[7631:7652],
-	75497473:  _TagName[7652:7662],
-	75497474:  _TagName[7662:7672],
-	75497475:  _TagName[7672:7682],
-	75497476:  _TagName[7682:7692],
-	75497477:  _TagName[7692:7702],
-	75497478:  _TagName[7702:7712],
-	75497480:  _TagName[7712:7733],
-	75497481:  _TagName[7733:7751],
-	75497482:  _TagName[7751:7769],
-	75497483:  _TagName[7769:7787],
-	75497484:  _TagName[7787:7807],
-	75497485:  _TagName[7807:7822],
-	75497486:  _TagName[7822:7838],
-	75497487:  _TagName[7838:7848],
-	75497488:  _TagName[7848:7859],
-	75497489:  _TagName[7859:7869],
-	75497490:  _TagName[7869:7882],
-	75497491:  _TagName[7882:7903],
-	75497492:  _TagName[7903:7923],
-	75497493:  _TagName[7923:7946],
-	75759616:  _TagName[7946:7955],
-	75890688:  _TagName[7955:7972],
-	75890689:  _TagName[7972:7993],
-	75890690:  _TagName[7993:8012],
-	75890691:  _TagName[8012:8034],
-	83886079:  _TagName[8034:8052],
-	83886081:  _TagName[8052:8067],
-	83886082:  _TagName[8067:8082],
-	83886083:  _TagName[8082:8097],
-	83886084:  _TagName[8097:8117],
-	83886085:  _TagName[8117:8128],
-	83886086:  _TagName[8128:8144],
-	83886087:  _TagName[8144:8160],
-	83886088:  _TagName[8160:8176],
-	83886089:  _TagName[8176:8192],
-	83886090:  _TagName[8192:8209],
-	83886091:  _TagName[8209:8237],
-	83886092:  _TagName[8237:8260],
-	83886097:  _TagName[8260:8277],
-	83886098:  _TagName[8277:8294],
-	83886099:  _TagName[8294:8311],
-	83886100:  _TagName[8311:8322],
-	83886104:  _TagName[8322:8350],
-	84148224:  _TagName[8350:8361],
-	84148225:  _TagName[8361:8369],
-	84279296:  _TagName[8369:8388],
-	92274689:  _TagName[8388:8399],
-	92274690:  _TagName[8399:8410],
-	92274691:  _TagName[8410:8421],
-	92274692:  _TagName[8421:8437],
-	92274693:  _TagName[8437:8444],
-	92274694:  _TagName[8444:8456],
-	92274695:  _TagName[8456:8468],
-	92274696:  _TagName[8468:8480],
-	92274697:  _TagName[8480:8492],
-	92274698:  _TagName[8492:8505],
-	92274699:  _TagName[8505:8529],
-	92274700:  _TagName[8529:8548],
-	92274705:  _TagName[8548:8561],
-	92274706:  _TagName[8561:8574],
-	92274707:  _TagName[8574:8587],
-	92274708:  _TagName[8587:8594],
-	92274712:  _TagName[8594:8618],
-	92274769:  _TagName[8618:8634],
-	92274770:  _TagName[8634:8649],
-	92274771:  _TagName[8649:8670],
-	92274772:  _TagName[8670:8691],
-	92274773:  _TagName[8691:8711],
-	92274774:  _TagName[8711:8731],
-	92274775:  _TagName[8731:8751],
-	92274776:  _TagName[8751:8771],
-	92274777:  _TagName[8771:8787],
-	92274778:  _TagName[8787:8802],
-	92536832:  _TagName[8802:8809],
-	92667904:  _TagName[8809:8824],
-	92667905:  _TagName[8824:8843],
-	92667906:  _TagName[8843:8860],
-	92667907:  _TagName[8860:8880],
-	100663295: _TagName[8880:8896],
-	100663552: _TagName[8896:8919],
-	100663553: _TagName[8919:8944],
-	100663554: _TagName[8944:8972],
-	100663555: _TagName[8972:8996],
-	100663808: _TagName[8996:9020],
-	100664064: _TagName[9020:9045],
-	100664320: _TagName[9045:9069],
-	109051905: _TagName[9069:9083],
-	109051906: _TagName[9083:9098],
-	109051907: _TagName[9098:9114],
-	109051908: _TagName[9114:9125],
-	109051909: _TagName[9125:9141],
-	109051910: _TagName[9141:9158],
-	109051911: _TagName[9158:9172],
-	109051912: _TagName[9172:9185],
-	109051913: _TagName[9185:9198],
-	109051914: _TagName[9198:9217],
-	109051915: _TagName[9217:9235],
-	109051916: _TagName[9235:9257],
-	109051917: _TagName[9257:9267],
-	109051920: _TagName[9267:9283],
-	109051936: _TagName[9283:9301],
-	109052160: _TagName[9301:9320],
-	109052416: _TagName[9320:9340],
-	109052672: _TagName[9340:9361],
-	109052928: _TagName[9361:9381],
-	112197632: _TagName[9381:9396],
-	112197633: _TagName[9396:9411],
-	112197634: _TagName[9411:9423],
-	112197635: _TagName[9423:9438],
-	112197636: _TagName[9438:9452],
-	112197637: _TagName[9452:9466],
-	112197638: _TagName[9466:9483],
-	112197639: _TagName[9483:9496],
-	112197640: _TagName[9496:9512],
-	112197641: _TagName[9512:9527],
-	134217729: _TagName[9527:9544],
-	134217730: _TagName[9544:9560],
-	142606337: _TagName[9560:9573],
-	142606338: _TagName[9573:9585],
-	150994943: _TagName[9585:9602],
-	150994945: _TagName[9602:9623],
-	150994960: _TagName[9623:9645],
-	150994976: _TagName[9645:9664],
-	150994992: _TagName[9664:9686],
-	150995008: _TagName[9686:9709],
-	150995009: _TagName[9709:9723],
-	150995024: _TagName[9723:9749],
-	150995040: _TagName[9749:9784],
-	151388160: _TagName[9784:9803],
-	159383553: _TagName[9803:9820],
-	159383554: _TagName[9820:9832],
-	159383555: _TagName[9832:9850],
-	159383556: _TagName[9850:9867],
-	159383557: _TagName[9867:9884],
-	159383558: _TagName[9884:9909],
-	159383559: _TagName[9909:9933],
-	159383560: _TagName[9933:9962],
-	159383561: _TagName[9962:9992],
-	159383568: _TagName[9992:10010],
-	159383569: _TagName[10010:10028],
-	159383570: _TagName[10028:10045],
-	159383571: _TagName[10045:10073],
-	159383572: _TagName[10073:10097],
-	159383573: _TagName[10097:10124],
-	159383574: _TagName[10124:10151],
-	159383584: _TagName[10151:10166],
-	159383600: _TagName[10166:10184],
-	159383616: _TagName[10184:10203],
-	159383632: _TagName[10203:10225],
-	159383648: _TagName[10225:10256],
-	159776768: _TagName[10256:10271],
-	159776769: _TagName[10271:10290],
-	159776770: _TagName[10290:10307],
-	159776771: _TagName[10307:10327],
-	167772159: _TagName[10327:10343],
-	167772161: _TagName[10343:10365],
-	167772162: _TagName[10365:10389],
-	167772163: _TagName[10389:10417],
-	167772167: _TagName[10417:10443],
-	167772168: _TagName[10443:10462],
-	167772169: _TagName[10462:10482],
-	167772170: _TagName[10482:10502],
-	167772171: _TagName[10502:10518],
-	167772172: _TagName[10518:10530],
-	167772173: _TagName[10530:10550],
-	167772174: _TagName[10550:10563],
-	167772175: _TagName[10563:10580],
-	167772176: _TagName[10580:10598],
-	167772177: _TagName[10598:10611],
-	167772178: _TagName[10611:10634],
-	167772179: _TagName[10634:10658],
-	167772180: _TagName[10658:10682],
-	167772181: _TagName[10682:10702],
-	167772182: _TagName[10702:10718],
-	167772184: _TagName[10718:10740],
-	167772185: _TagName[10740:10759],
-	176160769: _TagName[10759:10777],
-	176160770: _TagName[10777:10797],
-	176160771: _TagName[10797:10821],
-	176160772: _TagName[10821:10843],
-	176160773: _TagName[10843:10854],
-	176160774: _TagName[10854:10866],
-	176160775: _TagName[10866:10888],
-	176160776: _TagName[10888:10903],
-	176160777: _TagName[10903:10919],
-	176160778: _TagName[10919:10935],
-	176160779: _TagName[10935:10947],
-	176160780: _TagName[10947:10955],
-	176160781: _TagName[10955:10971],
-	176160782: _TagName[10971:10980],
-	176160783: _TagName[10980:10993],
-	176160784: _TagName[10993:11007],
-	176160785: _TagName[11007:11016],
-	176160786: _TagName[11016:11035],
-	176160787: _TagName[11035:11055],
-	176160788: _TagName[11055:11075],
-	176160789: _TagName[11075:11091],
-	176160790: _TagName[11091:11103],
-	176160791: _TagName[11103:11116],
-	176160792: _TagName[11116:11134],
-	176160793: _TagName[11134:11149],
-	184549375: _TagName[11149:11167],
-	184549379: _TagName[11167:11193],
-	184549380: _TagName[11193:11217],
-	184549381: _TagName[11217:11238],
-	184549382: _TagName[11238:11261],
-	184549383: _TagName[11261:11279],
-	192937987: _TagName[11279:11301],
-	192937988: _TagName[11301:11321],
-	192937989: _TagName[11321:11338],
-	192937990: _TagName[11338:11357],
-	192937991: _TagName[11357:11371],
-	201326591: _TagName[11371:11387],
-	201326593: _TagName[11387:11408],
-	201326594: _TagName[11408:11435],
-	201326595: _TagName[11435:11462],
-	209715201: _TagName[11462:11479],
-	209715202: _TagName[11479:11502],
-	209715203: _TagName[11502:11525],
-	209715217: _TagName[11525:11540],
-	218103807: _TagName[11540:11557],
-	218103809: _TagName[11557:11577],
-	218103811: _TagName[11577:11601],
-	226492417: _TagName[11601:11617],
-	226492419: _TagName[11617:11637],
-	234881023: _TagName[11637:11653],
-	234881025: _TagName[11653:11670],
-	234881026: _TagName[11670:11689],
-	234881027: _TagName[11689:11699],
-	234881028: _TagName[11699:11712],
-	234881029: _TagName[11712:11729],
-	234881030: _TagName[11729:11740],
-	234881031: _TagName[11740:11759],
-	234881032: _TagName[11759:11785],
-	234881033: _TagName[11785:11802],
-	234881034: _TagName[11802:11822],
-	234881035: _TagName[11822:11838],
-	234881036: _TagName[11838:11856],
-	234881037: _TagName[11856:11874],
-	234881038: _TagName[11874:11892],
-	234881039: _TagName[11892:11915],
-	234881041: _TagName[11915:11929],
-	234881042: _TagName[11929:11948],
-	234881043: _TagName[11948:11967],
-	234881044: _TagName[11967:11986],
-	234881045: _TagName[11986:12005],
-	234881046: _TagName[12005:12025],
-	234881047: _TagName[12025:12051],
-	234881055: _TagName[12051:12068],
-	234881056: _TagName[12068:12088],
-	234881057: _TagName[12088:12106],
-	234881058: _TagName[12106:12124],
-	234881059: _TagName[12124:12142],
-	234881065: _TagName[12142:12164],
-	234881072: _TagName[12164:12179],
-	234881073: _TagName[12179:12190],
-	235143168: _TagName[12190:12201],
-	235143169: _TagName[12201:12209],
-	235143217: _TagName[12209:12227],
-	235143218: _TagName[12227:12252],
-	235147264: _TagName[12252:12280],
-	235147265: _TagName[12280:12288],
-	235147266: _TagName[12288:12301],
-	235147280: _TagName[12301:12318],
-	235147281: _TagName[12318:12340],
-	235147282: _TagName[12340:12362],
-	235147283: _TagName[12362:12384],
-	235147284: _TagName[12384:12406],
-	235147285: _TagName[12406:12429],
-	235147288: _TagName[12429:12447],
-	235147289: _TagName[12447:12465],
-	235147290: _TagName[12465:12479],
-	235147291: _TagName[12479:12493],
-	235151376: _TagName[12493:12507],
-	235151377: _TagName[12507:12525],
-	235274240: _TagName[12525:12544],
-	243269633: _TagName[12544:12557],
-	243269634: _TagName[12557:12572],
-	243269635: _TagName[12572:12578],
-	243269636: _TagName[12578:12587],
-	243269637: _TagName[12587:12600],
-	243269638: _TagName[12600:12607],
-	243269639: _TagName[12607:12622],
-	243269640: _TagName[12622:12644],
-	243269641: _TagName[12644:12657],
-	243269642: _TagName[12657:12673],
-	243269643: _TagName[12673:12685],
-	243269644: _TagName[12685:12699],
-	243269645: _TagName[12699:12713],
-	243269646: _TagName[12713:12727],
-	243269647: _TagName[12727:12746],
-	243269649: _TagName[12746:12756],
-	243269650: _TagName[12756:12771],
-	243269651: _TagName[12771:12786],
-	243269652: _TagName[12786:12801],
-	243269653: _TagName[12801:12816],
-	243269654: _TagName[12816:12832],
-	243269655: _TagName[12832:12854],
-	243269663: _TagName[12854:12867],
-	243269664: _TagName[12867:12883],
-	243269665: _TagName[12883:12897],
-	243269666: _TagName[12897:12911],
-	243269667: _TagName[12911:12925],
-	243269673: _TagName[12925:12943],
-	243269680: _TagName[12943:12971],
-	243269681: _TagName[12971:12997],
-	243269682: _TagName[12997:13026],
-	243531776: _TagName[13026:13033],
-	243535872: _TagName[13033:13057],
-	243535888: _TagName[13057:13070],
-	243535889: _TagName[13070:13088],
-	243535890: _TagName[13088:13106],
-	243535891: _TagName[13106:13124],
-	243535892: _TagName[13124:13142],
-	243535893: _TagName[13142:13161],
-	243535896: _TagName[13161:13175],
-	243535897: _TagName[13175:13189],
-	243535898: _TagName[13189:13203],
-	243535899: _TagName[13203:13217],
-	243662848: _TagName[13217:13232],
-	243662849: _TagName[13232:13251],
-	243662850: _TagName[13251:13268],
-	243662851: _TagName[13268:13288],
-	251658239: _TagName[13288:13304],
+	50331714:  _TagName[6283:6299],
+	50593792:  _TagName[6299:6311],
+	50593793:  _TagName[6311:6320],
+	50724864:  _TagName[6320:6340],
+	58720257:  _TagName[6340:6348],
+	58720258:  _TagName[6348:6366],
+	58720259:  _TagName[6366:6377],
+	58720260:  _TagName[6377:6396],
+	58720261:  _TagName[6396:6418],
+	58720262:  _TagName[6418:6433],
+	58720263:  _TagName[6433:6458],
+	58720264:  _TagName[6458:6475],
+	58720265:  _TagName[6475:6495],
+	58720266:  _TagName[6495:6510],
+	58720267:  _TagName[6510:6524],
+	58720268:  _TagName[6524:6539],
+	58720269:  _TagName[6539:6552],
+	58720278:  _TagName[6552:6580],
+	58720279:  _TagName[6580:6608],
+	58720281:  _TagName[6608:6632],
+	58720283:  _TagName[6632:6652],
+	58720286:  _TagName[6652:6674],
+	58720288:  _TagName[6674:6682],
+	58720289:  _TagName[6682:6699],
+	58720322:  _TagName[6699:6711],
+	58720512:  _TagName[6711:6724],
+	58720513:  _TagName[6724:6754],
+	58720514:  _TagName[6754:6780],
+	58720515:  _TagName[6780:6806],
+	58720516:  _TagName[6806:6830],
+	58720517:  _TagName[6830:6859],
+	58720518:  _TagName[6859:6887],
+	58720519:  _TagName[6887:6913],
+	58720520:  _TagName[6913:6924],
+	58720521:  _TagName[6924:6935],
+	58720528:  _TagName[6935:6954],
+	58720529:  _TagName[6954:6969],
+	58720530:  _TagName[6969:6984],
+	58720531:  _TagName[6984:7007],
+	58720532:  _TagName[7007:7030],
+	58720533:  _TagName[7030:7053],
+	58720534:  _TagName[7053:7075],
+	58720535:  _TagName[7075:7105],
+	58720536:  _TagName[7105:7136],
+	58720537:  _TagName[7136:7160],
+	58720544:  _TagName[7160:7176],
+	58720545:  _TagName[7176:7200],
+	58720546:  _TagName[7200:7218],
+	58720547:  _TagName[7218:7244],
+	58720548:  _TagName[7244:7263],
+	58982400:  _TagName[7263:7271],
+	59113472:  _TagName[7271:7287],
+	59113473:  _TagName[7287:7307],
+	59113474:  _TagName[7307:7325],
+	59113475:  _TagName[7325:7346],
+	67108863:  _TagName[7346:7363],
+	67108865:  _TagName[7363:7377],
+	67108866:  _TagName[7377:7391],
+	67108867:  _TagName[7391:7405],
+	67108868:  _TagName[7405:7419],
+	67108869:  _TagName[7419:7433],
+	67108870:  _TagName[7433:7447],
+	67108872:  _TagName[7447:7472],
+	67108873:  _TagName[7472:7494],
+	67108874:  _TagName[7494:7516],
+	67108875:  _TagName[7516:7538],
+	67108876:  _TagName[7538:7562],
+	67108878:  _TagName[7562:7582],
+	67108879:  _TagName[7582:7596],
+	67108880:  _TagName[7596:7611],
+	67108883:  _TagName[7611:7636],
+	67371008:  _TagName[7636:7649],
+	67371009:  _TagName[7649:7659],
+	67502080:  _TagName[7659:7680],
+	75497473:  _TagName[7680:7690],
+	75497474:  _TagName[7690:7700],
+	75497475:  _TagName[7700:7710],
+	75497476:  _TagName[7710:7720],
+	75497477:  _TagName[7720:7730],
+	75497478:  _TagName[7730:7740],
+	75497480:  _TagName[7740:7761],
+	75497481:  _TagName[7761:7779],
+	75497482:  _TagName[7779:7797],
+	75497483:  _TagName[7797:7815],
+	75497484:  _TagName[7815:7835],
+	75497485:  _TagName[7835:7850],
+	75497486:  _TagName[7850:7866],
+	75497487:  _TagName[7866:7876],
+	75497488:  _TagName[7876:7887],
+	75497489:  _TagName[7887:7897],
+	75497490:  _TagName[7897:7910],
+	75497491:  _TagName[7910:7931],
+	75497492:  _TagName[7931:7951],
+	75497493:  _TagName[7951:7974],
+	75759616:  _TagName[7974:7983],
+	75890688:  _TagName[7983:8000],
+	75890689:  _TagName[8000:8021],
+	75890690:  _TagName[8021:8040],
+	75890691:  _TagName[8040:8062],
+	83886079:  _TagName[8062:8080],
+	83886081:  _TagName[8080:8095],
+	83886082:  _TagName[8095:8110],
+	83886083:  _TagName[8110:8125],
+	83886084:  _TagName[8125:8145],
+	83886085:  _TagName[8145:8156],
+	83886086:  _TagName[8156:8172],
+	83886087:  _TagName[8172:8188],
+	83886088:  _TagName[8188:8204],
+	83886089:  _TagName[8204:8220],
+	83886090:  _TagName[8220:8237],
+	83886091:  _TagName[8237:8265],
+	83886092:  _TagName[8265:8288],
+	83886097:  _TagName[8288:8305],
+	83886098:  _TagName[8305:8322],
+	83886099:  _TagName[8322:8339],
+	83886100:  _TagName[8339:8350],
+	83886104:  _TagName[8350:8378],
+	84148224:  _TagName[8378:8389],
+	84148225:  _TagName[8389:8397],
+	84279296:  _TagName[8397:8416],
+	92274689:  _TagName[8416:8427],
+	92274690:  _TagName[8427:8438],
+	92274691:  _TagName[8438:8449],
+	92274692:  _TagName[8449:8465],
+	92274693:  _TagName[8465:8472],
+	92274694:  _TagName[8472:8484],
+	92274695:  _TagName[8484:8496],
+	92274696:  _TagName[8496:8508],
+	92274697:  _TagName[8508:8520],
+	92274698:  _TagName[8520:8533],
+	92274699:  _TagName[8533:8557],
+	92274700:  _TagName[8557:8576],
+	92274705:  _TagName[8576:8589],
+	92274706:  _TagName[8589:8602],
+	92274707:  _TagName[8602:8615],
+	92274708:  _TagName[8615:8622],
+	92274712:  _TagName[8622:8646],
+	92274769:  _TagName[8646:8662],
+	92274770:  _TagName[8662:8677],
+	92274771:  _TagName[8677:8698],
+	92274772:  _TagName[8698:8719],
+	92274773:  _TagName[8719:8739],
+	92274774:  _TagName[8739:8759],
+	92274775:  _TagName[8759:8779],
+	92274776:  _TagName[8779:8799],
+	92274777:  _TagName[8799:8815],
+	92274778:  _TagName[8815:8830],
+	92536832:  _TagName[8830:8837],
+	92667904:  _TagName[8837:8852],
+	92667905:  _TagName[8852:8871],
+	92667906:  _TagName[8871:8888],
+	92667907:  _TagName[8888:8908],
+	100663295: _TagName[8908:8924],
+	100663552: _TagName[8924:8947],
+	100663553: _TagName[8947:8972],
+	100663554: _TagName[8972:9000],
+	100663555: _TagName[9000:9024],
+	100663808: _TagName[9024:9048],
+	100664064: _TagName[9048:9073],
+	100664320: _TagName[9073:9097],
+	109051905: _TagName[9097:9111],
+	109051906: _TagName[9111:9126],
+	109051907: _TagName[9126:9142],
+	109051908: _TagName[9142:9153],
+	109051909: _TagName[9153:9169],
+	109051910: _TagName[9169:9186],
+	109051911: _TagName[9186:9200],
+	109051912: _TagName[9200:9213],
+	109051913: _TagName[9213:9226],
+	109051914: _TagName[9226:9245],
+	109051915: _TagName[9245:9263],
+	109051916: _TagName[9263:9285],
+	109051917: _TagName[9285:9295],
+	109051920: _TagName[9295:9311],
+	109051936: _TagName[9311:9329],
+	109052160: _TagName[9329:9348],
+	109052416: _TagName[9348:9368],
+	109052672: _TagName[9368:9389],
+	109052928: _TagName[9389:9409],
+	112197632: _TagName[9409:9424],
+	112197633: _TagName[9424:9439],
+	112197634: _TagName[9439:9451],
+	112197635: _TagName[9451:9466],
+	112197636: _TagName[9466:9480],
+	112197637: _TagName[9480:9494],
+	112197638: _TagName[9494:9511],
+	112197639: _TagName[9511:9524],
+	112197640: _TagName[9524:9540],
+	112197641: _TagName[9540:9555],
+	134217729: _TagName[9555:9572],
+	134217730: _TagName[9572:9588],
+	142606337: _TagName[9588:9601],
+	142606338: _TagName[9601:9613],
+	150994943: _TagName[9613:9630],
+	150994945: _TagName[9630:9651],
+	150994960: _TagName[9651:9673],
+	150994976: _TagName[9673:9692],
+	150994992: _TagName[9692:9714],
+	150995008: _TagName[9714:9737],
+	150995009: _TagName[9737:9751],
+	150995024: _TagName[9751:9777],
+	150995040: _TagName[9777:9812],
+	151388160: _TagName[9812:9831],
+	159383553: _TagName[9831:9848],
+	159383554: _TagName[9848:9860],
+	159383555: _TagName[9860:9878],
+	159383556: _TagName[9878:9895],
+	159383557: _TagName[9895:9912],
+	159383558: _TagName[9912:9937],
+	159383559: _TagName[9937:9961],
+	159383560: _TagName[9961:9990],
+	159383561: _TagName[9990:10020],
+	159383568: _TagName[10020:10038],
+	159383569: _TagName[10038:10056],
+	159383570: _TagName[10056:10073],
+	159383571: _TagName[10073:10101],
+	159383572: _TagName[10101:10125],
+	159383573: _TagName[10125:10152],
+	159383574: _TagName[10152:10179],
+	159383584: _TagName[10179:10194],
+	159383600: _TagName[10194:10212],
+	159383616: _TagName[10212:10231],
+	159383632: _TagName[10231:10253],
+	159383648: _TagName[10253:10284],
+	159776768: _TagName[10284:10299],
+	159776769: _TagName[10299:10318],
+	159776770: _TagName[10318:10335],
+	159776771: _TagName[10335:10355],
+	167772159: _TagName[10355:10371],
+	167772161: _TagName[10371:10393],
+	167772162: _TagName[10393:10417],
+	167772163: _TagName[10417:10445],
+	167772167: _TagName[10445:10471],
+	167772168: _TagName[10471:10490],
+	167772169: _TagName[10490:10510],
+	167772170: _TagName[10510:10530],
+	167772171: _TagName[10530:10546],
+	167772172: _TagName[10546:10558],
+	167772173: _TagName[10558:10578],
+	167772174: _TagName[10578:10591],
+	167772175: _TagName[10591:10608],
+	167772176: _TagName[10608:10626],
+	167772177: _TagName[10626:10639],
+	167772178: _TagName[10639:10662],
+	167772179: _TagName[10662:10686],
+	167772180: _TagName[10686:10710],
+	167772181: _TagName[10710:10730],
+	167772182: _TagName[10730:10746],
+	167772184: _TagName[10746:10768],
+	167772185: _TagName[10768:10787],
+	176160769: _TagName[10787:10805],
+	176160770: _TagName[10805:10825],
+	176160771: _TagName[10825:10849],
+	176160772: _TagName[10849:10871],
+	176160773: _TagName[10871:10882],
+	176160774: _TagName[10882:10894],
+	176160775: _TagName[10894:10916],
+	176160776: _TagName[10916:10931],
+	176160777: _TagName[10931:10947],
+	176160778: _TagName[10947:10963],
+	176160779: _TagName[10963:10975],
+	176160780: _TagName[10975:10983],
+	176160781: _TagName[10983:10999],
+	176160782: _TagName[10999:11008],
+	176160783: _TagName[11008:11021],
+	176160784: _TagName[11021:11035],
+	176160785: _TagName[11035:11044],
+	176160786: _TagName[11044:11063],
+	176160787: _TagName[11063:11083],
+	176160788: _TagName[11083:11103],
+	176160789: _TagName[11103:11119],
+	176160790: _TagName[11119:11131],
+	176160791: _TagName[11131:11144],
+	176160792: _TagName[11144:11162],
+	176160793: _TagName[11162:11177],
+	184549375: _TagName[11177:11195],
+	184549379: _TagName[11195:11221],
+	184549380: _TagName[11221:11245],
+	184549381: _TagName[11245:11266],
+	184549382: _TagName[11266:11289],
+	184549383: _TagName[11289:11307],
+	192937987: _TagName[11307:11329],
+	192937988: _TagName[11329:11349],
+	192937989: _TagName[11349:11366],
+	192937990: _TagName[11366:11385],
+	192937991: _TagName[11385:11399],
+	201326591: _TagName[11399:11415],
+	201326593: _TagName[11415:11436],
+	201326594: _TagName[11436:11463],
+	201326595: _TagName[11463:11490],
+	209715201: _TagName[11490:11507],
+	209715202: _TagName[11507:11530],
+	209715203: _TagName[11530:11553],
+	209715217: _TagName[11553:11568],
+	218103807: _TagName[11568:11585],
+	218103809: _TagName[11585:11605],
+	218103811: _TagName[11605:11629],
+	226492417: _TagName[11629:11645],
+	226492419: _TagName[11645:11665],
+	234881023: _TagName[11665:11681],
+	234881025: _TagName[11681:11698],
+	234881026: _TagName[11698:11717],
+	234881027: _TagName[11717:11727],
+	234881028: _TagName[11727:11740],
+	234881029: _TagName[11740:11757],
+	234881030: _TagName[11757:11768],
+	234881031: _TagName[11768:11787],
+	234881032: _TagName[11787:11813],
+	234881033: _TagName[11813:11830],
+	234881034: _TagName[11830:11850],
+	234881035: _TagName[11850:11866],
+	234881036: _TagName[11866:11884],
+	234881037: _TagName[11884:11902],
+	234881038: _TagName[11902:11920],
+	234881039: _TagName[11920:11943],
+	234881041: _TagName[11943:11957],
+	234881042: _TagName[11957:11976],
+	234881043: _TagName[11976:11995],
+	234881044: _TagName[11995:12014],
+	234881045: _TagName[12014:12033],
+	234881046: _TagName[12033:12053],
+	234881047: _TagName[12053:12079],
+	234881055: _TagName[12079:12096],
+	234881056: _TagName[12096:12116],
+	234881057: _TagName[12116:12134],
+	234881058: _TagName[12134:12152],
+	234881059: _TagName[12152:12170],
+	234881065: _TagName[12170:12192],
+	234881072: _TagName[12192:12207],
+	234881073: _TagName[12207:12218],
+	235143168: _TagName[12218:12229],
+	235143169: _TagName[12229:12237],
+	235143217: _TagName[12237:12255],
+	235143218: _TagName[12255:12280],
+	235147264: _TagName[12280:12308],
+	235147265: _TagName[12308:12316],
+	235147266: _TagName[12316:12329],
+	235147280: _TagName[12329:12346],
+	235147281: _TagName[12346:12368],
+	235147282: _TagName[12368:12390],
+	235147283: _TagName[12390:12412],
+	235147284: _TagName[12412:12434],
+	235147285: _TagName[12434:12457],
+	235147288: _TagName[12457:12475],
+	235147289: _TagName[12475:12493],
+	235147290: _TagName[12493:12507],
+	235147291: _TagName[12507:12521],
+	235151376: _TagName[12521:12535],
+	235151377: _TagName[12535:12553],
+	235274240: _TagName[12553:12572],
+	243269633: _TagName[12572:12585],
+	243269634: _TagName[12585:12600],
+	243269635: _TagName[12600:12606],
+	243269636: _TagName[12606:12615],
+	243269637: _TagName[12615:12628],
+	243269638: _TagName[12628:12635],
+	243269639: _TagName[12635:12650],
+	243269640: _TagName[12650:12672],
+	243269641: _TagName[12672:12685],
+	243269642: _TagName[12685:12701],
+	243269643: _TagName[12701:12713],
+	243269644: _TagName[12713:12727],
+	243269645: _TagName[12727:12741],
+	243269646: _TagName[12741:12755],
+	243269647: _TagName[12755:12774],
+	243269649: _TagName[12774:12784],
+	243269650: _TagName[12784:12799],
+	243269651: _TagName[12799:12814],
+	243269652: _TagName[12814:12829],
+	243269653: _TagName[12829:12844],
+	243269654: _TagName[12844:12860],
+	243269655: _TagName[12860:12882],
+	243269663: _TagName[12882:12895],
+	243269664: _TagName[12895:12911],
+	243269665: _TagName[12911:12925],
+	243269666: _TagName[12925:12939],
+	243269667: _TagName[12939:12953],
+	243269673: _TagName[12953:12971],
+	243269680: _TagName[12971:12999],
+	243269681: _TagName[12999:13025],
+	243269682: _TagName[13025:13054],
+	243531776: _TagName[13054:13061],
+	243535872: _TagName[13061:13085],
+	243535888: _TagName[13085:13098],
+	243535889: _TagName[13098:13116],
+	243535890: _TagName[13116:13134],
+	243535891: _TagName[13134:13152],
+	243535892: _TagName[13152:13170],
+	243535893: _TagName[13170:13189],
+	243535896: _TagName[13189:13203],
+	243535897: _TagName[13203:13217],
+	243535898: _TagName[13217:13231],
+	243535899: _TagName[13231:13245],
+	243662848: _TagName[13245:13260],
+	243662849: _TagName[13260:13279],
+	243662850: _TagName[13279:13296],
+	243662851: _TagName[13296:13316],
+	251658239: _TagName[13316:13332],
 }
 
 func (i Tag) String() string {
@@ -703,7 +705,7 @@ func (i Tag) String() string {
 	return fmt.Sprintf("Tag(%d)", i)
 }
 
-var _TagValues = []Tag{1, 2, 3, 4, 5, 8388609, 8388612, 8388613, 16777215, 16777217, 16777218, 16777219, 16777220, 16777221, 16777222, 16777223, 16777224, 16777225, 16777226, 16777233, 16777234, 16777235, 16777236, 16777237, 16777238, 16777239, 16777240, 16777241, 16777242, 16777243, 16777244, 16777245, 16777246, 16777247, 16777248, 16777249, 16777264, 16777265, 16777266, 16777280, 16777281, 16777282, 16777283, 16777284, 16777285, 16777286, 16777287, 16777288, 16777312, 16777329, 16777330, 16777331, 16777332, 16777333, 16777334, 16777335, 16777336, 16777337, 16777344, 16777345, 16777346, 16777347, 16777348, 16777349, 16777350, 16777351, 16777352, 16777353, 16777354, 16777355, 16777356, 16777357, 16777358, 16777359, 16777360, 16777361, 16777362, 16777363, 16777364, 16777365, 16777366, 16777367, 16777368, 16777369, 16777370, 16777371, 16777372, 16777373, 16777374, 16777472, 16777473, 16777474, 16777475, 16777476, 16777477, 16777478, 16777552, 16777553, 16777554, 16777555, 17104896, 25165825, 25165826, 25165827, 25165828, 25165829, 25165830, 25165831, 25165832, 25165833, 25165834, 25165835, 25165836, 25165837, 25165838, 25165839, 25165840, 25165841, 25165842, 25165843, 25165844, 25165845, 25165846, 25165847, 25165848, 25165849, 25165850, 25165851, 25165852, 25165853, 25165854, 25165855, 25165856, 25165857, 25165872, 25165888, 25165889, 25165890, 25165891, 25165892, 25165893, 25165894, 25165895, 25165896, 25165920, 25165937, 25165938, 25165939, 25165940, 25165941, 25165942, 25165943, 25165944, 25165945, 25165952, 25165953, 25165962, 25165963, 25165964, 25165965, 25165966, 25165967, 25165968, 25165969, 25165970, 25165971, 25165976, 25166080, 25166081, 25166082, 25166083, 25166084, 25166085, 25166086, 25493504, 33554431, 33554433, 33554434, 33554435, 33554441, 33554528, 33554529, 33554530, 33554531, 33554532, 33554544, 33554546, 33554547, 33554548, 33554549, 33554560, 33554562, 33554563, 33554565, 33554567, 33554688, 33554689, 33554690, 33554691, 33816576, 33816577, 33816581, 33947648, 34257921, 34257922, 34257923, 34257924, 34258944, 34258945, 34258946, 34258947, 34258948, 34258949, 34258950, 34258951, 34258952, 34258953, 34455552, 34455553, 34455554, 34455555, 34455556, 34455557, 34455558, 34455559, 34455560, 34455561, 41943041, 41943042, 41943043, 41943047, 41943049, 41943136, 41943152, 41943168, 41943173, 41943175, 41943296, 41943297, 41943298, 41943299, 42205184, 42336256, 42336257, 42336258, 42336259, 42646529, 42646530, 42647552, 42647553, 42647554, 42647555, 42647556, 42647557, 42647558, 42647559, 42647560, 42647561, 42844160, 42844161, 42844162, 42844163, 42844164, 42844165, 42844166, 42844167, 42844168, 42844169, 50331647, 50331649, 50331650, 50331651, 50331652, 50331653, 50331654, 50331655, 50331656, 50331657, 50331658, 50331659, 50331660, 50331661, 50331670, 50331671, 50331678, 50331680, 50331681, 50593792, 50593793, 50724864, 58720257, 58720258, 58720259, 58720260, 58720261, 58720262, 58720263, 58720264, 58720265, 58720266, 58720267, 58720268, 58720269, 58720278, 58720279, 58720281, 58720283, 58720286, 58720288, 58720289, 58720512, 58720513, 58720514, 58720515, 58720516, 58720517, 58720518, 58720519, 58720520, 58720521, 58720528, 58720529, 58720530, 58720531, 58720532, 58720533, 58720534, 58720535, 58720536, 58720537, 58720544, 58720545, 58720546, 58720547, 58720548, 58982400, 59113472, 59113473, 59113474, 59113475, 67108863, 67108865, 67108866, 67108867, 67108868, 67108869, 67108870, 67108872, 67108873, 67108874, 67108875, 67108876, 67108878, 67108879, 67108880, 67108883, 67371008, 67371009, 67502080, 75497473, 75497474, 75497475, 75497476, 75497477, 75497478, 75497480, 75497481, 75497482, 75497483, 75497484, 75497485, 75497486, 75497487, 75497488, 75497489, 75497490, 75497491, 75497492, 75497493, 75759616, 75890688, 75890689, 75890690, 75890691, 83886079, 83886081, 83886082, 83886083, 83886084, 83886085, 83886086, 83886087, 83886088, 83886089, 83886090, 83886091, 83886092, 83886097, 83886098, 83886099, 83886100, 83886104, 84148224, 84148225, 84279296, 92274689, 92274690, 92274691, 92274692, 92274693, 92274694, 92274695, 92274696, 92274697, 92274698, 92274699, 92274700, 92274705, 92274706, 92274707, 92274708, 92274712, 92274769, 92274770, 92274771, 92274772, 92274773, 92274774, 92274775, 92274776, 92274777, 92274778, 92536832, 92667904, 92667905, 92667906, 92667907, 100663295, 100663552, 100663553, 100663554, 100663555, 100663808, 100664064, 100664320, 109051905, 109051906, 109051907, 109051908, 109051909, 109051910, 109051911, 109051912, 109051913, 109051914, 109051915, 109051916, 109051917, 109051920, 109051936, 109052160, 109052416, 109052672, 109052928, 112197632, 112197633, 112197634, 112197635, 112197636, 112197637, 112197638, 112197639, 112197640, 112197641, 134217729, 134217730, 142606337, 142606338, 150994943, 150994945, 150994960, 150994976, 150994992, 150995008, 150995009, 150995024, 150995040, 151388160, 159383553, 159383554, 159383555, 159383556, 159383557, 159383558, 159383559, 159383560, 159383561, 159383568, 159383569, 159383570, 159383571, 159383572, 159383573, 159383574, 159383584, 159383600, 159383616, 159383632, 159383648, 159776768, 159776769, 159776770, 159776771, 167772159, 167772161, 167772162, 167772163, 167772167, 167772168, 167772169, 167772170, 167772171, 167772172, 167772173, 167772174, 167772175, 167772176, 167772177, 167772178, 167772179, 167772180, 167772181, 167772182, 167772184, 167772185, 176160769, 176160770, 176160771, 176160772, 176160773, 176160774, 176160775, 176160776, 176160777, 176160778, 176160779, 176160780, 176160781, 176160782, 176160783, 176160784, 176160785, 176160786, 176160787, 176160788, 176160789, 176160790, 176160791, 176160792, 176160793, 184549375, 184549379, 184549380, 184549381, 184549382, 184549383, 192937987, 192937988, 192937989, 192937990, 192937991, 201326591, 201326593, 201326594, 201326595, 209715201, 209715202, 209715203, 209715217, 218103807, 218103809, 218103811, 226492417, 226492419, 234881023, 234881025, 234881026, 234881027, 234881028, 234881029, 234881030, 234881031, 234881032, 234881033, 234881034, 234881035, 234881036, 234881037, 234881038, 234881039, 234881041, 234881042, 234881043, 234881044, 234881045, 234881046, 234881047, 234881055, 234881056, 234881057, 234881058, 234881059, 234881065, 234881072, 234881073, 235143168, 235143169, 235143217, 235143218, 235147264, 235147265, 235147266, 235147280, 235147281, 235147282, 235147283, 235147284, 235147285, 235147288, 235147289, 235147290, 235147291, 235151376, 235151377, 235274240, 243269633, 243269634, 243269635, 243269636, 243269637, 243269638, 243269639, 243269640, 243269641, 243269642, 243269643, 243269644, 243269645, 243269646, 243269647, 243269649, 243269650, 243269651, 243269652, 243269653, 243269654, 243269655, 243269663, 243269664, 243269665, 243269666, 243269667, 243269673, 243269680, 243269681, 243269682, 243531776, 243535872, 243535888, 243535889, 243535890, 243535891, 243535892, 243535893, 243535896, 243535897, 243535898, 243535899, 243662848, 243662849, 243662850, 243662851, 251658239}
+var _TagValues = []Tag{1, 2, 3, 4, 5, 8388609, 8388612, 8388613, 16777215, 16777217, 16777218, 16777219, 16777220, 16777221, 16777222, 16777223, 16777224, 16777225, 16777226, 16777233, 16777234, 16777235, 16777236, 16777237, 16777238, 16777239, 16777240, 16777241, 16777242, 16777243, 16777244, 16777245, 16777246, 16777247, 16777248, 16777249, 16777264, 16777265, 16777266, 16777280, 16777281, 16777282, 16777283, 16777284, 16777285, 16777286, 16777287, 16777288, 16777312, 16777329, 16777330, 16777331, 16777332, 16777333, 16777334, 16777335, 16777336, 16777337, 16777344, 16777345, 16777346, 16777347, 16777348, 16777349, 16777350, 16777351, 16777352, 16777353, 16777354, 16777355, 16777356, 16777357, 16777358, 16777359, 16777360, 16777361, 16777362, 16777363, 16777364, 16777365, 16777366, 16777367, 16777368, 16777369, 16777370, 16777371, 16777372, 16777373, 16777374, 16777472, 16777473, 16777474, 16777475, 16777476, 16777477, 16777478, 16777552, 16777553, 16777554, 16777555, 17104896, 25165825, 25165826, 25165827, 25165828, 25165829, 25165830, 25165831, 25165832, 25165833, 25165834, 25165835, 25165836, 25165837, 25165838, 25165839, 25165840, 25165841, 25165842, 25165843, 25165844, 25165845, 25165846, 25165847, 25165848, 25165849, 25165850, 25165851, 25165852, 25165853, 25165854, 25165855, 25165856, 25165857, 25165872, 25165888, 25165889, 25165890, 25165891, 25165892, 25165893, 25165894, 25165895, 25165896, 25165920, 25165937, 25165938, 25165939, 25165940, 25165941, 25165942, 25165943, 25165944, 25165945, 25165952, 25165953, 25165962, 25165963, 25165964, 25165965, 25165966, 25165967, 25165968, 25165969, 25165970, 25165971, 25165976, 25166080, 25166081, 25166082, 25166083, 25166084, 25166085, 25166086, 25493504, 33554431, 33554433, 33554434, 33554435, 33554441, 33554528, 33554529, 33554530, 33554531, 33554532, 33554544, 33554546, 33554547, 33554548, 33554549, 33554560, 33554562, 33554563, 33554565, 33554567, 33554688, 33554689, 33554690, 33554691, 33816576, 33816577, 33816581, 33947648, 34257921, 34257922, 34257923, 34257924, 34258944, 34258945, 34258946, 34258947, 34258948, 34258949, 34258950, 34258951, 34258952, 34258953, 34455552, 34455553, 34455554, 34455555, 34455556, 34455557, 34455558, 34455559, 34455560, 34455561, 41943041, 41943042, 41943043, 41943047, 41943049, 41943136, 41943152, 41943168, 41943173, 41943175, 41943296, 41943297, 41943298, 41943299, 42205184, 42336256, 42336257, 42336258, 42336259, 42646529, 42646530, 42647552, 42647553, 42647554, 42647555, 42647556, 42647557, 42647558, 42647559, 42647560, 42647561, 42844160, 42844161, 42844162, 42844163, 42844164, 42844165, 42844166, 42844167, 42844168, 42844169, 50331647, 50331649, 50331650, 50331651, 50331652, 50331653, 50331654, 50331655, 50331656, 50331657, 50331658, 50331659, 50331660, 50331661, 50331670, 50331671, 50331678, 50331680, 50331681, 50331714, 50593792, 50593793, 50724864, 58720257, 58720258, 58720259, 58720260, 58720261, 58720262, 58720263, 58720264, 58720265, 58720266, 58720267, 58720268, 58720269, 58720278, 58720279, 58720281, 58720283, 58720286, 58720288, 58720289, 58720322, 58720512, 58720513, 58720514, 58720515, 58720516, 58720517, 58720518, 58720519, 58720520, 58720521, 58720528, 58720529, 58720530, 58720531, 58720532, 58720533, 58720534, 58720535, 58720536, 58720537, 58720544, 58720545, 58720546, 58720547, 58720548, 58982400, 59113472, 59113473, 59113474, 59113475, 67108863, 67108865, 67108866, 67108867, 67108868, 67108869, 67108870, 67108872, 67108873, 67108874, 67108875, 67108876, 67108878, 67108879, 67108880, 67108883, 67371008, 67371009, 67502080, 75497473, 75497474, 75497475, 75497476, 75497477, 75497478, 75497480, 75497481, 75497482, 75497483, 75497484, 75497485, 75497486, 75497487, 75497488, 75497489, 75497490, 75497491, 75497492, 75497493, 75759616, 75890688, 75890689, 75890690, 75890691, 83886079, 83886081, 83886082, 83886083, 83886084, 83886085, 83886086, 83886087, 83886088, 83886089, 83886090, 83886091, 83886092, 83886097, 83886098, 83886099, 83886100, 83886104, 84148224, 84148225, 84279296, 92274689, 92274690, 92274691, 92274692, 92274693, 92274694, 92274695, 92274696, 92274697, 92274698, 92274699, 92274700, 92274705, 92274706, 92274707, 92274708, 92274712, 92274769, 92274770, 92274771, 92274772, 92274773, 92274774, 92274775, 92274776, 92274777, 92274778, 92536832, 92667904, 92667905, 92667906, 92667907, 100663295, 100663552, 100663553, 100663554, 100663555, 100663808, 100664064, 100664320, 109051905, 109051906, 109051907, 109051908, 109051909, 109051910, 109051911, 109051912, 109051913, 109051914, 109051915, 109051916, 109051917, 109051920, 109051936, 109052160, 109052416, 109052672, 109052928, 112197632, 112197633, 112197634, 112197635, 112197636, 112197637, 112197638, 112197639, 112197640, 112197641, 134217729, 134217730, 142606337, 142606338, 150994943, 150994945, 150994960, 150994976, 150994992, 150995008, 150995009, 150995024, 150995040, 151388160, 159383553, 159383554, 159383555, 159383556, 159383557, 159383558, 159383559, 159383560, 159383561, 159383568, 159383569, 159383570, 159383571, 159383572, 159383573, 159383574, 159383584, 159383600, 159383616, 159383632, 159383648, 159776768, 159776769, 159776770, 159776771, 167772159, 167772161, 167772162, 167772163, 167772167, 167772168, 167772169, 167772170, 167772171, 167772172, 167772173, 167772174, 167772175, 167772176, 167772177, 167772178, 167772179, 167772180, 167772181, 167772182, 167772184, 167772185, 176160769, 176160770, 176160771, 176160772, 176160773, 176160774, 176160775, 176160776, 176160777, 176160778, 176160779, 176160780, 176160781, 176160782, 176160783, 176160784, 176160785, 176160786, 176160787, 176160788, 176160789, 176160790, 176160791, 176160792, 176160793, 184549375, 184549379, 184549380, 184549381, 184549382, 184549383, 192937987, 192937988, 192937989, 192937990, 192937991, 201326591, 201326593, 201326594, 201326595, 209715201, 209715202, 209715203, 209715217, 218103807, 218103809, 218103811, 226492417, 226492419, 234881023, 234881025, 234881026, 234881027, 234881028, 234881029, 234881030, 234881031, 234881032, 234881033, 234881034, 234881035, 234881036, 234881037, 234881038, 234881039, 234881041, 234881042, 234881043, 234881044, 234881045, 234881046, 234881047, 234881055, 234881056, 234881057, 234881058, 234881059, 234881065, 234881072, 234881073, 235143168, 235143169, 235143217, 235143218, 235147264, 235147265, 235147266, 235147280, 235147281, 235147282, 235147283, 235147284, 235147285, 235147288, 235147289, 235147290, 235147291, 235151376, 235151377, 235274240, 243269633, 243269634, 243269635, 243269636, 243269637, 243269638, 243269639, 243269640, 243269641, 243269642, 243269643, 243269644, 243269645, 243269646, 243269647, 243269649, 243269650, 243269651, 243269652, 243269653, 243269654, 243269655, 243269663, 243269664, 243269665, 243269666, 243269667, 243269673, 243269680, 243269681, 243269682, 243531776, 243535872, 243535888, 243535889, 243535890, 243535891, 243535892, 243535893, 243535896, 243535897, 243535898, 243535899, 243662848, 243662849, 243662850, 243662851, 251658239}
 
 var _TagNameToValueMap = map[string]Tag{
 	_TagName[0:23]:        1,
@@ -993,402 +995,404 @@ var _TagNameToValueMap = map[string]Tag{
 	_TagName[6224:6250]:   50331678,
 	_TagName[6250:6262]:   50331680,
 	_TagName[6262:6283]:   50331681,
-	_TagName[6283:6295]:   50593792,
-	_TagName[6295:6304]:   50593793,
-	_TagName[6304:6324]:   50724864,
-	_TagName[6324:6332]:   58720257,
-	_TagName[6332:6350]:   58720258,
-	_TagName[6350:6361]:   58720259,
-	_TagName[6361:6380]:   58720260,
-	_TagName[6380:6402]:   58720261,
-	_TagName[6402:6417]:   58720262,
-	_TagName[6417:6442]:   58720263,
-	_TagName[6442:6459]:   58720264,
-	_TagName[6459:6479]:   58720265,
-	_TagName[6479:6494]:   58720266,
-	_TagName[6494:6508]:   58720267,
-	_TagName[6508:6523]:   58720268,
-	_TagName[6523:6536]:   58720269,
-	_TagName[6536:6564]:   58720278,
-	_TagName[6564:6592]:   58720279,
-	_TagName[6592:6616]:   58720281,
-	_TagName[6616:6636]:   58720283,
-	_TagName[6636:6658]:   58720286,
-	_TagName[6658:6666]:   58720288,
-	_TagName[6666:6683]:   58720289,
-	_TagName[6683:6696]:   58720512,
-	_TagName[6696:6726]:   58720513,
-	_TagName[6726:6752]:   58720514,
-	_TagName[6752:6778]:   58720515,
-	_TagName[6778:6802]:   58720516,
-	_TagName[6802:6831]:   58720517,
-	_TagName[6831:6859]:   58720518,
-	_TagName[6859:6885]:   58720519,
-	_TagName[6885:6896]:   58720520,
-	_TagName[6896:6907]:   58720521,
-	_TagName[6907:6926]:   58720528,
-	_TagName[6926:6941]:   58720529,
-	_TagName[6941:6956]:   58720530,
-	_TagName[6956:6979]:   58720531,
-	_TagName[6979:7002]:   58720532,
-	_TagName[7002:7025]:   58720533,
-	_TagName[7025:7047]:   58720534,
-	_TagName[7047:7077]:   58720535,
-	_TagName[7077:7108]:   58720536,
-	_TagName[7108:7132]:   58720537,
-	_TagName[7132:7148]:   58720544,
-	_TagName[7148:7172]:   58720545,
-	_TagName[7172:7190]:   58720546,
-	_TagName[7190:7216]:   58720547,
-	_TagName[7216:7235]:   58720548,
-	_TagName[7235:7243]:   58982400,
-	_TagName[7243:7259]:   59113472,
-	_TagName[7259:7279]:   59113473,
-	_TagName[7279:7297]:   59113474,
-	_TagName[7297:7318]:   59113475,
-	_TagName[7318:7335]:   67108863,
-	_TagName[7335:7349]:   67108865,
-	_TagName[7349:7363]:   67108866,
-	_TagName[7363:7377]:   67108867,
-	_TagName[7377:7391]:   67108868,
-	_TagName[7391:7405]:   67108869,
-	_TagName[7405:7419]:   67108870,
-	_TagName[7419:7444]:   67108872,
-	_TagName[7444:7466]:   67108873,
-	_TagName[7466:7488]:   67108874,
-	_TagName[7488:7510]:   67108875,
-	_TagName[7510:7534]:   67108876,
-	_TagName[7534:7554]:   67108878,
-	_TagName[7554:7568]:   67108879,
-	_TagName[7568:7583]:   67108880,
-	_TagName[7583:7608]:   67108883,
-	_TagName[7608:7621]:   67371008,
-	_TagName[7621:7631]:   67371009,
-	_TagName[7631:7652]:   67502080,
-	_TagName[7652:7662]:   75497473,
-	_TagName[7662:7672]:   75497474,
-	_TagName[7672:7682]:   75497475,
-	_TagName[7682:7692]:   75497476,
-	_TagName[7692:7702]:   75497477,
-	_TagName[7702:7712]:   75497478,
-	_TagName[7712:7733]:   75497480,
-	_TagName[7733:7751]:   75497481,
-	_TagName[7751:7769]:   75497482,
-	_TagName[7769:7787]:   75497483,
-	_TagName[7787:7807]:   75497484,
-	_TagName[7807:7822]:   75497485,
-	_TagName[7822:7838]:   75497486,
-	_TagName[7838:7848]:   75497487,
-	_TagName[7848:7859]:   75497488,
-	_TagName[7859:7869]:   75497489,
-	_TagName[7869:7882]:   75497490,
-	_TagName[7882:7903]:   75497491,
-	_TagName[7903:7923]:   75497492,
-	_TagName[7923:7946]:   75497493,
-	_TagName[7946:7955]:   75759616,
-	_TagName[7955:7972]:   75890688,
-	_TagName[7972:7993]:   75890689,
-	_TagName[7993:8012]:   75890690,
-	_TagName[8012:8034]:   75890691,
-	_TagName[8034:8052]:   83886079,
-	_TagName[8052:8067]:   83886081,
-	_TagName[8067:8082]:   83886082,
-	_TagName[8082:8097]:   83886083,
-	_TagName[8097:8117]:   83886084,
-	_TagName[8117:8128]:   83886085,
-	_TagName[8128:8144]:   83886086,
-	_TagName[8144:8160]:   83886087,
-	_TagName[8160:8176]:   83886088,
-	_TagName[8176:8192]:   83886089,
-	_TagName[8192:8209]:   83886090,
-	_TagName[8209:8237]:   83886091,
-	_TagName[8237:8260]:   83886092,
-	_TagName[8260:8277]:   83886097,
-	_TagName[8277:8294]:   83886098,
-	_TagName[8294:8311]:   83886099,
-	_TagName[8311:8322]:   83886100,
-	_TagName[8322:8350]:   83886104,
-	_TagName[8350:8361]:   84148224,
-	_TagName[8361:8369]:   84148225,
-	_TagName[8369:8388]:   84279296,
-	_TagName[8388:8399]:   92274689,
-	_TagName[8399:8410]:   92274690,
-	_TagName[8410:8421]:   92274691,
-	_TagName[8421:8437]:   92274692,
-	_TagName[8437:8444]:   92274693,
-	_TagName[8444:8456]:   92274694,
-	_TagName[8456:8468]:   92274695,
-	_TagName[8468:8480]:   92274696,
-	_TagName[8480:8492]:   92274697,
-	_TagName[8492:8505]:   92274698,
-	_TagName[8505:8529]:   92274699,
-	_TagName[8529:8548]:   92274700,
-	_TagName[8548:8561]:   92274705,
-	_TagName[8561:8574]:   92274706,
-	_TagName[8574:8587]:   92274707,
-	_TagName[8587:8594]:   92274708,
-	_TagName[8594:8618]:   92274712,
-	_TagName[8618:8634]:   92274769,
-	_TagName[8634:8649]:   92274770,
-	_TagName[8649:8670]:   92274771,
-	_TagName[8670:8691]:   92274772,
-	_TagName[8691:8711]:   92274773,
-	_TagName[8711:8731]:   92274774,
-	_TagName[8731:8751]:   92274775,
-	_TagName[8751:8771]:   92274776,
-	_TagName[8771:8787]:   92274777,
-	_TagName[8787:8802]:   92274778,
-	_TagName[8802:8809]:   92536832,
-	_TagName[8809:8824]:   92667904,
-	_TagName[8824:8843]:   92667905,
-	_TagName[8843:8860]:   92667906,
-	_TagName[8860:8880]:   92667907,
-	_TagName[8880:8896]:   100663295,
-	_TagName[8896:8919]:   100663552,
-	_TagName[8919:8944]:   100663553,
-	_TagName[8944:8972]:   100663554,
-	_TagName[8972:8996]:   100663555,
-	_TagName[8996:9020]:   100663808,
-	_TagName[9020:9045]:   100664064,
-	_TagName[9045:9069]:   100664320,
-	_TagName[9069:9083]:   109051905,
-	_TagName[9083:9098]:   109051906,
-	_TagName[9098:9114]:   109051907,
-	_TagName[9114:9125]:   109051908,
-	_TagName[9125:9141]:   109051909,
-	_TagName[9141:9158]:   109051910,
-	_TagName[9158:9172]:   109051911,
-	_TagName[9172:9185]:   109051912,
-	_TagName[9185:9198]:   109051913,
-	_TagName[9198:9217]:   109051914,
-	_TagName[9217:9235]:   109051915,
-	_TagName[9235:9257]:   109051916,
-	_TagName[9257:9267]:   109051917,
-	_TagName[9267:9283]:   109051920,
-	_TagName[9283:9301]:   109051936,
-	_TagName[9301:9320]:   109052160,
-	_TagName[9320:9340]:   109052416,
-	_TagName[9340:9361]:   109052672,
-	_TagName[9361:9381]:   109052928,
-	_TagName[9381:9396]:   112197632,
-	_TagName[9396:9411]:   112197633,
-	_TagName[9411:9423]:   112197634,
-	_TagName[9423:9438]:   112197635,
-	_TagName[9438:9452]:   112197636,
-	_TagName[9452:9466]:   112197637,
-	_TagName[9466:9483]:   112197638,
-	_TagName[9483:9496]:   112197639,
-	_TagName[9496:9512]:   112197640,
-	_TagName[9512:9527]:   112197641,
-	_TagName[9527:9544]:   134217729,
-	_TagName[9544:9560]:   134217730,
-	_TagName[9560:9573]:   142606337,
-	_TagName[9573:9585]:   142606338,
-	_TagName[9585:9602]:   150994943,
-	_TagName[9602:9623]:   150994945,
-	_TagName[9623:9645]:   150994960,
-	_TagName[9645:9664]:   150994976,
-	_TagName[9664:9686]:   150994992,
-	_TagName[9686:9709]:   150995008,
-	_TagName[9709:9723]:   150995009,
-	_TagName[9723:9749]:   150995024,
-	_TagName[9749:9784]:   150995040,
-	_TagName[9784:9803]:   151388160,
-	_TagName[9803:9820]:   159383553,
-	_TagName[9820:9832]:   159383554,
-	_TagName[9832:9850]:   159383555,
-	_TagName[9850:9867]:   159383556,
-	_TagName[9867:9884]:   159383557,
-	_TagName[9884:9909]:   159383558,
-	_TagName[9909:9933]:   159383559,
-	_TagName[9933:9962]:   159383560,
-	_TagName[9962:9992]:   159383561,
-	_TagName[9992:10010]:  159383568,
-	_TagName[10010:10028]: 159383569,
-	_TagName[10028:10045]: 159383570,
-	_TagName[10045:10073]: 159383571,
-	_TagName[10073:10097]: 159383572,
-	_TagName[10097:10124]: 159383573,
-	_TagName[10124:10151]: 159383574,
-	_TagName[10151:10166]: 159383584,
-	_TagName[10166:10184]: 159383600,
-	_TagName[10184:10203]: 159383616,
-	_TagName[10203:10225]: 159383632,
-	_TagName[10225:10256]: 159383648,
-	_TagName[10256:10271]: 159776768,
-	_TagName[10271:10290]: 159776769,
-	_TagName[10290:10307]: 159776770,
-	_TagName[10307:10327]: 159776771,
-	_TagName[10327:10343]: 167772159,
-	_TagName[10343:10365]: 167772161,
-	_TagName[10365:10389]: 167772162,
-	_TagName[10389:10417]: 167772163,
-	_TagName[10417:10443]: 167772167,
-	_TagName[10443:10462]: 167772168,
-	_TagName[10462:10482]: 167772169,
-	_TagName[10482:10502]: 167772170,
-	_TagName[10502:10518]: 167772171,
-	_TagName[10518:10530]: 167772172,
-	_TagName[10530:10550]: 167772173,
-	_TagName[10550:10563]: 167772174,
-	_TagName[10563:10580]: 167772175,
-	_TagName[10580:10598]: 167772176,
-	_TagName[10598:10611]: 167772177,
-	_TagName[10611:10634]: 167772178,
-	_TagName[10634:10658]: 167772179,
-	_TagName[10658:10682]: 167772180,
-	_TagName[10682:10702]: 167772181,
-	_TagName[10702:10718]: 167772182,
-	_TagName[10718:10740]: 167772184,
-	_TagName[10740:10759]: 167772185,
-	_TagName[10759:10777]: 176160769,
-	_TagName[10777:10797]: 176160770,
-	_TagName[10797:10821]: 176160771,
-	_TagName[10821:10843]: 176160772,
-	_TagName[10843:10854]: 176160773,
-	_TagName[10854:10866]: 176160774,
-	_TagName[10866:10888]: 176160775,
-	_TagName[10888:10903]: 176160776,
-	_TagName[10903:10919]: 176160777,
-	_TagName[10919:10935]: 176160778,
-	_TagName[10935:10947]: 176160779,
-	_TagName[10947:10955]: 176160780,
-	_TagName[10955:10971]: 176160781,
-	_TagName[10971:10980]: 176160782,
-	_TagName[10980:10993]: 176160783,
-	_TagName[10993:11007]: 176160784,
-	_TagName[11007:11016]: 176160785,
-	_TagName[11016:11035]: 176160786,
-	_TagName[11035:11055]: 176160787,
-	_TagName[11055:11075]: 176160788,
-	_TagName[11075:11091]: 176160789,
-	_TagName[11091:11103]: 176160790,
-	_TagName[11103:11116]: 176160791,
-	_TagName[11116:11134]: 176160792,
-	_TagName[11134:11149]: 176160793,
-	_TagName[11149:11167]: 184549375,
-	_TagName[11167:11193]: 184549379,
-	_TagName[11193:11217]: 184549380,
-	_TagName[11217:11238]: 184549381,
-	_TagName[11238:11261]: 184549382,
-	_TagName[11261:11279]: 184549383,
-	_TagName[11279:11301]: 192937987,
-	_TagName[11301:11321]: 192937988,
-	_TagName[11321:11338]: 192937989,
-	_TagName[11338:11357]: 192937990,
-	_TagName[11357:11371]: 192937991,
-	_TagName[11371:11387]: 201326591,
-	_TagName[11387:11408]: 201326593,
-	_TagName[11408:11435]: 201326594,
-	_TagName[11435:11462]: 201326595,
-	_TagName[11462:11479]: 209715201,
-	_TagName[11479:11502]: 209715202,
-	_TagName[11502:11525]: 209715203,
-	_TagName[11525:11540]: 209715217,
-	_TagName[11540:11557]: 218103807,
-	_TagName[11557:11577]: 218103809,
-	_TagName[11577:11601]: 218103811,
-	_TagName[11601:11617]: 226492417,
-	_TagName[11617:11637]: 226492419,
-	_TagName[11637:11653]: 234881023,
-	_TagName[11653:11670]: 234881025,
-	_TagName[11670:11689]: 234881026,
-	_TagName[11689:11699]: 234881027,
-	_TagName[11699:11712]: 234881028,
-	_TagName[11712:11729]: 234881029,
-	_TagName[11729:11740]: 234881030,
-	_TagName[11740:11759]: 234881031,
-	_TagName[11759:11785]: 234881032,
-	_TagName[11785:11802]: 234881033,
-	_TagName[11802:11822]: 234881034,
-	_TagName[11822:11838]: 234881035,
-	_TagName[11838:11856]: 234881036,
-	_TagName[11856:11874]: 234881037,
-	_TagName[11874:11892]: 234881038,
-	_TagName[11892:11915]: 234881039,
-	_TagName[11915:11929]: 234881041,
-	_TagName[11929:11948]: 234881042,
-	_TagName[11948:11967]: 234881043,
-	_TagName[11967:11986]: 234881044,
-	_TagName[11986:12005]: 234881045,
-	_TagName[12005:12025]: 234881046,
-	_TagName[12025:12051]: 234881047,
-	_TagName[12051:12068]: 234881055,
-	_TagName[12068:12088]: 234881056,
-	_TagName[12088:12106]: 234881057,
-	_TagName[12106:12124]: 234881058,
-	_TagName[12124:12142]: 234881059,
-	_TagName[12142:12164]: 234881065,
-	_TagName[12164:12179]: 234881072,
-	_TagName[12179:12190]: 234881073,
-	_TagName[12190:12201]: 235143168,
-	_TagName[12201:12209]: 235143169,
-	_TagName[12209:12227]: 235143217,
-	_TagName[12227:12252]: 235143218,
-	_TagName[12252:12280]: 235147264,
-	_TagName[12280:12288]: 235147265,
-	_TagName[12288:12301]: 235147266,
-	_TagName[12301:12318]: 235147280,
-	_TagName[12318:12340]: 235147281,
-	_TagName[12340:12362]: 235147282,
-	_TagName[12362:12384]: 235147283,
-	_TagName[12384:12406]: 235147284,
-	_TagName[12406:12429]: 235147285,
-	_TagName[12429:12447]: 235147288,
-	_TagName[12447:12465]: 235147289,
-	_TagName[12465:12479]: 235147290,
-	_TagName[12479:12493]: 235147291,
-	_TagName[12493:12507]: 235151376,
-	_TagName[12507:12525]: 235151377,
-	_TagName[12525:12544]: 235274240,
-	_TagName[12544:12557]: 243269633,
-	_TagName[12557:12572]: 243269634,
-	_TagName[12572:12578]: 243269635,
-	_TagName[12578:12587]: 243269636,
-	_TagName[12587:12600]: 243269637,
-	_TagName[12600:12607]: 243269638,
-	_TagName[12607:12622]: 243269639,
-	_TagName[12622:12644]: 243269640,
-	_TagName[12644:12657]: 243269641,
-	_TagName[12657:12673]: 243269642,
-	_TagName[12673:12685]: 243269643,
-	_TagName[12685:12699]: 243269644,
-	_TagName[12699:12713]: 243269645,
-	_TagName[12713:12727]: 243269646,
-	_TagName[12727:12746]: 243269647,
-	_TagName[12746:12756]: 243269649,
-	_TagName[12756:12771]: 243269650,
-	_TagName[12771:12786]: 243269651,
-	_TagName[12786:12801]: 243269652,
-	_TagName[12801:12816]: 243269653,
-	_TagName[12816:12832]: 243269654,
-	_TagName[12832:12854]: 243269655,
-	_TagName[12854:12867]: 243269663,
-	_TagName[12867:12883]: 243269664,
-	_TagName[12883:12897]: 243269665,
-	_TagName[12897:12911]: 243269666,
-	_TagName[12911:12925]: 243269667,
-	_TagName[12925:12943]: 243269673,
-	_TagName[12943:12971]: 243269680,
-	_TagName[12971:12997]: 243269681,
-	_TagName[12997:13026]: 243269682,
-	_TagName[13026:13033]: 243531776,
-	_TagName[13033:13057]: 243535872,
-	_TagName[13057:13070]: 243535888,
-	_TagName[13070:13088]: 243535889,
-	_TagName[13088:13106]: 243535890,
-	_TagName[13106:13124]: 243535891,
-	_TagName[13124:13142]: 243535892,
-	_TagName[13142:13161]: 243535893,
-	_TagName[13161:13175]: 243535896,
-	_TagName[13175:13189]: 243535897,
-	_TagName[13189:13203]: 243535898,
-	_TagName[13203:13217]: 243535899,
-	_TagName[13217:13232]: 243662848,
-	_TagName[13232:13251]: 243662849,
-	_TagName[13251:13268]: 243662850,
-	_TagName[13268:13288]: 243662851,
-	_TagName[13288:13304]: 251658239,
+	_TagName[6283:6299]:   50331714,
+	_TagName[6299:6311]:   50593792,
+	_TagName[6311:6320]:   50593793,
+	_TagName[6320:6340]:   50724864,
+	_TagName[6340:6348]:   58720257,
+	_TagName[6348:6366]:   58720258,
+	_TagName[6366:6377]:   58720259,
+	_TagName[6377:6396]:   58720260,
+	_TagName[6396:6418]:   58720261,
+	_TagName[6418:6433]:   58720262,
+	_TagName[6433:6458]:   58720263,
+	_TagName[6458:6475]:   58720264,
+	_TagName[6475:6495]:   58720265,
+	_TagName[6495:6510]:   58720266,
+	_TagName[6510:6524]:   58720267,
+	_TagName[6524:6539]:   58720268,
+	_TagName[6539:6552]:   58720269,
+	_TagName[6552:6580]:   58720278,
+	_TagName[6580:6608]:   58720279,
+	_TagName[6608:6632]:   58720281,
+	_TagName[6632:6652]:   58720283,
+	_TagName[6652:6674]:   58720286,
+	_TagName[6674:6682]:   58720288,
+	_TagName[6682:6699]:   58720289,
+	_TagName[6699:6711]:   58720322,
+	_TagName[6711:6724]:   58720512,
+	_TagName[6724:6754]:   58720513,
+	_TagName[6754:6780]:   58720514,
+	_TagName[6780:6806]:   58720515,
+	_TagName[6806:6830]:   58720516,
+	_TagName[6830:6859]:   58720517,
+	_TagName[6859:6887]:   58720518,
+	_TagName[6887:6913]:   58720519,
+	_TagName[6913:6924]:   58720520,
+	_TagName[6924:6935]:   58720521,
+	_TagName[6935:6954]:   58720528,
+	_TagName[6954:6969]:   58720529,
+	_TagName[6969:6984]:   58720530,
+	_TagName[6984:7007]:   58720531,
+	_TagName[7007:7030]:   58720532,
+	_TagName[7030:7053]:   58720533,
+	_TagName[7053:7075]:   58720534,
+	_TagName[7075:7105]:   58720535,
+	_TagName[7105:7136]:   58720536,
+	_TagName[7136:7160]:   58720537,
+	_TagName[7160:7176]:   58720544,
+	_TagName[7176:7200]:   58720545,
+	_TagName[7200:7218]:   58720546,
+	_TagName[7218:7244]:   58720547,
+	_TagName[7244:7263]:   58720548,
+	_TagName[7263:7271]:   58982400,
+	_TagName[7271:7287]:   59113472,
+	_TagName[7287:7307]:   59113473,
+	_TagName[7307:7325]:   59113474,
+	_TagName[7325:7346]:   59113475,
+	_TagName[7346:7363]:   67108863,
+	_TagName[7363:7377]:   67108865,
+	_TagName[7377:7391]:   67108866,
+	_TagName[7391:7405]:   67108867,
+	_TagName[7405:7419]:   67108868,
+	_TagName[7419:7433]:   67108869,
+	_TagName[7433:7447]:   67108870,
+	_TagName[7447:7472]:   67108872,
+	_TagName[7472:7494]:   67108873,
+	_TagName[7494:7516]:   67108874,
+	_TagName[7516:7538]:   67108875,
+	_TagName[7538:7562]:   67108876,
+	_TagName[7562:7582]:   67108878,
+	_TagName[7582:7596]:   67108879,
+	_TagName[7596:7611]:   67108880,
+	_TagName[7611:7636]:   67108883,
+	_TagName[7636:7649]:   67371008,
+	_TagName[7649:7659]:   67371009,
+	_TagName[7659:7680]:   67502080,
+	_TagName[7680:7690]:   75497473,
+	_TagName[7690:7700]:   75497474,
+	_TagName[7700:7710]:   75497475,
+	_TagName[7710:7720]:   75497476,
+	_TagName[7720:7730]:   75497477,
+	_TagName[7730:7740]:   75497478,
+	_TagName[7740:7761]:   75497480,
+	_TagName[7761:7779]:   75497481,
+	_TagName[7779:7797]:   75497482,
+	_TagName[7797:7815]:   75497483,
+	_TagName[7815:7835]:   75497484,
+	_TagName[7835:7850]:   75497485,
+	_TagName[7850:7866]:   75497486,
+	_TagName[7866:7876]:   75497487,
+	_TagName[7876:7887]:   75497488,
+	_TagName[7887:7897]:   75497489,
+	_TagName[7897:7910]:   75497490,
+	_TagName[7910:7931]:   75497491,
+	_TagName[7931:7951]:   75497492,
+	_TagName[7951:7974]:   75497493,
+	_TagName[7974:7983]:   75759616,
+	_TagName[7983:8000]:   75890688,
+	_TagName[8000:8021]:   75890689,
+	_TagName[8021:8040]:   75890690,
+	_TagName[8040:8062]:   75890691,
+	_TagName[8062:8080]:   83886079,
+	_TagName[8080:8095]:   83886081,
+	_TagName[8095:8110]:   83886082,
+	_TagName[8110:8125]:   83886083,
+	_TagName[8125:8145]:   83886084,
+	_TagName[8145:8156]:   83886085,
+	_TagName[8156:8172]:   83886086,
+	_TagName[8172:8188]:   83886087,
+	_TagName[8188:8204]:   83886088,
+	_TagName[8204:8220]:   83886089,
+	_TagName[8220:8237]:   83886090,
+	_TagName[8237:8265]:   83886091,
+	_TagName[8265:8288]:   83886092,
+	_TagName[8288:8305]:   83886097,
+	_TagName[8305:8322]:   83886098,
+	_TagName[8322:8339]:   83886099,
+	_TagName[8339:8350]:   83886100,
+	_TagName[8350:8378]:   83886104,
+	_TagName[8378:8389]:   84148224,
+	_TagName[8389:8397]:   84148225,
+	_TagName[8397:8416]:   84279296,
+	_TagName[8416:8427]:   92274689,
+	_TagName[8427:8438]:   92274690,
+	_TagName[8438:8449]:   92274691,
+	_TagName[8449:8465]:   92274692,
+	_TagName[8465:8472]:   92274693,
+	_TagName[8472:8484]:   92274694,
+	_TagName[8484:8496]:   92274695,
+	_TagName[8496:8508]:   92274696,
+	_TagName[8508:8520]:   92274697,
+	_TagName[8520:8533]:   92274698,
+	_TagName[8533:8557]:   92274699,
+	_TagName[8557:8576]:   92274700,
+	_TagName[8576:8589]:   92274705,
+	_TagName[8589:8602]:   92274706,
+	_TagName[8602:8615]:   92274707,
+	_TagName[8615:8622]:   92274708,
+	_TagName[8622:8646]:   92274712,
+	_TagName[8646:8662]:   92274769,
+	_TagName[8662:8677]:   92274770,
+	_TagName[8677:8698]:   92274771,
+	_TagName[8698:8719]:   92274772,
+	_TagName[8719:8739]:   92274773,
+	_TagName[8739:8759]:   92274774,
+	_TagName[8759:8779]:   92274775,
+	_TagName[8779:8799]:   92274776,
+	_TagName[8799:8815]:   92274777,
+	_TagName[8815:8830]:   92274778,
+	_TagName[8830:8837]:   92536832,
+	_TagName[8837:8852]:   92667904,
+	_TagName[8852:8871]:   92667905,
+	_TagName[8871:8888]:   92667906,
+	_TagName[8888:8908]:   92667907,
+	_TagName[8908:8924]:   100663295,
+	_TagName[8924:8947]:   100663552,
+	_TagName[8947:8972]:   100663553,
+	_TagName[8972:9000]:   100663554,
+	_TagName[9000:9024]:   100663555,
+	_TagName[9024:9048]:   100663808,
+	_TagName[9048:9073]:   100664064,
+	_TagName[9073:9097]:   100664320,
+	_TagName[9097:9111]:   109051905,
+	_TagName[9111:9126]:   109051906,
+	_TagName[9126:9142]:   109051907,
+	_TagName[9142:9153]:   109051908,
+	_TagName[9153:9169]:   109051909,
+	_TagName[9169:9186]:   109051910,
+	_TagName[9186:9200]:   109051911,
+	_TagName[9200:9213]:   109051912,
+	_TagName[9213:9226]:   109051913,
+	_TagName[9226:9245]:   109051914,
+	_TagName[9245:9263]:   109051915,
+	_TagName[9263:9285]:   109051916,
+	_TagName[9285:9295]:   109051917,
+	_TagName[9295:9311]:   109051920,
+	_TagName[9311:9329]:   109051936,
+	_TagName[9329:9348]:   109052160,
+	_TagName[9348:9368]:   109052416,
+	_TagName[9368:9389]:   109052672,
+	_TagName[9389:9409]:   109052928,
+	_TagName[9409:9424]:   112197632,
+	_TagName[9424:9439]:   112197633,
+	_TagName[9439:9451]:   112197634,
+	_TagName[9451:9466]:   112197635,
+	_TagName[9466:9480]:   112197636,
+	_TagName[9480:9494]:   112197637,
+	_TagName[9494:9511]:   112197638,
+	_TagName[9511:9524]:   112197639,
+	_TagName[9524:9540]:   112197640,
+	_TagName[9540:9555]:   112197641,
+	_TagName[9555:9572]:   134217729,
+	_TagName[9572:9588]:   134217730,
+	_TagName[9588:9601]:   142606337,
+	_TagName[9601:9613]:   142606338,
+	_TagName[9613:9630]:   150994943,
+	_TagName[9630:9651]:   150994945,
+	_TagName[9651:9673]:   150994960,
+	_TagName[9673:9692]:   150994976,
+	_TagName[9692:9714]:   150994992,
+	_TagName[9714:9737]:   150995008,
+	_TagName[9737:9751]:   150995009,
+	_TagName[9751:9777]:   150995024,
+	_TagName[9777:9812]:   150995040,
+	_TagName[9812:9831]:   151388160,
+	_TagName[9831:9848]:   159383553,
+	_TagName[9848:9860]:   159383554,
+	_TagName[9860:9878]:   159383555,
+	_TagName[9878:9895]:   159383556,
+	_TagName[9895:9912]:   159383557,
+	_TagName[9912:9937]:   159383558,
+	_TagName[9937:9961]:   159383559,
+	_TagName[9961:9990]:   159383560,
+	_TagName[9990:10020]:  159383561,
+	_TagName[10020:10038]: 159383568,
+	_TagName[10038:10056]: 159383569,
+	_TagName[10056:10073]: 159383570,
+	_TagName[10073:10101]: 159383571,
+	_TagName[10101:10125]: 159383572,
+	_TagName[10125:10152]: 159383573,
+	_TagName[10152:10179]: 159383574,
+	_TagName[10179:10194]: 159383584,
+	_TagName[10194:10212]: 159383600,
+	_TagName[10212:10231]: 159383616,
+	_TagName[10231:10253]: 159383632,
+	_TagName[10253:10284]: 159383648,
+	_TagName[10284:10299]: 159776768,
+	_TagName[10299:10318]: 159776769,
+	_TagName[10318:10335]: 159776770,
+	_TagName[10335:10355]: 159776771,
+	_TagName[10355:10371]: 167772159,
+	_TagName[10371:10393]: 167772161,
+	_TagName[10393:10417]: 167772162,
+	_TagName[10417:10445]: 167772163,
+	_TagName[10445:10471]: 167772167,
+	_TagName[10471:10490]: 167772168,
+	_TagName[10490:10510]: 167772169,
+	_TagName[10510:10530]: 167772170,
+	_TagName[10530:10546]: 167772171,
+	_TagName[10546:10558]: 167772172,
+	_TagName[10558:10578]: 167772173,
+	_TagName[10578:10591]: 167772174,
+	_TagName[10591:10608]: 167772175,
+	_TagName[10608:10626]: 167772176,
+	_TagName[10626:10639]: 167772177,
+	_TagName[10639:10662]: 167772178,
+	_TagName[10662:10686]: 167772179,
+	_TagName[10686:10710]: 167772180,
+	_TagName[10710:10730]: 167772181,
+	_TagName[10730:10746]: 167772182,
+	_TagName[10746:10768]: 167772184,
+	_TagName[10768:10787]: 167772185,
+	_TagName[10787:10805]: 176160769,
+	_TagName[10805:10825]: 176160770,
+	_TagName[10825:10849]: 176160771,
+	_TagName[10849:10871]: 176160772,
+	_TagName[10871:10882]: 176160773,
+	_TagName[10882:10894]: 176160774,
+	_TagName[10894:10916]: 176160775,
+	_TagName[10916:10931]: 176160776,
+	_TagName[10931:10947]: 176160777,
+	_TagName[10947:10963]: 176160778,
+	_TagName[10963:10975]: 176160779,
+	_TagName[10975:10983]: 176160780,
+	_TagName[10983:10999]: 176160781,
+	_TagName[10999:11008]: 176160782,
+	_TagName[11008:11021]: 176160783,
+	_TagName[11021:11035]: 176160784,
+	_TagName[11035:11044]: 176160785,
+	_TagName[11044:11063]: 176160786,
+	_TagName[11063:11083]: 176160787,
+	_TagName[11083:11103]: 176160788,
+	_TagName[11103:11119]: 176160789,
+	_TagName[11119:11131]: 176160790,
+	_TagName[11131:11144]: 176160791,
+	_TagName[11144:11162]: 176160792,
+	_TagName[11162:11177]: 176160793,
+	_TagName[11177:11195]: 184549375,
+	_TagName[11195:11221]: 184549379,
+	_TagName[11221:11245]: 184549380,
+	_TagName[11245:11266]: 184549381,
+	_TagName[11266:11289]: 184549382,
+	_TagName[11289:11307]: 184549383,
+	_TagName[11307:11329]: 192937987,
+	_TagName[11329:11349]: 192937988,
+	_TagName[11349:11366]: 192937989,
+	_TagName[11366:11385]: 192937990,
+	_TagName[11385:11399]: 192937991,
+	_TagName[11399:11415]: 201326591,
+	_TagName[11415:11436]: 201326593,
+	_TagName[11436:11463]: 201326594,
+	_TagName[11463:11490]: 201326595,
+	_TagName[11490:11507]: 209715201,
+	_TagName[11507:11530]: 209715202,
+	_TagName[11530:11553]: 209715203,
+	_TagName[11553:11568]: 209715217,
+	_TagName[11568:11585]: 218103807,
+	_TagName[11585:11605]: 218103809,
+	_TagName[11605:11629]: 218103811,
+	_TagName[11629:11645]: 226492417,
+	_TagName[11645:11665]: 226492419,
+	_TagName[11665:11681]: 234881023,
+	_TagName[11681:11698]: 234881025,
+	_TagName[11698:11717]: 234881026,
+	_TagName[11717:11727]: 234881027,
+	_TagName[11727:11740]: 234881028,
+	_TagName[11740:11757]: 234881029,
+	_TagName[11757:11768]: 234881030,
+	_TagName[11768:11787]: 234881031,
+	_TagName[11787:11813]: 234881032,
+	_TagName[11813:11830]: 234881033,
+	_TagName[11830:11850]: 234881034,
+	_TagName[11850:11866]: 234881035,
+	_TagName[11866:11884]: 234881036,
+	_TagName[11884:11902]: 234881037,
+	_TagName[11902:11920]: 234881038,
+	_TagName[11920:11943]: 234881039,
+	_TagName[11943:11957]: 234881041,
+	_TagName[11957:11976]: 234881042,
+	_TagName[11976:11995]: 234881043,
+	_TagName[11995:12014]: 234881044,
+	_TagName[12014:12033]: 234881045,
+	_TagName[12033:12053]: 234881046,
+	_TagName[12053:12079]: 234881047,
+	_TagName[12079:12096]: 234881055,
+	_TagName[12096:12116]: 234881056,
+	_TagName[12116:12134]: 234881057,
+	_TagName[12134:12152]: 234881058,
+	_TagName[12152:12170]: 234881059,
+	_TagName[12170:12192]: 234881065,
+	_TagName[12192:12207]: 234881072,
+	_TagName[12207:12218]: 234881073,
+	_TagName[12218:12229]: 235143168,
+	_TagName[12229:12237]: 235143169,
+	_TagName[12237:12255]: 235143217,
+	_TagName[12255:12280]: 235143218,
+	_TagName[12280:12308]: 235147264,
+	_TagName[12308:12316]: 235147265,
+	_TagName[12316:12329]: 235147266,
+	_TagName[12329:12346]: 235147280,
+	_TagName[12346:12368]: 235147281,
+	_TagName[12368:12390]: 235147282,
+	_TagName[12390:12412]: 235147283,
+	_TagName[12412:12434]: 235147284,
+	_TagName[12434:12457]: 235147285,
+	_TagName[12457:12475]: 235147288,
+	_TagName[12475:12493]: 235147289,
+	_TagName[12493:12507]: 235147290,
+	_TagName[12507:12521]: 235147291,
+	_TagName[12521:12535]: 235151376,
+	_TagName[12535:12553]: 235151377,
+	_TagName[12553:12572]: 235274240,
+	_TagName[12572:12585]: 243269633,
+	_TagName[12585:12600]: 243269634,
+	_TagName[12600:12606]: 243269635,
+	_TagName[12606:12615]: 243269636,
+	_TagName[12615:12628]: 243269637,
+	_TagName[12628:12635]: 243269638,
+	_TagName[12635:12650]: 243269639,
+	_TagName[12650:12672]: 243269640,
+	_TagName[12672:12685]: 243269641,
+	_TagName[12685:12701]: 243269642,
+	_TagName[12701:12713]: 243269643,
+	_TagName[12713:12727]: 243269644,
+	_TagName[12727:12741]: 243269645,
+	_TagName[12741:12755]: 243269646,
+	_TagName[12755:12774]: 243269647,
+	_TagName[12774:12784]: 243269649,
+	_TagName[12784:12799]: 243269650,
+	_TagName[12799:12814]: 243269651,
+	_TagName[12814:12829]: 243269652,
+	_TagName[12829:12844]: 243269653,
+	_TagName[12844:12860]: 243269654,
+	_TagName[12860:12882]: 243269655,
+	_TagName[12882:12895]: 243269663,
+	_TagName[12895:12911]: 243269664,
+	_TagName[12911:12925]: 243269665,
+	_TagName[12925:12939]: 243269666,
+	_TagName[12939:12953]: 243269667,
+	_TagName[12953:12971]: 243269673,
+	_TagName[12971:12999]: 243269680,
+	_TagName[12999:13025]: 243269681,
+	_TagName[13025:13054]: 243269682,
+	_TagName[13054:13061]: 243531776,
+	_TagName[13061:13085]: 243535872,
+	_TagName[13085:13098]: 243535888,
+	_TagName[13098:13116]: 243535889,
+	_TagName[13116:13134]: 243535890,
+	_TagName[13134:13152]: 243535891,
+	_TagName[13152:13170]: 243535892,
+	_TagName[13170:13189]: 243535893,
+	_TagName[13189:13203]: 243535896,
+	_TagName[13203:13217]: 243535897,
+	_TagName[13217:13231]: 243535898,
+	_TagName[13231:13245]: 243535899,
+	_TagName[13245:13260]: 243662848,
+	_TagName[13260:13279]: 243662849,
+	_TagName[13279:13296]: 243662850,
+	_TagName[13296:13316]: 243662851,
+	_TagName[13316:13332]: 251658239,
 }
 
 // TagString retrieves an enum value from the enum constants string name.
@@ -12,3 +12,13 @@ func (t Tag) isRequest() bool {
 func (t Tag) isResponse() bool {
 	return ((t >> TypeFlagBit) & 1) == 1
 }
+
+// ResponseTag returns the response tag belonging to the request tag (returns the tag itself if it's a response tag)
+func (t Tag) ResponseTag() Tag {
+	return t | (1 << TypeFlagBit)
+}
+
+// RequestTag returns the request tag belonging to the response tag (returns the tag itself if it's a request tag)
+func (t Tag) RequestTag() Tag {
+	return t &^ (1 << TypeFlagBit)
+}
@@ -0,0 +1,69 @@
+package rscp
+
+// setterTags are the request tags changing the state or configuration of the system
+var setterTags = []Tag{
+	RSCP_REQ_SET_ENCRYPTION_PASSPHRASE,
+	EMS_REQ_SET_BALANCED_PHASES,
+	EMS_REQ_SET_INSTALLED_PEAK_POWER,
+	EMS_REQ_SET_DERATE_PERCENT,
+	EMS_REQ_SET_ERROR_BUZZER_ENABLED,
+	EMS_REQ_START_ADJUST_BATTERY_VOLTAGE,
+	EMS_REQ_CANCEL_ADJUST_BATTERY_VOLTAGE,
+	EMS_REQ_CONFIRM_ERRORS,
+	EMS_REQ_SET_POWER,
+	EMS_REQ_SET_POWER_CONTROL_OFFSET,
+	EMS_REQ_SET_EMERGENCY_POWER,
+	EMS_REQ_SET_OVERRIDE_AVAILABLE_POWER,
+	EMS_REQ_SET_BATTERY_TO_CAR_MODE,
+	EMS_REQ_SET_BATTERY_BEFORE_CAR_MODE,
+	EMS_REQ_SET_IDLE_PERIODS,
+	EMS_REQ_SET_POWER_SETTINGS,
+	EMS_REQ_START_MANUAL_CHARGE,
+	EMS_REQ_START_EMERGENCYPOWER_TEST,
+	EMS_REQ_SET_GENERATOR_MODE,
+	PVI_REQ_SET_COS_PHI,
+	DCDC_REQ_FLASH,
+	PM_REQ_SET_PHASE_ELIMINATION,
+	SRV_REQ_ADD_USER,
+	HA_REQ_ADD_ACTUATOR,
+	HA_REQ_REMOVE_ACTUATOR,
+	HA_REQ_COMMAND_ACTUATOR,
+	HA_REQ_DESCRIPTIONS_CHANGE,
+	INFO_REQ_SET_IP_ADDRESS,
+	INFO_REQ_SET_SUBNET_MASK,
+	INFO_REQ_SET_DHCP_STATUS,
+	INFO_REQ_SET_GATEWAY,
+	INFO_REQ_SET_DNS,
+	INFO_REQ_SET_TIME_ZONE,
+	SYS_REQ_SYSTEM_REBOOT,
+	SYS_REQ_RESTART_APPLICATION,
+	UM_REQ_CHECK_FOR_UPDATES,
+	WB_REQ_SET_MODE,
+	WB_REQ_SET_EXTERN,
+	WB_REQ_SET_BAT_CAPACITY,
+	WB_REQ_SET_PARAM_1,
+	WB_REQ_SET_PARAM_2,
+}
+
+// IsSetter returns if the request tag changes the state or configuration of the system
+func (t Tag) IsSetter() bool {
+	for _, v := range setterTags {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsSetter returns if any of the messages or their nested messages is a setter
+func ContainsSetter(messages []Message) bool {
+	for _, m := range messages {
+		if m.Tag.IsSetter() {
+			return true
+		}
+		if nested, ok := m.Value.([]Message); ok && ContainsSetter(nested) {
+			return true
+		}
+	}
+	return false
+}
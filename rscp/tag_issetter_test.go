@@ -0,0 +1,29 @@
+package rscp
+
+import "testing"
+
+func TestContainsSetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		want     bool
+	}{
+		{"getter", []Message{{EMS_REQ_POWER_PV, None, nil}, {EMS_REQ_BAT_SOC, None, nil}}, false},
+		{"setter", []Message{{EMS_REQ_POWER_PV, None, nil}, {EMS_REQ_SET_POWER, Container, []Message{}}}, true},
+		{"nested setter",
+			[]Message{{WB_REQ_DATA, Container, []Message{{WB_INDEX, UChar8, uint8(0)}, {WB_REQ_SET_EXTERN, Container, []Message{}}}}},
+			true,
+		},
+		{"nested getter",
+			[]Message{{WB_REQ_DATA, Container, []Message{{WB_INDEX, UChar8, uint8(0)}, {WB_REQ_EXTERN_DATA_ALL, None, nil}}}},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsSetter(tt.messages); got != tt.want {
+				t.Errorf("ContainsSetter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
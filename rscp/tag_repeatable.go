@@ -0,0 +1,50 @@
+package rscp
+
+// repeatableTags are the response tags which may occur multiple times within a response or container,
+// by the index tag identifying the occurrences or 0 if they are identified by position only
+var repeatableTags = map[Tag]Tag{
+	BAT_DATA:               BAT_INDEX,
+	BAT_DCB_INFO:           BAT_DCB_INDEX,
+	DCDC_DATA:              DCDC_INDEX,
+	PM_DATA:                PM_INDEX,
+	PVI_DATA:               PVI_INDEX,
+	WB_DATA:                WB_INDEX,
+	EMS_SYS_SPEC:           EMS_SYS_SPEC_INDEX,
+	EMS_IDLE_PERIOD:        0,
+	HA_DATAPOINT:           HA_DATAPOINT_INDEX,
+	INFO_MODULE_SW_VERSION: 0,
+	// DB_GRAPH_INDEX is the position within the span in percent
+	DB_VALUE_CONTAINER: 0,
+	// PVI_INDEX & PVI_VALUE by phase, string or sensor
+	PVI_AC_POWER:                   PVI_INDEX,
+	PVI_AC_VOLTAGE:                 PVI_INDEX,
+	PVI_AC_CURRENT:                 PVI_INDEX,
+	PVI_AC_APPARENTPOWER:           PVI_INDEX,
+	PVI_AC_REACTIVEPOWER:           PVI_INDEX,
+	PVI_AC_ENERGY_ALL:              PVI_INDEX,
+	PVI_AC_MAX_APPARENTPOWER:       PVI_INDEX,
+	PVI_AC_ENERGY_DAY:              PVI_INDEX,
+	PVI_AC_ENERGY_GRID_CONSUMPTION: PVI_INDEX,
+	PVI_DC_POWER:                   PVI_INDEX,
+	PVI_DC_VOLTAGE:                 PVI_INDEX,
+	PVI_DC_CURRENT:                 PVI_INDEX,
+	PVI_DC_MAX_POWER:               PVI_INDEX,
+	PVI_DC_MAX_VOLTAGE:             PVI_INDEX,
+	PVI_DC_MIN_VOLTAGE:             PVI_INDEX,
+	PVI_DC_MAX_CURRENT:             PVI_INDEX,
+	PVI_DC_MIN_CURRENT:             PVI_INDEX,
+	PVI_DC_STRING_ENERGY_ALL:       PVI_INDEX,
+	PVI_TEMPERATURE:                PVI_INDEX,
+}
+
+// IsRepeatable returns if the response tag may occur multiple times within a response or container
+func (t Tag) IsRepeatable() bool {
+	_, exists := repeatableTags[t]
+	return exists
+}
+
+// RepeatIndexTag returns the index tag identifying the occurrences of a repeatable container tag
+func (t Tag) RepeatIndexTag() (Tag, bool) {
+	index := repeatableTags[t]
+	return index, index != 0
+}
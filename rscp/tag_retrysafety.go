@@ -0,0 +1,115 @@
+package rscp
+
+import (
+	"fmt"
+)
+
+// RetrySafety classifies if a request can be sent again when it's unknown whether the first attempt reached the system
+type RetrySafety uint8
+
+// all retry safety classes, ordered from safe to unsafe
+const (
+	// the request only reads, it can always be retried
+	RetrySafe RetrySafety = iota
+	// the request sets a state or value, repeating it results in the same state
+	RetryIdempotent
+	// the request triggers an action (i.e. a reboot), repeating it may apply the action twice
+	RetryUnsafe
+)
+
+// String returns the name of the retry safety
+func (r RetrySafety) String() string {
+	switch r {
+	case RetrySafe:
+		return "safe"
+	case RetryIdempotent:
+		return "idempotent-setter"
+	case RetryUnsafe:
+		return "non-idempotent"
+	}
+	return fmt.Sprintf("RetrySafety(%d)", r)
+}
+
+// nonIdempotentTags are the setter tags triggering an action instead of setting a state
+var nonIdempotentTags = []Tag{
+	// changes the key of the connection, a repeated request is encrypted with the old key
+	RSCP_REQ_SET_ENCRYPTION_PASSPHRASE,
+	EMS_REQ_START_ADJUST_BATTERY_VOLTAGE,
+	EMS_REQ_CONFIRM_ERRORS,
+	EMS_REQ_START_MANUAL_CHARGE,
+	EMS_REQ_START_EMERGENCYPOWER_TEST,
+	DCDC_REQ_FLASH,
+	SRV_REQ_ADD_USER,
+	HA_REQ_ADD_ACTUATOR,
+	HA_REQ_REMOVE_ACTUATOR,
+	SYS_REQ_SYSTEM_REBOOT,
+	SYS_REQ_RESTART_APPLICATION,
+	UM_REQ_CHECK_FOR_UPDATES,
+}
+
+// RetrySafety returns the retry safety of the request tag
+func (t Tag) RetrySafety() RetrySafety {
+	for _, v := range nonIdempotentTags {
+		if v == t {
+			return RetryUnsafe
+		}
+	}
+	if t.IsSetter() {
+		return RetryIdempotent
+	}
+	return RetrySafe
+}
+
+// togglesExtern returns if the WB_REQ_SET_EXTERN request toggles the charging (byte 5) or swaps the phases (byte 4)
+func togglesExtern(m Message) bool {
+	nested, ok := m.Value.([]Message)
+	if m.Tag != WB_REQ_SET_EXTERN || !ok {
+		return false
+	}
+	data := FindTag(nested, WB_EXTERN_DATA)
+	if data == nil {
+		return false
+	}
+	b, ok := data.Value.([]byte)
+	return ok && len(b) > 4 && (b[3] > 0 || b[4] > 0)
+}
+
+// RequestsRetrySafety returns the least safe retry safety of the messages and their nested messages
+func RequestsRetrySafety(messages []Message) RetrySafety {
+	r := RetrySafe
+	for _, m := range messages {
+		if togglesExtern(m) {
+			return RetryUnsafe
+		}
+		if s := m.Tag.RetrySafety(); s > r {
+			r = s
+		}
+		if nested, ok := m.Value.([]Message); ok {
+			if s := RequestsRetrySafety(nested); s > r {
+				r = s
+			}
+		}
+	}
+	return r
+}
+
+// OutcomeUnknownError is returned if a round-trip of requests changing the system failed after the requests were sent,
+// the requests may or may not have been applied by the system.
+type OutcomeUnknownError struct {
+	// retry safety of the requests
+	Safety RetrySafety
+	Err    error
+}
+
+func (e *OutcomeUnknownError) Error() string {
+	return fmt.Sprintf("outcome of %s request unknown: %s", e.Safety, e.Err)
+}
+
+// Is matches ErrOutcomeUnknown
+func (e *OutcomeUnknownError) Is(target error) bool {
+	return target == ErrOutcomeUnknown
+}
+
+func (e *OutcomeUnknownError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,50 @@
+package rscp
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRequestsRetrySafety(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		want     RetrySafety
+	}{
+		{"getter", []Message{{EMS_REQ_POWER_PV, None, nil}, {EMS_REQ_BAT_SOC, None, nil}}, RetrySafe},
+		{"setter", []Message{{EMS_REQ_POWER_PV, None, nil}, {EMS_REQ_SET_POWER, Container, []Message{}}}, RetryIdempotent},
+		{"action", []Message{{EMS_REQ_SET_POWER, Container, []Message{}}, {SYS_REQ_SYSTEM_REBOOT, None, nil}}, RetryUnsafe},
+		{"nested setter",
+			[]Message{{WB_REQ_DATA, Container, []Message{{WB_INDEX, UChar8, uint8(0)}, {WB_REQ_SET_EXTERN, Container, []Message{}}}}},
+			RetryIdempotent,
+		},
+		{"wallbox toggle",
+			[]Message{{WB_REQ_DATA, Container, []Message{{WB_INDEX, UChar8, uint8(0)}, {WB_REQ_SET_EXTERN, Container, []Message{
+				{WB_EXTERN_DATA, ByteArray, []byte{1, 16, 0, 0, 1, 0}}, {WB_EXTERN_DATA_LEN, UChar8, uint8(6)},
+			}}}}},
+			RetryUnsafe,
+		},
+		{"nested action",
+			[]Message{{DCDC_REQ_DATA, Container, []Message{{DCDC_INDEX, UInt16, uint16(0)}, {DCDC_REQ_FLASH, None, nil}}}},
+			RetryUnsafe,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequestsRetrySafety(tt.messages); got != tt.want {
+				t.Errorf("RequestsRetrySafety() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutcomeUnknownError(t *testing.T) {
+	var err error = &OutcomeUnknownError{Safety: RetryUnsafe, Err: io.EOF}
+	if !errors.Is(err, ErrOutcomeUnknown) || !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is() = false, want %v and %v", ErrOutcomeUnknown, io.EOF)
+	}
+	if want := "outcome of non-idempotent request unknown: EOF"; err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err, want)
+	}
+}
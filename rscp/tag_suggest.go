@@ -0,0 +1,70 @@
+package rscp
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggestMinDistance is the edit distance always accepted for suggestions, longer names accept a quarter of their length
+const suggestMinDistance = 2
+
+// SuggestTags returns up to max tags with a name similar to the given name, the most similar first.
+// Useful to give a hint on a misspelled tag name.
+func SuggestTags(name string, max int) []Tag {
+	name = strings.ToUpper(name)
+	limit := len(name) / 4 //nolint: gomnd
+	if limit < suggestMinDistance {
+		limit = suggestMinDistance
+	}
+	type candidate struct {
+		tag      Tag
+		name     string
+		distance int
+	}
+	candidates := []candidate{}
+	for _, t := range TagValues() {
+		n := t.String()
+		if d := editDistance(name, n); d <= limit {
+			candidates = append(candidates, candidate{t, n, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	tags := []Tag{}
+	for i := 0; i < len(candidates) && i < max; i++ {
+		tags = append(tags, candidates[i].tag)
+	}
+	return tags
+}
+
+// editDistance returns the levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
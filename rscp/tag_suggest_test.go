@@ -0,0 +1,44 @@
+package rscp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestSuggestTags(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		want []Tag
+	}{
+		{"EMS_REQ_POWER_PV", 1, []Tag{EMS_REQ_POWER_PV}},
+		{"EMS_REQ_POWR_PV", 1, []Tag{EMS_REQ_POWER_PV}},
+		{"ems_req_power_pv", 1, []Tag{EMS_REQ_POWER_PV}},
+		{"BAT_REQ_RSCO", 1, []Tag{BAT_REQ_RSOC}},
+		{"SOMETHING_COMPLETELY_DIFFERENT", 3, []Tag{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(SuggestTags(tt.name, tt.max), tt.want); diff != nil {
+				t.Errorf("SuggestTags() %s", diff)
+			}
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"ABC", "", 3},
+		{"KITTEN", "SITTING", 3},
+		{"RSOC", "RSCO", 2},
+	} {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
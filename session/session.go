@@ -0,0 +1,235 @@
+// Package session provides a sender with separate sessions for telemetry and for requests requiring a higher auth level.
+//
+// requests are sent with the user session unless they contain a tag requiring a higher auth level than granted to the user
+// (see rscp.Tag.MinAuthLevel). Those requests open an elevated session (i.e. an installer) with credentials read from a
+// separate secret source. The elevated session is closed after an idle timeout and every elevated request is audited.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrMissingCredentials    = errors.New("missing elevated credentials")
+	ErrInsufficientAuthLevel = errors.New("insufficient auth level")
+)
+
+// Credentials is the secret source of the elevated session
+type Credentials interface {
+	// Credentials returns the user and password, called every time the elevated session is opened
+	Credentials() (string, string, error)
+}
+
+// FileCredentials reads the credentials from a json file (i.e. {"user": "installer", "password": "secret"}),
+// the file is read every time the elevated session is opened to pick up rotated credentials.
+type FileCredentials string
+
+// Credentials returns the user and password of the file
+func (f FileCredentials) Credentials() (string, string, error) {
+	c := struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}{}
+	if err := jsonfile.Read(string(f), &c); err != nil {
+		return "", "", err
+	}
+	if c.User == "" || c.Password == "" {
+		return "", "", fmt.Errorf("%s: %w", f, ErrMissingCredentials)
+	}
+	return c.User, c.Password, nil
+}
+
+// Config of the sessions
+type Config struct {
+	// client config of the user session, the elevated session uses the same config with the elevated credentials
+	Client rscp.ClientConfig
+	// secret source of the elevated credentials
+	Elevated Credentials
+	// time after the last elevated request the elevated session is closed
+	IdleTimeout time.Duration
+	// every elevated request is written as json line
+	Audit io.Writer
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	IdleTimeout: time.Minute * 5,
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	if c.Elevated == nil {
+		return ErrMissingCredentials
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = defaultConfig.IdleTimeout
+	}
+	if c.Audit == nil {
+		c.Audit = io.Discard
+	}
+	return nil
+}
+
+// AuditEntry of an elevated request, the values are never logged as they may contain secrets
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	User string    `json:"user"`
+	// auth level required by the request
+	Level rscp.AuthLevel `json:"level"`
+	// all tags of the request including the nested ones
+	Tags  []rscp.Tag `json:"tags"`
+	Error string     `json:"error,omitempty"`
+}
+
+// Sender sends the requests with the user or the elevated session, safe for concurrent use
+type Sender struct {
+	config Config
+	mu     sync.Mutex
+	user   *rscp.Client
+	// elevated session, nil if closed
+	elevated     *rscp.Client
+	elevatedUser string
+	lastElevated time.Time
+	idle         *time.Timer
+}
+
+// New creates the sender, the sessions are opened by the first request
+func New(c Config) (*Sender, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	user, err := rscp.NewClient(c.Client)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{config: c, user: user}, nil
+}
+
+// SendMultiple sends the requests with the session of the auth level required.
+//
+// the user session is connected first to know the auth level granted to the user.
+func (s *Sender) SendMultiple(requests []rscp.Message) ([]rscp.Message, error) {
+	level := rscp.RequestsMinAuthLevel(requests)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if level > rscp.AUTH_LEVEL_USER {
+		if err := s.user.Connect(); err != nil {
+			return nil, err
+		}
+	}
+	if level <= rscp.AUTH_LEVEL_USER || s.user.AuthLevel() >= level {
+		return s.user.SendMultiple(requests)
+	}
+	responses, err := s.sendElevated(requests, level)
+	s.audit(requests, level, err)
+	return responses, err
+}
+
+// sendElevated sends the requests with the elevated session, opens the session if closed
+func (s *Sender) sendElevated(requests []rscp.Message, level rscp.AuthLevel) ([]rscp.Message, error) {
+	if s.elevated == nil {
+		user, password, err := s.config.Elevated.Credentials()
+		if err != nil {
+			return nil, fmt.Errorf("elevated credentials: %w", err)
+		}
+		c := s.config.Client
+		c.Username, c.Password = user, password
+		if s.elevated, err = rscp.NewClient(c); err != nil {
+			return nil, err
+		}
+		s.elevatedUser = user
+		log.Infof("opening elevated session of %s", user)
+	}
+	s.lastElevated = time.Now()
+	if s.idle == nil {
+		s.idle = time.AfterFunc(s.config.IdleTimeout, s.closeIdle)
+	} else {
+		s.idle.Reset(s.config.IdleTimeout)
+	}
+	if err := s.elevated.Connect(); err != nil {
+		s.closeElevated()
+		return nil, err
+	}
+	if granted := s.elevated.AuthLevel(); granted < level {
+		s.closeElevated()
+		return nil, fmt.Errorf("%s granted, %s required: %w", granted, level, ErrInsufficientAuthLevel)
+	}
+	return s.elevated.SendMultiple(requests)
+}
+
+// audit writes the audit entry of the elevated request
+func (s *Sender) audit(requests []rscp.Message, level rscp.AuthLevel, err error) {
+	e := AuditEntry{Time: time.Now(), User: s.elevatedUser, Level: level, Tags: tags(requests)}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	log.Infof("elevated request of %s: %v", e.User, e.Tags)
+	b, merr := json.Marshal(e)
+	if merr == nil {
+		_, merr = s.config.Audit.Write(append(b, '\n'))
+	}
+	if merr != nil {
+		log.Errorf("audit log failed: %s", merr)
+	}
+}
+
+// tags returns the tags of the messages and their nested messages
+func tags(messages []rscp.Message) []rscp.Tag {
+	t := make([]rscp.Tag, 0, len(messages))
+	for _, m := range messages {
+		t = append(t, m.Tag)
+		if nested, ok := m.Value.([]rscp.Message); ok {
+			t = append(t, tags(nested)...)
+		}
+	}
+	return t
+}
+
+// closeIdle closes the elevated session if it was idle for the timeout
+func (s *Sender) closeIdle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.elevated != nil && time.Since(s.lastElevated) >= s.config.IdleTimeout {
+		log.Infof("closing idle elevated session of %s", s.elevatedUser)
+		s.closeElevated()
+	}
+}
+
+// closeElevated closes the elevated session
+func (s *Sender) closeElevated() {
+	if s.elevated == nil {
+		return
+	}
+	if err := s.elevated.Disconnect(); err != nil {
+		log.Errorf("closing elevated session failed: %s", err)
+	}
+	s.elevated = nil
+}
+
+// Elevated returns if the elevated session is open
+func (s *Sender) Elevated() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elevated != nil
+}
+
+// Disconnect closes both sessions
+func (s *Sender) Disconnect() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	s.closeElevated()
+	return s.user.Disconnect()
+}
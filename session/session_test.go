@@ -0,0 +1,117 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+	"github.com/spali/go-rscp/simulator"
+)
+
+// credentials writes the credentials file
+func credentials(t *testing.T, user, password string) FileCredentials {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "installer.json")
+	if err := jsonfile.Write(path, map[string]string{"user": user, "password": password}); err != nil {
+		t.Fatal(err)
+	}
+	return FileCredentials(path)
+}
+
+func start(t *testing.T, user, password string, elevated Credentials, audit *bytes.Buffer) *Sender {
+	t.Helper()
+	server, err := simulator.Start("127.0.0.1:0", simulator.Config{
+		Key: "key", User: "user", Password: "password",
+		Accounts: []simulator.Account{{User: "installer", Password: "secret", AuthLevel: rscp.AUTH_LEVEL_INSTALLER}},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	s, err := New(Config{
+		Client: rscp.ClientConfig{
+			Address: "127.0.0.1", Port: uint16(server.Addr().Port), Username: user, Password: password, Key: "key",
+		},
+		Elevated:    elevated,
+		IdleTimeout: time.Millisecond * 50,
+		Audit:       audit,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Disconnect() })
+	return s
+}
+
+func TestSender(t *testing.T) {
+	audit := &bytes.Buffer{}
+	s := start(t, "user", "password", credentials(t, "installer", "secret"), audit)
+	if _, err := s.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.EMS_REQ_POWER_PV, nil)}); err != nil {
+		t.Fatalf("SendMultiple() error = %v", err)
+	}
+	if s.Elevated() || audit.Len() != 0 {
+		t.Errorf("SendMultiple() of a getter opened the elevated session")
+	}
+	setter := []rscp.Message{*rscp.NewMessage(rscp.EMS_REQ_SET_DERATE_PERCENT, float32(70))}
+	responses, err := s.SendMultiple(setter)
+	if err != nil || responses[0].DataType == rscp.Error {
+		t.Fatalf("SendMultiple() = %v, error = %v", responses, err)
+	}
+	if !s.Elevated() {
+		t.Errorf("SendMultiple() of a setter didn't open the elevated session")
+	}
+	e := AuditEntry{}
+	if err := json.Unmarshal(audit.Bytes(), &e); err != nil {
+		t.Fatalf("audit = %s, error = %v", audit, err)
+	}
+	if diff := deep.Equal(e.Tags, []rscp.Tag{rscp.EMS_REQ_SET_DERATE_PERCENT}); diff != nil || e.User != "installer" || e.Level != rscp.AUTH_LEVEL_INSTALLER {
+		t.Errorf("audit = %+v", e)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if s.Elevated() {
+		t.Errorf("elevated session still open after the idle timeout")
+	}
+	// reopened after the idle timeout
+	if _, err := s.SendMultiple(setter); err != nil {
+		t.Errorf("SendMultiple() error = %v", err)
+	}
+}
+
+func TestSender_insufficient(t *testing.T) {
+	audit := &bytes.Buffer{}
+	s := start(t, "user", "password", credentials(t, "user", "password"), audit)
+	_, err := s.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.SYS_REQ_SYSTEM_REBOOT, nil)})
+	if !errors.Is(err, ErrInsufficientAuthLevel) {
+		t.Errorf("SendMultiple() error = %v, want %v", err, ErrInsufficientAuthLevel)
+	}
+	if s.Elevated() || !bytes.Contains(audit.Bytes(), []byte(ErrInsufficientAuthLevel.Error())) {
+		t.Errorf("audit = %s, elevated %v", audit, s.Elevated())
+	}
+	s = start(t, "user", "password", FileCredentials(filepath.Join(t.TempDir(), "missing.json")), audit)
+	if _, err := s.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.SYS_REQ_SYSTEM_REBOOT, nil)}); err == nil {
+		t.Errorf("SendMultiple() expected error on missing credentials")
+	}
+	// a failed authentication closes the elevated session
+	s = start(t, "user", "password", credentials(t, "installer", "wrong"), audit)
+	if _, err := s.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.SYS_REQ_SYSTEM_REBOOT, nil)}); err == nil || s.Elevated() {
+		t.Errorf("SendMultiple() with wrong credentials error = %v, elevated %v", err, s.Elevated())
+	}
+}
+
+func TestSender_userLevel(t *testing.T) {
+	audit := &bytes.Buffer{}
+	// the user session grants the level itself, even before its first request
+	s := start(t, "installer", "secret", credentials(t, "installer", "secret"), audit)
+	if _, err := s.SendMultiple([]rscp.Message{*rscp.NewMessage(rscp.EMS_REQ_SET_DERATE_PERCENT, float32(70))}); err != nil {
+		t.Fatalf("SendMultiple() error = %v", err)
+	}
+	if s.Elevated() || audit.Len() != 0 {
+		t.Errorf("SendMultiple() opened the elevated session for a user with the auth level required")
+	}
+}
@@ -0,0 +1,101 @@
+package simulator
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Fault injected into a response
+type Fault string
+
+// all faults injected by the server
+const (
+	// response sent as is
+	FaultNone Fault = ""
+	// connection closed instead of responding
+	FaultDrop Fault = "drop"
+	// no response, the connection is closed once the client gives up
+	FaultStall Fault = "stall"
+	// part of the response sent before the connection is closed
+	FaultTruncate Fault = "truncate"
+	// response sent in small chunks, not a failure but a partial frame for the client
+	FaultPartial Fault = "partial"
+	// bit flipped in the last block of the response
+	FaultCorrupt Fault = "corrupt"
+	// last response omitted (of multiple), the last value within the container of a component if answered last
+	FaultOmit Fault = "omit"
+)
+
+// Faults defines the probabilities (0-1) of the faults injected per response, authentication is never faulted
+type Faults struct {
+	Drop     float64 `json:"drop"`
+	Stall    float64 `json:"stall"`
+	Truncate float64 `json:"truncate"`
+	Partial  float64 `json:"partial"`
+	Corrupt  float64 `json:"corrupt"`
+	Omit     float64 `json:"omit"`
+	// seed of the random faults for reproducible runs
+	Seed int64 `json:"seed"`
+}
+
+// injector draws the faults
+type injector struct {
+	faults Faults
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+// newInjector creates the injector for the faults
+func newInjector(f Faults) *injector {
+	return &injector{faults: f, rand: rand.New(rand.NewSource(f.Seed))} //nolint: gosec
+}
+
+// next draws the fault of the next response
+func (i *injector) next() Fault {
+	i.mu.Lock()
+	r := i.rand.Float64()
+	i.mu.Unlock()
+	for _, f := range []struct {
+		fault       Fault
+		probability float64
+	}{
+		{FaultDrop, i.faults.Drop},
+		{FaultStall, i.faults.Stall},
+		{FaultTruncate, i.faults.Truncate},
+		{FaultPartial, i.faults.Partial},
+		{FaultCorrupt, i.faults.Corrupt},
+		{FaultOmit, i.faults.Omit},
+	} {
+		if r < f.probability {
+			return f.fault
+		}
+		r -= f.probability
+	}
+	return FaultNone
+}
+
+// intn returns a random number in [0,n)
+func (i *injector) intn(n int) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Intn(n)
+}
+
+// omit returns the responses with the last response omitted, a single response is kept (the frame is never empty),
+// the last value within the container of a component is omitted instead if answered last (a partial response).
+func omit(responses []rscp.Message) []rscp.Message {
+	last := responses[len(responses)-1]
+	if n, ok := rscp.NamespaceByContainer(last.Tag); ok {
+		// the index is kept
+		if nested, isContainer := last.Value.([]rscp.Message); isContainer && len(nested) > 1 && nested[len(nested)-1].Tag != n.IndexTag {
+			last.Value = nested[:len(nested)-1]
+			return append(responses[:len(responses)-1:len(responses)-1], last)
+		}
+	}
+	if len(responses) == 1 {
+		return responses
+	}
+	return responses[:len(responses)-1]
+}
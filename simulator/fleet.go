@@ -0,0 +1,296 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/spali/go-rscp/fleet"
+	"github.com/spali/go-rscp/internal/yamlfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoDevices       = errors.New("no devices configured")
+	ErrMissingDeviceID = errors.New("device without id")
+	ErrDuplicateDevice = errors.New("duplicate device id")
+	ErrUnknownFirmware = errors.New("unknown firmware")
+)
+
+// FleetConfig of the simulated devices started by a fleet
+type FleetConfig struct {
+	// host the devices listen on
+	Host string `json:"host"`
+	// port of the first device without port, the following devices use the next ports, random ports if 0
+	BasePort uint16 `json:"basePort"`
+	// values used for every device not defining it's own (except the id, port and serial)
+	Defaults Device `json:"defaults"`
+	// all devices of the fleet
+	Devices []Device `json:"devices"`
+}
+
+// Device is a simulated system, or multiple equal systems if a count is defined
+type Device struct {
+	// unique id of the device, used as site id of the fleet config
+	ID string `json:"id"`
+	// number of devices started with the suffix -1 to -n of the id, port and serial, a single device if 0
+	Count uint16 `json:"count"`
+	// port the device listens on
+	Port uint16 `json:"port"`
+	// serial number, the id if empty
+	Serial   string `json:"serial"`
+	Key      string `json:"key"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	// components of the device
+	Layout Layout `json:"layout"`
+	// type of the firmware (see Firmwares)
+	Firmware string `json:"firmware"`
+	// release of the firmware, the one of the firmware type if empty
+	Release string `json:"release"`
+	// quirks instead of the ones of the firmware type
+	Quirks *Quirks `json:"quirks"`
+	// faults injected, overrides the default faults completely
+	Faults *Faults `json:"faults"`
+	// region of the site in the fleet config
+	Region string `json:"region"`
+}
+
+// Layout of the components of a device
+type Layout struct {
+	// batteries, each with it's own dcdc converter
+	Batteries uint16 `json:"batteries"`
+	Inverters uint16 `json:"inverters"`
+	// dc strings of each inverter
+	Strings   uint8  `json:"strings"`
+	Wallboxes uint16 `json:"wallboxes"`
+	Meters    uint16 `json:"meters"`
+}
+
+// defaultFleetConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultFleetConfig = FleetConfig{
+	Host: "127.0.0.1",
+	Defaults: Device{
+		Key:      "simulator",
+		User:     "user",
+		Password: "password",
+		Layout:   Layout{Batteries: 1, Inverters: 1, Strings: 2, Meters: 1},
+		Firmware: "current",
+	},
+}
+
+// LoadFleetConfig reads the fleet config from a yaml file
+func LoadFleetConfig(path string) (FleetConfig, error) {
+	c := FleetConfig{}
+	if err := yamlfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if required
+func (c *FleetConfig) check() error {
+	if len(c.Devices) == 0 {
+		return ErrNoDevices
+	}
+	if c.Host == "" {
+		c.Host = defaultFleetConfig.Host
+	}
+	c.Defaults.inherit(defaultFleetConfig.Defaults)
+	ids := map[string]bool{}
+	for i := range c.Devices {
+		d := &c.Devices[i]
+		if d.ID == "" {
+			return fmt.Errorf("device at index %d: %w", i, ErrMissingDeviceID)
+		}
+		// the ids of the replicas have to be unique as well
+		for _, r := range d.replicas() {
+			if ids[r.ID] {
+				return fmt.Errorf("%s: %w", r.ID, ErrDuplicateDevice)
+			}
+			ids[r.ID] = true
+		}
+		d.inherit(c.Defaults)
+		if _, ok := Firmwares[d.Firmware]; !ok {
+			return fmt.Errorf("%s: %s: %w", d.ID, d.Firmware, ErrUnknownFirmware)
+		}
+	}
+	return nil
+}
+
+// inherit sets the values not defined by the device to the ones of the defaults
+func (d *Device) inherit(defaults Device) {
+	if d.Key == "" {
+		d.Key = defaults.Key
+	}
+	if d.User == "" {
+		d.User = defaults.User
+	}
+	if d.Password == "" {
+		d.Password = defaults.Password
+	}
+	d.Layout.inherit(defaults.Layout)
+	if d.Firmware == "" {
+		d.Firmware = defaults.Firmware
+	}
+	if d.Release == "" {
+		d.Release = defaults.Release
+	}
+	if d.Quirks == nil {
+		d.Quirks = defaults.Quirks
+	}
+	if d.Faults == nil {
+		d.Faults = defaults.Faults
+	}
+	if d.Region == "" {
+		d.Region = defaults.Region
+	}
+}
+
+// inherit sets the counts not defined by the layout to the ones of the defaults
+func (l *Layout) inherit(defaults Layout) {
+	if l.Batteries == 0 {
+		l.Batteries = defaults.Batteries
+	}
+	if l.Inverters == 0 {
+		l.Inverters = defaults.Inverters
+	}
+	if l.Strings == 0 {
+		l.Strings = defaults.Strings
+	}
+	if l.Wallboxes == 0 {
+		l.Wallboxes = defaults.Wallboxes
+	}
+	if l.Meters == 0 {
+		l.Meters = defaults.Meters
+	}
+}
+
+// replicas returns the single devices of the device, ports are only defined if set
+func (d Device) replicas() []Device {
+	if d.Count == 0 {
+		return []Device{d}
+	}
+	r := make([]Device, 0, d.Count)
+	for i := uint16(0); i < d.Count; i++ {
+		s := d
+		s.Count = 0
+		suffix := "-" + strconv.Itoa(int(i)+1)
+		s.ID += suffix
+		if d.Serial != "" {
+			s.Serial += suffix
+		}
+		if d.Port != 0 {
+			s.Port += i
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+// config returns the config of the simulated system
+func (d Device) config() Config {
+	firmware := Firmwares[d.Firmware]
+	if d.Release != "" {
+		firmware.Release = d.Release
+	}
+	if d.Quirks != nil {
+		firmware.Quirks = *d.Quirks
+	}
+	c := Config{
+		Key:      d.Key,
+		User:     d.User,
+		Password: d.Password,
+		Serial:   d.Serial,
+		Values:   map[rscp.Tag]interface{}{rscp.INFO_SW_RELEASE: firmware.Release},
+		Components: map[string]uint16{
+			"BAT":  d.Layout.Batteries,
+			"DCDC": d.Layout.Batteries,
+			"PVI":  d.Layout.Inverters,
+			"WB":   d.Layout.Wallboxes,
+			"PM":   d.Layout.Meters,
+		},
+		Strings: d.Layout.Strings,
+		Quirks:  firmware.Quirks,
+	}
+	if c.Serial == "" {
+		c.Serial = d.ID
+	}
+	if d.Faults != nil {
+		c.Faults = *d.Faults
+	}
+	return c
+}
+
+// Fleet of running simulated devices
+type Fleet struct {
+	config  FleetConfig
+	devices []Device
+	servers []*Server
+}
+
+// StartFleet starts all devices of the config, no device is left running on failure
+func StartFleet(c FleetConfig) (*Fleet, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	f := &Fleet{config: c}
+	next := c.BasePort
+	for _, device := range c.Devices {
+		for _, d := range device.replicas() {
+			port := d.Port
+			if port == 0 && next != 0 {
+				port = next
+				next++
+			}
+			s, err := Start(net.JoinHostPort(c.Host, strconv.Itoa(int(port))), d.config())
+			if err != nil {
+				_ = f.Close()
+				return nil, fmt.Errorf("%s: %w", d.ID, err)
+			}
+			f.devices = append(f.devices, d)
+			f.servers = append(f.servers, s)
+		}
+	}
+	return f, nil
+}
+
+// Sites returns the fleet config to connect to the running devices
+func (f *Fleet) Sites() fleet.Config {
+	c := fleet.Config{Sites: make([]fleet.Site, 0, len(f.servers))}
+	for i, s := range f.servers {
+		d := f.devices[i]
+		c.Sites = append(c.Sites, fleet.Site{
+			ID:       d.ID,
+			Host:     f.config.Host,
+			Port:     uint16(s.Addr().Port),
+			User:     d.User,
+			Password: d.Password,
+			Key:      d.Key,
+			Region:   d.Region,
+		})
+	}
+	return c
+}
+
+// Stats returns the stats of the servers by device id
+func (f *Fleet) Stats() map[string]Stats {
+	r := make(map[string]Stats, len(f.servers))
+	for i, s := range f.servers {
+		r[f.devices[i].ID] = s.Stats()
+	}
+	return r
+}
+
+// Close stops all devices
+func (f *Fleet) Close() error {
+	var err error
+	for _, s := range f.servers {
+		if e := s.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
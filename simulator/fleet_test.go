@@ -0,0 +1,167 @@
+package simulator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/fleet"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const fleetYAML = `
+defaults:
+  key: secret
+  region: north
+devices:
+  - id: home
+    serial: S10-1
+    user: admin
+    password: pw
+    layout: {batteries: 2, inverters: 1, strings: 3, wallboxes: 1, meters: 2}
+  - id: old
+    count: 2
+    firmware: legacy
+    region: south
+  - id: plain
+    firmware: unchecked
+    faults: {partial: 1}
+`
+
+func TestStartFleet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sim.yaml")
+	if err := os.WriteFile(path, []byte(fleetYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadFleetConfig(path)
+	if err != nil {
+		t.Fatalf("LoadFleetConfig() error = %v", err)
+	}
+	f, err := StartFleet(c)
+	if err != nil {
+		t.Fatalf("StartFleet() error = %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	sites := f.Sites()
+	ports := map[string]uint16{}
+	for i := range sites.Sites {
+		ports[sites.Sites[i].ID] = sites.Sites[i].Port
+		sites.Sites[i].Port = 0
+	}
+	want := fleet.Config{Sites: []fleet.Site{
+		{ID: "home", Host: "127.0.0.1", User: "admin", Password: "pw", Key: "secret", Region: "north"},
+		{ID: "old-1", Host: "127.0.0.1", User: "user", Password: "password", Key: "secret", Region: "south"},
+		{ID: "old-2", Host: "127.0.0.1", User: "user", Password: "password", Key: "secret", Region: "south"},
+		{ID: "plain", Host: "127.0.0.1", User: "user", Password: "password", Key: "secret", Region: "north"},
+	}}
+	if diff := deep.Equal(sites, want); diff != nil {
+		t.Fatalf("Sites() = %v, want %v\n%s", sites, want, diff)
+	}
+	connect := func(id, password string) *rscp.Client {
+		s, _ := want.Site(id)
+		cc := s.ClientConfig()
+		cc.Port, cc.Password, cc.ReceiveTimeout = ports[id], password, time.Millisecond*200
+		client, err := rscp.NewClient(cc)
+		if err != nil {
+			t.Fatalf("NewClient(%s) error = %v", id, err)
+		}
+		t.Cleanup(func() { _ = client.Disconnect() })
+		return client
+	}
+
+	t.Run("layout", func(t *testing.T) {
+		client := connect("home", "pw")
+		components, err := client.Discover()
+		if err != nil {
+			t.Fatalf("Discover() error = %v", err)
+		}
+		for n, count := range map[string]int{"BAT": 2, "DCDC": 2, "PVI": 1, "WB": 1, "PM": 2} {
+			if len(components[n]) != count {
+				t.Errorf("Discover() %s = %+v, want %d", n, components[n], count)
+			}
+		}
+		requests, _ := rscp.CreateRequests(
+			[]interface{}{rscp.INFO_REQ_SERIAL_NUMBER},
+			[]interface{}{rscp.INFO_REQ_SW_RELEASE},
+			[]interface{}{rscp.PVI_REQ_DATA, rscp.PVI_INDEX, uint16(0),
+				rscp.PVI_REQ_DC_MAX_STRING_COUNT, rscp.PVI_REQ_DC_POWER, uint8(2), rscp.PVI_REQ_DC_POWER, uint8(3)},
+		)
+		got, err := client.SendMultiple(requests)
+		if err != nil {
+			t.Fatalf("SendMultiple() error = %v", err)
+		}
+		want := []rscp.Message{
+			{Tag: rscp.INFO_SERIAL_NUMBER, DataType: rscp.CString, Value: "S10-1"},
+			{Tag: rscp.INFO_SW_RELEASE, DataType: rscp.CString, Value: Firmwares["current"].Release},
+			{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+				{Tag: rscp.PVI_DC_MAX_STRING_COUNT, DataType: rscp.UChar8, Value: uint8(3)},
+				{Tag: rscp.PVI_DC_POWER, DataType: rscp.Container, Value: []rscp.Message{
+					{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(2)},
+					{Tag: rscp.PVI_VALUE, DataType: rscp.Float32, Value: float32(0)},
+				}},
+				{Tag: rscp.PVI_DC_POWER, DataType: rscp.Error, Value: rscp.ERR_OUT_OF_BOUNDS},
+			}},
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("SendMultiple() = %v, want %v\n%s", got, want, diff)
+		}
+	})
+
+	t.Run("legacy firmware", func(t *testing.T) {
+		got, err := connect("old-2", "password").Send(*rscp.NewMessage(rscp.EMS_REQ_GET_SYS_SPECS, nil))
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		want := &rscp.Message{Tag: rscp.EMS_REQ_GET_SYS_SPECS.ResponseTag(), DataType: rscp.Error, Value: rscp.ERR_NOT_HANDLED}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("Send() = %v, want %v\n%s", got, want, diff)
+		}
+		_, err = connect("old-1", "wrong").Send(*rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil))
+		if err == nil {
+			t.Errorf("Send() expected authentication error")
+		}
+	})
+
+	t.Run("unchecked firmware", func(t *testing.T) {
+		if _, err := connect("plain", "password").Send(*rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil)); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if st := f.Stats()["plain"]; st.Faults[FaultPartial] != 1 {
+			t.Errorf("Stats() = %s", st)
+		}
+	})
+}
+
+func TestFleetConfig_check(t *testing.T) {
+	tests := []struct {
+		name    string
+		devices []Device
+		wantErr error
+	}{
+		{"no devices", nil, ErrNoDevices},
+		{"missing id", []Device{{}}, ErrMissingDeviceID},
+		{"duplicate replica", []Device{{ID: "a", Count: 2}, {ID: "a-2"}}, ErrDuplicateDevice},
+		{"unknown firmware", []Device{{ID: "a", Firmware: "beta"}}, ErrUnknownFirmware},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := FleetConfig{Devices: tt.devices}
+			if err := c.check(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	// every count of the layout not set is inherited
+	c := FleetConfig{Defaults: Device{Layout: Layout{Batteries: 2, Wallboxes: 1}}, Devices: []Device{{ID: "a", Layout: Layout{Inverters: 3}}}}
+	if err := c.check(); err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	want := Layout{Batteries: 2, Inverters: 3, Strings: defaultFleetConfig.Defaults.Layout.Strings, Wallboxes: 1, Meters: 1}
+	if c.Devices[0].Layout != want {
+		t.Errorf("check() layout = %+v, want %+v", c.Devices[0].Layout, want)
+	}
+}
@@ -0,0 +1,42 @@
+package simulator
+
+import (
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Quirks of a firmware the clients have to cope with
+type Quirks struct {
+	// wrong credentials are answered with an Int32 0 instead of an UChar8 auth level
+	AuthInt32 bool `json:"authInt32"`
+	// frames are sent without checksum
+	NoChecksum bool `json:"noChecksum"`
+	// request tags not supported by the firmware, answered with ERR_NOT_HANDLED
+	Unsupported []rscp.Tag `json:"unsupported"`
+}
+
+// Firmware is a release of the firmware with its quirks
+type Firmware struct {
+	// answered to INFO_REQ_SW_RELEASE
+	Release string
+	Quirks  Quirks
+}
+
+// Firmwares contains the known firmware types by name
+var Firmwares = map[string]Firmware{
+	"current": {Release: "S10_2022_04"},
+	"legacy": {Release: "S10_2019_02", Quirks: Quirks{
+		AuthInt32:   true,
+		Unsupported: []rscp.Tag{rscp.EMS_REQ_GET_SYS_SPECS, rscp.EMS_REQ_EMERGENCY_POWER_STATUS},
+	}},
+	"unchecked": {Release: "S10_2020_07", Quirks: Quirks{NoChecksum: true}},
+}
+
+// unsupported returns if the request tag is not supported
+func (q Quirks) unsupported(tag rscp.Tag) bool {
+	for _, t := range q.Unsupported {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,420 @@
+// Package simulator provides a fake rscp server simulating an E3/DC system with injectable faults.
+//
+// the server answers every known request tag with a configured or zero value, indexed components
+// are available up to the configured count. Faults like dropped connections or partial frames are injected
+// at random to test the reliability of clients.
+package simulator
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var ErrMissingCredentials = errors.New("missing key, user or password")
+
+// Config of a simulated system
+type Config struct {
+	Key      string
+	User     string
+	Password string
+	// auth level granted to the user, AUTH_LEVEL_USER if 0
+	AuthLevel rscp.AuthLevel
+	// additional accounts (i.e. an installer)
+	Accounts []Account
+	// answered to INFO_REQ_SERIAL_NUMBER
+	Serial string
+	// values answered by response tag, zero values are answered for all other tags
+	Values map[rscp.Tag]interface{}
+	// number of components by namespace name (i.e. BAT), namespaces not defined have no components
+	Components map[string]uint16
+	// number of dc strings of each inverter, requests of a string beyond fail with ERR_OUT_OF_BOUNDS
+	Strings uint8
+	// quirks of the simulated firmware
+	Quirks Quirks
+	// Handler answers a request before the values, returns nil to answer by the values
+	Handler func(request rscp.Message) *rscp.Message
+	Faults  Faults
+}
+
+// Account of an additional user
+type Account struct {
+	User     string
+	Password string
+	// auth level granted to the user, AUTH_LEVEL_USER if 0
+	AuthLevel rscp.AuthLevel
+}
+
+// Stats of the server
+type Stats struct {
+	// connections accepted
+	Connections int
+	// connections currently open
+	Active   int
+	Requests int
+	Faults   map[Fault]int
+}
+
+// Server is a fake rscp server
+type Server struct {
+	config   Config
+	listener net.Listener
+	faults   *injector
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	stats    Stats
+}
+
+// partialDelay is the delay between two chunks of a partial response
+const partialDelay = time.Microsecond * 100
+
+// maxChunkSize is the maximum size of a chunk of a partial response
+const maxChunkSize = 7
+
+// Start starts the server listening on the address (i.e. "127.0.0.1:0")
+func Start(address string, c Config) (*Server, error) {
+	if c.Key == "" || c.User == "" || c.Password == "" {
+		return nil, ErrMissingCredentials
+	}
+	c.Accounts = append([]Account{{c.User, c.Password, c.AuthLevel}}, c.Accounts...)
+	for i := range c.Accounts {
+		if c.Accounts[i].AuthLevel == rscp.AUTH_LEVEL_NO_AUTH {
+			c.Accounts[i].AuthLevel = rscp.AUTH_LEVEL_USER
+		}
+	}
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		config:   c,
+		listener: l,
+		faults:   newInjector(c.Faults),
+		conns:    map[net.Conn]struct{}{},
+		stats:    Stats{Faults: map[Fault]int{}},
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Stats returns a copy of the current stats
+func (s *Server) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stats
+	st.Active = len(s.conns)
+	st.Faults = make(map[Fault]int, len(s.stats.Faults))
+	for f, n := range s.stats.Faults {
+		st.Faults[f] = n
+	}
+	return st
+}
+
+// Close stops the server, closes all connections and waits until all goroutines have ended
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// accept accepts connections until the listener is closed
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.stats.Connections++
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serve(c)
+	}
+}
+
+// serve answers the requests of a connection until it's closed
+func (s *Server) serve(c net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		_ = c.Close()
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+	}()
+	encrypter, decrypter := rscp.NewCipherModes(s.config.Key)
+	level := rscp.AUTH_LEVEL_NO_AUTH
+	for {
+		requests, err := receive(c, decrypter)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Debugf("simulator: %s", err)
+			}
+			return
+		}
+		responses, isAuth := s.answer(requests, &level)
+		fault := FaultNone
+		if !isAuth {
+			fault = s.faults.next()
+		}
+		if fault == FaultOmit {
+			responses = omit(responses)
+		}
+		frame, err := rscp.Write(&encrypter, responses, !s.config.Quirks.NoChecksum)
+		if err != nil {
+			log.Errorf("simulator: %s", err)
+			return
+		}
+		s.mu.Lock()
+		s.stats.Requests++
+		if fault != FaultNone {
+			s.stats.Faults[fault]++
+		}
+		s.mu.Unlock()
+		if !s.write(c, frame, fault) {
+			return
+		}
+	}
+}
+
+// write writes the frame with the fault injected, returns false if the connection has to be closed
+func (s *Server) write(c net.Conn, frame []byte, fault Fault) bool {
+	switch fault {
+	case FaultDrop:
+		return false
+	case FaultStall:
+		// wait until the client gives up
+		_, _ = io.Copy(ioutil.Discard, c)
+		return false
+	case FaultTruncate:
+		_, _ = c.Write(frame[:len(frame)/2])
+		return false
+	case FaultCorrupt:
+		frame[len(frame)-1] ^= 0x01
+	case FaultPartial:
+		for len(frame) > 0 {
+			n := 1 + s.faults.intn(maxChunkSize)
+			if n > len(frame) {
+				n = len(frame)
+			}
+			if _, err := c.Write(frame[:n]); err != nil {
+				return false
+			}
+			frame = frame[n:]
+			time.Sleep(partialDelay)
+		}
+		return true
+	case FaultNone, FaultOmit:
+	}
+	_, err := c.Write(frame)
+	return err == nil
+}
+
+// receive reads the next request frame
+func receive(c net.Conn, decrypter cipher.BlockMode) ([]rscp.Message, error) {
+	var (
+		buf       []byte
+		crcFlag   bool
+		frameSize uint32
+		dataSize  uint16
+		pending   []byte
+	)
+	b := make([]byte, rscp.RSCP_CRYPT_BLOCK_SIZE)
+	for {
+		n, err := c.Read(b)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, b[:n]...)
+		complete := len(pending) - len(pending)%int(rscp.RSCP_CRYPT_BLOCK_SIZE)
+		if complete == 0 {
+			continue
+		}
+		data := pending[:complete]
+		pending = pending[complete:]
+		m, err := rscp.Read(&decrypter, &buf, &crcFlag, &frameSize, &dataSize, data)
+		switch {
+		case errors.Is(err, rscp.ErrRscpInvalidFrameLength):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		return m, nil
+	}
+}
+
+// answer returns the responses to the requests and if it's the authentication
+func (s *Server) answer(requests []rscp.Message, level *rscp.AuthLevel) ([]rscp.Message, bool) {
+	responses := make([]rscp.Message, 0, len(requests))
+	isAuth := false
+	for _, r := range requests {
+		switch {
+		case r.Tag == rscp.RSCP_REQ_AUTHENTICATION:
+			isAuth = true
+			*level = s.authenticate(r)
+			if *level == rscp.AUTH_LEVEL_NO_AUTH && s.config.Quirks.AuthInt32 {
+				responses = append(responses, rscp.Message{Tag: rscp.RSCP_AUTHENTICATION, DataType: rscp.Int32, Value: int32(0)})
+				continue
+			}
+			responses = append(responses, rscp.Message{Tag: rscp.RSCP_AUTHENTICATION, DataType: rscp.UChar8, Value: uint8(*level)})
+		case *level == rscp.AUTH_LEVEL_NO_AUTH || rscp.RequestsMinAuthLevel([]rscp.Message{r}) > *level:
+			responses = append(responses, errorResponse(r.Tag, rscp.ERR_ACCESS_DENIED))
+		default:
+			responses = append(responses, s.respond(r))
+		}
+	}
+	return responses, isAuth
+}
+
+// authenticate checks the credentials of the authentication request, returns the auth level granted
+func (s *Server) authenticate(r rscp.Message) rscp.AuthLevel {
+	values, _ := r.Value.([]rscp.Message)
+	user := rscp.FindTag(values, rscp.RSCP_AUTHENTICATION_USER)
+	password := rscp.FindTag(values, rscp.RSCP_AUTHENTICATION_PASSWORD)
+	if user == nil || password == nil {
+		return rscp.AUTH_LEVEL_NO_AUTH
+	}
+	for _, a := range s.config.Accounts {
+		if user.Value == a.User && password.Value == a.Password {
+			return a.AuthLevel
+		}
+	}
+	return rscp.AUTH_LEVEL_NO_AUTH
+}
+
+// errorResponse returns the error response to the request
+func errorResponse(tag rscp.Tag, e rscp.RscpError) rscp.Message {
+	return rscp.Message{Tag: tag.ResponseTag(), DataType: rscp.Error, Value: e}
+}
+
+// respond returns the response to a single request
+func (s *Server) respond(r rscp.Message) rscp.Message {
+	if s.config.Handler != nil {
+		if m := s.config.Handler(r); m != nil {
+			return *m
+		}
+	}
+	if s.config.Quirks.unsupported(r.Tag) {
+		return errorResponse(r.Tag, rscp.ERR_NOT_HANDLED)
+	}
+	rt := r.Tag.ResponseTag()
+	if !rt.IsATag() {
+		// i.e. index tags are returned as they are
+		return r
+	}
+	if n, ok := rscp.NamespaceByContainer(r.Tag); ok {
+		return s.respondComponent(n, r)
+	}
+	if n, ok := rscp.NamespaceByTag(r.Tag); ok && r.Tag == n.DeviceStateTag {
+		return deviceState(n)
+	}
+	if r.Tag >= rscp.PVI_REQ_DC_POWER && r.Tag <= rscp.PVI_REQ_DC_STRING_ENERGY_ALL {
+		return s.respondString(r)
+	}
+	var value interface{}
+	if v, ok := s.config.Values[rt]; ok {
+		value = v
+	} else if rt == rscp.INFO_SERIAL_NUMBER {
+		value = s.config.Serial
+	} else if rt == rscp.PVI_DC_MAX_STRING_COUNT {
+		value = s.config.Strings
+	}
+	if rt.DataType() == rscp.Container {
+		// answer the nested requests
+		values := []rscp.Message{}
+		nested, _ := r.Value.([]rscp.Message)
+		for _, n := range nested {
+			values = append(values, s.respond(n))
+		}
+		if value == nil {
+			value = values
+		}
+	}
+	m, err := rscp.NewResponse(r.Tag, value)
+	if err != nil {
+		log.Errorf("simulator: %s", err)
+		return errorResponse(r.Tag, rscp.ERR_FORMAT)
+	}
+	return *m
+}
+
+// respondComponent answers the request container of an indexed component
+func (s *Server) respondComponent(n rscp.Namespace, r rscp.Message) rscp.Message {
+	nested, _ := r.Value.([]rscp.Message)
+	index := rscp.FindTag(nested, n.IndexTag)
+	if index == nil {
+		return errorResponse(r.Tag, rscp.ERR_FORMAT)
+	}
+	i, err := index.Float64()
+	if err != nil || i >= float64(s.config.Components[n.Name]) {
+		return errorResponse(r.Tag, rscp.ERR_NOT_AVAILABLE)
+	}
+	values := make([]rscp.Message, 0, len(nested))
+	for _, m := range nested {
+		values = append(values, s.respond(m))
+	}
+	return rscp.Message{Tag: n.ResponseContainer, DataType: rscp.Container, Value: values}
+}
+
+// respondString answers the request of a value of a dc string, the value is answered as float
+func (s *Server) respondString(r rscp.Message) rscp.Message {
+	index, err := r.Float64()
+	if err != nil {
+		return errorResponse(r.Tag, rscp.ERR_FORMAT)
+	}
+	if index >= float64(s.config.Strings) {
+		return errorResponse(r.Tag, rscp.ERR_OUT_OF_BOUNDS)
+	}
+	// the same value is answered for all strings
+	var value float32
+	switch v := s.config.Values[r.Tag.ResponseTag()].(type) {
+	case float32:
+		value = v
+	case float64:
+		value = float32(v)
+	case int:
+		value = float32(v)
+	}
+	return rscp.Message{Tag: r.Tag.ResponseTag(), DataType: rscp.Container, Value: []rscp.Message{
+		{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(index)},
+		{Tag: rscp.PVI_VALUE, DataType: rscp.Float32, Value: value},
+	}}
+}
+
+// deviceState returns the device state of an available component
+func deviceState(n rscp.Namespace) rscp.Message {
+	state := n.DeviceStateTag.ResponseTag()
+	values := []rscp.Message{}
+	for t := state + 1; t <= state+3; t++ {
+		if t.IsATag() {
+			values = append(values, rscp.Message{Tag: t, DataType: rscp.Bool, Value: true})
+		}
+	}
+	return rscp.Message{Tag: state, DataType: rscp.Container, Value: values}
+}
+
+// String returns a short description of the stats
+func (st Stats) String() string {
+	return fmt.Sprintf("%d connections (%d active), %d requests, faults %v", st.Connections, st.Active, st.Requests, st.Faults)
+}
@@ -0,0 +1,191 @@
+package simulator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func start(t *testing.T, c Config) (*Server, *rscp.Client) {
+	c.Key, c.User, c.Password = "key", "user", "password"
+	s, err := Start("127.0.0.1:0", c)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	client, err := rscp.NewClient(rscp.ClientConfig{
+		Address: "127.0.0.1", Port: uint16(s.Addr().Port), Username: "user", Password: "password", Key: "key",
+		ReceiveTimeout: time.Millisecond * 200,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect() })
+	return s, client
+}
+
+func TestServer(t *testing.T) {
+	_, client := start(t, Config{
+		Serial:     "S10-123",
+		Values:     map[rscp.Tag]interface{}{rscp.EMS_BAT_SOC: 42},
+		Components: map[string]uint16{"BAT": 1, "PVI": 2},
+	})
+	requests, _ := rscp.CreateRequests(
+		[]interface{}{rscp.INFO_REQ_SERIAL_NUMBER},
+		[]interface{}{rscp.EMS_REQ_BAT_SOC},
+		[]interface{}{rscp.EMS_REQ_POWER_PV},
+		[]interface{}{rscp.BAT_REQ_DATA, rscp.BAT_INDEX, uint16(0), rscp.BAT_REQ_RSOC},
+	)
+	got, err := client.SendMultiple(requests)
+	if err != nil {
+		t.Fatalf("SendMultiple() error = %v", err)
+	}
+	want := []rscp.Message{
+		{Tag: rscp.INFO_SERIAL_NUMBER, DataType: rscp.CString, Value: "S10-123"},
+		{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: uint8(42)},
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(0)},
+		{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_RSOC, DataType: rscp.Float32, Value: float32(0)},
+		}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("SendMultiple() = %v, want %v\n%s", got, want, diff)
+	}
+	c, err := client.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	for n, count := range map[string]int{"BAT": 1, "PVI": 2, "PM": 0, "WB": 0} {
+		if len(c[n]) != count || (count > 0 && !c[n][0].Connected) {
+			t.Errorf("Discover() %s = %+v, want %d connected", n, c[n], count)
+		}
+	}
+}
+
+func TestServer_authentication(t *testing.T) {
+	s, _ := start(t, Config{})
+	client, _ := rscp.NewClient(rscp.ClientConfig{
+		Address: "127.0.0.1", Port: uint16(s.Addr().Port), Username: "user", Password: "wrong", Key: "key",
+	})
+	defer func() { _ = client.Disconnect() }()
+	if _, err := client.Send(*rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil)); err == nil {
+		t.Errorf("Send() expected authentication error")
+	}
+}
+
+func TestServer_faults(t *testing.T) {
+	for _, fault := range []Fault{FaultDrop, FaultStall, FaultTruncate, FaultPartial, FaultCorrupt} {
+		t.Run(string(fault), func(t *testing.T) {
+			f := Faults{}
+			switch fault {
+			case FaultDrop:
+				f.Drop = 1
+			case FaultStall:
+				f.Stall = 1
+			case FaultTruncate:
+				f.Truncate = 1
+			case FaultPartial:
+				f.Partial = 1
+			case FaultCorrupt:
+				f.Corrupt = 1
+			case FaultNone:
+			}
+			s, client := start(t, Config{Faults: f})
+			_, err := client.Send(*rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil))
+			if (err == nil) != (fault == FaultPartial) {
+				t.Errorf("Send() error = %v", err)
+			}
+			if st := s.Stats(); st.Faults[fault] != 1 {
+				t.Errorf("Stats() = %s", st)
+			}
+		})
+	}
+}
+
+func TestClient_retries(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     rscp.Message
+		wantAttempt int
+		wantUnknown bool
+	}{
+		{"safe", *rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil), 3, false},
+		{"idempotent setter", *rscp.NewMessage(rscp.EMS_REQ_SET_ERROR_BUZZER_ENABLED, true), 3, true},
+		{"non-idempotent", *rscp.NewMessage(rscp.SYS_REQ_SYSTEM_REBOOT, nil), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Start("127.0.0.1:0", Config{Key: "key", User: "user", Password: "password", Faults: Faults{Drop: 1}})
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer func() { _ = s.Close() }()
+			client, err := rscp.NewClient(rscp.ClientConfig{
+				Address: "127.0.0.1", Port: uint16(s.Addr().Port), Username: "user", Password: "password", Key: "key",
+				Retries: 2,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer func() { _ = client.Disconnect() }()
+			_, err = client.Send(tt.request)
+			if err == nil || errors.Is(err, rscp.ErrOutcomeUnknown) != tt.wantUnknown {
+				t.Errorf("Send() error = %v, want outcome unknown %v", err, tt.wantUnknown)
+			}
+			if st := s.Stats(); st.Faults[FaultDrop] != tt.wantAttempt {
+				t.Errorf("Stats() = %s, want %d attempts", st, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestClient_partialResponses(t *testing.T) {
+	omitted := false
+	_, client := start(t, Config{
+		Components: map[string]uint16{"BAT": 1},
+		Handler: func(r rscp.Message) *rscp.Message {
+			if r.Tag != rscp.BAT_REQ_DATA || omitted {
+				return nil
+			}
+			// the first answer of the battery misses the values
+			omitted = true
+			return &rscp.Message{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+				{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			}}
+		},
+	})
+	requests, _ := rscp.CreateRequests(
+		[]interface{}{rscp.BAT_REQ_DATA, rscp.BAT_INDEX, uint16(0), rscp.BAT_REQ_RSOC, rscp.BAT_REQ_MODULE_VOLTAGE},
+		[]interface{}{rscp.EMS_REQ_BAT_SOC},
+	)
+	got, err := client.SendMultiple(requests)
+	if err != nil {
+		t.Fatalf("SendMultiple() error = %v", err)
+	}
+	want := []rscp.Message{
+		{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_RSOC, DataType: rscp.Float32, Value: float32(0)},
+			{Tag: rscp.BAT_MODULE_VOLTAGE, DataType: rscp.Float32, Value: float32(0)},
+		}},
+		{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: uint8(0)},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("SendMultiple() = %v, want %v\n%s", got, want, diff)
+	}
+
+	// the last value of the battery is omitted by every answer
+	s, client := start(t, Config{Components: map[string]uint16{"BAT": 1}, Faults: Faults{Omit: 1}})
+	got, err = client.SendMultiple(requests[:1])
+	if !errors.Is(err, rscp.ErrPartialResponse) || rscp.FindTag(got, rscp.BAT_RSOC) == nil {
+		t.Errorf("SendMultiple() = %v, %v, want %v", got, err, rscp.ErrPartialResponse)
+	}
+	// the request and the default 2 re-requests
+	if st := s.Stats(); st.Faults[FaultOmit] != 3 {
+		t.Errorf("Stats() = %s, want 3 omitted", st)
+	}
+}
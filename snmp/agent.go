@@ -0,0 +1,399 @@
+package snmp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRepetitions limits the repetitions of a GETBULK request
+	maxRepetitions = 100
+	// sysDescr is the description of the system group
+	sysDescr = "E3DC system (go-rscp snmp agent)"
+)
+
+// objects of the system group (RFC 3418) and the notifications (RFC 3416)
+var (
+	sysDescrOID    = MustParseOID("1.3.6.1.2.1.1.1.0")
+	sysObjectIDOID = MustParseOID("1.3.6.1.2.1.1.2.0")
+	sysUpTimeOID   = MustParseOID("1.3.6.1.2.1.1.3.0")
+	sysNameOID     = MustParseOID("1.3.6.1.2.1.1.5.0")
+	sysLocationOID = MustParseOID("1.3.6.1.2.1.1.6.0")
+	snmpTrapOID    = MustParseOID("1.3.6.1.6.3.1.1.4.1.0")
+)
+
+// localUser is a user with the keys localized to the engine of the agent
+type localUser struct {
+	User
+	keys *keys
+}
+
+// Agent serves the objects with SNMPv2c and SNMPv3 and sends notifications to the targets, safe for concurrent use
+type Agent struct {
+	config   Config
+	conn     *net.UDPConn
+	users    map[string]localUser
+	engineID []byte
+	boots    int32
+	start    time.Time
+	// system group
+	system []Varbind
+	mu     sync.RWMutex
+	tree   *Tree
+	// usmStats counters by object identifier
+	stats map[string]Counter32
+	done  chan struct{}
+}
+
+// Listen starts the agent with the engine of the state, the objects are set by Update
+func Listen(c Config, s *State) (*Agent, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	addr, err := net.ResolveUDPAddr("udp", c.Listen)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	a := &Agent{
+		config:   c,
+		conn:     conn,
+		users:    map[string]localUser{},
+		engineID: s.EngineID,
+		boots:    s.Boots,
+		start:    time.Now(),
+		system: []Varbind{
+			{sysDescrOID, sysDescr},
+			{sysObjectIDOID, c.enterprise},
+			{sysUpTimeOID, TimeTicks(0)},
+			{sysNameOID, c.Name},
+			{sysLocationOID, c.Location},
+		},
+		stats: map[string]Counter32{},
+		done:  make(chan struct{}),
+	}
+	for _, u := range c.Users {
+		a.users[u.Name] = localUser{User: u, keys: u.localize(a.engineID)}
+	}
+	a.tree = NewTree(a.system)
+	go a.serve()
+	return a, nil
+}
+
+// Addr returns the address the agent listens on
+func (a *Agent) Addr() *net.UDPAddr {
+	return a.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Close stops the agent
+func (a *Agent) Close() error {
+	err := a.conn.Close()
+	<-a.done
+	return err
+}
+
+// Update replaces the objects served in addition to the system group
+func (a *Agent) Update(objects []Varbind) {
+	t := NewTree(append(append([]Varbind{}, a.system...), objects...))
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tree = t
+}
+
+// uptime returns the time since the start of the agent
+func (a *Agent) uptime() TimeTicks {
+	return TimeTicks(time.Since(a.start) / (time.Second / 100)) //nolint: gomnd
+}
+
+// engineTime returns the seconds since the boot of the engine
+func (a *Agent) engineTime() int32 {
+	return int32(time.Since(a.start) / time.Second)
+}
+
+// serve answers the requests until closed
+func (a *Agent) serve() {
+	defer close(a.done)
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Errorf("snmp: receive failed: %s", err)
+			continue
+		}
+		response, err := a.handle(buf[:n])
+		if err != nil {
+			log.Debugf("snmp: dropping message of %s: %s", addr, err)
+			continue
+		}
+		if _, err := a.conn.WriteToUDP(response, addr); err != nil {
+			log.Errorf("snmp: sending response to %s failed: %s", addr, err)
+		}
+	}
+}
+
+// handle returns the response to a message
+func (a *Agent) handle(b []byte) ([]byte, error) {
+	version, d, err := decodeVersion(b)
+	if err != nil {
+		return nil, err
+	}
+	switch version {
+	case Version2c:
+		community, p, err := decodeCommunity(d)
+		if err != nil {
+			return nil, err
+		}
+		if a.config.Community == "" || community != a.config.Community {
+			return nil, ErrWrongCommunity
+		}
+		r, err := a.respond(p, maxMessageSize)
+		if err != nil {
+			return nil, err
+		}
+		return encodeCommunity(community, r)
+	case Version3:
+		return a.handleV3(b, d)
+	}
+	return nil, fmt.Errorf("%d: %w", version, ErrUnsupportedVersion)
+}
+
+// handleV3 returns the response or report to a SNMPv3 message
+func (a *Agent) handleV3(b []byte, d decoder) ([]byte, error) {
+	m, err := decodeV3(d)
+	if err != nil {
+		return nil, err
+	}
+	level := m.flags & (flagAuth | flagPriv)
+	if len(m.engineID) == 0 || !bytes.Equal(m.engineID, a.engineID) {
+		// engine discovery
+		return a.report(m, b, usmStatsUnknownEngineIDs, nil)
+	}
+	u, exists := a.users[m.user]
+	if !exists {
+		return a.report(m, b, usmStatsUnknownUserNames, nil)
+	}
+	if level != u.flags() {
+		return a.report(m, b, usmStatsUnsupportedSecLevels, nil)
+	}
+	switch err := m.open(b, u.keys); {
+	case errors.Is(err, ErrWrongDigest):
+		return a.report(m, nil, usmStatsWrongDigests, nil)
+	case errors.Is(err, ErrDecryption):
+		return a.report(m, nil, usmStatsDecryptionErrors, nil)
+	case err != nil:
+		return nil, err
+	}
+	if level&flagAuth != 0 {
+		if diff := m.time - a.engineTime(); m.boots != a.boots || diff > timeWindow || diff < -timeWindow {
+			return a.report(m, nil, usmStatsNotInTimeWindows, u.keys)
+		}
+	}
+	maxSize := int(m.maxSize)
+	if maxSize <= 0 || maxSize > maxMessageSize {
+		maxSize = maxMessageSize
+	}
+	p, err := a.respond(m.pdu, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	r := &v3Message{
+		id:              m.id,
+		maxSize:         maxMessageSize,
+		flags:           level,
+		engineID:        a.engineID,
+		boots:           a.boots,
+		time:            a.engineTime(),
+		user:            m.user,
+		contextEngineID: a.engineID,
+		contextName:     m.contextName,
+		pdu:             p,
+	}
+	return r.encode(u.keys)
+}
+
+// report returns a report of the usmStats counter, the request id is taken from the request if not encrypted.
+// The report is authenticated with the keys if not nil.
+func (a *Agent) report(m *v3Message, b []byte, counter OID, k *keys) ([]byte, error) {
+	if m.flags&flagReportable == 0 {
+		return nil, fmt.Errorf("%s: %w", counter, ErrReport)
+	}
+	a.mu.Lock()
+	a.stats[counter.String()]++
+	count := a.stats[counter.String()]
+	a.mu.Unlock()
+	requestID := m.pdu.RequestID
+	if b != nil && m.flags&flagPriv == 0 && m.flags&flagAuth == 0 {
+		if err := m.open(b, nil); err == nil {
+			requestID = m.pdu.RequestID
+		}
+	}
+	var flags byte
+	if k != nil {
+		flags = flagAuth
+	}
+	r := &v3Message{
+		id:              m.id,
+		maxSize:         maxMessageSize,
+		flags:           flags,
+		engineID:        a.engineID,
+		boots:           a.boots,
+		time:            a.engineTime(),
+		user:            m.user,
+		contextEngineID: a.engineID,
+		pdu: PDU{
+			Type:      PDUReport,
+			RequestID: requestID,
+			Varbinds:  []Varbind{{counter, count}},
+		},
+	}
+	return r.encode(k)
+}
+
+// respond returns the response to a request PDU
+func (a *Agent) respond(p PDU, maxSize int) (PDU, error) {
+	r := PDU{Type: PDUResponse, RequestID: p.RequestID}
+	a.mu.RLock()
+	t := a.tree
+	a.mu.RUnlock()
+	switch p.Type {
+	case PDUGet:
+		for _, vb := range p.Varbinds {
+			r.Varbinds = append(r.Varbinds, a.resolve(Varbind{vb.OID, t.Get(vb.OID)}))
+		}
+	case PDUGetNext:
+		for _, vb := range p.Varbinds {
+			r.Varbinds = append(r.Varbinds, a.next(t, vb.OID))
+		}
+	case PDUGetBulk:
+		nonRepeaters, repetitions := p.ErrorStatus, p.ErrorIndex
+		if nonRepeaters < 0 {
+			nonRepeaters = 0
+		}
+		if nonRepeaters > len(p.Varbinds) {
+			nonRepeaters = len(p.Varbinds)
+		}
+		if repetitions > maxRepetitions {
+			repetitions = maxRepetitions
+		}
+		for _, vb := range p.Varbinds[:nonRepeaters] {
+			r.Varbinds = append(r.Varbinds, a.next(t, vb.OID))
+		}
+		last := p.Varbinds[nonRepeaters:]
+		for i := 0; i < repetitions && len(last) > 0; i++ {
+			next, end := make([]Varbind, len(last)), true
+			for j, vb := range last {
+				next[j] = a.next(t, vb.OID)
+				end = end && next[j].Value == EndOfMibView
+			}
+			r.Varbinds = append(r.Varbinds, next...)
+			if end {
+				break
+			}
+			last = next
+		}
+	case PDUSet:
+		r.ErrorStatus, r.ErrorIndex, r.Varbinds = StatusNotWritable, 1, p.Varbinds
+	default:
+		return r, fmt.Errorf("pdu %#x: %w", p.Type, ErrUnexpectedTag)
+	}
+	return a.fit(p, r, maxSize)
+}
+
+// fit truncates the varbinds of a GETBULK response to the max message size or returns tooBig
+func (a *Agent) fit(request, response PDU, maxSize int) (PDU, error) {
+	// reserve for the message header and security parameters
+	const overhead = 200
+	for {
+		b, err := response.encode()
+		if err != nil {
+			return response, err
+		}
+		if len(b)+overhead <= maxSize {
+			return response, nil
+		}
+		if request.Type != PDUGetBulk || len(response.Varbinds) <= 1 {
+			return PDU{Type: PDUResponse, RequestID: request.RequestID, ErrorStatus: StatusTooBig}, nil
+		}
+		response.Varbinds = response.Varbinds[:len(response.Varbinds)/2]
+	}
+}
+
+// next returns the object following the object identifier, EndOfMibView if there is none
+func (a *Agent) next(t *Tree, o OID) Varbind {
+	vb, found := t.Next(o)
+	if !found {
+		return Varbind{o, EndOfMibView}
+	}
+	return a.resolve(vb)
+}
+
+// resolve sets the value of the objects maintained by the agent
+func (a *Agent) resolve(vb Varbind) Varbind {
+	if vb.OID.Compare(sysUpTimeOID) == 0 {
+		vb.Value = a.uptime()
+	}
+	return vb
+}
+
+// Notify sends the notification with the objects to all targets, returns the first error
+func (a *Agent) Notify(trap OID, objects ...Varbind) error {
+	p := PDU{
+		Type:      PDUTrap,
+		RequestID: rand.Int31(), //nolint: gosec
+		Varbinds:  append([]Varbind{{sysUpTimeOID, a.uptime()}, {snmpTrapOID, trap}}, objects...),
+	}
+	var first error
+	for _, t := range a.config.Targets {
+		if err := a.notify(t, p); err != nil {
+			log.Errorf("snmp: notification %s to %s failed: %s", trap, t.Address, err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// notify sends the notification to the target
+func (a *Agent) notify(t Target, p PDU) error {
+	addr, err := net.ResolveUDPAddr("udp", t.Address)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if t.User != "" {
+		u := a.users[t.User]
+		m := &v3Message{
+			id:              rand.Int31(), //nolint: gosec
+			maxSize:         maxMessageSize,
+			flags:           u.flags(),
+			engineID:        a.engineID,
+			boots:           a.boots,
+			time:            a.engineTime(),
+			user:            u.Name,
+			contextEngineID: a.engineID,
+			pdu:             p,
+		}
+		b, err = m.encode(u.keys)
+	} else {
+		b, err = encodeCommunity(t.Community, p)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = a.conn.WriteToUDP(b, addr)
+	return err
+}
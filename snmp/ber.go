@@ -0,0 +1,370 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BER tags of the types used by SNMP (RFC 3416)
+const (
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagNull        = 0x05
+	tagOID         = 0x06
+	tagSequence    = 0x30
+	tagIPAddress   = 0x40
+	tagCounter32   = 0x41
+	tagGauge32     = 0x42
+	tagTimeTicks   = 0x43
+	tagCounter64   = 0x46
+	// exceptions of a variable binding
+	tagNoSuchObject   = 0x80
+	tagNoSuchInstance = 0x81
+	tagEndOfMibView   = 0x82
+)
+
+// OID is an object identifier
+type OID []uint32
+
+// ParseOID parses a dotted object identifier (i.e. 1.3.6.1.2.1.1.3.0), a leading dot is ignored
+func ParseOID(s string) (OID, error) {
+	s = strings.TrimPrefix(s, ".")
+	if s == "" {
+		return nil, fmt.Errorf("empty oid: %w", ErrInvalidOID)
+	}
+	parts := strings.Split(s, ".")
+	o := make(OID, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s, ErrInvalidOID)
+		}
+		o[i] = uint32(v)
+	}
+	return o, nil
+}
+
+// MustParseOID parses the object identifier, panics if invalid
+func MustParseOID(s string) OID {
+	o, err := ParseOID(s)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// String returns the dotted object identifier
+func (o OID) String() string {
+	parts := make([]string, len(o))
+	for i, v := range o {
+		parts[i] = strconv.FormatUint(uint64(v), 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Append returns a new object identifier with the sub identifiers appended
+func (o OID) Append(sub ...uint32) OID {
+	r := make(OID, 0, len(o)+len(sub))
+	return append(append(r, o...), sub...)
+}
+
+// Compare compares the object identifiers lexicographically, returns -1, 0 or 1
+func (o OID) Compare(other OID) int {
+	for i := 0; i < len(o) && i < len(other); i++ {
+		switch {
+		case o[i] < other[i]:
+			return -1
+		case o[i] > other[i]:
+			return 1
+		}
+	}
+	switch {
+	case len(o) < len(other):
+		return -1
+	case len(o) > len(other):
+		return 1
+	}
+	return 0
+}
+
+// HasPrefix returns if the object identifier is within the subtree of the prefix
+func (o OID) HasPrefix(prefix OID) bool {
+	return len(o) >= len(prefix) && o[:len(prefix)].Compare(prefix) == 0
+}
+
+// Counter32 is a 32 bit counter value
+type Counter32 uint32
+
+// Gauge32 is a 32 bit unsigned value (also Unsigned32)
+type Gauge32 uint32
+
+// TimeTicks is a time in hundredths of a second
+type TimeTicks uint32
+
+// Counter64 is a 64 bit counter value
+type Counter64 uint64
+
+// exception of a variable binding instead of a value
+type exception byte
+
+// exceptions of a variable binding
+const (
+	NoSuchObject   exception = tagNoSuchObject
+	NoSuchInstance exception = tagNoSuchInstance
+	EndOfMibView   exception = tagEndOfMibView
+)
+
+// String returns the name of the exception
+func (e exception) String() string {
+	switch e {
+	case NoSuchObject:
+		return "noSuchObject"
+	case NoSuchInstance:
+		return "noSuchInstance"
+	case EndOfMibView:
+		return "endOfMibView"
+	}
+	return fmt.Sprintf("exception(%#x)", byte(e))
+}
+
+// encodeLength encodes the length of a BER element in the short or long form
+func encodeLength(l int) []byte {
+	if l < 0x80 {
+		return []byte{byte(l)}
+	}
+	var b []byte
+	for ; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// tlv encodes a BER element
+func tlv(tag byte, content ...[]byte) []byte {
+	l := 0
+	for _, c := range content {
+		l += len(c)
+	}
+	b := append([]byte{tag}, encodeLength(l)...)
+	for _, c := range content {
+		b = append(b, c...)
+	}
+	return b
+}
+
+// encodeInt encodes a signed integer in the minimal two's complement form
+func encodeInt(tag byte, v int64) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; !(v == 0 && b[0]&0x80 == 0) && !(v == -1 && b[0]&0x80 != 0); v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	return tlv(tag, b)
+}
+
+// encodeUint encodes an unsigned integer, with a leading zero octet if the high bit is set
+func encodeUint(tag byte, v uint64) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return tlv(tag, b)
+}
+
+// encodeOID encodes an object identifier
+func encodeOID(o OID) ([]byte, error) {
+	if len(o) < 2 || o[0] > 2 || (o[0] < 2 && o[1] >= 40) {
+		return nil, fmt.Errorf("%s: %w", o, ErrInvalidOID)
+	}
+	b := encodeSubID(nil, o[0]*40+o[1])
+	for _, v := range o[2:] {
+		b = encodeSubID(b, v)
+	}
+	return tlv(tagOID, b), nil
+}
+
+// encodeSubID appends the base 128 encoded sub identifier
+func encodeSubID(b []byte, v uint32) []byte {
+	s := []byte{byte(v & 0x7f)}
+	for v >>= 7; v > 0; v >>= 7 {
+		s = append([]byte{byte(v&0x7f) | 0x80}, s...)
+	}
+	return append(b, s...)
+}
+
+// encodeValue encodes the value of a variable binding
+func encodeValue(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return tlv(tagNull), nil
+	case int:
+		return encodeInt(tagInteger, int64(v)), nil
+	case int32:
+		return encodeInt(tagInteger, int64(v)), nil
+	case int64:
+		return encodeInt(tagInteger, v), nil
+	case bool:
+		// TruthValue (RFC 2579)
+		if v {
+			return encodeInt(tagInteger, 1), nil
+		}
+		return encodeInt(tagInteger, 2), nil //nolint: gomnd
+	case string:
+		return tlv(tagOctetString, []byte(v)), nil
+	case []byte:
+		return tlv(tagOctetString, v), nil
+	case OID:
+		return encodeOID(v)
+	case Counter32:
+		return encodeUint(tagCounter32, uint64(v)), nil
+	case Gauge32:
+		return encodeUint(tagGauge32, uint64(v)), nil
+	case TimeTicks:
+		return encodeUint(tagTimeTicks, uint64(v)), nil
+	case Counter64:
+		return encodeUint(tagCounter64, uint64(v)), nil
+	case exception:
+		return tlv(byte(v)), nil
+	}
+	return nil, fmt.Errorf("%T: %w", v, ErrUnsupportedType)
+}
+
+// decoder reads BER elements
+type decoder []byte
+
+// next reads the next element, returns its tag and content
+func (d *decoder) next() (byte, []byte, error) {
+	b := *d
+	if len(b) < 2 {
+		return 0, nil, ErrTruncated
+	}
+	tag, l, n := b[0], int(b[1]), 2
+	if l&0x80 != 0 {
+		octets := l & 0x7f
+		if octets == 0 || octets > 4 || len(b) < 2+octets {
+			return 0, nil, ErrInvalidLength
+		}
+		l = 0
+		for _, o := range b[2 : 2+octets] {
+			l = l<<8 | int(o)
+		}
+		n += octets
+	}
+	if l < 0 || len(b)-n < l {
+		return 0, nil, ErrTruncated
+	}
+	*d = b[n+l:]
+	return tag, b[n : n+l], nil
+}
+
+// expect reads the next element and fails if its tag does not match
+func (d *decoder) expect(tag byte) ([]byte, error) {
+	t, c, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	if t != tag {
+		return nil, fmt.Errorf("tag %#x instead of %#x: %w", t, tag, ErrUnexpectedTag)
+	}
+	return c, nil
+}
+
+// int reads the next element as integer
+func (d *decoder) int() (int64, error) {
+	c, err := d.expect(tagInteger)
+	if err != nil {
+		return 0, err
+	}
+	return decodeInt(c)
+}
+
+// decodeInt decodes a two's complement integer
+func decodeInt(c []byte) (int64, error) {
+	if len(c) == 0 || len(c) > 8 {
+		return 0, ErrInvalidLength
+	}
+	v := int64(int8(c[0]))
+	for _, b := range c[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, nil
+}
+
+// decodeUint decodes an unsigned integer
+func decodeUint(c []byte) (uint64, error) {
+	if len(c) == 0 || len(c) > 9 || (len(c) == 9 && c[0] != 0) {
+		return 0, ErrInvalidLength
+	}
+	var v uint64
+	for _, b := range c {
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// decodeOID decodes an object identifier
+func decodeOID(c []byte) (OID, error) {
+	var o OID
+	var v uint32
+	for i, b := range c {
+		if v > 0x1ffffff {
+			return nil, ErrInvalidOID
+		}
+		v = v<<7 | uint32(b&0x7f)
+		if b&0x80 != 0 {
+			if i == len(c)-1 {
+				return nil, ErrInvalidOID
+			}
+			continue
+		}
+		if o == nil {
+			first := v / 40 //nolint: gomnd
+			if first > 2 {
+				first = 2
+			}
+			o = OID{first, v - first*40} //nolint: gomnd
+		} else {
+			o = append(o, v)
+		}
+		v = 0
+	}
+	if o == nil {
+		return nil, ErrInvalidOID
+	}
+	return o, nil
+}
+
+// decodeValue decodes the value of a variable binding
+func decodeValue(tag byte, c []byte) (interface{}, error) {
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagInteger:
+		v, err := decodeInt(c)
+		return int(v), err
+	case tagOctetString:
+		return append([]byte{}, c...), nil
+	case tagOID:
+		return decodeOID(c)
+	case tagIPAddress:
+		return append([]byte{}, c...), nil
+	case tagCounter32:
+		v, err := decodeUint(c)
+		return Counter32(v), err
+	case tagGauge32:
+		v, err := decodeUint(c)
+		return Gauge32(v), err
+	case tagTimeTicks:
+		v, err := decodeUint(c)
+		return TimeTicks(v), err
+	case tagCounter64:
+		v, err := decodeUint(c)
+		return Counter64(v), err
+	case tagNoSuchObject, tagNoSuchInstance, tagEndOfMibView:
+		return exception(tag), nil
+	}
+	return nil, fmt.Errorf("tag %#x: %w", tag, ErrUnsupportedType)
+}
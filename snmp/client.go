@@ -0,0 +1,352 @@
+package snmp
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+)
+
+const (
+	// walkRepetitions is the max repetitions of the GETBULK requests of a walk
+	walkRepetitions = 20
+	// timeSyncRetries is the number of retries of a request after a report of the time window
+	timeSyncRetries = 1
+)
+
+// ClientConfig of a client
+type ClientConfig struct {
+	// community of SNMPv2c, used if User is nil
+	Community string
+	// user of SNMPv3
+	User *User
+	// timeout of a request
+	Timeout time.Duration
+}
+
+// defaultClientConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultClientConfig = ClientConfig{
+	Timeout: time.Second * 2,
+}
+
+// Client is a SNMPv2c or SNMPv3 client (i.e. to query an agent in tests or scripts), not safe for concurrent use
+type Client struct {
+	config ClientConfig
+	conn   *net.UDPConn
+	// engine of the agent discovered by the first SNMPv3 request
+	engineID []byte
+	boots    int32
+	time     int32
+	synced   time.Time
+	keys     *keys
+}
+
+// Dial creates a client of the agent at the address
+func Dial(address string, c ClientConfig) (*Client, error) {
+	if c.User != nil {
+		if err := c.User.check(); err != nil {
+			return nil, err
+		}
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultClientConfig.Timeout
+	}
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{config: c, conn: conn}, nil
+}
+
+// Close closes the client
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Get returns the values of the objects
+func (c *Client) Get(oids ...OID) ([]Varbind, error) {
+	return c.request(PDUGet, 0, 0, oids)
+}
+
+// GetNext returns the objects following the object identifiers
+func (c *Client) GetNext(oids ...OID) ([]Varbind, error) {
+	return c.request(PDUGetNext, 0, 0, oids)
+}
+
+// GetBulk returns the objects following the non repeaters once and the others up to the max repetitions
+func (c *Client) GetBulk(nonRepeaters, maxRepetitions int, oids ...OID) ([]Varbind, error) {
+	return c.request(PDUGetBulk, nonRepeaters, maxRepetitions, oids)
+}
+
+// Walk returns all objects within the subtree of the root
+func (c *Client) Walk(root OID) ([]Varbind, error) {
+	r := []Varbind{}
+	for o := root; ; {
+		vbs, err := c.GetBulk(0, walkRepetitions, o)
+		if err != nil {
+			return r, err
+		}
+		for _, vb := range vbs {
+			if vb.Value == EndOfMibView || !vb.OID.HasPrefix(root) {
+				return r, nil
+			}
+			r = append(r, vb)
+			o = vb.OID
+		}
+		if len(vbs) == 0 {
+			return r, nil
+		}
+	}
+}
+
+// request sends the request and returns the varbinds of the response, fails on an error status
+func (c *Client) request(t byte, status, index int, oids []OID) ([]Varbind, error) {
+	p := PDU{Type: t, ErrorStatus: status, ErrorIndex: index}
+	for _, o := range oids {
+		p.Varbinds = append(p.Varbinds, Varbind{OID: o})
+	}
+	r, err := c.Send(p)
+	if err != nil {
+		return nil, err
+	}
+	if r.ErrorStatus != StatusNoError {
+		return r.Varbinds, fmt.Errorf("error status %d at %d: %w", r.ErrorStatus, r.ErrorIndex, ErrResponse)
+	}
+	return r.Varbinds, nil
+}
+
+// Send sends the PDU with a new request id and returns the response
+func (c *Client) Send(p PDU) (PDU, error) {
+	p.RequestID = rand.Int31() //nolint: gosec
+	if c.config.User == nil {
+		b, err := encodeCommunity(c.config.Community, p)
+		if err != nil {
+			return PDU{}, err
+		}
+		return c.roundTrip(b, p.RequestID, func(b []byte) (PDU, error) {
+			version, d, err := decodeVersion(b)
+			if err != nil {
+				return PDU{}, err
+			}
+			if version != Version2c {
+				return PDU{}, fmt.Errorf("%d: %w", version, ErrUnsupportedVersion)
+			}
+			_, r, err := decodeCommunity(d)
+			return r, err
+		})
+	}
+	if c.engineID == nil {
+		if err := c.discover(); err != nil {
+			return PDU{}, err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		r, err := c.sendV3(p, c.config.User.flags(), c.keys)
+		if err != nil {
+			return r, err
+		}
+		if r.Type != PDUReport {
+			return r, nil
+		}
+		// the engine time was synchronized by the report
+		if attempt >= timeSyncRetries || len(r.Varbinds) == 0 || r.Varbinds[0].OID.Compare(usmStatsNotInTimeWindows) != 0 {
+			return r, fmt.Errorf("%s: %w", reportOID(r), ErrReport)
+		}
+	}
+}
+
+// reportOID returns the counter of a report
+func reportOID(r PDU) OID {
+	if len(r.Varbinds) == 0 {
+		return nil
+	}
+	return r.Varbinds[0].OID
+}
+
+// discover discovers the engine of the agent and localizes the keys
+func (c *Client) discover() error {
+	r, err := c.sendV3(PDU{Type: PDUGet, RequestID: rand.Int31()}, 0, nil) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	if c.engineID == nil {
+		return fmt.Errorf("engine discovery: %s: %w", reportOID(r), ErrReport)
+	}
+	c.keys = c.config.User.localize(c.engineID)
+	return nil
+}
+
+// engineTime returns the estimated time of the engine
+func (c *Client) engineTime() int32 {
+	return c.time + int32(time.Since(c.synced)/time.Second)
+}
+
+// sendV3 sends the PDU as SNMPv3 message with the security level of the flags, returns the response or report
+func (c *Client) sendV3(p PDU, flags byte, k *keys) (PDU, error) {
+	user := ""
+	if flags != 0 {
+		user = c.config.User.Name
+	}
+	m := &v3Message{
+		id:              rand.Int31(), //nolint: gosec
+		maxSize:         maxMessageSize,
+		flags:           flags | flagReportable,
+		engineID:        c.engineID,
+		boots:           c.boots,
+		time:            c.engineTime(),
+		user:            user,
+		contextEngineID: c.engineID,
+		pdu:             p,
+	}
+	if c.engineID == nil {
+		m.time = 0
+	}
+	b, err := m.encode(k)
+	if err != nil {
+		return PDU{}, err
+	}
+	return c.roundTrip(b, p.RequestID, func(b []byte) (PDU, error) {
+		version, d, err := decodeVersion(b)
+		if err != nil {
+			return PDU{}, err
+		}
+		if version != Version3 {
+			return PDU{}, fmt.Errorf("%d: %w", version, ErrUnsupportedVersion)
+		}
+		r, err := decodeV3(d)
+		if err != nil {
+			return PDU{}, err
+		}
+		if r.id != m.id {
+			return PDU{}, fmt.Errorf("message id %d instead of %d: %w", r.id, m.id, ErrUnexpectedResponse)
+		}
+		if c.engineID != nil && !bytes.Equal(r.engineID, c.engineID) {
+			return PDU{}, fmt.Errorf("engine %x: %w", r.engineID, ErrUnexpectedResponse)
+		}
+		var rk *keys
+		if r.flags&flagAuth != 0 {
+			rk = k
+		}
+		if err := r.open(b, rk); err != nil {
+			return PDU{}, err
+		}
+		if r.pdu.Type == PDUReport || r.flags&flagAuth != 0 {
+			// synchronize with the engine of the agent by reports and authenticated responses
+			c.engineID, c.boots, c.time, c.synced = r.engineID, r.boots, r.time, time.Now()
+		}
+		return r.pdu, nil
+	})
+}
+
+// roundTrip sends the message and returns the decoded response to the request id
+func (c *Client) roundTrip(b []byte, requestID int32, decode func([]byte) (PDU, error)) (PDU, error) {
+	if _, err := c.conn.Write(b); err != nil {
+		return PDU{}, err
+	}
+	if err := c.conn.SetReadDeadline(time.Now().Add(c.config.Timeout)); err != nil {
+		return PDU{}, err
+	}
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return PDU{}, err
+		}
+		r, err := decode(buf[:n])
+		if err != nil {
+			return PDU{}, err
+		}
+		// reports of a failed request id can not be decoded, i.e. unknown users
+		if r.RequestID == requestID || r.Type == PDUReport {
+			return r, nil
+		}
+	}
+}
+
+// TrapListener receives notifications of SNMPv2c and SNMPv3 (i.e. in tests)
+type TrapListener struct {
+	conn      *net.UDPConn
+	community string
+	users     map[string]User
+	// keys by engine and user
+	keys map[string]*keys
+}
+
+// ListenTraps creates a listener of the notifications of the community or the users
+func ListenTraps(address, community string, users ...User) (*TrapListener, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	l := &TrapListener{conn: conn, community: community, users: map[string]User{}, keys: map[string]*keys{}}
+	for _, u := range users {
+		l.users[u.Name] = u
+	}
+	return l, nil
+}
+
+// Addr returns the address the listener receives on
+func (l *TrapListener) Addr() *net.UDPAddr {
+	return l.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Close closes the listener
+func (l *TrapListener) Close() error {
+	return l.conn.Close()
+}
+
+// Receive waits for the next notification, returns its PDU
+func (l *TrapListener) Receive(timeout time.Duration) (PDU, error) {
+	if err := l.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return PDU{}, err
+	}
+	buf := make([]byte, maxMessageSize)
+	n, _, err := l.conn.ReadFromUDP(buf)
+	if err != nil {
+		return PDU{}, err
+	}
+	version, d, err := decodeVersion(buf[:n])
+	if err != nil {
+		return PDU{}, err
+	}
+	switch version {
+	case Version2c:
+		community, p, err := decodeCommunity(d)
+		if err != nil {
+			return p, err
+		}
+		if community != l.community {
+			return p, ErrWrongCommunity
+		}
+		return p, nil
+	case Version3:
+		m, err := decodeV3(d)
+		if err != nil {
+			return PDU{}, err
+		}
+		u, exists := l.users[m.user]
+		if !exists {
+			return PDU{}, fmt.Errorf("%s: %w", m.user, ErrUnknownUser)
+		}
+		if m.flags&(flagAuth|flagPriv) != u.flags() {
+			return PDU{}, fmt.Errorf("%s: security level: %w", m.user, ErrUnsupportedSecurity)
+		}
+		id := fmt.Sprintf("%x/%s", m.engineID, m.user)
+		if _, localized := l.keys[id]; !localized {
+			l.keys[id] = u.localize(m.engineID)
+		}
+		err = m.open(buf[:n], l.keys[id])
+		return m.pdu, err
+	}
+	return PDU{}, fmt.Errorf("%d: %w", version, ErrUnsupportedVersion)
+}
@@ -0,0 +1,175 @@
+// Package snmp provides a SNMP agent serving the telemetry of a system as E3DC MIB.
+//
+// the agent answers GET, GETNEXT and GETBULK requests of SNMPv2c (community) and SNMPv3 (user-based security model
+// with HMAC-MD5-96/HMAC-SHA-96 authentication and AES-128 privacy), SET requests are refused as not writable.
+// The MIB is generated from the tag metadata: the objects are identified by the rscp tag below the enterprise
+// (see WriteMIB). The values are polled from the system by the Monitor, which returns notifications for alarm events
+// (grid loss, new errors, connection lost) sent as traps to the configured targets.
+package snmp
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+var (
+	ErrInvalidOID          = errors.New("invalid object identifier")
+	ErrUnsupportedType     = errors.New("unsupported type")
+	ErrTruncated           = errors.New("truncated element")
+	ErrInvalidLength       = errors.New("invalid length")
+	ErrUnexpectedTag       = errors.New("unexpected tag")
+	ErrUnsupportedVersion  = errors.New("unsupported version")
+	ErrUnsupportedSecurity = errors.New("unsupported security")
+	ErrWrongCommunity      = errors.New("wrong community")
+	ErrMissingUserName     = errors.New("missing user name")
+	ErrInvalidUser         = errors.New("invalid user")
+	ErrUnknownUser         = errors.New("unknown user")
+	ErrMissingKeys         = errors.New("missing keys of the security level")
+	ErrWrongDigest         = errors.New("wrong digest")
+	ErrDecryption          = errors.New("decryption error")
+	ErrReport              = errors.New("report received")
+	ErrResponse            = errors.New("error response")
+	ErrUnexpectedResponse  = errors.New("unexpected response")
+	ErrNoAccess            = errors.New("neither community nor users configured")
+	ErrMissingTarget       = errors.New("missing target address")
+)
+
+const (
+	// defaultTrapPort is the port of the targets without port
+	defaultTrapPort = "162"
+	// enterpriseEngineID is the format of the generated engine id (RFC 3411: enterprise number and octets)
+	enterpriseEngineID = 0x80000000
+	// engineIDOctets is the format octet of the engine id followed by random octets
+	engineIDOctets = 5
+)
+
+// enterprises is the prefix of the private enterprise numbers
+var enterprises = OID{1, 3, 6, 1, 4, 1}
+
+// Target of the notifications
+type Target struct {
+	// host and port (162 if missing)
+	Address string `json:"address"`
+	// community of a SNMPv2c trap
+	Community string `json:"community"`
+	// name of the user of a SNMPv3 trap, the keys are localized to the engine of the agent
+	User string `json:"user"`
+}
+
+// Config of the agent
+type Config struct {
+	// address of the agent (i.e. :161)
+	Listen string `json:"listen"`
+	// read only community of SNMPv2c, empty to disable SNMPv2c
+	Community string `json:"community"`
+	// users of SNMPv3
+	Users []User `json:"users"`
+	// targets of the notifications
+	Targets []Target `json:"targets"`
+	// object identifier of the MIB, the private enterprise number of the documentation (RFC 5612) by default
+	Enterprise string `json:"enterprise"`
+	// sysName and sysLocation of the system group
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	// parsed enterprise
+	enterprise OID
+}
+
+// defaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultConfig = Config{
+	Listen:     ":161",
+	Enterprise: "1.3.6.1.4.1.32473",
+}
+
+// LoadConfig reads the config from a json file
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if invalid
+func (c *Config) check() error {
+	if c.Listen == "" {
+		c.Listen = defaultConfig.Listen
+	}
+	if c.Enterprise == "" {
+		c.Enterprise = defaultConfig.Enterprise
+	}
+	var err error
+	if c.enterprise, err = ParseOID(c.Enterprise); err != nil {
+		return err
+	}
+	if c.Community == "" && len(c.Users) == 0 {
+		return ErrNoAccess
+	}
+	users := map[string]bool{}
+	for _, u := range c.Users {
+		if err := u.check(); err != nil {
+			return err
+		}
+		users[u.Name] = true
+	}
+	for i, t := range c.Targets {
+		if t.Address == "" {
+			return ErrMissingTarget
+		}
+		if _, _, err := net.SplitHostPort(t.Address); err != nil {
+			c.Targets[i].Address = net.JoinHostPort(t.Address, defaultTrapPort)
+		}
+		if t.User != "" && !users[t.User] {
+			return fmt.Errorf("target %s: %s: %w", t.Address, t.User, ErrUnknownUser)
+		}
+	}
+	return nil
+}
+
+// State of the agent persisted across restarts
+type State struct {
+	// engine id of SNMPv3, generated on the first start
+	EngineID []byte `json:"engineID"`
+	// number of starts of the engine
+	Boots int32 `json:"boots"`
+}
+
+// LoadState reads the state from a json file, a missing file results in an empty state
+func LoadState(path string) (*State, error) {
+	s := &State{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to a json file
+func (s *State) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// Boot counts a start of the engine, generates the engine id on the first start.
+// The state has to be saved before the agent is started, to never reuse the boots.
+func (s *State) Boot(c Config) error {
+	if len(s.EngineID) == 0 {
+		id := make([]byte, engineIDOctets+8) //nolint: gomnd
+		pen := uint32(enterpriseEngineID)
+		if len(c.enterprise) > len(enterprises) && c.enterprise.HasPrefix(enterprises) {
+			pen |= c.enterprise[len(enterprises)]
+		}
+		id[0], id[1], id[2], id[3] = byte(pen>>24), byte(pen>>16), byte(pen>>8), byte(pen) //nolint: gomnd
+		id[4] = engineIDOctets
+		if _, err := rand.Read(id[5:]); err != nil {
+			return err
+		}
+		s.EngineID = id
+	}
+	s.Boots++
+	return nil
+}
@@ -0,0 +1,154 @@
+package snmp
+
+import (
+	"fmt"
+)
+
+// PDU types (RFC 3416)
+const (
+	PDUGet      = 0xa0
+	PDUGetNext  = 0xa1
+	PDUResponse = 0xa2
+	PDUSet      = 0xa3
+	PDUGetBulk  = 0xa5
+	PDUInform   = 0xa6
+	PDUTrap     = 0xa7
+	PDUReport   = 0xa8
+)
+
+// error status of a response (RFC 3416)
+const (
+	StatusNoError     = 0
+	StatusTooBig      = 1
+	StatusGenErr      = 5
+	StatusNotWritable = 17
+)
+
+// versions of the message
+const (
+	Version2c = 1
+	Version3  = 3
+)
+
+// Varbind is a variable binding of a PDU.
+//
+// the value is one of nil (NULL), int (INTEGER), bool (TruthValue), string or []byte (OCTET STRING), OID, Counter32,
+// Gauge32, TimeTicks, Counter64 or one of the exceptions NoSuchObject, NoSuchInstance or EndOfMibView.
+// Decoded OCTET STRING values are always []byte.
+type Varbind struct {
+	OID   OID
+	Value interface{}
+}
+
+// PDU is a protocol data unit, for PDUGetBulk the error status and index are the non repeaters and max repetitions
+type PDU struct {
+	Type        byte
+	RequestID   int32
+	ErrorStatus int
+	ErrorIndex  int
+	Varbinds    []Varbind
+}
+
+// encode encodes the PDU
+func (p PDU) encode() ([]byte, error) {
+	vbs := make([][]byte, 0, len(p.Varbinds))
+	for _, vb := range p.Varbinds {
+		o, err := encodeOID(vb.OID)
+		if err != nil {
+			return nil, err
+		}
+		v, err := encodeValue(vb.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", vb.OID, err)
+		}
+		vbs = append(vbs, tlv(tagSequence, o, v))
+	}
+	return tlv(p.Type,
+		encodeInt(tagInteger, int64(p.RequestID)),
+		encodeInt(tagInteger, int64(p.ErrorStatus)),
+		encodeInt(tagInteger, int64(p.ErrorIndex)),
+		tlv(tagSequence, vbs...),
+	), nil
+}
+
+// decodePDU decodes a PDU
+func decodePDU(b []byte) (PDU, error) {
+	p := PDU{}
+	d := decoder(b)
+	t, c, err := d.next()
+	if err != nil {
+		return p, err
+	}
+	if t < PDUGet || t > PDUReport {
+		return p, fmt.Errorf("pdu %#x: %w", t, ErrUnexpectedTag)
+	}
+	p.Type = t
+	d = decoder(c)
+	var v [3]int64
+	for i := range v {
+		if v[i], err = d.int(); err != nil {
+			return p, err
+		}
+	}
+	p.RequestID, p.ErrorStatus, p.ErrorIndex = int32(v[0]), int(v[1]), int(v[2])
+	list, err := d.expect(tagSequence)
+	if err != nil {
+		return p, err
+	}
+	for l := decoder(list); len(l) > 0; {
+		c, err := l.expect(tagSequence)
+		if err != nil {
+			return p, err
+		}
+		vb := decoder(c)
+		oc, err := vb.expect(tagOID)
+		if err != nil {
+			return p, err
+		}
+		o, err := decodeOID(oc)
+		if err != nil {
+			return p, err
+		}
+		vt, vc, err := vb.next()
+		if err != nil {
+			return p, err
+		}
+		value, err := decodeValue(vt, vc)
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", o, err)
+		}
+		p.Varbinds = append(p.Varbinds, Varbind{OID: o, Value: value})
+	}
+	return p, nil
+}
+
+// encodeCommunity encodes a v2c message
+func encodeCommunity(community string, p PDU) ([]byte, error) {
+	pdu, err := p.encode()
+	if err != nil {
+		return nil, err
+	}
+	return tlv(tagSequence, encodeInt(tagInteger, Version2c), tlv(tagOctetString, []byte(community)), pdu), nil
+}
+
+// decodeVersion decodes the outer sequence of a message, returns the version and the remaining elements
+func decodeVersion(b []byte) (int64, decoder, error) {
+	d := decoder(b)
+	c, err := d.expect(tagSequence)
+	if err != nil {
+		return 0, nil, err
+	}
+	d = decoder(c)
+	v, err := d.int()
+	return v, d, err
+}
+
+// decodeCommunity decodes the remaining elements of a v2c message
+func decodeCommunity(d decoder) (string, PDU, error) {
+	c, err := d.expect(tagOctetString)
+	if err != nil {
+		return "", PDU{}, err
+	}
+	p, err := decodePDU(d)
+	return string(c), p, err
+}
@@ -0,0 +1,371 @@
+package snmp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// groups of the E3DC MIB below the enterprise
+const (
+	groupNotifications = 0
+	groupScalars       = 1
+	groupStatus        = 2
+	groupTables        = 3
+	groupConformance   = 4
+	groupComputed      = 5
+)
+
+// columns of the computed tags table E.5.1.1.<column>.<n>
+const (
+	computedIndex = 1
+	computedName  = 2
+	computedValue = 3
+)
+
+// status objects E.2.<n>
+const (
+	statusConnected    = 1
+	statusPolls        = 2
+	statusPollFailures = 3
+	statusStoredErrors = 4
+)
+
+// notifications E.0.<n>
+const (
+	trapGridLoss           = 1
+	trapGridRestored       = 2
+	trapNewErrors          = 3
+	trapConnectionLost     = 4
+	trapConnectionRestored = 5
+)
+
+// tenths is the scale of the float values, served as integer with one decimal (DISPLAY-HINT "d-1")
+const tenths = 10
+
+// mibRevision is the revision of the generated MIB
+const mibRevision = "202610170000Z"
+
+// scalarTags are the values of the system served as scalars E.1.<tag>.0
+var scalarTags = []rscp.Tag{
+	rscp.EMS_POWER_PV,
+	rscp.EMS_POWER_BAT,
+	rscp.EMS_POWER_HOME,
+	rscp.EMS_POWER_GRID,
+	rscp.EMS_POWER_ADD,
+	rscp.EMS_POWER_WB_ALL,
+	rscp.EMS_POWER_WB_SOLAR,
+	rscp.EMS_AUTARKY,
+	rscp.EMS_SELF_CONSUMPTION,
+	rscp.EMS_BAT_SOC,
+	rscp.EP_IS_GRID_CONNECTED,
+	rscp.EP_IS_ISLAND_GRID,
+}
+
+// tableColumns are the values of the components by namespace served in the tables E.3.<container>.1.<tag>.<index>
+// in addition to the device state
+var tableColumns = map[string][]rscp.Tag{
+	"BAT":  {rscp.BAT_RSOC, rscp.BAT_MODULE_VOLTAGE, rscp.BAT_CURRENT},
+	"DCDC": {rscp.DCDC_P_BAT, rscp.DCDC_U_BAT, rscp.DCDC_I_BAT},
+	"PM":   {rscp.PM_POWER_L1, rscp.PM_POWER_L2, rscp.PM_POWER_L3},
+}
+
+// stateColumns returns the device state tags of the namespace (connected, working and in service)
+func stateColumns(n rscp.Namespace) []rscp.Tag {
+	state := n.DeviceStateTag.ResponseTag()
+	return []rscp.Tag{state + 1, state + 2, state + 3} //nolint: gomnd
+}
+
+// columns returns the columns of the table of the namespace without the index, ordered by tag
+func columns(n rscp.Namespace) []rscp.Tag {
+	return append(append([]rscp.Tag{}, tableColumns[n.Name]...), stateColumns(n)...)
+}
+
+// scalarOID returns the object identifier of a scalar
+func scalarOID(enterprise OID, tag rscp.Tag) OID {
+	return enterprise.Append(groupScalars, uint32(tag), 0)
+}
+
+// statusOID returns the object identifier of a status object
+func statusOID(enterprise OID, status uint32) OID {
+	return enterprise.Append(groupStatus, status, 0)
+}
+
+// tableOID returns the object identifier of the table of the namespace
+func tableOID(enterprise OID, n rscp.Namespace) OID {
+	return enterprise.Append(groupTables, uint32(n.ResponseContainer))
+}
+
+// columnOID returns the object identifier of a cell of a table
+func columnOID(enterprise OID, n rscp.Namespace, tag rscp.Tag, index uint16) OID {
+	return tableOID(enterprise, n).Append(1, uint32(tag), uint32(index))
+}
+
+// computedOID returns the object identifier of a cell of the computed tags table, n starts with 1
+func computedOID(enterprise OID, column uint32, n int) OID {
+	return enterprise.Append(groupComputed, 1, 1, column, uint32(n))
+}
+
+// trapOID returns the object identifier of a notification
+func trapOID(enterprise OID, trap uint32) OID {
+	return enterprise.Append(groupNotifications, trap)
+}
+
+// clampInt32 limits the value to the range of Integer32
+func clampInt32(v float64) int {
+	return int(math.Max(math.MinInt32, math.Min(math.MaxInt32, math.Round(v))))
+}
+
+// value converts the value of a response to the value served, false if not served
+func value(m rscp.Message) (interface{}, bool) {
+	switch m.DataType {
+	case rscp.Bool:
+		v, ok := m.Value.(bool)
+		return v, ok
+	case rscp.CString:
+		v, ok := m.Value.(string)
+		return v, ok
+	case rscp.Float32, rscp.Double64:
+		v, err := m.Float64()
+		return clampInt32(v * tenths), err == nil
+	case rscp.Uint32, rscp.Uint64:
+		v, err := m.Float64()
+		return Gauge32(math.Max(0, math.Min(math.MaxUint32, v))), err == nil
+	case rscp.Char8, rscp.UChar8, rscp.Int16, rscp.UInt16, rscp.Int32, rscp.Int64:
+		v, err := m.Float64()
+		return clampInt32(v), err == nil
+	}
+	return nil, false
+}
+
+// syntax returns the SMIv2 syntax of the values of the tag
+func syntax(t rscp.Tag) string {
+	switch t.DataType() {
+	case rscp.Bool:
+		return "TruthValue"
+	case rscp.CString:
+		return "DisplayString"
+	case rscp.Float32, rscp.Double64:
+		return "Tenths"
+	case rscp.Uint32, rscp.Uint64:
+		return "Unsigned32"
+	}
+	return "Integer32"
+}
+
+// descriptor returns the MIB descriptor of a tag (i.e. EMS_POWER_PV: emsPowerPv)
+func descriptor(t rscp.Tag) string {
+	return camel(t.String(), false)
+}
+
+// camel converts an upper snake case name to camel case
+func camel(name string, upperFirst bool) string {
+	var b strings.Builder
+	for i, w := range strings.Split(strings.ToLower(name), "_") {
+		if w == "" {
+			continue
+		}
+		if i > 0 || upperFirst {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// Tree is an immutable set of objects sorted by object identifier
+type Tree struct {
+	objects []Varbind
+}
+
+// NewTree creates a tree of the objects
+func NewTree(objects []Varbind) *Tree {
+	t := &Tree{objects: append([]Varbind{}, objects...)}
+	sort.Slice(t.objects, func(i, j int) bool { return t.objects[i].OID.Compare(t.objects[j].OID) < 0 })
+	return t
+}
+
+// Get returns the value of the object, NoSuchInstance if there are instances of the same object or NoSuchObject
+func (t *Tree) Get(o OID) interface{} {
+	i := sort.Search(len(t.objects), func(i int) bool { return t.objects[i].OID.Compare(o) >= 0 })
+	if i < len(t.objects) && t.objects[i].OID.Compare(o) == 0 {
+		return t.objects[i].Value
+	}
+	if len(o) > 1 {
+		object := o[:len(o)-1]
+		for _, j := range []int{i - 1, i} {
+			if j >= 0 && j < len(t.objects) && t.objects[j].OID.HasPrefix(object) && len(t.objects[j].OID) == len(o) {
+				return NoSuchInstance
+			}
+		}
+	}
+	return NoSuchObject
+}
+
+// Next returns the first object after the object identifier, false if there is none
+func (t *Tree) Next(o OID) (Varbind, bool) {
+	i := sort.Search(len(t.objects), func(i int) bool { return t.objects[i].OID.Compare(o) > 0 })
+	if i == len(t.objects) {
+		return Varbind{}, false
+	}
+	return t.objects[i], true
+}
+
+// Len returns the number of objects
+func (t *Tree) Len() int {
+	return len(t.objects)
+}
+
+// mibWriter writes the definitions of the MIB, the first error is kept
+type mibWriter struct {
+	w   io.Writer
+	err error
+}
+
+// printf writes the formatted text unless an error occurred before
+func (m *mibWriter) printf(format string, a ...interface{}) {
+	if m.err == nil {
+		_, m.err = fmt.Fprintf(m.w, format, a...)
+	}
+}
+
+// objectType writes an OBJECT-TYPE definition
+func (m *mibWriter) objectType(name, syntax, access, description, parent string, sub uint32) {
+	m.printf("\n%s OBJECT-TYPE\n    SYNTAX      %s\n    MAX-ACCESS  %s\n    STATUS      current\n"+
+		"    DESCRIPTION \"%s\"\n    ::= { %s %d }\n", name, syntax, access, description, parent, sub)
+}
+
+// tagDescription returns the description of an object of a tag
+func tagDescription(t rscp.Tag) string {
+	d := fmt.Sprintf("Value of the rscp tag %s (0x%08X).", t, uint32(t))
+	if syntax(t) == "Tenths" {
+		d += " Floats are served in tenths."
+	}
+	return d
+}
+
+// WriteMIB writes the E3DC MIB of the config as SMIv2 module.
+//
+// the objects are generated from the tag metadata and identified by the rscp tag:
+//  E.1.<tag>.0                   scalars of the system (i.e. EMS_POWER_PV)
+//  E.2.<n>.0                     status of the connection to the system
+//  E.3.<container>.1.<tag>.<i>   tables of the components by namespace (i.e. BAT_DATA) with the device state
+//  E.5.1.1.<column>.<n>          table of the computed tags by position in order of evaluation
+//  E.0.<n>                       notifications of alarm events
+func WriteMIB(w io.Writer, c Config) error {
+	m := &mibWriter{w: w}
+	objects, notifications := []string{}, []string{}
+	e := c.enterprise
+	if len(e) < 2 { //nolint: gomnd
+		return fmt.Errorf("%s: %w", c.Enterprise, ErrInvalidOID)
+	}
+	parent := make([]string, len(e))
+	for i, v := range e {
+		parent[i] = fmt.Sprint(v)
+	}
+	parent[0] = "iso"
+	m.printf("E3DC-MIB DEFINITIONS ::= BEGIN\n\nIMPORTS\n"+
+		"    MODULE-IDENTITY, OBJECT-TYPE, NOTIFICATION-TYPE, Integer32, Unsigned32, Counter32\n        FROM SNMPv2-SMI\n"+
+		"    TEXTUAL-CONVENTION, DisplayString, TruthValue\n        FROM SNMPv2-TC\n"+
+		"    MODULE-COMPLIANCE, OBJECT-GROUP, NOTIFICATION-GROUP\n        FROM SNMPv2-CONF;\n"+
+		"\ne3dcMIB MODULE-IDENTITY\n    LAST-UPDATED \"%s\"\n    ORGANIZATION \"go-rscp\"\n"+
+		"    CONTACT-INFO \"https://github.com/spali/go-rscp\"\n"+
+		"    DESCRIPTION \"Telemetry of an E3DC system polled with rscp, generated from the tag metadata.\"\n"+
+		"    REVISION \"%s\"\n    DESCRIPTION \"Generated by e3dc snmp.\"\n    ::= { %s }\n",
+		mibRevision, mibRevision, strings.Join(parent, " "))
+	m.printf("\nTenths ::= TEXTUAL-CONVENTION\n    DISPLAY-HINT \"d-1\"\n    STATUS       current\n"+
+		"    DESCRIPTION  \"Float value in tenths.\"\n    SYNTAX       Integer32\n")
+	for _, g := range []struct {
+		name string
+		sub  uint32
+	}{
+		{"e3dcNotifications", groupNotifications},
+		{"e3dcScalars", groupScalars},
+		{"e3dcStatus", groupStatus},
+		{"e3dcTables", groupTables},
+		{"e3dcConformance", groupConformance},
+		{"e3dcComputed", groupComputed},
+	} {
+		m.printf("\n%s OBJECT IDENTIFIER ::= { e3dcMIB %d }\n", g.name, g.sub)
+	}
+	for _, t := range scalarTags {
+		m.objectType(descriptor(t), syntax(t), "read-only", tagDescription(t), "e3dcScalars", uint32(t))
+		objects = append(objects, descriptor(t))
+	}
+	for _, s := range []struct {
+		name, syntax, description string
+		sub                       uint32
+	}{
+		{"e3dcConnected", "TruthValue", "If the last poll of the system succeeded.", statusConnected},
+		{"e3dcPolls", "Counter32", "Number of polls of the system.", statusPolls},
+		{"e3dcPollFailures", "Counter32", "Number of failed polls of the system.", statusPollFailures},
+		{"e3dcStoredErrors", "Unsigned32", "Number of errors stored by the system (EMS_STORED_ERRORS).", statusStoredErrors},
+	} {
+		m.objectType(s.name, s.syntax, "read-only", s.description, "e3dcStatus", s.sub)
+		objects = append(objects, s.name)
+	}
+	for _, n := range rscp.Namespaces {
+		table, entry := camel(n.Name, false)+"Table", camel(n.Name, false)+"Entry"
+		index := descriptor(n.IndexTag)
+		cols := columns(n)
+		m.printf("\n%s OBJECT-TYPE\n    SYNTAX      SEQUENCE OF %s\n    MAX-ACCESS  not-accessible\n    STATUS      current\n"+
+			"    DESCRIPTION \"Components of the namespace %s (%s).\"\n    ::= { e3dcTables %d }\n",
+			table, camel(n.Name, true)+"Entry", n.Name, n.ResponseContainer, uint32(n.ResponseContainer))
+		m.printf("\n%s OBJECT-TYPE\n    SYNTAX      %s\n    MAX-ACCESS  not-accessible\n    STATUS      current\n"+
+			"    DESCRIPTION \"Component by %s.\"\n    INDEX       { %s }\n    ::= { %s 1 }\n",
+			entry, camel(n.Name, true)+"Entry", n.IndexTag, index, table)
+		m.printf("\n%s ::= SEQUENCE {\n    %s Integer32", camel(n.Name, true)+"Entry", index)
+		for _, t := range cols {
+			m.printf(",\n    %s %s", descriptor(t), syntax(t))
+		}
+		m.printf("\n}\n")
+		m.objectType(index, "Integer32 (0..65535)", "not-accessible", tagDescription(n.IndexTag), entry, uint32(n.IndexTag))
+		for _, t := range cols {
+			m.objectType(descriptor(t), syntax(t), "read-only", tagDescription(t), entry, uint32(t))
+			objects = append(objects, descriptor(t))
+		}
+	}
+	m.printf("\ne3dcComputedTable OBJECT-TYPE\n    SYNTAX      SEQUENCE OF E3dcComputedEntry\n    MAX-ACCESS  not-accessible\n"+
+		"    STATUS      current\n    DESCRIPTION \"Computed tags in order of evaluation.\"\n    ::= { e3dcComputed 1 }\n")
+	m.printf("\ne3dcComputedEntry OBJECT-TYPE\n    SYNTAX      E3dcComputedEntry\n    MAX-ACCESS  not-accessible\n"+
+		"    STATUS      current\n    DESCRIPTION \"Computed tag by position.\"\n    INDEX       { e3dcComputedIndex }\n"+
+		"    ::= { e3dcComputedTable 1 }\n")
+	m.printf("\nE3dcComputedEntry ::= SEQUENCE {\n    e3dcComputedIndex Integer32,\n    e3dcComputedName DisplayString,\n"+
+		"    e3dcComputedValue Tenths\n}\n")
+	m.objectType("e3dcComputedIndex", "Integer32 (1..2147483647)", "not-accessible", "Position of the computed tag.",
+		"e3dcComputedEntry", computedIndex)
+	m.objectType("e3dcComputedName", "DisplayString", "read-only", "Name of the computed tag.", "e3dcComputedEntry", computedName)
+	m.objectType("e3dcComputedValue", "Tenths", "read-only", "Value of the computed tag, missing if it can't be computed.",
+		"e3dcComputedEntry", computedValue)
+	objects = append(objects, "e3dcComputedName", "e3dcComputedValue")
+	for _, n := range []struct {
+		name, description string
+		objects           []string
+		sub               uint32
+	}{
+		{"e3dcGridLoss", "The system lost the grid connection.", []string{descriptor(rscp.EP_IS_GRID_CONNECTED)}, trapGridLoss},
+		{"e3dcGridRestored", "The grid connection of the system was restored.",
+			[]string{descriptor(rscp.EP_IS_GRID_CONNECTED)}, trapGridRestored},
+		{"e3dcNewErrors", "The system stored new errors.", []string{"e3dcStoredErrors"}, trapNewErrors},
+		{"e3dcConnectionLost", "Polling the system failed.", []string{"e3dcConnected", "e3dcPollFailures"}, trapConnectionLost},
+		{"e3dcConnectionRestored", "Polling the system succeeded again.", []string{"e3dcConnected", "e3dcPollFailures"},
+			trapConnectionRestored},
+	} {
+		m.printf("\n%s NOTIFICATION-TYPE\n    OBJECTS     { %s }\n    STATUS      current\n"+
+			"    DESCRIPTION \"%s\"\n    ::= { e3dcNotifications %d }\n", n.name, strings.Join(n.objects, ", "), n.description, n.sub)
+		notifications = append(notifications, n.name)
+	}
+	m.printf("\ne3dcGroups OBJECT IDENTIFIER ::= { e3dcConformance 1 }\ne3dcCompliances OBJECT IDENTIFIER ::= { e3dcConformance 2 }\n")
+	m.printf("\ne3dcObjectGroup OBJECT-GROUP\n    OBJECTS     { %s }\n    STATUS      current\n"+
+		"    DESCRIPTION \"Objects of the system.\"\n    ::= { e3dcGroups 1 }\n", strings.Join(objects, ",\n                  "))
+	m.printf("\ne3dcNotificationGroup NOTIFICATION-GROUP\n    NOTIFICATIONS { %s }\n    STATUS      current\n"+
+		"    DESCRIPTION \"Notifications of alarm events.\"\n    ::= { e3dcGroups 2 }\n", strings.Join(notifications, ",\n                    "))
+	m.printf("\ne3dcCompliance MODULE-COMPLIANCE\n    STATUS      current\n    DESCRIPTION \"Implemented by e3dc snmp.\"\n"+
+		"    MODULE\n        MANDATORY-GROUPS { e3dcObjectGroup, e3dcNotificationGroup }\n    ::= { e3dcCompliances 1 }\n")
+	m.printf("\nEND\n")
+	return m.err
+}
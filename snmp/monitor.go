@@ -0,0 +1,239 @@
+package snmp
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Notification of an alarm event
+type Notification struct {
+	Trap    OID
+	Objects []Varbind
+}
+
+// Monitor polls the system and keeps the objects of the E3DC MIB and the state of the alarms
+type Monitor struct {
+	config Config
+	// computed tags served in addition, nil if none
+	computed *computed.Tags
+	// components by namespace, discovered by the first successful poll
+	components map[string][]rscp.Component
+	// state of the last poll, nil if unknown yet
+	connected *bool
+	grid      *bool
+	errors    *int
+	polls     Counter32
+	failures  Counter32
+	// values of the last successful poll
+	values []Varbind
+}
+
+// NewMonitor creates a monitor of the E3DC MIB of the config
+func NewMonitor(c Config) (*Monitor, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	return &Monitor{config: c}, nil
+}
+
+// SetComputed serves the computed tags in the computed tags table
+func (m *Monitor) SetComputed(t *computed.Tags) {
+	m.computed = t
+}
+
+// Requests returns the requests of a poll of the discovered components
+func (m *Monitor) Requests() ([]rscp.Message, error) {
+	values := [][]interface{}{{rscp.EMS_REQ_STORED_ERRORS}}
+	for _, t := range scalarTags {
+		values = append(values, []interface{}{t.RequestTag()})
+	}
+	r, err := rscp.CreateRequests(values...)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range rscp.Namespaces {
+		sub := []interface{}{n.DeviceStateTag}
+		for _, t := range tableColumns[n.Name] {
+			sub = append(sub, t.RequestTag())
+		}
+		for _, c := range m.components[n.Name] {
+			req, err := n.NewRequest(c.Index, sub...)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, *req)
+		}
+	}
+	if m.computed != nil {
+		deps, err := m.computed.Requests()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, deps...)
+	}
+	return r, nil
+}
+
+// Step polls the system, returns the objects to serve and the notifications of the alarm events since the last step.
+// On a failed poll the values of the last successful poll are returned with the connection status.
+func (m *Monitor) Step(s rscp.Sender) ([]Varbind, []Notification, error) {
+	m.polls++
+	values, err := m.poll(s)
+	if err != nil {
+		m.failures++
+	}
+	ns := m.connection(err == nil)
+	if err == nil {
+		ns = append(ns, m.alarms(values)...)
+		m.values = values
+	}
+	return append(m.status(), m.values...), ns, err
+}
+
+// poll discovers the components if not done yet and returns the objects of the values
+func (m *Monitor) poll(s rscp.Sender) ([]Varbind, error) {
+	if m.components == nil {
+		c, err := rscp.Discover(s)
+		if err != nil {
+			return nil, fmt.Errorf("discovery: %w", err)
+		}
+		m.components = c
+	}
+	requests, err := m.Requests()
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.SendMultiple(requests)
+	if err != nil {
+		return nil, err
+	}
+	return m.objects(responses), nil
+}
+
+// objects returns the objects of the responses to the requests
+func (m *Monitor) objects(responses []rscp.Message) []Varbind {
+	e := m.config.enterprise
+	r := []Varbind{}
+	for _, t := range scalarTags {
+		if v, ok := find(responses, t); ok {
+			r = append(r, Varbind{scalarOID(e, t), v})
+		}
+	}
+	if errs := rscp.FindTag(responses, rscp.EMS_STORED_ERRORS); errs != nil && errs.DataType == rscp.Container {
+		count := 0
+		for _, msg := range errs.Value.([]rscp.Message) {
+			if msg.Tag == rscp.EMS_ERROR_CONTAINER {
+				count++
+			}
+		}
+		r = append(r, Varbind{statusOID(e, statusStoredErrors), Gauge32(count)})
+	}
+	for _, n := range rscp.Namespaces {
+		for _, c := range m.components[n.Name] {
+			data := rscp.FindIndexed(responses, n.ResponseContainer, n.IndexTag, c.Index)
+			if data == nil || data.DataType != rscp.Container {
+				log.Warnf("snmp: missing %s %d in response", n.Name, c.Index)
+				continue
+			}
+			// the device state values are found within the nested device state container
+			nested := data.Value.([]rscp.Message)
+			for _, t := range columns(n) {
+				if v, ok := find(nested, t); ok {
+					r = append(r, Varbind{columnOID(e, n, t, c.Index), v})
+				}
+			}
+		}
+	}
+	return append(r, m.computedObjects(responses)...)
+}
+
+// computedObjects returns the rows of the computed tags table, the value is missing if the tag can't be computed
+func (m *Monitor) computedObjects(responses []rscp.Message) []Varbind {
+	if m.computed == nil {
+		return nil
+	}
+	values, err := m.computed.Evaluate(responses)
+	if err != nil {
+		log.Warnf("snmp: computed tags: %s", err)
+	}
+	e := m.config.enterprise
+	r := []Varbind{}
+	for i, name := range m.computed.Names() {
+		r = append(r, Varbind{computedOID(e, computedName, i+1), name})
+		if v, exists := values[name]; exists {
+			r = append(r, Varbind{computedOID(e, computedValue, i+1), clampInt32(v * tenths)})
+		}
+	}
+	return r
+}
+
+// find returns the served value of the tag within the messages
+func find(messages []rscp.Message, t rscp.Tag) (interface{}, bool) {
+	msg := rscp.FindTag(messages, t)
+	if msg == nil {
+		return nil, false
+	}
+	return value(*msg)
+}
+
+// status returns the status objects of the connection
+func (m *Monitor) status() []Varbind {
+	e := m.config.enterprise
+	return []Varbind{
+		{statusOID(e, statusConnected), m.connected != nil && *m.connected},
+		{statusOID(e, statusPolls), m.polls},
+		{statusOID(e, statusPollFailures), m.failures},
+	}
+}
+
+// connection updates the connection state, returns the notification of a change
+func (m *Monitor) connection(connected bool) []Notification {
+	// restored is only notified after a lost connection
+	changed := (m.connected == nil && !connected) || (m.connected != nil && *m.connected != connected)
+	m.connected = &connected
+	if !changed {
+		return nil
+	}
+	trap, event := uint32(trapConnectionRestored), "restored"
+	if !connected {
+		trap, event = trapConnectionLost, "lost"
+	}
+	log.Infof("snmp: connection %s", event)
+	e := m.config.enterprise
+	return []Notification{{trapOID(e, trap), []Varbind{
+		{statusOID(e, statusConnected), connected},
+		{statusOID(e, statusPollFailures), m.failures},
+	}}}
+}
+
+// alarms updates the alarm state by the values, returns the notifications of the changes
+func (m *Monitor) alarms(values []Varbind) []Notification {
+	e := m.config.enterprise
+	ns := []Notification{}
+	tree := NewTree(values)
+	if grid, ok := tree.Get(scalarOID(e, rscp.EP_IS_GRID_CONNECTED)).(bool); ok {
+		// a grid loss is notified if the grid is not connected by the first poll
+		if (m.grid == nil && !grid) || (m.grid != nil && *m.grid != grid) {
+			trap, event := uint32(trapGridRestored), "restored"
+			if !grid {
+				trap, event = trapGridLoss, "lost"
+			}
+			log.Infof("snmp: grid %s", event)
+			ns = append(ns, Notification{trapOID(e, trap), []Varbind{{scalarOID(e, rscp.EP_IS_GRID_CONNECTED), grid}}})
+		}
+		m.grid = &grid
+	}
+	if count, ok := tree.Get(statusOID(e, statusStoredErrors)).(Gauge32); ok {
+		// errors stored before the first poll are not notified
+		if m.errors != nil && int(count) > *m.errors {
+			log.Infof("snmp: %d new errors", int(count)-*m.errors)
+			ns = append(ns, Notification{trapOID(e, trapNewErrors), []Varbind{{statusOID(e, statusStoredErrors), count}}})
+		}
+		c := int(count)
+		m.errors = &c
+	}
+	return ns
+}
@@ -0,0 +1,341 @@
+package snmp
+
+import (
+	"crypto/md5" //nolint: gosec
+	"crypto/sha1" //nolint: gosec
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+	"github.com/spali/go-rscp/simulator"
+)
+
+func TestPDU_encode(t *testing.T) {
+	p := PDU{Type: PDUResponse, RequestID: -5, Varbinds: []Varbind{
+		{MustParseOID("1.3.6.1.4.1.32473.1.25165825.0"), -1200},
+		{MustParseOID("1.3.6.1.2.1.1.1.0"), []byte("E3DC")},
+		{MustParseOID("1.3.6.1.2.1.1.2.0"), MustParseOID("1.3.6.1.4.1.32473")},
+		{MustParseOID("1.3.6.1.2.1.1.3.0"), TimeTicks(4294967295)},
+		{MustParseOID("2.999.1"), Counter32(128)},
+		{MustParseOID("1.3.6.1.6.3.15.1.1.4.0"), Gauge32(0)},
+		{MustParseOID("1.3.6.1.2.1.31.1.1.1.6.1"), Counter64(1 << 63)},
+		{MustParseOID("1.3.6.1.2.1.1.4.0"), nil},
+		{MustParseOID("1.3.6.1.2.1.1.5.0"), NoSuchInstance},
+	}}
+	b, err := p.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	got, err := decodePDU(b)
+	if err != nil {
+		t.Fatalf("decodePDU() error = %v", err)
+	}
+	if diff := deep.Equal(got, p); diff != nil {
+		t.Error(diff)
+	}
+	if _, err := decodePDU(b[:len(b)-1]); !errors.Is(err, ErrTruncated) {
+		t.Errorf("decodePDU() of truncated error = %v, want %v", err, ErrTruncated)
+	}
+}
+
+func TestPasswordToKey(t *testing.T) {
+	// RFC 3414 A.3
+	engineID, _ := hex.DecodeString("000000000000000000000002")
+	if got := hex.EncodeToString(passwordToKey(md5.New, "maplesyrup", engineID)); got != "526f5eed9fcce26f8964c2930787d82b" {
+		t.Errorf("passwordToKey(MD5) = %s", got)
+	}
+	if got := hex.EncodeToString(passwordToKey(sha1.New, "maplesyrup", engineID)); got != "6695febc9288e36282235fc7151f128497b38f3f" {
+		t.Errorf("passwordToKey(SHA) = %s", got)
+	}
+}
+
+// pollResponses returns the responses of a poll with the grid state and number of stored errors
+func pollResponses(grid bool, errors int) []rscp.Message {
+	errs := []rscp.Message{}
+	for i := 0; i < errors; i++ {
+		errs = append(errs, rscp.Message{Tag: rscp.EMS_ERROR_CONTAINER, DataType: rscp.Container, Value: []rscp.Message{}})
+	}
+	return []rscp.Message{
+		{Tag: rscp.EP_IS_GRID_CONNECTED, DataType: rscp.Bool, Value: grid},
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1500)},
+		{Tag: rscp.EMS_STORED_ERRORS, DataType: rscp.Container, Value: errs},
+	}
+}
+
+// sha1AES is a user with authentication and privacy
+var sha1AES = User{Name: "monitor", Auth: "SHA", AuthPassword: "authsecret", Priv: "AES", PrivPassword: "privsecret"}
+
+// listen starts an agent on a random port
+func listen(t *testing.T, c Config) *Agent {
+	t.Helper()
+	c.Listen = "127.0.0.1:0"
+	if err := c.check(); err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	s := &State{}
+	if err := s.Boot(c); err != nil {
+		t.Fatalf("Boot() error = %v", err)
+	}
+	a, err := Listen(c, s)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
+
+// dial creates a client of the agent
+func dial(t *testing.T, a *Agent, c ClientConfig) *Client {
+	t.Helper()
+	c.Timeout = time.Millisecond * 500
+	client, err := Dial(a.Addr().String(), c)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestAgent(t *testing.T) {
+	server, err := simulator.Start("127.0.0.1:0", simulator.Config{
+		Key: "key", User: "user", Password: "password",
+		Values: map[rscp.Tag]interface{}{
+			rscp.EMS_POWER_PV:         int32(4200),
+			rscp.EMS_BAT_SOC:          uint8(81),
+			rscp.EP_IS_GRID_CONNECTED: true,
+			rscp.BAT_RSOC:             float32(80.56),
+			rscp.PM_POWER_L1:          float64(-230.5),
+		},
+		Components: map[string]uint16{"BAT": 1, "PM": 2},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	client, err := rscp.NewClient(rscp.ClientConfig{
+		Address: "127.0.0.1", Port: uint16(server.Addr().Port), Username: "user", Password: "password", Key: "key",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect() })
+
+	a := listen(t, Config{Community: "public", Users: []User{sha1AES}, Name: "home"})
+	m, err := NewMonitor(a.config)
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	objects, _, err := m.Step(client)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	a.Update(objects)
+
+	e := a.config.enterprise
+	bat, _ := rscp.NamespaceByName("BAT")
+	pm, _ := rscp.NamespaceByName("PM")
+	get := []OID{
+		scalarOID(e, rscp.EMS_POWER_PV),
+		scalarOID(e, rscp.EMS_BAT_SOC),
+		scalarOID(e, rscp.EP_IS_GRID_CONNECTED),
+		columnOID(e, bat, rscp.BAT_RSOC, 0),
+		columnOID(e, bat, rscp.BAT_DEVICE_CONNECTED, 0),
+		columnOID(e, pm, rscp.PM_POWER_L1, 1),
+		statusOID(e, statusConnected),
+		statusOID(e, statusStoredErrors),
+		sysNameOID,
+		columnOID(e, bat, rscp.BAT_RSOC, 1),
+		e.Append(9),
+	}
+	want := []Varbind{
+		{get[0], 4200},
+		{get[1], 81},
+		{get[2], 1},
+		{get[3], 806},
+		{get[4], 1},
+		{get[5], -2305},
+		{get[6], 1},
+		{get[7], Gauge32(0)},
+		{get[8], []byte("home")},
+		{get[9], NoSuchInstance},
+		{get[10], NoSuchObject},
+	}
+	for _, c := range []struct {
+		name   string
+		config ClientConfig
+	}{
+		{"v2c", ClientConfig{Community: "public"}},
+		{"v3 authPriv", ClientConfig{User: &sha1AES}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			client := dial(t, a, c.config)
+			got, err := client.Get(get...)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if diff := deep.Equal(got, want); diff != nil {
+				t.Error(diff)
+			}
+			walk, err := client.Walk(tableOID(e, pm))
+			if err != nil {
+				t.Fatalf("Walk() error = %v", err)
+			}
+			// 2 power meters with 3 phases and device state, by column
+			if len(walk) != 12 || walk[0].OID.Compare(columnOID(e, pm, rscp.PM_POWER_L1, 0)) != 0 ||
+				walk[11].OID.Compare(columnOID(e, pm, rscp.PM_DEVICE_IN_SERVICE, 1)) != 0 {
+				t.Errorf("Walk() = %v", walk)
+			}
+			if _, err := client.request(PDUSet, 0, 0, []OID{sysNameOID}); !errors.Is(err, ErrResponse) {
+				t.Errorf("request(SET) error = %v, want %v", err, ErrResponse)
+			}
+		})
+	}
+	t.Run("wrong community", func(t *testing.T) {
+		if _, err := dial(t, a, ClientConfig{Community: "private"}).Get(sysNameOID); err == nil {
+			t.Errorf("Get() of a wrong community succeeded")
+		}
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		u := sha1AES
+		u.AuthPassword = "wrongsecret"
+		if _, err := dial(t, a, ClientConfig{User: &u}).Get(sysNameOID); !errors.Is(err, ErrReport) {
+			t.Errorf("Get() error = %v, want %v", err, ErrReport)
+		}
+	})
+	t.Run("wrong security level", func(t *testing.T) {
+		u := sha1AES
+		u.Priv, u.PrivPassword = "", ""
+		if _, err := dial(t, a, ClientConfig{User: &u}).Get(sysNameOID); !errors.Is(err, ErrReport) {
+			t.Errorf("Get() error = %v, want %v", err, ErrReport)
+		}
+	})
+}
+
+func TestMonitor_notifications(t *testing.T) {
+	v2c, err := ListenTraps("127.0.0.1:0", "traps")
+	if err != nil {
+		t.Fatalf("ListenTraps() error = %v", err)
+	}
+	defer v2c.Close()
+	v3, err := ListenTraps("127.0.0.1:0", "", sha1AES)
+	if err != nil {
+		t.Fatalf("ListenTraps() error = %v", err)
+	}
+	defer v3.Close()
+	a := listen(t, Config{Community: "public", Users: []User{sha1AES}, Targets: []Target{
+		{Address: v2c.Addr().String(), Community: "traps"},
+		{Address: v3.Addr().String(), User: sha1AES.Name},
+	}})
+	m, err := NewMonitor(a.config)
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	e := a.config.enterprise
+	for _, step := range []struct {
+		name string
+		// responses of the poll, nil if the connection is lost
+		responses []rscp.Message
+		traps     []uint32
+	}{
+		{"initial", pollResponses(true, 2), nil},
+		{"unchanged", pollResponses(true, 2), nil},
+		{"grid loss", pollResponses(false, 2), []uint32{trapGridLoss}},
+		{"new errors", pollResponses(false, 3), []uint32{trapNewErrors}},
+		{"errors confirmed", pollResponses(false, 0), nil},
+		{"connection lost", nil, []uint32{trapConnectionLost}},
+		{"still lost", nil, nil},
+		{"restored", pollResponses(true, 0), []uint32{trapConnectionRestored, trapGridRestored}},
+	} {
+		objects, ns, err := m.Step(rscptest.NewSender(step.responses))
+		if (err != nil) != (step.responses == nil) {
+			t.Fatalf("%s: Step() error = %v", step.name, err)
+		}
+		got := []uint32{}
+		for _, n := range ns {
+			got = append(got, n.Trap[len(n.Trap)-1])
+			if err := a.Notify(n.Trap, n.Objects...); err != nil {
+				t.Fatalf("%s: Notify() error = %v", step.name, err)
+			}
+			for _, l := range []*TrapListener{v2c, v3} {
+				p, err := l.Receive(time.Second)
+				if err != nil {
+					t.Fatalf("%s: Receive() error = %v", step.name, err)
+				}
+				if p.Type != PDUTrap || len(p.Varbinds) < 3 || deep.Equal(p.Varbinds[1].Value, n.Trap) != nil {
+					t.Errorf("%s: Receive() = %+v, want %s", step.name, p, n.Trap)
+				}
+			}
+		}
+		if diff := deep.Equal(got, step.traps); diff != nil && !(len(got) == 0 && step.traps == nil) {
+			t.Errorf("%s: %v", step.name, diff)
+		}
+		if v := NewTree(objects).Get(statusOID(e, statusPolls)); v == NoSuchObject {
+			t.Errorf("%s: missing polls", step.name)
+		}
+	}
+	if m.failures != 2 || m.polls != 8 {
+		t.Errorf("failures = %d, polls = %d", m.failures, m.polls)
+	}
+}
+
+func TestMonitor_computed(t *testing.T) {
+	m, err := NewMonitor(Config{Community: "public"})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	tags, err := computed.New(map[string]string{"HALF": "EMS_POWER_PV / 2", "MISSING": "EMS_POWER_ADD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetComputed(tags)
+	// without components to skip the discovery
+	m.components = map[string][]rscp.Component{}
+	objects, _, err := m.Step(rscptest.NewSender([]rscp.Message{{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1000)}}))
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	tree, e := NewTree(objects), m.config.enterprise
+	for _, o := range []struct {
+		oid  OID
+		want interface{}
+	}{
+		{computedOID(e, computedName, 1), "HALF"},
+		{computedOID(e, computedValue, 1), 5000},
+		{computedOID(e, computedName, 2), "MISSING"},
+		{computedOID(e, computedValue, 2), NoSuchInstance},
+	} {
+		if got := tree.Get(o.oid); got != o.want {
+			t.Errorf("Get(%s) = %v, want %v", o.oid, got, o.want)
+		}
+	}
+}
+
+func TestWriteMIB(t *testing.T) {
+	c := Config{Community: "public"}
+	if err := c.check(); err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	b := &strings.Builder{}
+	if err := WriteMIB(b, c); err != nil {
+		t.Fatalf("WriteMIB() error = %v", err)
+	}
+	for _, s := range []string{
+		"::= { iso 3 6 1 4 1 32473 }",
+		"emsPowerPv OBJECT-TYPE\n    SYNTAX      Integer32\n    MAX-ACCESS  read-only",
+		"::= { e3dcScalars 25165825 }",
+		"batRsoc OBJECT-TYPE\n    SYNTAX      Tenths",
+		"INDEX       { batIndex }",
+		"e3dcGridLoss NOTIFICATION-TYPE",
+		"e3dcComputedValue OBJECT-TYPE\n    SYNTAX      Tenths",
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("WriteMIB() does not contain %q", s)
+		}
+	}
+}
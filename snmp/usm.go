@@ -0,0 +1,365 @@
+package snmp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5" //nolint: gosec
+	"crypto/rand"
+	"crypto/sha1" //nolint: gosec
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// message flags of SNMPv3 (RFC 3412)
+const (
+	flagAuth       = 0x01
+	flagPriv       = 0x02
+	flagReportable = 0x04
+)
+
+const (
+	// securityModelUSM is the user-based security model (RFC 3414)
+	securityModelUSM = 3
+	// authParamsLength is the length of the truncated HMAC of HMAC-MD5-96 and HMAC-SHA-96
+	authParamsLength = 12
+	// passwordKeyLength is the length of the expanded password hashed into the key (RFC 3414 A.2)
+	passwordKeyLength = 1048576
+	// aesKeyLength is the key length of AES-128 (RFC 3826)
+	aesKeyLength = 16
+	// minPasswordLength is the minimal length of a password (RFC 3414 11.2)
+	minPasswordLength = 8
+	// timeWindow is the time window of authenticated messages in seconds (RFC 3414 2.2.3)
+	timeWindow = 150
+	// maxMessageSize is the max message size accepted
+	maxMessageSize = 65507
+)
+
+// usmStats objects of the reports (RFC 3414)
+var (
+	usmStatsUnsupportedSecLevels = MustParseOID("1.3.6.1.6.3.15.1.1.1.0")
+	usmStatsNotInTimeWindows     = MustParseOID("1.3.6.1.6.3.15.1.1.2.0")
+	usmStatsUnknownUserNames     = MustParseOID("1.3.6.1.6.3.15.1.1.3.0")
+	usmStatsUnknownEngineIDs     = MustParseOID("1.3.6.1.6.3.15.1.1.4.0")
+	usmStatsWrongDigests         = MustParseOID("1.3.6.1.6.3.15.1.1.5.0")
+	usmStatsDecryptionErrors     = MustParseOID("1.3.6.1.6.3.15.1.1.6.0")
+)
+
+// User of SNMPv3 with the user-based security model
+type User struct {
+	Name string `json:"name"`
+	// authentication protocol: MD5 or SHA, empty for noAuthNoPriv
+	Auth         string `json:"auth"`
+	AuthPassword string `json:"authPassword"`
+	// privacy protocol: AES (AES-128-CFB), empty for no privacy
+	Priv         string `json:"priv"`
+	PrivPassword string `json:"privPassword"`
+}
+
+// check fails if the user is invalid
+func (u User) check() error {
+	if u.Name == "" {
+		return ErrMissingUserName
+	}
+	switch u.Auth {
+	case "":
+		if u.Priv != "" {
+			return fmt.Errorf("%s: privacy without authentication: %w", u.Name, ErrInvalidUser)
+		}
+	case "MD5", "SHA":
+		if len(u.AuthPassword) < minPasswordLength {
+			return fmt.Errorf("%s: auth password shorter than %d: %w", u.Name, minPasswordLength, ErrInvalidUser)
+		}
+	default:
+		return fmt.Errorf("%s: auth protocol %s: %w", u.Name, u.Auth, ErrInvalidUser)
+	}
+	switch u.Priv {
+	case "":
+	case "AES":
+		if len(u.PrivPassword) < minPasswordLength {
+			return fmt.Errorf("%s: priv password shorter than %d: %w", u.Name, minPasswordLength, ErrInvalidUser)
+		}
+	default:
+		return fmt.Errorf("%s: priv protocol %s: %w", u.Name, u.Priv, ErrInvalidUser)
+	}
+	return nil
+}
+
+// flags returns the message flags of the security level of the user
+func (u User) flags() byte {
+	var f byte
+	if u.Auth != "" {
+		f |= flagAuth
+	}
+	if u.Priv != "" {
+		f |= flagPriv
+	}
+	return f
+}
+
+// keys are the keys of a user localized to an engine
+type keys struct {
+	hash func() hash.Hash
+	auth []byte
+	priv []byte
+}
+
+// localize returns the keys of the user localized to the engine, nil for noAuthNoPriv
+func (u User) localize(engineID []byte) *keys {
+	if u.Auth == "" {
+		return nil
+	}
+	k := &keys{hash: md5.New}
+	if u.Auth == "SHA" {
+		k.hash = sha1.New
+	}
+	k.auth = passwordToKey(k.hash, u.AuthPassword, engineID)
+	if u.Priv != "" {
+		k.priv = passwordToKey(k.hash, u.PrivPassword, engineID)[:aesKeyLength]
+	}
+	return k
+}
+
+// passwordToKey returns the localized key of the password (RFC 3414 A.2)
+func passwordToKey(h func() hash.Hash, password string, engineID []byte) []byte {
+	d := h()
+	buf := make([]byte, 64) //nolint: gomnd
+	for i := 0; i < passwordKeyLength; i += len(buf) {
+		for j := range buf {
+			buf[j] = password[(i+j)%len(password)]
+		}
+		_, _ = d.Write(buf)
+	}
+	ku := d.Sum(nil)
+	d.Reset()
+	_, _ = d.Write(ku)
+	_, _ = d.Write(engineID)
+	_, _ = d.Write(ku)
+	return d.Sum(nil)
+}
+
+// digest returns the truncated HMAC of the message
+func (k *keys) digest(message []byte) []byte {
+	m := hmac.New(k.hash, k.auth)
+	_, _ = m.Write(message)
+	return m.Sum(nil)[:authParamsLength]
+}
+
+// aesIV returns the initialization vector of AES-128-CFB (RFC 3826 3.1.2.1)
+func aesIV(boots, time int32, salt []byte) []byte {
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint32(iv, uint32(boots))
+	binary.BigEndian.PutUint32(iv[4:], uint32(time))
+	copy(iv[8:], salt)
+	return iv
+}
+
+// encrypt encrypts the scoped PDU, returns the encrypted data and the salt
+func (k *keys) encrypt(scoped []byte, boots, time int32) ([]byte, []byte, error) {
+	salt := make([]byte, 8) //nolint: gomnd
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	b, err := aes.NewCipher(k.priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]byte, len(scoped))
+	cipher.NewCFBEncrypter(b, aesIV(boots, time, salt)).XORKeyStream(out, scoped)
+	return out, salt, nil
+}
+
+// decrypt decrypts the scoped PDU
+func (k *keys) decrypt(data []byte, boots, time int32, salt []byte) ([]byte, error) {
+	if len(salt) != 8 { //nolint: gomnd
+		return nil, ErrDecryption
+	}
+	b, err := aes.NewCipher(k.priv)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCFBDecrypter(b, aesIV(boots, time, salt)).XORKeyStream(out, data)
+	return out, nil
+}
+
+// v3Message is a SNMPv3 message with the user-based security model
+type v3Message struct {
+	id       int32
+	maxSize  int32
+	flags    byte
+	engineID []byte
+	boots    int32
+	time     int32
+	user     string
+	// context of the scoped PDU
+	contextEngineID []byte
+	contextName     string
+	pdu             PDU
+	// decoded only: authentication and privacy parameters and the encoded scoped PDU (encrypted with privacy)
+	authParams []byte
+	privParams []byte
+	scoped     []byte
+}
+
+// encode encodes the message, authenticates and encrypts it according to the flags with the keys
+func (m *v3Message) encode(k *keys) ([]byte, error) {
+	pdu, err := m.pdu.encode()
+	if err != nil {
+		return nil, err
+	}
+	scoped := tlv(tagSequence, tlv(tagOctetString, m.contextEngineID), tlv(tagOctetString, []byte(m.contextName)), pdu)
+	var authParams, privParams []byte
+	if m.flags&flagAuth != 0 {
+		if k == nil {
+			return nil, ErrMissingKeys
+		}
+		authParams = make([]byte, authParamsLength)
+	}
+	if m.flags&flagPriv != 0 {
+		if k == nil || k.priv == nil {
+			return nil, ErrMissingKeys
+		}
+		var encrypted []byte
+		if encrypted, privParams, err = k.encrypt(scoped, m.boots, m.time); err != nil {
+			return nil, err
+		}
+		scoped = tlv(tagOctetString, encrypted)
+	}
+	usmPrefix := append(append(append(
+		tlv(tagOctetString, m.engineID),
+		encodeInt(tagInteger, int64(m.boots))...),
+		encodeInt(tagInteger, int64(m.time))...),
+		tlv(tagOctetString, []byte(m.user))...)
+	authTLV, privTLV := tlv(tagOctetString, authParams), tlv(tagOctetString, privParams)
+	usm := tlv(tagSequence, usmPrefix, authTLV, privTLV)
+	version := encodeInt(tagInteger, Version3)
+	header := tlv(tagSequence,
+		encodeInt(tagInteger, int64(m.id)),
+		encodeInt(tagInteger, int64(m.maxSize)),
+		tlv(tagOctetString, []byte{m.flags}),
+		encodeInt(tagInteger, securityModelUSM))
+	security := tlv(tagOctetString, usm)
+	b := tlv(tagSequence, version, header, security, scoped)
+	if m.flags&flagAuth != 0 {
+		// the auth params are followed by the priv params at the end of the security parameters and the scoped PDU
+		offset := len(b) - len(scoped) - len(privTLV) - authParamsLength
+		copy(b[offset:], k.digest(b))
+	}
+	return b, nil
+}
+
+// decodeV3 decodes the header and security parameters of a v3 message, the scoped PDU is decoded by open
+func decodeV3(d decoder) (*v3Message, error) {
+	m := &v3Message{}
+	hc, err := d.expect(tagSequence)
+	if err != nil {
+		return nil, err
+	}
+	h := decoder(hc)
+	var v [2]int64
+	for i := range v {
+		if v[i], err = h.int(); err != nil {
+			return nil, err
+		}
+	}
+	m.id, m.maxSize = int32(v[0]), int32(v[1])
+	flags, err := h.expect(tagOctetString)
+	if err != nil {
+		return nil, err
+	}
+	if len(flags) != 1 {
+		return nil, ErrInvalidLength
+	}
+	m.flags = flags[0]
+	if model, err := h.int(); err != nil {
+		return nil, err
+	} else if model != securityModelUSM {
+		return nil, fmt.Errorf("security model %d: %w", model, ErrUnsupportedSecurity)
+	}
+	sc, err := d.expect(tagOctetString)
+	if err != nil {
+		return nil, err
+	}
+	s := decoder(sc)
+	uc, err := s.expect(tagSequence)
+	if err != nil {
+		return nil, err
+	}
+	u := decoder(uc)
+	if m.engineID, err = u.expect(tagOctetString); err != nil {
+		return nil, err
+	}
+	for _, i := range []*int32{&m.boots, &m.time} {
+		v, err := u.int()
+		if err != nil {
+			return nil, err
+		}
+		*i = int32(v)
+	}
+	user, err := u.expect(tagOctetString)
+	if err != nil {
+		return nil, err
+	}
+	m.user = string(user)
+	if m.authParams, err = u.expect(tagOctetString); err != nil {
+		return nil, err
+	}
+	if m.privParams, err = u.expect(tagOctetString); err != nil {
+		return nil, err
+	}
+	m.scoped = d
+	return m, nil
+}
+
+// open verifies the authentication of the message, decrypts and decodes the scoped PDU.
+// The message is the whole received message, its auth params are zeroed for the verification.
+func (m *v3Message) open(message []byte, k *keys) error {
+	if m.flags&flagAuth != 0 {
+		if k == nil {
+			return ErrMissingKeys
+		}
+		if len(m.authParams) != authParamsLength {
+			return ErrWrongDigest
+		}
+		received := append([]byte{}, m.authParams...)
+		// the auth params alias the message
+		for i := range m.authParams {
+			m.authParams[i] = 0
+		}
+		if !hmac.Equal(received, k.digest(message)) {
+			return ErrWrongDigest
+		}
+	}
+	d := decoder(m.scoped)
+	if m.flags&flagPriv != 0 {
+		if k == nil || k.priv == nil {
+			return ErrMissingKeys
+		}
+		encrypted, err := d.expect(tagOctetString)
+		if err != nil {
+			return err
+		}
+		plain, err := k.decrypt(encrypted, m.boots, m.time, m.privParams)
+		if err != nil {
+			return err
+		}
+		d = decoder(plain)
+	}
+	c, err := d.expect(tagSequence)
+	if err != nil {
+		return fmt.Errorf("%s: %w", err, ErrDecryption)
+	}
+	sp := decoder(c)
+	if m.contextEngineID, err = sp.expect(tagOctetString); err != nil {
+		return err
+	}
+	name, err := sp.expect(tagOctetString)
+	if err != nil {
+		return err
+	}
+	m.contextName = string(name)
+	m.pdu, err = decodePDU(sp)
+	return err
+}
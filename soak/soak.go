@@ -0,0 +1,262 @@
+// Package soak runs the client against the fault injecting simulator for many simulated hours.
+//
+// the clock is accelerated by polling without waiting for the simulated interval. The run checks that every
+// failure is caused by an injected fault, the client recovers by reconnecting with a fresh cipher block chain,
+// responses match their requests and no goroutines, file descriptors or memory are leaked.
+package soak
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+	"github.com/spali/go-rscp/simulator"
+)
+
+var (
+	ErrViolations     = errors.New("soak test failed")
+	ErrUnknownProfile = errors.New("unknown soak profile")
+)
+
+// Profile of a soak run
+type Profile struct {
+	Name string
+	// simulated duration of the run
+	Duration time.Duration
+	// simulated interval between two polls
+	Interval time.Duration
+	// simulated interval the client is recreated (i.e. restart of a daemon)
+	Restart time.Duration
+	// receive timeout of the client, bounds the real time spent on stalled responses
+	ReceiveTimeout time.Duration
+	Faults         simulator.Faults
+	// maximum growth of the heap in bytes between the end of the warm up and the end of the run
+	MaxHeapGrowth uint64
+}
+
+// Profiles are the predefined profiles, short for every CI run and long for nightly runs
+//nolint: gomnd
+var Profiles = map[string]Profile{
+	"short": {
+		Name:           "short",
+		Duration:       time.Hour * 4,
+		Interval:       time.Second * 10,
+		Restart:        time.Hour,
+		ReceiveTimeout: time.Millisecond * 100,
+		Faults:         simulator.Faults{Drop: 0.01, Stall: 0.002, Truncate: 0.01, Partial: 0.05, Corrupt: 0.01, Seed: 1},
+		MaxHeapGrowth:  8 << 20,
+	},
+	"long": {
+		Name:           "long",
+		Duration:       time.Hour * 24 * 7,
+		Interval:       time.Second * 10,
+		Restart:        time.Hour * 6,
+		ReceiveTimeout: time.Millisecond * 100,
+		Faults:         simulator.Faults{Drop: 0.002, Stall: 0.0005, Truncate: 0.002, Partial: 0.02, Corrupt: 0.002, Seed: 1},
+		MaxHeapGrowth:  8 << 20,
+	},
+}
+
+// warmUpDivisor defines the part of the run used to warm up before the heap is measured
+const warmUpDivisor = 10
+
+// settleTimeout is the time waited for goroutines to end and connections to be closed
+const settleTimeout = time.Second * 2
+
+// settle polls the condition until it's true or the settle timeout is reached
+func settle(condition func() bool) bool {
+	for deadline := time.Now().Add(settleTimeout); !condition(); {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond * 10) //nolint: gomnd
+	}
+	return true
+}
+
+// Report of a soak run
+type Report struct {
+	Profile   string        `json:"profile"`
+	Simulated time.Duration `json:"simulated"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Requests  int           `json:"requests"`
+	Failures  int           `json:"failures"`
+	Restarts  int           `json:"restarts"`
+	// connections accepted by the simulator
+	Connections int                     `json:"connections"`
+	Faults      map[simulator.Fault]int `json:"faults"`
+	Goroutines  [2]int                  `json:"goroutines"`
+	// open file descriptors before and after the run, -1 if not supported
+	FileDescriptors [2]int    `json:"fileDescriptors"`
+	Heap            [2]uint64 `json:"heap"`
+	Violations      []string  `json:"violations"`
+}
+
+// violation records a failed check
+func (r *Report) violation(format string, args ...interface{}) {
+	r.Violations = append(r.Violations, fmt.Sprintf(format, args...))
+}
+
+// openFiles returns the number of open file descriptors or -1 if not supported
+func openFiles() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
+
+// heap returns the allocated heap after a garbage collection
+func heap() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// echo answers EMS_REQ_SET_POWER with the requested power like the device does
+func echo(r rscp.Message) *rscp.Message {
+	if r.Tag != rscp.EMS_REQ_SET_POWER {
+		return nil
+	}
+	values, _ := r.Value.([]rscp.Message)
+	if m := rscp.FindTag(values, rscp.EMS_REQ_SET_POWER_VALUE); m != nil {
+		return &rscp.Message{Tag: rscp.EMS_SET_POWER, DataType: rscp.Int32, Value: m.Value}
+	}
+	return nil
+}
+
+// pollRequests returns the requests of a poll, the power identifies the response
+func pollRequests(power int32) ([]rscp.Message, error) {
+	set, err := rscp.NewSetPowerRequest(rscp.POWER_MODE_AUTO, power)
+	if err != nil {
+		return nil, err
+	}
+	return []rscp.Message{*set, *rscp.NewMessage(rscp.EMS_REQ_BAT_SOC, nil), *rscp.NewMessage(rscp.INFO_REQ_SERIAL_NUMBER, nil)}, nil
+}
+
+// Run runs the profile and returns the report, fails with ErrViolations if a check failed
+func Run(p Profile) (Report, error) {
+	r := Report{Profile: p.Name}
+	r.Goroutines[0], r.FileDescriptors[0] = runtime.NumGoroutine(), openFiles()
+	start := time.Now()
+	const serial = "S10-SOAK"
+	server, err := simulator.Start("127.0.0.1:0", simulator.Config{
+		Key: "soak", User: "soak", Password: "soak", Serial: serial,
+		Values:  map[rscp.Tag]interface{}{rscp.EMS_BAT_SOC: 50},
+		Handler: echo,
+		Faults:  p.Faults,
+	})
+	if err != nil {
+		return r, err
+	}
+	// the simulator closes its side as soon as the client closed the connection
+	disconnect := func(c *rscp.Client) {
+		if err := c.Disconnect(); err != nil {
+			r.violation("disconnect failed: %s", err)
+		}
+		if !settle(func() bool { return server.Stats().Active == 0 }) {
+			r.violation("connection still open after disconnect at %s", r.Simulated)
+		}
+	}
+	newClient := func() (*rscp.Client, error) {
+		return rscp.NewClient(rscp.ClientConfig{
+			Address: "127.0.0.1", Port: uint16(server.Addr().Port), Username: "soak", Password: "soak", Key: "soak",
+			ReceiveTimeout: p.ReceiveTimeout,
+		})
+	}
+	client, err := newClient()
+	if err != nil {
+		_ = server.Close()
+		return r, err
+	}
+	var (
+		connects   int
+		connect    = true
+		mismatches int
+		restarted  time.Duration
+	)
+	for r.Simulated = 0; r.Simulated < p.Duration; r.Simulated += p.Interval {
+		if r.Simulated-restarted >= p.Restart {
+			disconnect(client)
+			if client, err = newClient(); err != nil {
+				_ = server.Close()
+				return r, err
+			}
+			restarted, connect = r.Simulated, true
+			r.Restarts++
+		}
+		if r.Heap[0] == 0 && r.Simulated >= p.Duration/warmUpDivisor {
+			r.Heap[0] = heap()
+		}
+		power := int32(r.Requests % (1 << 16))
+		requests, err := pollRequests(power)
+		if err != nil {
+			_ = server.Close()
+			return r, err
+		}
+		if connect {
+			connects++
+			connect = false
+		}
+		r.Requests++
+		responses, err := client.SendMultiple(requests)
+		if err != nil {
+			r.Failures++
+			connect = true
+			continue
+		}
+		if len(responses) != len(requests) || responses[0].Value != power || responses[2].Value != serial {
+			mismatches++
+		}
+	}
+	disconnect(client)
+	if err := server.Close(); err != nil {
+		r.violation("closing the simulator failed: %s", err)
+	}
+	r.Elapsed = time.Since(start)
+	st := server.Stats()
+	r.Connections, r.Faults = st.Connections, st.Faults
+	// before the garbage collection closes leaked connections
+	r.FileDescriptors[1] = openFiles()
+	r.Heap[1] = heap()
+	r.check(p, connects, mismatches)
+	if len(r.Violations) > 0 {
+		return r, fmt.Errorf("%d violations: %w", len(r.Violations), ErrViolations)
+	}
+	return r, nil
+}
+
+// check checks the results of the run
+func (r *Report) check(p Profile, connects, mismatches int) {
+	failing := 0
+	for f, n := range r.Faults {
+		if f != simulator.FaultPartial {
+			failing += n
+		}
+	}
+	if r.Failures != failing {
+		r.violation("%d failures, but %d failing faults injected (cipher block chain out of sync?)", r.Failures, failing)
+	}
+	if mismatches > 0 {
+		r.violation("%d responses did not match their request", mismatches)
+	}
+	if r.Connections != connects {
+		r.violation("%d connections, want %d (one per start, restart and failure)", r.Connections, connects)
+	}
+	if p.Faults.Partial > 0 && r.Faults[simulator.FaultPartial] == 0 {
+		r.violation("no partial frames injected")
+	}
+	if !settle(func() bool { r.Goroutines[1] = runtime.NumGoroutine(); return r.Goroutines[1] <= r.Goroutines[0] }) {
+		r.violation("goroutines leaked: %d before, %d after", r.Goroutines[0], r.Goroutines[1])
+	}
+	if r.FileDescriptors[0] >= 0 && r.FileDescriptors[1] > r.FileDescriptors[0] {
+		r.violation("file descriptors leaked: %d before, %d after", r.FileDescriptors[0], r.FileDescriptors[1])
+	}
+	if r.Heap[1] > r.Heap[0] && r.Heap[1]-r.Heap[0] > p.MaxHeapGrowth {
+		r.violation("heap grew by %d bytes (from %d to %d)", r.Heap[1]-r.Heap[0], r.Heap[0], r.Heap[1])
+	}
+}
@@ -0,0 +1,31 @@
+package soak
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TestSoak runs the short profile or the profile set by E3DC_SOAK_PROFILE (i.e. long for nightly runs)
+func TestSoak(t *testing.T) {
+	name := os.Getenv("E3DC_SOAK_PROFILE")
+	if name == "" {
+		name = "short"
+	}
+	p, ok := Profiles[name]
+	if !ok {
+		t.Fatalf("%s: %s", name, ErrUnknownProfile)
+	}
+	level := log.GetLevel()
+	log.SetLevel(log.ErrorLevel)
+	defer log.SetLevel(level)
+	r, err := Run(p)
+	t.Logf("%+v", r)
+	if err != nil {
+		t.Errorf("Run() error = %v, violations %v", err, r.Violations)
+	}
+	if r.Failures == 0 || r.Restarts == 0 {
+		t.Errorf("Run() = %+v, want failures and restarts", r)
+	}
+}
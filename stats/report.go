@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	secondsPerHour = 3600
+	percent        = 100
+)
+
+// couplingModeNames as documented for EMS_COUPLING_MODE
+var couplingModeNames = map[uint8]string{
+	0: "DC",
+	1: "DC-MultiWR",
+	2: "AC",
+	3: "HYBRID",
+	4: "ISLAND",
+}
+
+// Report of a period, durations are in hours and effectiveness in percent
+type Report struct {
+	Period                     string             `json:"period"`
+	Sampled                    float64            `json:"sampled"`
+	Modes                      map[string]float64 `json:"modes"`
+	CouplingModes              map[string]float64 `json:"couplingModes"`
+	Full                       float64            `json:"full"`
+	Empty                      float64            `json:"empty"`
+	ChargeBlocked              float64            `json:"chargeBlocked"`
+	DischargeBlocked           float64            `json:"dischargeBlocked"`
+	ChargeIdle                 float64            `json:"chargeIdle"`
+	ChargeIdleEffectiveness    float64            `json:"chargeIdleEffectiveness"`
+	DischargeIdle              float64            `json:"dischargeIdle"`
+	DischargeIdleEffectiveness float64            `json:"dischargeIdleEffectiveness"`
+	Cycles                     float64            `json:"cycles"`
+	CyclesPerDay               float64            `json:"cyclesPerDay"`
+}
+
+// NewReport creates the report of the period
+func NewReport(name string, p Period) Report {
+	h := func(v float64) float64 { return v / secondsPerHour }
+	r := Report{
+		Period:           name,
+		Sampled:          h(p.Sampled),
+		Modes:            map[string]float64{},
+		CouplingModes:    map[string]float64{},
+		Full:             h(p.Full),
+		Empty:            h(p.Empty),
+		ChargeBlocked:    h(p.ChargeBlocked),
+		DischargeBlocked: h(p.DischargeBlocked),
+		ChargeIdle:       h(p.ChargeIdle),
+		DischargeIdle:    h(p.DischargeIdle),
+		Cycles:           p.Cycles(),
+	}
+	for m, v := range p.Modes {
+		r.Modes[fmt.Sprint(m)] = h(v)
+	}
+	for m, v := range p.CouplingModes {
+		name, known := couplingModeNames[m]
+		if !known {
+			name = fmt.Sprint(m)
+		}
+		r.CouplingModes[name] = h(v)
+	}
+	if p.ChargeIdle > 0 {
+		r.ChargeIdleEffectiveness = p.ChargeIdleEffective / p.ChargeIdle * percent
+	}
+	if p.DischargeIdle > 0 {
+		r.DischargeIdleEffectiveness = p.DischargeIdleEffective / p.DischargeIdle * percent
+	}
+	if p.Days > 0 {
+		r.CyclesPerDay = r.Cycles / float64(p.Days)
+	}
+	return r
+}
+
+// DailyReports returns the reports of all days
+func (s *Statistics) DailyReports() []Report {
+	r := []Report{}
+	for _, d := range s.DaysAvailable() {
+		r = append(r, NewReport(d, s.Day(d)))
+	}
+	return r
+}
+
+// MonthlyReports returns the reports of all months
+func (s *Statistics) MonthlyReports() []Report {
+	r := []Report{}
+	for _, m := range s.MonthsAvailable() {
+		r = append(r, NewReport(m, s.Month(m)))
+	}
+	return r
+}
+
+// WriteJSON writes the reports as json array
+func WriteJSON(w io.Writer, reports []Report) error {
+	return json.NewEncoder(w).Encode(reports)
+}
@@ -0,0 +1,85 @@
+// Package stats derives long running operating statistics (time in mode, battery utilization) from polled EMS values.
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+)
+
+// Sample is a single reading of the EMS state
+type Sample struct {
+	Time time.Time `json:"time"`
+	// EMS_MODE
+	Mode uint8 `json:"mode"`
+	// EMS_COUPLING_MODE
+	CouplingMode uint8 `json:"couplingMode"`
+	// battery state of charge in %
+	SoC float64 `json:"soc"`
+	// decoded EMS_STATUS
+	Status rscp.EMSStatus `json:"status"`
+	// battery power in W (-=discharge / +=charge)
+	Battery float64 `json:"battery"`
+}
+
+// Requests returns the requests required to create a sample
+func Requests() ([]rscp.Message, error) {
+	return rscp.CreateRequests(
+		[]interface{}{rscp.EMS_REQ_MODE},
+		[]interface{}{rscp.EMS_REQ_COUPLING_MODE},
+		[]interface{}{rscp.EMS_REQ_BAT_SOC},
+		[]interface{}{rscp.EMS_REQ_STATUS},
+		[]interface{}{rscp.EMS_REQ_POWER_BAT},
+	)
+}
+
+// NewSample creates a sample from the responses of the requests returned by Requests
+func NewSample(t time.Time, responses []rscp.Message) (Sample, error) {
+	s := Sample{Time: t}
+	values := map[rscp.Tag]float64{}
+	for _, tag := range []rscp.Tag{rscp.EMS_MODE, rscp.EMS_COUPLING_MODE, rscp.EMS_BAT_SOC, rscp.EMS_POWER_BAT} {
+		m := rscp.FindTag(responses, tag)
+		if m == nil {
+			return s, fmt.Errorf("missing %s in response", tag)
+		}
+		var err error
+		if values[tag], err = m.Float64(); err != nil {
+			return s, err
+		}
+	}
+	m := rscp.FindTag(responses, rscp.EMS_STATUS)
+	if m == nil {
+		return s, fmt.Errorf("missing %s in response", rscp.EMS_STATUS)
+	}
+	var err error
+	if s.Status, err = rscp.NewEMSStatus(*m); err != nil {
+		return s, err
+	}
+	s.Mode = uint8(values[rscp.EMS_MODE])
+	s.CouplingMode = uint8(values[rscp.EMS_COUPLING_MODE])
+	s.SoC = values[rscp.EMS_BAT_SOC]
+	s.Battery = values[rscp.EMS_POWER_BAT]
+	return s, nil
+}
+
+// Collect requests a new sample and adds it to the statistics
+func Collect(sender rscp.Sender, c Config, s *Statistics, now time.Time) error {
+	var (
+		requests  []rscp.Message
+		responses []rscp.Message
+		sample    Sample
+		err       error
+	)
+	if requests, err = Requests(); err != nil {
+		return err
+	}
+	if responses, err = sender.SendMultiple(requests); err != nil {
+		return err
+	}
+	if sample, err = NewSample(now, responses); err != nil {
+		return err
+	}
+	s.Add(c, sample)
+	return nil
+}
@@ -0,0 +1,288 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+	"github.com/spali/go-rscp/rscp"
+)
+
+const (
+	dayFormat   = "2006-01-02"
+	monthFormat = "2006-01"
+)
+
+// Config of the statistics
+type Config struct {
+	// state of charge in % at or above the battery is considered full
+	FullSoC float64 `json:"fullSoc"`
+	// state of charge in % at or below the battery is considered empty
+	EmptySoC float64 `json:"emptySoc"`
+	// battery power in W (absolute) below the battery is considered idle
+	IdlePower float64 `json:"idlePower"`
+	// intervals between two samples longer than this are not accounted (i.e. polling was down)
+	MaxGap time.Duration `json:"-"`
+	// location used to assign intervals to days
+	Location *time.Location `json:"-"`
+}
+
+// DefaultConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var DefaultConfig = Config{
+	FullSoC:   100,
+	EmptySoC:  0,
+	IdlePower: 50,
+	MaxGap:    time.Minute * 5,
+	Location:  time.Local,
+}
+
+// UnmarshalJSON unmarshals the config, the maximum gap is expected as duration string (i.e. "5m")
+// and the location as IANA time zone name (i.e. "Europe/Berlin").
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	tmp := struct {
+		*config
+		MaxGap   string `json:"maxGap"`
+		Location string `json:"location"`
+	}{config: (*config)(c)}
+	if err := json.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	if tmp.MaxGap != "" {
+		var err error
+		if c.MaxGap, err = time.ParseDuration(tmp.MaxGap); err != nil {
+			return fmt.Errorf("invalid max gap: %w", err)
+		}
+	}
+	if tmp.Location != "" {
+		var err error
+		if c.Location, err = time.LoadLocation(tmp.Location); err != nil {
+			return fmt.Errorf("invalid location: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a json file, missing values are set to the defaults
+func LoadConfig(path string) (Config, error) {
+	c := Config{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	c.check()
+	return c, nil
+}
+
+// check does set default values on missing
+func (c *Config) check() {
+	if c.FullSoC <= 0 {
+		c.FullSoC = DefaultConfig.FullSoC
+	}
+	if c.IdlePower <= 0 {
+		c.IdlePower = DefaultConfig.IdlePower
+	}
+	if c.MaxGap <= 0 {
+		c.MaxGap = DefaultConfig.MaxGap
+	}
+	if c.Location == nil {
+		c.Location = DefaultConfig.Location
+	}
+}
+
+// Period contains the accumulated statistics of a period, durations are in seconds
+type Period struct {
+	// total time covered by samples
+	Sampled float64 `json:"sampled"`
+	// time in each EMS_MODE
+	Modes map[uint8]float64 `json:"modes"`
+	// time in each EMS_COUPLING_MODE
+	CouplingModes map[uint8]float64 `json:"couplingModes"`
+	// time with a full battery
+	Full float64 `json:"full"`
+	// time with an empty battery
+	Empty float64 `json:"empty"`
+	// time with charging blocked
+	ChargeBlocked float64 `json:"chargeBlocked"`
+	// time with discharging blocked
+	DischargeBlocked float64 `json:"dischargeBlocked"`
+	// time with an active charge idle period
+	ChargeIdle float64 `json:"chargeIdle"`
+	// time with an active charge idle period while the battery was not charging
+	ChargeIdleEffective float64 `json:"chargeIdleEffective"`
+	// time with an active discharge idle period
+	DischargeIdle float64 `json:"dischargeIdle"`
+	// time with an active discharge idle period while the battery was not discharging
+	DischargeIdleEffective float64 `json:"dischargeIdleEffective"`
+	// sum of all state of charge changes in %
+	SoCSwing float64 `json:"socSwing"`
+	// days with samples
+	Days int `json:"days"`
+}
+
+// merge adds the other period to this one
+func (p *Period) merge(o Period) {
+	p.Sampled += o.Sampled
+	for k, v := range o.Modes {
+		p.mode(k, v)
+	}
+	for k, v := range o.CouplingModes {
+		p.couplingMode(k, v)
+	}
+	p.Full += o.Full
+	p.Empty += o.Empty
+	p.ChargeBlocked += o.ChargeBlocked
+	p.DischargeBlocked += o.DischargeBlocked
+	p.ChargeIdle += o.ChargeIdle
+	p.ChargeIdleEffective += o.ChargeIdleEffective
+	p.DischargeIdle += o.DischargeIdle
+	p.DischargeIdleEffective += o.DischargeIdleEffective
+	p.SoCSwing += o.SoCSwing
+	p.Days += o.Days
+}
+
+func (p *Period) mode(m uint8, d float64) {
+	if p.Modes == nil {
+		p.Modes = map[uint8]float64{}
+	}
+	p.Modes[m] += d
+}
+
+func (p *Period) couplingMode(m uint8, d float64) {
+	if p.CouplingModes == nil {
+		p.CouplingModes = map[uint8]float64{}
+	}
+	p.CouplingModes[m] += d
+}
+
+// Cycles returns the equivalent full cycles (a full cycle is a swing of 200% state of charge)
+func (p Period) Cycles() float64 {
+	return p.SoCSwing / 200 //nolint: gomnd
+}
+
+// Statistics accumulates the statistics per day
+type Statistics struct {
+	// last sample added, used as start of the next interval
+	Last *Sample `json:"last,omitempty"`
+	// statistics by day
+	Days map[string]*Period `json:"days"`
+}
+
+// Load reads the statistics from a json file, a missing file results in empty statistics
+func Load(path string) (*Statistics, error) {
+	s := &Statistics{}
+	if err := jsonfile.Read(path, s); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the statistics to a json file
+func (s *Statistics) Save(path string) error {
+	return jsonfile.Write(path, s)
+}
+
+// Add accounts the interval since the last sample with the state of the last sample to the day the interval starts in.
+func (s *Statistics) Add(c Config, sample Sample) {
+	c.check()
+	last := s.Last
+	s.Last = &sample
+	if last == nil {
+		return
+	}
+	d := sample.Time.Sub(last.Time)
+	if d <= 0 {
+		log.Warnf("ignoring sample at %s not after last sample at %s", sample.Time, last.Time)
+		s.Last = last
+		return
+	}
+	if d > c.MaxGap {
+		log.Infof("gap of %s between samples not accounted", d)
+		return
+	}
+	day := last.Time.In(c.Location).Format(dayFormat)
+	if s.Days == nil {
+		s.Days = map[string]*Period{}
+	}
+	p := s.Days[day]
+	if p == nil {
+		p = &Period{Days: 1}
+		s.Days[day] = p
+	}
+	sec := d.Seconds()
+	p.Sampled += sec
+	p.mode(last.Mode, sec)
+	p.couplingMode(last.CouplingMode, sec)
+	if last.SoC >= c.FullSoC {
+		p.Full += sec
+	}
+	if last.SoC <= c.EmptySoC {
+		p.Empty += sec
+	}
+	if last.Status.Has(rscp.EMS_STATUS_CHARGE_BLOCKED) {
+		p.ChargeBlocked += sec
+	}
+	if last.Status.Has(rscp.EMS_STATUS_DISCHARGE_BLOCKED) {
+		p.DischargeBlocked += sec
+	}
+	if last.Status.Has(rscp.EMS_STATUS_CHARGE_IDLE_PERIOD) {
+		p.ChargeIdle += sec
+		if last.Battery < c.IdlePower {
+			p.ChargeIdleEffective += sec
+		}
+	}
+	if last.Status.Has(rscp.EMS_STATUS_DISCHARGE_IDLE_PERIOD) {
+		p.DischargeIdle += sec
+		if last.Battery > -c.IdlePower {
+			p.DischargeIdleEffective += sec
+		}
+	}
+	p.SoCSwing += math.Abs(sample.SoC - last.SoC)
+}
+
+// Day returns the statistics of the day (format "2006-01-02")
+func (s *Statistics) Day(day string) Period {
+	if p := s.Days[day]; p != nil {
+		return *p
+	}
+	return Period{}
+}
+
+// Month returns the merged statistics of all days of the month (format "2006-01")
+func (s *Statistics) Month(month string) Period {
+	p := Period{}
+	for day, d := range s.Days {
+		if strings.HasPrefix(day, month+"-") {
+			p.merge(*d)
+		}
+	}
+	return p
+}
+
+// DaysAvailable returns the days with statistics sorted ascending
+func (s *Statistics) DaysAvailable() []string {
+	days := make([]string, 0, len(s.Days))
+	for d := range s.Days {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+	return days
+}
+
+// MonthsAvailable returns the months with statistics sorted ascending
+func (s *Statistics) MonthsAvailable() []string {
+	months := []string{}
+	for _, d := range s.DaysAvailable() {
+		if m := d[:len(monthFormat)]; len(months) == 0 || months[len(months)-1] != m {
+			months = append(months, m)
+		}
+	}
+	return months
+}
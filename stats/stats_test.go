@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func testResponses(mode, coupling, soc uint8, status uint32, bat int32) []rscp.Message {
+	return []rscp.Message{
+		{Tag: rscp.EMS_MODE, DataType: rscp.UChar8, Value: mode},
+		{Tag: rscp.EMS_COUPLING_MODE, DataType: rscp.UChar8, Value: coupling},
+		{Tag: rscp.EMS_BAT_SOC, DataType: rscp.UChar8, Value: soc},
+		{Tag: rscp.EMS_STATUS, DataType: rscp.Uint32, Value: status},
+		{Tag: rscp.EMS_POWER_BAT, DataType: rscp.Int32, Value: bat},
+	}
+}
+
+func TestNewSample(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		responses []rscp.Message
+		want      Sample
+		wantErr   bool
+	}{
+		{"sample",
+			testResponses(1, 3, 50, 0b1, -100),
+			Sample{Time: now, Mode: 1, CouplingMode: 3, SoC: 50, Status: rscp.EMS_STATUS_CHARGE_BLOCKED, Battery: -100},
+			false,
+		},
+		{"missing status",
+			testResponses(1, 3, 50, 0, 0)[:3],
+			Sample{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSample(now, tt.responses)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSample() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("NewSample() = %v, want %v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	c := Config{Location: time.UTC}
+	start := time.Date(2021, 1, 31, 23, 58, 0, 0, time.UTC)
+	sender := rscptest.NewSender(
+		// full battery with charge idle period, not charging
+		testResponses(0, 3, 100, 0b100001, 0),
+		// discharging with discharge idle period
+		testResponses(1, 3, 90, 0b1000000, -1000),
+		// empty battery, discharge blocked
+		testResponses(1, 3, 0, 0b10, 0),
+		// after a gap
+		testResponses(1, 3, 0, 0b10, 0),
+		testResponses(1, 3, 10, 0, 500),
+	)
+	s := &Statistics{}
+	for _, tm := range []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute),
+		start.Add(time.Hour), start.Add(time.Hour + time.Minute)} {
+		if err := Collect(sender, c, s, tm); err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+	}
+	want := map[string]*Period{
+		"2021-01-31": {
+			Sampled: 120, Modes: map[uint8]float64{0: 60, 1: 60}, CouplingModes: map[uint8]float64{3: 120},
+			Full: 60, ChargeBlocked: 60, ChargeIdle: 60, ChargeIdleEffective: 60, DischargeIdle: 60,
+			SoCSwing: 100, Days: 1,
+		},
+		"2021-02-01": {
+			Sampled: 60, Modes: map[uint8]float64{1: 60}, CouplingModes: map[uint8]float64{3: 60},
+			Empty: 60, DischargeBlocked: 60, SoCSwing: 10, Days: 1,
+		},
+	}
+	if diff := deep.Equal(s.Days, want); diff != nil {
+		t.Errorf("Statistics.Days = %v, want %v\n%s", s.Days, want, diff)
+	}
+
+	reports := s.MonthlyReports()
+	if len(reports) != 2 || reports[0].Period != "2021-01" || reports[1].Period != "2021-02" {
+		t.Fatalf("MonthlyReports() = %v", reports)
+	}
+	if r := reports[0]; r.ChargeIdleEffectiveness != 100 || r.DischargeIdleEffectiveness != 0 ||
+		r.Cycles != 0.5 || r.CyclesPerDay != 0.5 || r.CouplingModes["HYBRID"] != 120.0/3600 {
+		t.Errorf("MonthlyReports()[0] = %+v", r)
+	}
+	if r := s.DailyReports(); len(r) != 2 || r[1].Empty != 60.0/3600 {
+		t.Errorf("DailyReports() = %+v", r)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    Config
+		wantErr bool
+	}{
+		{"defaults", `{}`, DefaultConfig, false},
+		{"values",
+			`{"fullSoc": 95, "emptySoc": 5, "idlePower": 100, "maxGap": "1m", "location": "UTC"}`,
+			Config{FullSoC: 95, EmptySoC: 5, IdlePower: 100, MaxGap: time.Minute, Location: time.UTC},
+			false,
+		},
+		{"invalid max gap", `{"maxGap": "1x"}`, Config{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stats.json")
+			if err := ioutil.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("LoadConfig() = %+v, want %+v\n%s", got, tt.want, diff)
+			}
+		})
+	}
+}
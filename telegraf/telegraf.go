@@ -0,0 +1,266 @@
+// Package telegraf gathers values of the system as InfluxDB line protocol for the execd input plugin of Telegraf.
+//
+// Every gather returns one point per component with the values of the queried tags as fields, tagged with the device
+// (the serial number unless configured) and for indexed components the namespace and index:
+//
+//	e3dc,device=S10-123 EMS_POWER_PV=1000i,EMS_POWER_BAT=-500i 1651400000000000000
+//	e3dc,device=S10-123,index=0,namespace=BAT BAT_RSOC=42.5 1651400000000000000
+//
+// computed tags are fields of the point of the system.
+package telegraf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/rscp"
+)
+
+var (
+	ErrNoTags        = errors.New("no tags to gather")
+	ErrMissingDevice = errors.New("missing device")
+)
+
+// DefaultMeasurement is the measurement of the points if not configured
+const DefaultMeasurement = "e3dc"
+
+// Config of the gathered values
+type Config struct {
+	// measurement of the points
+	Measurement string
+	// device tag of the points, the serial number of the system if empty
+	Device string
+	// queries of the values (i.e. EMS_POWER_PV or BAT_RSOC@0)
+	Tags []string
+	// computed tags gathered in addition, nil if none
+	Computed *computed.Tags
+}
+
+// check sets the defaults and checks the config
+func (c *Config) check() error {
+	if c.Measurement == "" {
+		c.Measurement = DefaultMeasurement
+	}
+	if len(c.Tags) == 0 && c.Computed == nil {
+		return ErrNoTags
+	}
+	return nil
+}
+
+// Input gathers the values of the config
+type Input struct {
+	measurement string
+	device      string
+	queries     []rscp.Query
+	computed    *computed.Tags
+	requests    []rscp.Message
+}
+
+// New creates an input of the config
+func New(c Config) (*Input, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	in := &Input{measurement: c.Measurement, device: c.Device, computed: c.Computed}
+	for _, t := range c.Tags {
+		q, err := rscp.ParseQuery(t)
+		if err != nil {
+			return nil, err
+		}
+		in.queries = append(in.queries, q)
+	}
+	r, err := rscp.QueryRequests(in.queries...)
+	if err != nil {
+		return nil, err
+	}
+	in.requests = r
+	if in.computed != nil {
+		deps, err := in.computed.Requests()
+		if err != nil {
+			return nil, err
+		}
+		in.requests = append(in.requests, deps...)
+	}
+	return in, nil
+}
+
+// Gather requests the values and returns the points at the time,
+// values which are missing or answered with an error are logged and skipped.
+func (in *Input) Gather(s rscp.Sender, t time.Time) ([]Point, error) {
+	requests := in.requests
+	if in.device == "" {
+		// the serial number is requested until known, the session is persistent
+		requests = append([]rscp.Message{*rscp.NewMessage(rscp.INFO_REQ_SERIAL_NUMBER, nil)}, requests...)
+	}
+	responses, err := s.SendMultiple(requests)
+	if err != nil {
+		return nil, err
+	}
+	if in.device == "" {
+		serial, err := rscp.Query{Tag: rscp.INFO_SERIAL_NUMBER}.Text(responses)
+		if err != nil || serial == "" {
+			return nil, fmt.Errorf("serial number: %w", ErrMissingDevice)
+		}
+		in.device = serial
+	}
+	return in.points(responses, t), nil
+}
+
+// points returns the points of the values within the responses, one per component
+func (in *Input) points(responses []rscp.Message, t time.Time) []Point {
+	var r []Point
+	components := map[string]int{}
+	// point returns the index of the point of the component, the system without namespace
+	point := func(namespace *rscp.Namespace, index uint16) int {
+		tags := map[string]string{"device": in.device}
+		if namespace != nil {
+			tags["namespace"] = namespace.Name
+			tags["index"] = strconv.Itoa(int(index))
+		}
+		key := tags["namespace"] + rscp.QueryIndexSeparator + tags["index"]
+		i, exists := components[key]
+		if !exists {
+			i = len(r)
+			components[key] = i
+			r = append(r, Point{Measurement: in.measurement, Tags: tags, Time: t})
+		}
+		return i
+	}
+	for _, q := range in.queries {
+		v, ok := fieldValue(q, responses)
+		if !ok {
+			continue
+		}
+		i := point(q.Namespace, q.Index)
+		r[i].Fields = append(r[i].Fields, Field{q.Tag.String(), v})
+	}
+	if in.computed == nil {
+		return r
+	}
+	values, err := in.computed.Evaluate(responses)
+	if err != nil {
+		log.Warnf("telegraf: computed tags: %s", err)
+	}
+	for _, name := range in.computed.Names() {
+		v, exists := values[name]
+		if !exists || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		i := point(nil, 0)
+		r[i].Fields = append(r[i].Fields, Field{name, v})
+	}
+	return r
+}
+
+// fieldValue returns the value of the query as field value, logs why if not available
+func fieldValue(q rscp.Query, responses []rscp.Message) (interface{}, bool) {
+	m := q.Find(responses)
+	if m == nil {
+		log.Warnf("telegraf: %s: missing in response", q)
+		return nil, false
+	}
+	switch v := m.Value.(type) {
+	case rscp.RscpError:
+		log.Warnf("telegraf: %s returned error %s", q, v)
+		return nil, false
+	case bool, string, int8, uint8, int16, uint16, int32, uint32, int64, uint64:
+		return v, true
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			break
+		}
+		return v, true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			break
+		}
+		return v, true
+	case time.Time:
+		return v.Unix(), true
+	}
+	log.Warnf("telegraf: %s: unsupported value %v of type %T", q, m.Value, m.Value)
+	return nil, false
+}
+
+// Field of a point
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Point of the line protocol
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      []Field
+	Time        time.Time
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// String returns the point as line of the line protocol with the tags sorted by key
+func (p Point) String() string {
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+	keys := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("," + keyEscaper.Replace(k) + "=" + keyEscaper.Replace(p.Tags[k]))
+	}
+	for i, f := range p.Fields {
+		if i == 0 {
+			b.WriteString(" ")
+		} else {
+			b.WriteString(",")
+		}
+		b.WriteString(keyEscaper.Replace(f.Key) + "=" + formatValue(f.Value))
+	}
+	b.WriteString(" " + strconv.FormatInt(p.Time.UnixNano(), 10))
+	return b.String()
+}
+
+// formatValue returns the field value of the line protocol, integers with the suffix i and strings quoted
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return `"` + stringEscaper.Replace(v) + `"`
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case uint64:
+		if v > math.MaxInt64 {
+			return strconv.FormatUint(v, 10) + "u"
+		}
+		return strconv.FormatUint(v, 10) + "i"
+	default:
+		return fmt.Sprintf("%di", v)
+	}
+}
+
+// WritePoints writes the points as lines of the line protocol
+func WritePoints(w io.Writer, points []Point) error {
+	for _, p := range points {
+		if _, err := io.WriteString(w, p.String()+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package telegraf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spali/go-rscp/computed"
+	"github.com/spali/go-rscp/internal/rscptest"
+	"github.com/spali/go-rscp/rscp"
+)
+
+func TestInput_Gather(t *testing.T) {
+	// the battery power isn't available, a computed tag referencing it is skipped
+	tags, err := computed.New(map[string]string{"SOC_HALF": "BAT_RSOC@0 / 2", "BAT_KW": "EMS_POWER_BAT / 1000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := New(Config{Tags: []string{"EMS_POWER_PV", "BAT_RSOC@0", "EMS_POWER_BAT", "EMS_STATUS", "BAT_DEVICE_NAME@0",
+		"PVI_ON_GRID@0", "EMS_MAX_CHARGE_POWER"}, Computed: tags})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := in.Gather(rscptest.NewSender(), time.Now()); err == nil {
+		t.Error("Gather() of a failed request succeeded")
+	}
+	responses := []rscp.Message{
+		{Tag: rscp.INFO_SERIAL_NUMBER, DataType: rscp.CString, Value: "S10 1"},
+		{Tag: rscp.EMS_POWER_PV, DataType: rscp.Int32, Value: int32(1000)},
+		{Tag: rscp.EMS_POWER_BAT, DataType: rscp.Error, Value: rscp.ERR_NOT_AVAILABLE},
+		{Tag: rscp.EMS_GET_POWER_SETTINGS, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.EMS_MAX_CHARGE_POWER, DataType: rscp.Uint32, Value: uint32(4500)},
+		}},
+		{Tag: rscp.BAT_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.BAT_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.BAT_RSOC, DataType: rscp.Float32, Value: float32(42.5)},
+			{Tag: rscp.BAT_DEVICE_NAME, DataType: rscp.CString, Value: `BAT "1"`},
+		}},
+		{Tag: rscp.PVI_DATA, DataType: rscp.Container, Value: []rscp.Message{
+			{Tag: rscp.PVI_INDEX, DataType: rscp.UInt16, Value: uint16(0)},
+			{Tag: rscp.PVI_ON_GRID, DataType: rscp.Bool, Value: true},
+		}},
+	}
+	points, err := in.Gather(rscptest.NewSender(responses), time.Unix(1651400000, 0))
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var b bytes.Buffer
+	if err := WritePoints(&b, points); err != nil {
+		t.Fatal(err)
+	}
+	want := `e3dc,device=S10\ 1 EMS_POWER_PV=1000i,EMS_MAX_CHARGE_POWER=4500i,SOC_HALF=21.25 1651400000000000000
+e3dc,device=S10\ 1,index=0,namespace=BAT BAT_RSOC=42.5,BAT_DEVICE_NAME="BAT \"1\"" 1651400000000000000
+e3dc,device=S10\ 1,index=0,namespace=PVI PVI_ON_GRID=true 1651400000000000000
+`
+	if b.String() != want {
+		t.Errorf("Gather() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	// the serial number is only requested once
+	if _, err := in.Gather(rscptest.NewSender([]rscp.Message{}), time.Now()); err != nil {
+		t.Errorf("Gather() with known device error = %v", err)
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr error
+	}{
+		{"no tags", Config{}, ErrNoTags},
+		{"unknown tag", Config{Tags: []string{"FOO"}}, rscp.ErrValidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.config); !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	in, _ := New(Config{Tags: []string{"EMS_POWER_PV"}})
+	if _, err := in.Gather(rscptest.NewSender([]rscp.Message{}), time.Now()); !errors.Is(err, ErrMissingDevice) {
+		t.Errorf("Gather() without serial number error = %v, want %v", err, ErrMissingDevice)
+	}
+}
+
+func TestSummarizer(t *testing.T) {
+	s, err := NewSummarizer(DefaultMeasurement, time.Minute)
+	if err != nil {
+		t.Fatalf("NewSummarizer() error = %v", err)
+	}
+	start := time.Unix(1651399980, 0)
+	points := func(pv int32, rsoc float32, working bool) []Point {
+		return []Point{
+			{DefaultMeasurement, map[string]string{"device": "S10"}, []Field{{"EMS_POWER_PV", pv}}, start},
+			{DefaultMeasurement, map[string]string{"device": "S10", "index": "0", "namespace": "BAT"}, []Field{{"BAT_RSOC", rsoc}, {"BAT_DEVICE_WORKING", working}}, start},
+		}
+	}
+	for i, p := range [][]Point{points(1000, 40, true), points(3000, 42, false)} {
+		if got := s.Add(start.Add(time.Duration(i)*time.Second*30), p); len(got) != 0 {
+			t.Errorf("Add() within the window = %v", got)
+		}
+	}
+	var b bytes.Buffer
+	if err := WritePoints(&b, s.Add(start.Add(time.Minute), points(0, 0, true))); err != nil {
+		t.Fatal(err)
+	}
+	want := `e3dc,device=S10,index=0,namespace=BAT BAT_DEVICE_WORKING=false,BAT_DEVICE_WORKING_changes=1i,BAT_DEVICE_WORKING_count=2i,BAT_RSOC_min=40,BAT_RSOC_max=42,BAT_RSOC_mean=41,BAT_RSOC_last=42,BAT_RSOC_count=2i 1651399980000000000
+e3dc,device=S10 EMS_POWER_PV_min=1000,EMS_POWER_PV_max=3000,EMS_POWER_PV_mean=2000,EMS_POWER_PV_last=3000,EMS_POWER_PV_count=2i 1651399980000000000
+`
+	if b.String() != want {
+		t.Errorf("Add() =\n%s\nwant\n%s", b.String(), want)
+	}
+	if got := s.Flush(); len(got) != 2 || !got[0].Time.Equal(start.Add(time.Minute)) {
+		t.Errorf("Flush() = %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package telegraf
+
+import (
+	"time"
+
+	"github.com/spali/go-rscp/aggregate"
+	"github.com/spali/go-rscp/rscp"
+)
+
+// component of the points summarized
+type component struct {
+	key  string
+	tags map[string]string
+}
+
+// Summarizer summarizes the gathered points in time-aligned windows (see package aggregate).
+//
+// every window emits one point per component at the start of the window, with the fields <field>_min, <field>_max,
+// <field>_mean and <field>_last of numeric values and <field> and <field>_changes with the last value and the number
+// of changes of other values, and <field>_count with the number of samples of both.
+type Summarizer struct {
+	measurement string
+	window      *aggregate.Window
+	// component and field of the paths aggregated
+	components map[string]component
+	fields     map[string]string
+}
+
+// NewSummarizer creates a summarizer with windows of the size
+func NewSummarizer(measurement string, size time.Duration) (*Summarizer, error) {
+	w, err := aggregate.NewWindow(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Summarizer{measurement: measurement, window: w, components: map[string]component{}, fields: map[string]string{}}, nil
+}
+
+// Add adds the points gathered at the time, returns the summaries of the previous window if the time starts a new window
+func (s *Summarizer) Add(t time.Time, points []Point) []Point {
+	values := map[string]interface{}{}
+	for _, p := range points {
+		key := p.Tags["namespace"] + rscp.QueryIndexSeparator + p.Tags["index"]
+		for _, f := range p.Fields {
+			path := f.Key
+			if _, indexed := p.Tags["index"]; indexed {
+				path += rscp.QueryIndexSeparator + p.Tags["index"]
+			}
+			s.components[path] = component{key: key, tags: p.Tags}
+			s.fields[path] = f.Key
+			values[path] = f.Value
+		}
+	}
+	return s.summaries(s.window.AddValues(t, values))
+}
+
+// Flush returns the summaries of the current window (i.e. to emit the partial window on shutdown)
+func (s *Summarizer) Flush() []Point {
+	return s.summaries(s.window.Flush())
+}
+
+// summaries returns the points of the aggregates, one per component
+func (s *Summarizer) summaries(aggregates []aggregate.Aggregate) []Point {
+	var r []Point
+	points := map[string]int{}
+	for _, a := range aggregates {
+		c := s.components[a.Path]
+		i, exists := points[c.key]
+		if !exists {
+			i = len(r)
+			points[c.key] = i
+			r = append(r, Point{Measurement: s.measurement, Tags: c.tags, Time: a.Start})
+		}
+		f := s.fields[a.Path]
+		if a.Numeric {
+			r[i].Fields = append(r[i].Fields,
+				Field{f + "_min", a.Min}, Field{f + "_max", a.Max}, Field{f + "_mean", a.Mean}, Field{f + "_last", a.Last})
+		} else {
+			r[i].Fields = append(r[i].Fields, Field{f, a.Value}, Field{f + "_changes", a.Changes})
+		}
+		r[i].Fields = append(r[i].Fields, Field{f + "_count", a.Count})
+	}
+	return r
+}
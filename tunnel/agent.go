@@ -0,0 +1,225 @@
+package tunnel
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AgentConfig of the agent of a site
+type AgentConfig struct {
+	// host and port of the hub
+	Hub string
+	// path of the PEM encoded certificates the hub certificate is verified with, the system pool if empty
+	CA string
+	// id and token of the site as configured on the hub
+	Site  string
+	Token string
+	// host and port of the system (i.e. "192.168.1.10:5033")
+	Device string
+	// timeout of a connection to the hub or the system
+	Timeout time.Duration
+	// delay between two attempts to connect to the hub
+	Retry time.Duration
+}
+
+// defaultAgentConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultAgentConfig = AgentConfig{
+	Timeout: time.Second * 10,
+	Retry:   time.Second * 5,
+}
+
+// check does set default values on missing or fail if required
+func (c *AgentConfig) check() error {
+	switch {
+	case c.Hub == "":
+		return ErrMissingHub
+	case c.Device == "":
+		return ErrMissingDevice
+	case c.Site == "":
+		return ErrMissingSiteID
+	case c.Token == "":
+		return ErrMissingToken
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultAgentConfig.Timeout
+	}
+	if c.Retry <= 0 {
+		c.Retry = defaultAgentConfig.Retry
+	}
+	return nil
+}
+
+// Agent tunnels the sessions announced by the hub to the system
+type Agent struct {
+	config AgentConfig
+	tls    *tls.Config
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	// open connections, closed when the agent stops
+	conns   map[net.Conn]struct{}
+	stopped bool
+}
+
+// NewAgent creates the agent of the config
+func NewAgent(c AgentConfig) (*Agent, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(c.Hub)
+	if err != nil {
+		return nil, err
+	}
+	t := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if c.CA != "" {
+		pem, err := ioutil.ReadFile(c.CA)
+		if err != nil {
+			return nil, err
+		}
+		t.RootCAs = x509.NewCertPool()
+		if !t.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: %w", c.CA, ErrInvalidCA)
+		}
+	}
+	return &Agent{config: c, tls: t, conns: map[net.Conn]struct{}{}}, nil
+}
+
+// Run keeps the control connection to the hub until stopped, reconnects after a delay if it's lost.
+//
+// returns an error if the hub rejects the site, all sessions are closed on return. Run can only be called once.
+func (a *Agent) Run(stop <-chan struct{}) error {
+	defer func() {
+		a.mu.Lock()
+		a.stopped = true
+		for c := range a.conns {
+			_ = c.Close()
+		}
+		a.mu.Unlock()
+		a.wg.Wait()
+	}()
+	for {
+		err := a.control(stop)
+		if errors.Is(err, ErrRejected) {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		log.Warnf("tunnel: connection to hub %s: %s, retrying in %s", a.config.Hub, err, a.config.Retry)
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(a.config.Retry):
+		}
+	}
+}
+
+// control connects to the hub and serves the announced sessions until the connection is lost or stopped
+func (a *Agent) control(stop <-chan struct{}) error {
+	c, err := a.dial(0)
+	if err != nil {
+		return err
+	}
+	log.Infof("tunnel: connected to hub %s as site %s", a.config.Hub, a.config.Site)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		_ = c.Close()
+	}()
+	r := bufio.NewReader(c)
+	for {
+		var m announcement
+		if err := readLine(r, &m); err != nil {
+			return err
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			if err := a.session(m.Session); err != nil {
+				log.Warnf("tunnel: session %d: %s", m.Session, err)
+			}
+		}()
+	}
+}
+
+// session connects the session to the hub and pipes it to the system
+func (a *Agent) session(id uint64) error {
+	c, err := a.dial(id)
+	if err != nil {
+		return err
+	}
+	if !a.track(c) {
+		return nil
+	}
+	defer a.untrack(c)
+	// the session is closed by the hub if the system is not reachable
+	d, err := net.DialTimeout("tcp", a.config.Device, a.config.Timeout)
+	if err != nil {
+		return fmt.Errorf("device %s: %w", a.config.Device, err)
+	}
+	if !a.track(d) {
+		return nil
+	}
+	defer a.untrack(d)
+	pipe(c, d)
+	return nil
+}
+
+// dial connects to the hub with the hello of the session, returns the connection once accepted
+func (a *Agent) dial(session uint64) (net.Conn, error) {
+	c, err := tls.DialWithDialer(&net.Dialer{Timeout: a.config.Timeout}, "tcp", a.config.Hub, a.tls)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.SetDeadline(time.Now().Add(a.config.Timeout))
+	var r result
+	err = writeLine(c, hello{Site: a.config.Site, Token: a.config.Token, Session: session})
+	if err == nil {
+		err = readLine(c, &r)
+	}
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if r.Error != "" {
+		_ = c.Close()
+		return nil, fmt.Errorf("%s: %w", r.Error, ErrRejected)
+	}
+	_ = c.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// track adds an open connection, returns false and closes it if the agent is stopping
+func (a *Agent) track(c net.Conn) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopped {
+		_ = c.Close()
+		return false
+	}
+	a.conns[c] = struct{}{}
+	return true
+}
+
+// untrack closes and removes the connection
+func (a *Agent) untrack(c net.Conn) {
+	_ = c.Close()
+	a.mu.Lock()
+	delete(a.conns, c)
+	a.mu.Unlock()
+}
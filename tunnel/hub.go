@@ -0,0 +1,363 @@
+package tunnel
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spali/go-rscp/internal/jsonfile"
+)
+
+// Site reachable through the hub
+type Site struct {
+	// unique id of the site, used by the agent
+	ID string `json:"id"`
+	// secret token the agent of the site authenticates with
+	Token string `json:"token"`
+	// address the tools connect to for the system of the site (i.e. "127.0.0.1:15033")
+	Listen string `json:"listen"`
+}
+
+// HubConfig of the hub
+type HubConfig struct {
+	// address the agents connect to
+	Listen string `json:"listen"`
+	// paths of the PEM encoded TLS certificate and key of the hub
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+	// all sites reachable through the hub
+	Sites []Site `json:"sites"`
+	// time an agent has to authenticate or to connect a session
+	Timeout time.Duration `json:"-"`
+}
+
+// defaultHubConfig defines the default config values used when not provided by the user.
+//nolint: gomnd
+var defaultHubConfig = HubConfig{
+	Listen:  ":7443",
+	Timeout: time.Second * 10,
+}
+
+// LoadHubConfig reads the hub config from a json file
+func LoadHubConfig(path string) (HubConfig, error) {
+	c := HubConfig{}
+	if err := jsonfile.Read(path, &c); err != nil {
+		return c, err
+	}
+	return c, c.check()
+}
+
+// check does set default values on missing or fail if required
+func (c *HubConfig) check() error {
+	if c.Listen == "" {
+		c.Listen = defaultHubConfig.Listen
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultHubConfig.Timeout
+	}
+	if c.Cert == "" || c.Key == "" {
+		return ErrMissingCert
+	}
+	if len(c.Sites) == 0 {
+		return ErrNoSites
+	}
+	ids := map[string]bool{}
+	for i, s := range c.Sites {
+		switch {
+		case s.ID == "":
+			return fmt.Errorf("site at index %d: %w", i, ErrMissingSiteID)
+		case ids[s.ID]:
+			return fmt.Errorf("%s: %w", s.ID, ErrDuplicateSite)
+		case s.Token == "":
+			return fmt.Errorf("%s: %w", s.ID, ErrMissingToken)
+		case s.Listen == "":
+			return fmt.Errorf("%s: %w", s.ID, ErrMissingListen)
+		}
+		ids[s.ID] = true
+	}
+	return nil
+}
+
+// hubSite is a site with its listener and the control connection of its agent
+type hubSite struct {
+	config   Site
+	listener net.Listener
+	// control connection of the agent, nil if not connected
+	control net.Conn
+	// serializes the announcements on the control connection
+	write sync.Mutex
+}
+
+// pendingSession waits for the connection of the agent of the site
+type pendingSession struct {
+	site  *hubSite
+	agent chan net.Conn
+}
+
+// Hub accepts the agents and the tools connecting to the sites
+type Hub struct {
+	config   HubConfig
+	listener net.Listener
+	sites    map[string]*hubSite
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	// last session id
+	session uint64
+	// sessions waiting for the connection of the agent
+	pending map[uint64]pendingSession
+	// all open connections, closed with the hub
+	conns map[net.Conn]struct{}
+}
+
+// ListenHub starts the hub listening for agents and tools
+func ListenHub(c HubConfig) (*Hub, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	l, err := tls.Listen("tcp", c.Listen, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		return nil, err
+	}
+	h := &Hub{
+		config:   c,
+		listener: l,
+		sites:    map[string]*hubSite{},
+		pending:  map[uint64]pendingSession{},
+		conns:    map[net.Conn]struct{}{},
+	}
+	for _, s := range c.Sites {
+		sl, err := net.Listen("tcp", s.Listen)
+		if err != nil {
+			_ = h.Close()
+			return nil, fmt.Errorf("%s: %w", s.ID, err)
+		}
+		site := &hubSite{config: s, listener: sl}
+		h.sites[s.ID] = site
+		h.wg.Add(1)
+		go h.acceptTools(site)
+	}
+	h.wg.Add(1)
+	go h.acceptAgents()
+	return h, nil
+}
+
+// Addr returns the address the hub listens on for agents
+func (h *Hub) Addr() *net.TCPAddr {
+	return h.listener.Addr().(*net.TCPAddr)
+}
+
+// SiteAddr returns the address the tools connect to for the site, nil if the site is unknown
+func (h *Hub) SiteAddr(id string) *net.TCPAddr {
+	s, ok := h.sites[id]
+	if !ok {
+		return nil
+	}
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Connected returns if the agent of the site is connected
+func (h *Hub) Connected(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sites[id]
+	return ok && s.control != nil
+}
+
+// Close stops the hub, closes all connections and waits until all goroutines have ended
+func (h *Hub) Close() error {
+	err := h.listener.Close()
+	h.mu.Lock()
+	for _, s := range h.sites {
+		_ = s.listener.Close()
+	}
+	for c := range h.conns {
+		_ = c.Close()
+	}
+	h.mu.Unlock()
+	h.wg.Wait()
+	return err
+}
+
+// track adds an open connection
+func (h *Hub) track(c net.Conn) {
+	h.mu.Lock()
+	h.conns[c] = struct{}{}
+	h.mu.Unlock()
+}
+
+// untrack closes and removes the connection
+func (h *Hub) untrack(c net.Conn) {
+	_ = c.Close()
+	h.mu.Lock()
+	delete(h.conns, c)
+	h.mu.Unlock()
+}
+
+// acceptAgents accepts connections of the agents until the listener is closed
+func (h *Hub) acceptAgents() {
+	defer h.wg.Done()
+	for {
+		c, err := h.listener.Accept()
+		if err != nil {
+			return
+		}
+		h.track(c)
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			defer h.untrack(c)
+			h.serveAgent(c)
+		}()
+	}
+}
+
+// serveAgent authenticates the agent and serves its control or session connection
+func (h *Hub) serveAgent(c net.Conn) {
+	_ = c.SetDeadline(time.Now().Add(h.config.Timeout))
+	var m hello
+	if err := readLine(c, &m); err != nil {
+		log.Debugf("tunnel: agent %s: %s", c.RemoteAddr(), err)
+		return
+	}
+	site, err := h.authenticate(m)
+	if err != nil {
+		log.Warnf("tunnel: agent %s: %s", c.RemoteAddr(), err)
+		_ = writeLine(c, result{Error: err.Error()})
+		return
+	}
+	var session chan net.Conn
+	if m.Session != 0 {
+		h.mu.Lock()
+		// only the agent of the site can connect the session
+		if p, ok := h.pending[m.Session]; ok && p.site == site {
+			session = p.agent
+			delete(h.pending, m.Session)
+		}
+		h.mu.Unlock()
+		if session == nil {
+			_ = writeLine(c, result{Error: fmt.Sprintf("%d: %s", m.Session, ErrUnknownSession)})
+			return
+		}
+	}
+	if err := writeLine(c, result{}); err != nil {
+		return
+	}
+	_ = c.SetDeadline(time.Time{})
+	if session != nil {
+		done := make(chan struct{})
+		// the connection is piped by the tool connection and closed by it
+		session <- &notifyConn{Conn: c, done: done}
+		<-done
+		return
+	}
+	h.control(site, c)
+}
+
+// authenticate returns the site of the hello if the token matches
+func (h *Hub) authenticate(m hello) (*hubSite, error) {
+	s, ok := h.sites[m.Site]
+	if !ok || subtle.ConstantTimeCompare([]byte(s.config.Token), []byte(m.Token)) != 1 {
+		return nil, fmt.Errorf("site %q: %w", m.Site, ErrWrongToken)
+	}
+	return s, nil
+}
+
+// control registers the control connection of the site until it's closed, a previous one is replaced
+func (h *Hub) control(s *hubSite, c net.Conn) {
+	h.mu.Lock()
+	previous := s.control
+	s.control = c
+	h.mu.Unlock()
+	if previous != nil {
+		_ = previous.Close()
+	}
+	log.Infof("tunnel: agent of site %s connected from %s", s.config.ID, c.RemoteAddr())
+	// the agent sends nothing after the hello, the read returns once the connection is closed
+	_, _ = c.Read(make([]byte, 1))
+	h.mu.Lock()
+	if s.control == c {
+		s.control = nil
+	}
+	h.mu.Unlock()
+	log.Infof("tunnel: agent of site %s disconnected", s.config.ID)
+}
+
+// acceptTools accepts the connections of the tools to the site until the listener is closed
+func (h *Hub) acceptTools(s *hubSite) {
+	defer h.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		h.track(c)
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			defer h.untrack(c)
+			if err := h.serveTool(s, c); err != nil {
+				log.Warnf("tunnel: site %s: %s", s.config.ID, err)
+			}
+		}()
+	}
+}
+
+// serveTool announces the session of the tool to the agent and pipes it to the connection of the agent
+func (h *Hub) serveTool(s *hubSite, c net.Conn) error {
+	session := make(chan net.Conn, 1)
+	h.mu.Lock()
+	control := s.control
+	h.session++
+	id := h.session
+	if control != nil {
+		h.pending[id] = pendingSession{s, session}
+	}
+	h.mu.Unlock()
+	if control == nil {
+		return ErrNotConnected
+	}
+	s.write.Lock()
+	err := writeLine(control, announcement{Session: id})
+	s.write.Unlock()
+	if err == nil {
+		select {
+		case agent := <-session:
+			pipe(c, agent)
+			return nil
+		case <-time.After(h.config.Timeout):
+			err = fmt.Errorf("session %d not connected by the agent", id)
+		}
+	}
+	h.mu.Lock()
+	_, pending := h.pending[id]
+	delete(h.pending, id)
+	h.mu.Unlock()
+	if !pending {
+		// the agent connected meanwhile
+		agent := <-session
+		_ = agent.Close()
+	}
+	return err
+}
+
+// notifyConn is a connection notifying when closed
+type notifyConn struct {
+	net.Conn
+	once sync.Once
+	done chan struct{}
+}
+
+// Close closes the connection and notifies
+func (c *notifyConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { close(c.done) })
+	return err
+}
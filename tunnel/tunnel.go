@@ -0,0 +1,109 @@
+// Package tunnel provides a reverse tunnel to reach systems behind NAT through a central hub.
+//
+// the agent runs on the site and keeps an authenticated TLS control connection to the hub. The hub listens on a
+// local port per site, every connection of a tool to the port is announced to the agent over the control
+// connection, the agent then dials a new TLS connection to the hub for the session and pipes it to the system.
+// Tools address the system of a site by the port of the site on the hub as if it was local, the rscp
+// encryption stays end to end between the tool and the system.
+//
+// every TLS connection starts with a hello line (json) of the agent authenticating the site by its token,
+// answered by the hub with a result line. The hub announces new sessions to the agent by session lines.
+package tunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+var (
+	ErrNoSites        = errors.New("no sites configured")
+	ErrMissingSiteID  = errors.New("site without id")
+	ErrDuplicateSite  = errors.New("duplicate site id")
+	ErrMissingToken   = errors.New("missing token")
+	ErrMissingListen  = errors.New("missing listen address")
+	ErrMissingCert    = errors.New("missing certificate or key")
+	ErrMissingHub     = errors.New("missing hub address")
+	ErrMissingDevice  = errors.New("missing device address")
+	ErrInvalidCA      = errors.New("no certificate found in ca file")
+	ErrRejected       = errors.New("rejected by hub")
+	ErrWrongToken     = errors.New("unknown site or wrong token")
+	ErrLineTooLong    = errors.New("line too long")
+	ErrUnknownSession = errors.New("unknown session")
+	ErrNotConnected   = errors.New("agent not connected")
+)
+
+// maxLineLength is the maximum length of a line of the protocol
+const maxLineLength = 1024
+
+// hello authenticates an agent, a session of 0 is the control connection
+type hello struct {
+	Site    string `json:"site"`
+	Token   string `json:"token"`
+	Session uint64 `json:"session"`
+}
+
+// result of the hello, empty error if accepted
+type result struct {
+	Error string `json:"error,omitempty"`
+}
+
+// announcement of a new session to the agent
+type announcement struct {
+	Session uint64 `json:"session"`
+}
+
+// writeLine writes the value as json line
+func writeLine(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// readLine reads a json line into v.
+//
+// the line is read byte by byte to not consume data following it on a session connection.
+func readLine(r io.Reader, v interface{}) error {
+	b := make([]byte, 0, maxLineLength)
+	c := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, c); err != nil {
+			return err
+		}
+		if c[0] == '\n' {
+			return json.Unmarshal(b, v)
+		}
+		if len(b) == maxLineLength {
+			return fmt.Errorf("%d bytes: %w", maxLineLength, ErrLineTooLong)
+		}
+		b = append(b, c[0])
+	}
+}
+
+// pipe copies the data between both connections until one of them is closed, both are closed afterwards
+func pipe(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		_ = a.Close()
+		_ = b.Close()
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,191 @@
+package tunnel
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spali/go-rscp/rscp"
+	"github.com/spali/go-rscp/simulator"
+)
+
+// writeCert writes a self signed certificate of the loopback address and its key, returns the paths
+func writeCert(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hub"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	cert, keyFile := filepath.Join(dir, "hub.crt"), filepath.Join(dir, "hub.key")
+	if err := os.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: k}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, keyFile
+}
+
+// connect sends a request to the system through the port of the site on the hub
+func connect(addr *net.TCPAddr) (string, error) {
+	client, err := rscp.NewClient(rscp.ClientConfig{
+		Address: addr.IP.String(), Port: uint16(addr.Port), Username: "user", Password: "password", Key: "key",
+		ConnectionTimeout: time.Second, ReceiveTimeout: time.Second,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = client.Disconnect() }()
+	m, err := client.Send(*rscp.NewMessage(rscp.INFO_REQ_SERIAL_NUMBER, nil))
+	if err != nil {
+		return "", err
+	}
+	return m.Value.(string), nil
+}
+
+func TestTunnel(t *testing.T) {
+	device, err := simulator.Start("127.0.0.1:0", simulator.Config{Key: "key", User: "user", Password: "password", Serial: "S10-1"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = device.Close() }()
+	cert, key := writeCert(t)
+	hub, err := ListenHub(HubConfig{
+		Listen: "127.0.0.1:0",
+		Cert:   cert,
+		Key:    key,
+		Sites: []Site{
+			{ID: "home", Token: "secret", Listen: "127.0.0.1:0"},
+			{ID: "other", Token: "other", Listen: "127.0.0.1:0"},
+		},
+		Timeout: time.Millisecond * 500,
+	})
+	if err != nil {
+		t.Fatalf("ListenHub() error = %v", err)
+	}
+	defer func() { _ = hub.Close() }()
+	config := AgentConfig{Hub: hub.Addr().String(), CA: cert, Site: "home", Token: "secret", Device: device.Addr().String()}
+
+	t.Run("wrong token", func(t *testing.T) {
+		c := config
+		c.Token = "wrong"
+		agent, err := NewAgent(c)
+		if err != nil {
+			t.Fatalf("NewAgent() error = %v", err)
+		}
+		if err := agent.Run(make(chan struct{})); !errors.Is(err, ErrRejected) {
+			t.Errorf("Run() error = %v, want %v", err, ErrRejected)
+		}
+	})
+
+	t.Run("untrusted hub", func(t *testing.T) {
+		c := config
+		c.CA = ""
+		c.Retry = time.Hour
+		agent, err := NewAgent(c)
+		if err != nil {
+			t.Fatalf("NewAgent() error = %v", err)
+		}
+		stop := make(chan struct{})
+		done := make(chan error)
+		go func() { done <- agent.Run(stop) }()
+		time.Sleep(time.Millisecond * 100)
+		close(stop)
+		if err := <-done; err != nil || hub.Connected("home") {
+			t.Errorf("Run() error = %v, connected %v", err, hub.Connected("home"))
+		}
+	})
+
+	t.Run("sessions", func(t *testing.T) {
+		agent, err := NewAgent(config)
+		if err != nil {
+			t.Fatalf("NewAgent() error = %v", err)
+		}
+		stop := make(chan struct{})
+		done := make(chan error)
+		go func() { done <- agent.Run(stop) }()
+		for deadline := time.Now().Add(time.Second * 2); !hub.Connected("home"); time.Sleep(time.Millisecond * 10) {
+			if time.Now().After(deadline) {
+				t.Fatal("agent not connected")
+			}
+		}
+		// concurrent sessions are tunneled independently
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				serial, err := connect(hub.SiteAddr("home"))
+				if err == nil && serial != "S10-1" {
+					err = errors.New("wrong serial " + serial)
+				}
+				errs <- err
+			}()
+		}
+		for i := 0; i < 3; i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("connect() error = %v", err)
+			}
+		}
+		if _, err := connect(hub.SiteAddr("other")); err == nil {
+			t.Errorf("connect() to site without agent expected error")
+		}
+		close(stop)
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+		for deadline := time.Now().Add(time.Second * 2); hub.Connected("home"); time.Sleep(time.Millisecond * 10) {
+			if time.Now().After(deadline) {
+				t.Fatal("agent still connected")
+			}
+		}
+	})
+}
+
+func TestHubConfig_check(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HubConfig
+		wantErr error
+	}{
+		{"missing cert", HubConfig{Sites: []Site{{ID: "a", Token: "t", Listen: ":1"}}}, ErrMissingCert},
+		{"no sites", HubConfig{Cert: "c", Key: "k"}, ErrNoSites},
+		{"duplicate", HubConfig{Cert: "c", Key: "k", Sites: []Site{{ID: "a", Token: "t", Listen: ":1"}, {ID: "a", Token: "t", Listen: ":2"}}}, ErrDuplicateSite},
+		{"missing token", HubConfig{Cert: "c", Key: "k", Sites: []Site{{ID: "a", Listen: ":1"}}}, ErrMissingToken},
+		{"missing listen", HubConfig{Cert: "c", Key: "k", Sites: []Site{{ID: "a", Token: "t"}}}, ErrMissingListen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.check(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}